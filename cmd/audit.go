@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/andreygrechin/gcphelper/internal/auditlog"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// auditResults is what the running command wrote, recorded in its --audit-log record.
+var auditResults struct {
+	count  *int   // count is the number of resources written, nil until results are written
+	parent string // parent is the parent the listing was limited to
+}
+
+// auditCommand is the command being run, recorded for --audit-log when it panics.
+var auditCommand *cobra.Command
+
+// resetAudit forgets the results and command of a previous run.
+func resetAudit() {
+	auditResults.count = nil
+	auditResults.parent = ""
+	auditCommand = nil
+}
+
+// recordResults adds count resources written with the given parent filter to the --audit-log record.
+func recordResults(count int, parent string) {
+	total := count
+	if auditResults.count != nil {
+		total += *auditResults.count
+	}
+	auditResults.count = &total
+	if parent != "" {
+		auditResults.parent = parent
+	}
+}
+
+// writeAuditLog appends the --audit-log record of command, started at start and finished with err, if
+// --audit-log is set.
+func writeAuditLog(command *cobra.Command, start time.Time, err error) error {
+	if globalAuditLog == "" || command == nil {
+		return nil
+	}
+
+	record := &auditlog.Record{
+		Timestamp:   start.UTC(),
+		Command:     command.CommandPath(),
+		Args:        command.Flags().Args(),
+		Flags:       make(map[string]string),
+		Parent:      auditResults.parent,
+		ResultCount: auditResults.count,
+		DurationMS:  time.Since(start).Milliseconds(),
+	}
+	command.Flags().Visit(func(flag *pflag.Flag) {
+		record.Flags[flag.Name] = redactURL(flag.Value.String())
+	})
+	if query := command.Flags().Lookup("query"); query != nil {
+		record.Query = query.Value.String()
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	return auditlog.Append(globalAuditLog, record)
+}
+
+// redactURL masks the password of a URL with credentials, such as a --proxy URL, so that it is not
+// written to the audit log. Other values are returned unchanged.
+func redactURL(value string) string {
+	u, err := url.Parse(value)
+	if err != nil || u.User == nil {
+		return value
+	}
+
+	return u.Redacted()
+}
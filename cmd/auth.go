@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/andreygrechin/gcphelper/pkg/identity"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// whoamiOptions holds the flag values of the "auth whoami" command.
+type whoamiOptions struct {
+	outputFlags
+}
+
+// NewAuthCommand creates and returns the auth command and its subcommands.
+func NewAuthCommand(log logger.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Inspect the credentials gcphelper uses",
+	}
+
+	cmd.AddCommand(newWhoamiCommand(log))
+
+	return cmd
+}
+
+// newWhoamiCommand creates the "auth whoami" command.
+func newWhoamiCommand(_ logger.Logger) *cobra.Command {
+	var opts whoamiOptions
+
+	cmd := &cobra.Command{
+		Use:   "whoami",
+		Short: "Show the account of the active Application Default Credentials",
+		Long: `Show the account of the active Application Default Credentials.
+
+This command resolves Application Default Credentials the same way the other
+commands do and prints the principal they authenticate as, the credential type,
+and the quota project billed for API calls. The account of a service account
+key, or of a service account impersonated by the credentials, is read from the
+credentials file. For user credentials and the compute metadata server an access
+token is requested and introspected with Google's token info endpoint.
+
+Examples:
+  # Confirm the active account before listing anything
+  gcphelper auth whoami
+
+  # Report the identity as JSON
+  gcphelper --format json auth whoami
+
+  # Print only the account email
+  gcphelper --format id auth whoami`,
+		RunE: func(command *cobra.Command, _ []string) error {
+			opts.outputFlags = globalOutputFlags()
+
+			return runWhoamiCommand(command.Context(), command.OutOrStdout(), command.ErrOrStderr(), opts)
+		},
+	}
+
+	return cmd
+}
+
+func runWhoamiCommand(ctx context.Context, stdout, stderr io.Writer, opts whoamiOptions) error {
+	renderOpts, err := opts.renderOptions(stdout, output.ResourceTypeIdentity)
+	if err != nil {
+		return err
+	}
+
+	if err := validateOnly(); err != nil {
+		return err
+	}
+
+	id, err := identity.Whoami(ctx, identity.DetectDefault,
+		identity.NewTokenIntrospector(http.DefaultClient, identity.TokenInfoURL))
+	if err != nil {
+		return err
+	}
+
+	// output results
+	return OutputIdentity(ctx, stdout, stderr, id, renderOpts)
+}
+
+// OutputIdentity renders the active identity to stdout and status messages to stderr.
+func OutputIdentity(ctx context.Context, stdout, stderr io.Writer, id *identity.Identity, opts OutputOptions) error {
+	identities := []*identity.Identity{id}
+
+	return renderResources(ctx, stdout, stderr, identities, output.ResourceTypeIdentity, opts)
+}
@@ -0,0 +1,49 @@
+package cmd_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/cmd"
+	"github.com/andreygrechin/gcphelper/pkg/identity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputIdentity(t *testing.T) {
+	id := &identity.Identity{
+		Account:        "user@example.com",
+		CredentialType: "authorized_user",
+		QuotaProject:   "billing-proj",
+		AccountSource:  identity.SourceTokenIntrospection,
+	}
+
+	testCases := map[string]struct {
+		opts cmd.OutputOptions
+		want string
+	}{
+		"json": {
+			opts: cmd.OutputOptions{Format: "json", Compact: true},
+			want: `[{"account":"user@example.com","credential_type":"authorized_user",` +
+				`"quota_project":"billing-proj","account_source":"token introspection"}]` + "\n",
+		},
+		"id": {
+			opts: cmd.OutputOptions{Format: "id"},
+			want: "user@example.com\n",
+		},
+		"csv": {
+			opts: cmd.OutputOptions{Format: "csv"},
+			want: "Account,Credential Type,Quota Project,Account Source\n" +
+				"user@example.com,authorized_user,billing-proj,token introspection\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, cmd.OutputIdentity(t.Context(), &buf, io.Discard, id, tc.opts))
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+}
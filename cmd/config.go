@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/andreygrechin/gcphelper/pkg/settings"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// configViewOptions holds the flag values of the "config view" command.
+type configViewOptions struct {
+	outputFlags
+}
+
+// NewConfigCommand creates and returns the config command and its subcommands.
+func NewConfigCommand(log logger.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the configuration gcphelper runs with",
+	}
+
+	cmd.AddCommand(newConfigViewCommand(log))
+
+	return cmd
+}
+
+// newConfigViewCommand creates the "config view" command.
+func newConfigViewCommand(_ logger.Logger) *cobra.Command {
+	var opts configViewOptions
+
+	cmd := &cobra.Command{
+		Use:   "view",
+		Short: "Show the effective value and source of every global setting",
+		Long: `Show the effective value and source of every global setting.
+
+This command lists each global flag with the value in effect and whether it was
+set on the command line ("flag") or left at its default ("default"), followed by
+the settings the Google Cloud client libraries read from the environment: the
+credentials file from GOOGLE_APPLICATION_CREDENTIALS and the quota project from
+GOOGLE_CLOUD_QUOTA_PROJECT ("env"), and the log level. No API call is made.
+
+Examples:
+  # Show the effective settings
+  gcphelper config view
+
+  # Check which flags a script's invocation sets
+  gcphelper --format json --qps 5 config view
+
+  # Show the endpoint settings
+  gcphelper --filter 'name~endpoint' config view`,
+		RunE: func(command *cobra.Command, _ []string) error {
+			opts.outputFlags = globalOutputFlags()
+
+			effective := EffectiveSettings(command.Root().PersistentFlags(), os.Getenv)
+
+			return runConfigViewCommand(command.Context(), command.OutOrStdout(), command.ErrOrStderr(), effective, opts)
+		},
+	}
+
+	return cmd
+}
+
+// EffectiveSettings returns the value and source of every visible flag in flags, in name order,
+// followed by the settings read from the environment with getenv.
+func EffectiveSettings(flags *pflag.FlagSet, getenv func(string) string) []*settings.Setting {
+	var effective []*settings.Setting
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if flag.Hidden {
+			return
+		}
+		// a value derived from another flag, such as the format selected by the --output extension, was
+		// set on the command line too
+		source := settings.SourceDefault
+		if flag.Changed || flag.Value.String() != flag.DefValue {
+			source = settings.SourceFlag
+		}
+		effective = append(effective, &settings.Setting{Name: flag.Name, Value: flag.Value.String(), Source: source})
+	})
+
+	return append(effective, settings.Environment(getenv)...)
+}
+
+func runConfigViewCommand(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	effective []*settings.Setting,
+	opts configViewOptions,
+) error {
+	renderOpts, err := opts.renderOptions(stdout, output.ResourceTypeSettings)
+	if err != nil {
+		return err
+	}
+
+	if err := validateOnly(); err != nil {
+		return err
+	}
+
+	// output results
+	return OutputSettings(ctx, stdout, stderr, effective, renderOpts)
+}
+
+// OutputSettings renders the effective settings to stdout and status messages to stderr.
+func OutputSettings(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	effective []*settings.Setting,
+	opts OutputOptions,
+) error {
+	return renderResources(ctx, stdout, stderr, effective, output.ResourceTypeSettings, opts)
+}
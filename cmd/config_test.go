@@ -0,0 +1,83 @@
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/cmd"
+	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/andreygrechin/gcphelper/pkg/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigView(t *testing.T) {
+	t.Setenv(settings.EnvCredentials, "")
+	t.Setenv(settings.EnvQuotaProject, "billing-proj")
+
+	var stdout bytes.Buffer
+	rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+	rootCmd.SetOut(&stdout)
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"--format", "json", "--qps", "5", "config", "view"})
+	t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+	require.NoError(t, rootCmd.Execute())
+
+	var effective []settings.Setting
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &effective))
+	byName := make(map[string]settings.Setting, len(effective))
+	for _, setting := range effective {
+		byName[setting.Name] = setting
+	}
+
+	testCases := map[string]struct {
+		name string
+		want settings.Setting
+	}{
+		"flag": {
+			name: "qps",
+			want: settings.Setting{Name: "qps", Value: "5", Source: settings.SourceFlag},
+		},
+		"default": {
+			name: "max-retries",
+			want: settings.Setting{Name: "max-retries", Value: "3", Source: settings.SourceDefault},
+		},
+		"env": {
+			name: "quota_project",
+			want: settings.Setting{Name: "quota_project", Value: "billing-proj", Source: settings.SourceEnv},
+		},
+		"unset env": {
+			name: "credentials",
+			want: settings.Setting{Name: "credentials", Value: settings.DefaultCredentials, Source: settings.SourceDefault},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, byName[tc.name])
+		})
+	}
+}
+
+func TestEffectiveSettingsDerivedFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.csv")
+	rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"--output", path, "config", "view"})
+	t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+	require.NoError(t, rootCmd.Execute())
+
+	getenv := func(string) string { return "" }
+	for _, setting := range cmd.EffectiveSettings(rootCmd.PersistentFlags(), getenv) {
+		if setting.Name == "format" {
+			assert.Equal(t, &settings.Setting{Name: "format", Value: "csv", Source: settings.SourceFlag}, setting,
+				"the format selected by the --output extension counts as set on the command line")
+
+			return
+		}
+	}
+	t.Fatal("format setting not found")
+}
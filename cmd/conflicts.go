@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+// ErrConflictingFlags is returned when output flags that cannot be combined are set together.
+var ErrConflictingFlags = errors.New("conflicting output flags")
+
+// ErrColumnsWithFieldsFile is returned when both --columns and --fields-file are specified.
+var ErrColumnsWithFieldsFile = errors.New("cannot combine --columns with --fields-file")
+
+// ErrCountByWithGroupBy is returned when --count-by is combined with --group-by, whose tables the counts
+// replace.
+var ErrCountByWithGroupBy = errors.New("cannot combine --count-by with --group-by")
+
+// ErrPagerWithNoPager is returned when both --pager and --no-pager are specified.
+var ErrPagerWithNoPager = errors.New("cannot combine --pager with --no-pager")
+
+// ErrPagerWithOutput is returned when --pager is combined with --output or --clipboard, which take the
+// output away from the terminal.
+var ErrPagerWithOutput = errors.New("cannot combine --pager with --output or --clipboard")
+
+// ErrStripPrefixWithNameRegex is returned when both --strip-prefix and --name-regex are specified.
+var ErrStripPrefixWithNameRegex = errors.New("cannot combine --strip-prefix with --name-regex")
+
+// flagConflict is a flag that cannot be combined with any of the flags in with.
+type flagConflict struct {
+	flag string   // flag is the flag name, e.g. "count-by"
+	with []string // with are the flags that conflict with it
+	err  error    // err is the error the conflict is reported with
+}
+
+// flagConflicts is the matrix of global output flags that cannot be set together. Conflicts that also
+// depend on a --format projection are checked when the projection is applied.
+var flagConflicts = []flagConflict{
+	{flag: "count-by", with: []string{"columns", "fields-file", "sort-by"}, err: ErrCountByWithListingFlags},
+	{flag: "count-by", with: []string{"group-by"}, err: ErrCountByWithGroupBy},
+	{flag: "columns", with: []string{"fields-file"}, err: ErrColumnsWithFieldsFile},
+	{flag: "wide", with: []string{"columns", "fields-file"}, err: ErrWideWithColumns},
+	{flag: "template", with: []string{"template-file"}, err: ErrTemplateWithTemplateFile},
+	{flag: "output", with: []string{"clipboard"}, err: ErrOutputWithClipboard},
+	{flag: "pager", with: []string{"no-pager"}, err: ErrPagerWithNoPager},
+	{flag: "pager", with: []string{"output", "clipboard"}, err: ErrPagerWithOutput},
+	{flag: "strip-prefix", with: []string{"name-regex", "name-replacement"}, err: ErrStripPrefixWithNameRegex},
+}
+
+// anyFlagChanged reports whether any of the named flags is set in flags.
+func anyFlagChanged(flags *pflag.FlagSet, names ...string) bool {
+	for _, name := range names {
+		if flags.Changed(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckFlagConflicts returns an error naming the first pair of conflicting output flags set in flags,
+// wrapping ErrConflictingFlags and the conflict's own error.
+func CheckFlagConflicts(flags *pflag.FlagSet) error {
+	for _, conflict := range flagConflicts {
+		if !flags.Changed(conflict.flag) {
+			continue
+		}
+		for _, other := range conflict.with {
+			if flags.Changed(other) {
+				return fmt.Errorf("%w --%s and --%s: %w", ErrConflictingFlags, conflict.flag, other, conflict.err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/andreygrechin/gcphelper/internal/cleanup"
+	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/andreygrechin/gcphelper/internal/reqmeta"
+	"github.com/andreygrechin/gcphelper/internal/resourcename"
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// ErrNoFolderIDs is returned when folders describe is given no folder IDs.
+var ErrNoFolderIDs = errors.New("no folder IDs given")
+
+// ErrInvalidFolderID is returned when a folder ID is neither a number nor a "folders/" resource name.
+var ErrInvalidFolderID = errors.New("invalid folder ID")
+
+// stdinArg is the argument that reads IDs from stdin.
+const stdinArg = "-"
+
+// describeOptions holds the flag values of the folders describe command.
+type describeOptions struct {
+	outputFlags
+	clientFlags
+	concurrency     int
+	continueOnError bool
+	wrap            bool
+}
+
+// newFoldersDescribeCommand creates the "folders describe" command.
+func newFoldersDescribeCommand(log logger.Logger) *cobra.Command {
+	var opts describeOptions
+
+	cmd := &cobra.Command{
+		Use:   "describe ID... | -",
+		Short: "Show the folders with the given IDs",
+		Annotations: requiresIAM(
+			[]string{"roles/resourcemanager.folderViewer"},
+			"resourcemanager.folders.get",
+		),
+		Long: `Show the folders with the given IDs.
+
+This command fetches each folder with the GetFolder API, several at a time, and
+writes them as one list in the selected --format, in the order the IDs were
+given. IDs may be numbers or "folders/" resource names, and an ID given more than
+once is fetched once. With "-" as the only argument, IDs are read from stdin,
+separated by whitespace. With --format json a single folder is written as a JSON
+object, unless --wrap is set, and several folders as an array.
+
+By default the first folder that cannot be fetched, for example because it does
+not exist, stops the command. With --continue-on-error the remaining folders are
+fetched and written, and the failures are reported at the end.
+
+Examples:
+  # Describe two folders
+  gcphelper folders describe 123456789 987654321
+
+  # Describe folders found by another command as JSON
+  gcphelper -f id folders --parent-folder 123456789 | gcphelper -f json folders describe -
+
+  # Skip IDs that no longer exist
+  gcphelper folders describe --continue-on-error - < folder-ids.txt`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(command *cobra.Command, args []string) error {
+			opts.outputFlags = globalOutputFlags()
+			opts.clientFlags = globalClientFlags()
+
+			names, err := FolderNames(args, command.InOrStdin())
+			if err != nil {
+				return err
+			}
+			opts.format = DescribeFormat(opts.format, len(names), opts.wrap)
+
+			return runFoldersDescribeCommand(command.Context(), command.OutOrStdout(), command.ErrOrStderr(), names, opts, log)
+		},
+	}
+
+	cmd.Flags().IntVar(&opts.concurrency, "concurrency", folders.DefaultConcurrency,
+		"Number of folders to fetch in parallel")
+	cmd.Flags().BoolVar(&opts.continueOnError, "continue-on-error", false,
+		"Fetch the remaining folders when one fails and report all failures at the end")
+	cmd.Flags().BoolVar(&opts.wrap, "wrap", false,
+		"With --format json, write a single folder in a one-element array like the list commands")
+
+	return cmd
+}
+
+// DescribeFormat returns the format describe output is written in. With --format json, a single
+// requested resource is written as a JSON object unless wrap is set; several resources are written as
+// an array, and other formats are kept.
+func DescribeFormat(format string, count int, wrap bool) string {
+	if format == string(output.FormatJSON) && count == 1 && !wrap {
+		return string(output.FormatJSONObject)
+	}
+
+	return format
+}
+
+// FolderNames returns the resource names of the folder IDs in args, or of the whitespace-separated IDs
+// read from stdin when args is "-". IDs may be numbers or "folders/" resource names.
+func FolderNames(args []string, stdin io.Reader) ([]string, error) {
+	ids := args
+	if len(args) == 1 && args[0] == stdinArg {
+		var err error
+		if ids, err = scanIDs(stdin); err != nil {
+			return nil, fmt.Errorf("failed to read folder IDs from stdin: %w", err)
+		}
+	}
+
+	return folderNamesFromIDs(ids)
+}
+
+// scanIDs returns the whitespace-separated IDs read from r.
+func scanIDs(r io.Reader) ([]string, error) {
+	var ids []string
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanWords)
+	for scanner.Scan() {
+		ids = append(ids, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan IDs: %w", err)
+	}
+
+	return ids, nil
+}
+
+// folderNamesFromIDs returns the resource names of folder IDs given as numbers or "folders/" resource names.
+func folderNamesFromIDs(ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, ErrNoFolderIDs
+	}
+
+	names := make([]string, 0, len(ids))
+	for _, id := range ids {
+		name := id
+		if !strings.HasPrefix(name, "folders/") {
+			name = "folders/" + name
+		}
+		if _, err := resourcename.ExtractID(name, "folders/"); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidFolderID, id)
+		}
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+func runFoldersDescribeCommand(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	names []string,
+	opts describeOptions,
+	log logger.Logger,
+) error {
+	ctx = reqmeta.WithRequestReason(ctx, opts.requestReason)
+
+	// validate flags before any API client is created
+	renderOpts, err := opts.renderOptions(stdout, output.ResourceTypeFolders)
+	if err != nil {
+		return err
+	}
+	clientOpts, err := opts.clientOptions()
+	if err != nil {
+		return err
+	}
+	if err := validateOnly(); err != nil {
+		return err
+	}
+
+	// create folders service
+	service, err := folders.NewServiceFromContextWithLogger(ctx, log, clientOpts...)
+	if err != nil {
+		return fmt.Errorf("failed to create folders service: %w", err)
+	}
+	setSpinner(service, !opts.noSpinner)
+	defer cleanup.CloseAndLog(log, "failed to close service", service)
+
+	folderList, err := service.GetFolders(ctx, names, opts.concurrency, opts.continueOnError)
+	var lookupErr *folders.LookupError
+	if err != nil && !errors.As(err, &lookupErr) {
+		return err
+	}
+
+	// output results
+	if err := OutputFolders(ctx, stdout, stderr, folderList, renderOpts); err != nil {
+		return err
+	}
+
+	// report folders that failed under --continue-on-error after the successful results
+	if lookupErr != nil {
+		PrintLookupFailures(stderr, lookupErr)
+
+		return lookupErr
+	}
+
+	return nil
+}
+
+// PrintLookupFailures writes a summary of the folders that could not be retrieved.
+func PrintLookupFailures(w io.Writer, lookupErr *folders.LookupError) {
+	fmt.Fprintf(w, "Errors: %s:\n", lookupErr.Error())
+	for _, failure := range lookupErr.Failures {
+		fmt.Fprintf(w, "  %s: %v\n", failure.Name, failure.Err)
+	}
+}
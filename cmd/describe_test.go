@@ -0,0 +1,158 @@
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/cmd"
+	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFolderNames(t *testing.T) {
+	testCases := map[string]struct {
+		args    []string
+		stdin   string
+		want    []string
+		wantErr error
+	}{
+		"ids and resource names": {
+			args: []string{"123", "folders/456"},
+			want: []string{"folders/123", "folders/456"},
+		},
+		"ids from stdin": {
+			args:  []string{"-"},
+			stdin: "123\n456 789\n\n",
+			want:  []string{"folders/123", "folders/456", "folders/789"},
+		},
+		"empty stdin": {
+			args:    []string{"-"},
+			stdin:   "\n",
+			wantErr: cmd.ErrNoFolderIDs,
+		},
+		"non-numeric id": {
+			args:    []string{"123", "prod"},
+			wantErr: cmd.ErrInvalidFolderID,
+		},
+		"organization name": {
+			args:    []string{"organizations/123"},
+			wantErr: cmd.ErrInvalidFolderID,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := cmd.FolderNames(tc.args, strings.NewReader(tc.stdin))
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestFoldersDescribeCommandValidation(t *testing.T) {
+	testCases := map[string]struct {
+		args    []string
+		stdin   string
+		wantErr error
+	}{
+		"invalid id": {
+			args:    []string{"folders", "describe", "123", "abc"},
+			wantErr: cmd.ErrInvalidFolderID,
+		},
+		"no ids on stdin": {
+			args:    []string{"folders", "describe", "-"},
+			wantErr: cmd.ErrNoFolderIDs,
+		},
+		"count with columns": {
+			args:    []string{"--count-by", "state", "--columns", "id", "folders", "describe", "123"},
+			wantErr: cmd.ErrCountByWithListingFlags,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			rootCmd.SetOut(&bytes.Buffer{})
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetIn(strings.NewReader(tc.stdin))
+			rootCmd.SetArgs(tc.args)
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			err := rootCmd.Execute()
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestPrintLookupFailures(t *testing.T) {
+	lookupErr := &folders.LookupError{
+		Failures: []folders.FolderError{
+			{Name: "folders/404", Err: status.Error(codes.NotFound, "not found")},
+		},
+		Total: 3,
+	}
+
+	var stderr bytes.Buffer
+	cmd.PrintLookupFailures(&stderr, lookupErr)
+
+	assert.Equal(t, "Errors: failed to get 1 of 3 folders:\n"+
+		"  folders/404: rpc error: code = NotFound desc = not found\n", stderr.String())
+}
+
+func TestDescribeFormat(t *testing.T) {
+	testCases := map[string]struct {
+		format string
+		count  int
+		wrap   bool
+		want   string
+	}{
+		"single resource as an object": {format: "json", count: 1, want: "json-object"},
+		"wrapped single resource":      {format: "json", count: 1, wrap: true, want: "json"},
+		"several resources":            {format: "json", count: 2, want: "json"},
+		"other formats kept":           {format: "jsonl", count: 1, want: "jsonl"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, cmd.DescribeFormat(tc.format, tc.count, tc.wrap))
+		})
+	}
+}
+
+func TestDescribeJSONShape(t *testing.T) {
+	folder := &folders.Folder{ID: "1", Name: "folders/1", DisplayName: "Finance", Parent: "organizations/9"}
+
+	// describe writes a lone folder as an object
+	var describeOut bytes.Buffer
+	opts := cmd.OutputOptions{Format: cmd.DescribeFormat("json", 1, false)}
+	require.NoError(t, cmd.OutputFolders(t.Context(), &describeOut, io.Discard, []*folders.Folder{folder}, opts))
+	var described folders.Folder
+	require.NoError(t, json.Unmarshal(describeOut.Bytes(), &described))
+	assert.Equal(t, "folders/1", described.Name)
+
+	// the list command keeps an array, also for a single folder
+	path := writeFolderExport(t, []*folders.Folder{folder})
+	var listOut bytes.Buffer
+	rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+	rootCmd.SetOut(&listOut)
+	rootCmd.SetErr(io.Discard)
+	rootCmd.SetArgs([]string{"--format", "json", "folders", "--from-file", path})
+	t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+	require.NoError(t, rootCmd.Execute())
+	var listed []folders.Folder
+	require.NoError(t, json.Unmarshal(listOut.Bytes(), &listed))
+	require.Len(t, listed, 1)
+	assert.Equal(t, "folders/1", listed[0].Name)
+}
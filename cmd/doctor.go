@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"cloud.google.com/go/auth/credentials"
+	"github.com/andreygrechin/gcphelper/internal/cleanup"
+	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/andreygrechin/gcphelper/internal/reqmeta"
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/organizations"
+	"github.com/spf13/cobra"
+	"google.golang.org/api/option"
+)
+
+// ErrDoctorChecksFailed is returned when at least one doctor check fails.
+var ErrDoctorChecksFailed = errors.New("one or more checks failed")
+
+// doctorCheckTimeout bounds each check so that an unreachable API does not hang the command.
+const doctorCheckTimeout = 30 * time.Second
+
+// cloudPlatformScope is the OAuth scope requested when resolving Application Default Credentials.
+const cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// DoctorCheck is a single step of the doctor command.
+type DoctorCheck struct {
+	Name string                          // Name describes what the check verifies
+	Fix  string                          // Fix suggests how to resolve a failure
+	Run  func(ctx context.Context) error // Run performs the check and returns nil on success
+}
+
+// DoctorResult is the outcome of a doctor check.
+type DoctorResult struct {
+	Check DoctorCheck
+	Err   error
+}
+
+// Passed reports whether the check succeeded.
+func (r DoctorResult) Passed() bool {
+	return r.Err == nil
+}
+
+// doctorOptions holds the flag values of the doctor command.
+type doctorOptions struct {
+	clientFlags
+}
+
+// NewDoctorCommand creates and returns the doctor command.
+func NewDoctorCommand(log logger.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check that gcphelper can access the Google Cloud APIs",
+		Annotations: requiresIAM(
+			[]string{"roles/resourcemanager.organizationViewer"},
+			"resourcemanager.organizations.get",
+		),
+		Long: `Check that gcphelper can access the Google Cloud APIs.
+
+This command verifies that Application Default Credentials resolve, that the
+Resource Manager clients can be constructed, and that a minimal organization
+search succeeds. Every check runs even when an earlier one fails, and each
+failure is reported with a suggested fix.
+
+Examples:
+  # Verify the setup
+  gcphelper doctor
+
+  # Verify access through a regional endpoint
+  gcphelper --endpoint-region eu doctor`,
+		RunE: func(command *cobra.Command, _ []string) error {
+			opts := doctorOptions{clientFlags: globalClientFlags()}
+
+			return runDoctorCommand(command.Context(), command.OutOrStdout(), opts, log)
+		},
+	}
+
+	return cmd
+}
+
+func runDoctorCommand(ctx context.Context, stdout io.Writer, opts doctorOptions, log logger.Logger) error {
+	ctx = reqmeta.WithRequestReason(ctx, opts.requestReason)
+
+	clientOpts, err := opts.clientOptions()
+	if err != nil {
+		return err
+	}
+	if err := validateOnly(); err != nil {
+		return err
+	}
+
+	results := RunDoctorChecks(ctx, defaultDoctorChecks(log, clientOpts))
+	RenderDoctorChecklist(stdout, results)
+
+	for _, result := range results {
+		if !result.Passed() {
+			return ErrDoctorChecksFailed
+		}
+	}
+
+	return nil
+}
+
+// RunDoctorChecks runs every check in order, continuing past failures, and returns their results.
+func RunDoctorChecks(ctx context.Context, checks []DoctorCheck) []DoctorResult {
+	results := make([]DoctorResult, 0, len(checks))
+	for _, check := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, doctorCheckTimeout)
+		err := check.Run(checkCtx)
+		cancel()
+
+		results = append(results, DoctorResult{Check: check, Err: err})
+	}
+
+	return results
+}
+
+// RenderDoctorChecklist writes one line per check marked PASS or FAIL, followed by the error and
+// suggested fix of each failed check, and a closing summary line.
+func RenderDoctorChecklist(w io.Writer, results []DoctorResult) {
+	failed := 0
+	for _, result := range results {
+		if result.Passed() {
+			fmt.Fprintf(w, "[PASS] %s\n", result.Check.Name)
+
+			continue
+		}
+
+		failed++
+		fmt.Fprintf(w, "[FAIL] %s\n", result.Check.Name)
+		fmt.Fprintf(w, "       error: %v\n", result.Err)
+		if result.Check.Fix != "" {
+			fmt.Fprintf(w, "       fix:   %s\n", result.Check.Fix)
+		}
+	}
+
+	if failed == 0 {
+		fmt.Fprintf(w, "\nAll %d checks passed.\n", len(results))
+
+		return
+	}
+
+	fmt.Fprintf(w, "\n%d of %d checks failed.\n", failed, len(results))
+}
+
+// defaultDoctorChecks returns the checks run by the doctor command.
+func defaultDoctorChecks(log logger.Logger, clientOpts []option.ClientOption) []DoctorCheck {
+	return []DoctorCheck{
+		{
+			Name: "Application Default Credentials resolve",
+			Fix:  "run 'gcloud auth application-default login' or set GOOGLE_APPLICATION_CREDENTIALS",
+			Run:  checkCredentials,
+		},
+		{
+			Name: "Resource Manager clients can be constructed",
+			Fix:  "check the --endpoint and --endpoint-region flags and your network connectivity",
+			Run: func(ctx context.Context) error {
+				return checkClients(ctx, log, clientOpts)
+			},
+		},
+		{
+			Name: "Organization search succeeds (limit 1)",
+			Fix: "grant the resourcemanager.organizations.get permission and " +
+				"enable the Cloud Resource Manager API on your quota project",
+			Run: func(ctx context.Context) error {
+				return checkOrganizationSearch(ctx, clientOpts)
+			},
+		},
+	}
+}
+
+// checkCredentials resolves Application Default Credentials and fetches a token with them.
+func checkCredentials(ctx context.Context) error {
+	creds, err := credentials.DetectDefault(&credentials.DetectOptions{Scopes: []string{cloudPlatformScope}})
+	if err != nil {
+		return fmt.Errorf("failed to find default credentials: %w", err)
+	}
+
+	if _, err := creds.Token(ctx); err != nil {
+		return fmt.Errorf("failed to obtain a token: %w", err)
+	}
+
+	return nil
+}
+
+// checkClients constructs the organizations and folders services and closes them again.
+func checkClients(ctx context.Context, log logger.Logger, clientOpts []option.ClientOption) error {
+	orgService, err := organizations.NewServiceFromContextWithLogger(ctx, log, clientOpts...)
+	if err != nil {
+		return fmt.Errorf("failed to create organizations service: %w", err)
+	}
+
+	folderService, err := folders.NewServiceFromContextWithLogger(ctx, log, clientOpts...)
+	if err != nil {
+		return errors.Join(
+			fmt.Errorf("failed to create folders service: %w", err),
+			cleanup.CloseAll(orgService),
+		)
+	}
+
+	return cleanup.CloseAll(orgService, folderService)
+}
+
+// checkOrganizationSearch runs a search for at most one organization.
+func checkOrganizationSearch(ctx context.Context, clientOpts []option.ClientOption) error {
+	client, err := organizations.NewClientFromContext(ctx, clientOpts...)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.SearchOrganizationsLimit(ctx, 1)
+
+	return errors.Join(err, cleanup.CloseAll(client))
+}
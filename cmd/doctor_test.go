@@ -0,0 +1,80 @@
+package cmd_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/cmd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunDoctorChecks(t *testing.T) {
+	errNoCredentials := errors.New("no credentials")
+	var ran []string
+	check := func(name string, err error) cmd.DoctorCheck {
+		return cmd.DoctorCheck{
+			Name: name,
+			Fix:  "fix " + name,
+			Run: func(context.Context) error {
+				ran = append(ran, name)
+
+				return err
+			},
+		}
+	}
+
+	results := cmd.RunDoctorChecks(context.Background(), []cmd.DoctorCheck{
+		check("credentials", errNoCredentials),
+		check("clients", nil),
+		check("search", nil),
+	})
+
+	assert.Equal(t, []string{"credentials", "clients", "search"}, ran, "all checks should run past a failure")
+	assert.Len(t, results, 3)
+	assert.False(t, results[0].Passed())
+	assert.ErrorIs(t, results[0].Err, errNoCredentials)
+	assert.True(t, results[1].Passed())
+	assert.True(t, results[2].Passed())
+}
+
+func TestRenderDoctorChecklist(t *testing.T) {
+	pass := cmd.DoctorCheck{Name: "Clients can be constructed", Fix: "check the endpoint"}
+	fail := cmd.DoctorCheck{Name: "Credentials resolve", Fix: "run gcloud auth application-default login"}
+	noFix := cmd.DoctorCheck{Name: "Search succeeds"}
+
+	testCases := map[string]struct {
+		results []cmd.DoctorResult
+		want    string
+	}{
+		"all passed": {
+			results: []cmd.DoctorResult{{Check: pass}, {Check: noFix}},
+			want: "[PASS] Clients can be constructed\n" +
+				"[PASS] Search succeeds\n" +
+				"\nAll 2 checks passed.\n",
+		},
+		"failures with and without fix": {
+			results: []cmd.DoctorResult{
+				{Check: fail, Err: errors.New("could not find default credentials")},
+				{Check: pass},
+				{Check: noFix, Err: errors.New("permission denied")},
+			},
+			want: "[FAIL] Credentials resolve\n" +
+				"       error: could not find default credentials\n" +
+				"       fix:   run gcloud auth application-default login\n" +
+				"[PASS] Clients can be constructed\n" +
+				"[FAIL] Search succeeds\n" +
+				"       error: permission denied\n" +
+				"\n2 of 3 checks failed.\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			cmd.RenderDoctorChecklist(&buf, tc.results)
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/spf13/cobra"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorObject is the JSON object written to stderr for a failed command when a machine-readable
+// output format is selected.
+type ErrorObject struct {
+	Error ErrorDetail `json:"error"`
+}
+
+// ErrorDetail describes a command failure.
+type ErrorDetail struct {
+	Code    string `json:"code"`    // Code is the canonical gRPC code name, e.g. "PERMISSION_DENIED"
+	Message string `json:"message"` // Message is the API's error message, or the full error text
+}
+
+// NewErrorObject describes err. Errors carrying a gRPC status report its code and message; any other
+// error reports UNKNOWN and its full text.
+func NewErrorObject(err error) ErrorObject {
+	detail := ErrorDetail{Code: code.Code_UNKNOWN.String(), Message: err.Error()}
+
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &grpcErr) {
+		st := grpcErr.GRPCStatus().Proto()
+		detail.Code = code.Code(st.GetCode()).String()
+		detail.Message = st.GetMessage()
+	}
+
+	return ErrorObject{Error: detail}
+}
+
+// WriteError reports a failed command on w. For the json, jsonl and rawjson formats it writes a
+// single-line JSON error object so that scripts can parse failures; other formats get the
+// human-readable text, including any guidance added by the command.
+func WriteError(w io.Writer, err error, format string) {
+	if !machineReadableFormat(format) {
+		fmt.Fprintf(w, "error executing root command: %v\n", err)
+
+		return
+	}
+
+	data, marshalErr := json.Marshal(NewErrorObject(err))
+	if marshalErr != nil {
+		fmt.Fprintf(w, "error executing root command: %v\n", err)
+
+		return
+	}
+	fmt.Fprintf(w, "%s\n", data)
+}
+
+// machineReadableFormat reports whether the output format is meant for programs, so that failures and
+// the verbose summary are written to stderr as JSON.
+func machineReadableFormat(format string) bool {
+	switch output.Format(format) {
+	case output.FormatJSON, output.FormatJSONObject, output.FormatJSONL, output.FormatRawJSON, output.FormatBigQuery:
+		return true
+	default:
+		return false
+	}
+}
+
+// silenceHumanErrors stops cobra from printing its own error and usage text when errors are written
+// as JSON, keeping stderr parseable.
+func silenceHumanErrors(command *cobra.Command) {
+	if machineReadableFormat(globalFormat) {
+		command.SilenceErrors = true
+		command.SilenceUsage = true
+	}
+}
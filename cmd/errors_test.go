@@ -0,0 +1,77 @@
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/cmd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var errErrorsTestDiskFull = errors.New("disk full")
+
+func TestWriteError(t *testing.T) {
+	permissionErr := cmd.HandleFoldersError(
+		fmt.Errorf("failed to search folders: %w", status.Error(codes.PermissionDenied, "caller lacks permission")),
+		"",
+	)
+
+	tests := map[string]struct {
+		err      error
+		format   string
+		wantJSON *cmd.ErrorObject
+		wantText string
+	}{
+		"permission error as json": {
+			err:    permissionErr,
+			format: "json",
+			wantJSON: &cmd.ErrorObject{Error: cmd.ErrorDetail{
+				Code:    "PERMISSION_DENIED",
+				Message: "caller lacks permission",
+			}},
+		},
+		"generic error as jsonl": {
+			err:    fmt.Errorf("failed to write output: %w", errErrorsTestDiskFull),
+			format: "jsonl",
+			wantJSON: &cmd.ErrorObject{Error: cmd.ErrorDetail{
+				Code:    "UNKNOWN",
+				Message: "failed to write output: disk full",
+			}},
+		},
+		"permission error as table keeps guidance": {
+			err:      permissionErr,
+			format:   "table",
+			wantText: "Ensure you have the required IAM permissions",
+		},
+		"generic error as csv": {
+			err:      errErrorsTestDiskFull,
+			format:   "csv",
+			wantText: "error executing root command: disk full",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stderr bytes.Buffer
+			cmd.WriteError(&stderr, tt.err, tt.format)
+
+			if tt.wantJSON == nil {
+				assert.Contains(t, stderr.String(), tt.wantText)
+
+				return
+			}
+
+			assert.Equal(t, 1, strings.Count(stderr.String(), "\n"), "JSON errors are a single line")
+			var got cmd.ErrorObject
+			require.NoError(t, json.Unmarshal(stderr.Bytes(), &got))
+			assert.Equal(t, *tt.wantJSON, got)
+		})
+	}
+}
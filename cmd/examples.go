@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Annotation keys of the generated help examples: on a command, the comma-separated flags to generate
+// examples for, and on a flag, the value it takes in them.
+const (
+	exampleFlagsAnnotation = "gcphelper/example-flags"
+	flagExampleAnnotation  = "gcphelper/example"
+)
+
+// withExampleFlags adds the flags whose help examples are generated to the command annotations.
+func withExampleFlags(annotations map[string]string, flags ...string) map[string]string {
+	annotations[exampleFlagsAnnotation] = strings.Join(flags, ",")
+
+	return annotations
+}
+
+// setFlagExample records the value the flag takes in generated help examples. An unregistered flag is
+// ignored.
+func setFlagExample(flags *pflag.FlagSet, name, value string) {
+	flag := flags.Lookup(name)
+	if flag == nil {
+		return
+	}
+	if flag.Annotations == nil {
+		flag.Annotations = make(map[string][]string)
+	}
+	flag.Annotations[flagExampleAnnotation] = []string{value}
+}
+
+// generateExamples appends the examples generated from flag metadata to the curated examples of the
+// command and its subcommands. It runs once the command tree is built, so that inherited global flags
+// can be looked up.
+func generateExamples(command *cobra.Command) {
+	for _, child := range command.Commands() {
+		generateExamples(child)
+	}
+
+	names := splitAnnotation(command, exampleFlagsAnnotation)
+	if len(names) == 0 {
+		return
+	}
+	generated := FlagExamples(command, names...)
+	if command.Example == "" {
+		command.Example = generated
+
+		return
+	}
+	command.Example += "\n\n" + generated
+}
+
+// FlagExamples returns a help example for each of the named flags of the command, commented with the
+// first clause of the flag's usage and using the flag's recorded example value. Global flags are placed
+// before the command name and the command's own flags after it. Flags that are not registered, or have
+// no example value, are skipped, so the examples cannot mention flags the command does not accept.
+func FlagExamples(command *cobra.Command, names ...string) string {
+	root := command.Root().Name()
+	path := strings.TrimPrefix(command.CommandPath(), root)
+
+	examples := make([]string, 0, len(names))
+	for _, name := range names {
+		invocation := root + " %s" + path
+		flag := command.LocalFlags().Lookup(name)
+		if flag != nil {
+			invocation = root + path + " %s"
+		} else if flag = command.InheritedFlags().Lookup(name); flag == nil {
+			continue
+		}
+
+		value, ok := flag.Annotations[flagExampleAnnotation]
+		if !ok || len(value) == 0 {
+			continue
+		}
+		arg := "--" + flag.Name
+		if flag.Value.Type() != "bool" {
+			arg += " " + shellQuote(value[0])
+		}
+
+		comment := usageSummary(flag.Usage) + ": " + value[0]
+		examples = append(examples, "  # "+comment+"\n  "+strings.Replace(invocation, "%s", arg, 1))
+	}
+
+	return strings.Join(examples, "\n\n")
+}
+
+// usageSummary returns the usage of a flag up to its first parenthesis, comma or semicolon, e.g.
+// "Output format" for "Output format (table, json, ...)".
+func usageSummary(usage string) string {
+	if i := strings.IndexAny(usage, "(,;"); i > 0 {
+		usage = usage[:i]
+	}
+
+	return strings.TrimSpace(usage)
+}
+
+// shellQuote quotes value for a POSIX shell when it contains characters the shell would interpret.
+func shellQuote(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\n'\"\\$`!*?[]{}()<>|&;~#") {
+		return value
+	}
+
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,76 @@
+package cmd_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/cmd"
+	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlagExamples(t *testing.T) {
+	tests := map[string]struct {
+		command []string
+		flags   []string
+		want    []string
+		notWant []string
+	}{
+		"global and local flags": {
+			command: []string{"folders"},
+			flags:   []string{"format", "parent-folder", "filter"},
+			want: []string{
+				"  # Output format: json\n  gcphelper --format json folders",
+				"  # Parent folder ID to filter folders by: 987654321\n  gcphelper folders --parent-folder 987654321",
+				"gcphelper --filter state=ACTIVE folders",
+			},
+		},
+		"unregistered flag": {
+			command: []string{"organizations"},
+			flags:   []string{"parent-folder", "format"},
+			want:    []string{"gcphelper --format json organizations"},
+			notWant: []string{"--parent-folder"},
+		},
+		"flag without example": {
+			command: []string{"folders"},
+			flags:   []string{"verbose"},
+			notWant: []string{"--verbose"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			command, _, err := rootCmd.Find(tt.command)
+			require.NoError(t, err)
+
+			examples := cmd.FlagExamples(command, tt.flags...)
+			for _, want := range tt.want {
+				assert.Contains(t, examples, want)
+			}
+			for _, notWant := range tt.notWant {
+				assert.NotContains(t, examples, notWant)
+			}
+		})
+	}
+}
+
+func TestHelpGeneratedExamples(t *testing.T) {
+	rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+	var stdout bytes.Buffer
+	rootCmd.SetOut(&stdout)
+	rootCmd.SetArgs([]string{"folders", "--help"})
+
+	require.NoError(t, rootCmd.Execute())
+	// the curated examples are kept ahead of the generated ones
+	assert.Contains(t, stdout.String(), "  # List all accessible folders\n  gcphelper folders\n")
+	for _, want := range []string{
+		"gcphelper --format json folders",
+		"gcphelper folders --parent-organization 123456789\n",
+		"gcphelper folders --parent-folder 987654321\n",
+		"gcphelper --filter state=ACTIVE folders",
+	} {
+		assert.Contains(t, stdout.String(), want)
+	}
+}
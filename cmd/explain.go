@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+)
+
+// pageSizeNote describes the page size of folder listings, which leave it to the server.
+const pageSizeNote = "Page size: server default, no limit"
+
+// ExplainQuery writes the requests the folders command is about to send for --explain-query: the
+// composed SearchFolders query when no parent is given or results are streamed, otherwise one
+// ListFolders call, or with the asset backend one SearchAllResources query, per parent. A client-side
+// --filter, applied to the fetched folders, is listed after them.
+func ExplainQuery(w io.Writer, parents []string, fetchOpts *folders.FetchOptions, stream bool, backend, filter string) {
+	switch {
+	case backend == backendAsset:
+		for _, parent := range parents {
+			fmt.Fprintf(w, "SearchAllResources scope: %s, query: %s\n", parent, folders.AssetChildrenQuery(parent))
+		}
+	case len(parents) == 0 || stream:
+		fmt.Fprintf(w, "SearchFolders query: %s\n", folders.SearchQuery(fetchOpts))
+	default:
+		for _, parent := range parents {
+			fmt.Fprintf(w, "ListFolders parent: %s\n", parent)
+		}
+	}
+	fmt.Fprintln(w, pageSizeNote)
+
+	if filter != "" {
+		fmt.Fprintf(w, "Client-side filter: %s\n", filter)
+	}
+}
@@ -0,0 +1,63 @@
+package cmd_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/cmd"
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplainQuery(t *testing.T) {
+	tests := map[string]struct {
+		parents   []string
+		fetchOpts *folders.FetchOptions
+		stream    bool
+		backend   string
+		filter    string
+		want      string
+	}{
+		"search with raw query clauses and a filter": {
+			fetchOpts: &folders.FetchOptions{Query: "displayName:prod*"},
+			backend:   "resourcemanager",
+			filter:    "state=ACTIVE",
+			want: "SearchFolders query: state:ACTIVE AND displayName:prod*\n" +
+				"Page size: server default, no limit\n" +
+				"Client-side filter: state=ACTIVE\n",
+		},
+		"streamed parent is searched": {
+			parents:   []string{"folders/123"},
+			fetchOpts: &folders.FetchOptions{Parent: "folders/123"},
+			stream:    true,
+			backend:   "resourcemanager",
+			want: "SearchFolders query: state:ACTIVE AND parent:folders/123\n" +
+				"Page size: server default, no limit\n",
+		},
+		"parents are listed": {
+			parents:   []string{"folders/1", "organizations/2"},
+			fetchOpts: &folders.FetchOptions{},
+			backend:   "resourcemanager",
+			want: "ListFolders parent: folders/1\n" +
+				"ListFolders parent: organizations/2\n" +
+				"Page size: server default, no limit\n",
+		},
+		"asset backend": {
+			parents:   []string{"organizations/2"},
+			fetchOpts: &folders.FetchOptions{Parent: "organizations/2"},
+			backend:   "asset",
+			want: "SearchAllResources scope: organizations/2, query: " +
+				folders.AssetChildrenQuery("organizations/2") + "\n" +
+				"Page size: server default, no limit\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			cmd.ExplainQuery(&buf, tt.parents, tt.fetchOpts, tt.stream, tt.backend, tt.filter)
+
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/andreygrechin/gcphelper/internal/progress"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+)
+
+// SetEnableGRPCDebug replaces the function --debug-grpc calls to install gRPC's verbose logger and
+// returns a function restoring the original.
+func SetEnableGRPCDebug(enable func(io.Writer) func()) func() {
+	original := enableGRPCDebug
+	enableGRPCDebug = enable
+
+	return func() { enableGRPCDebug = original }
+}
+
+// SetSpinnerFactory replaces how the services create their progress spinners and returns a function
+// restoring the original.
+func SetSpinnerFactory(factory func(suffix string) progress.Spinner) func() {
+	original := newSpinner
+	newSpinner = factory
+
+	return func() { newSpinner = original }
+}
+
+// LookupResourceType returns the descriptor the commands registered for resourceType.
+func LookupResourceType(resourceType string) (output.ResourceDescriptor, error) {
+	return lookupResourceType(resourceType)
+}
@@ -4,12 +4,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os"
+	"io"
+	"strings"
+	"sync/atomic"
+	"time"
 
+	"github.com/andreygrechin/gcphelper/internal/cleanup"
+	"github.com/andreygrechin/gcphelper/internal/durationx"
 	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/andreygrechin/gcphelper/internal/progress"
+	"github.com/andreygrechin/gcphelper/internal/reqmeta"
 	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/organizations"
 	"github.com/andreygrechin/gcphelper/pkg/output"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"google.golang.org/api/option"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -17,105 +27,1127 @@ import (
 // ErrMutuallyExclusiveFlags is returned when both parent flags are specified.
 var ErrMutuallyExclusiveFlags = errors.New("cannot specify both --parent-folder and --parent-organization")
 
+// ErrInvalidScope is returned when an unknown --scope value is specified.
+var ErrInvalidScope = errors.New("invalid scope")
+
+// ErrScopeWithParent is returned when --scope is combined with a parent flag.
+var ErrScopeWithParent = errors.New(
+	"cannot combine --scope with --parent-folder, --parent-organization or --parent-organization-name")
+
+// ErrStreamWithAggregation is returned when --stream is combined with flags that need the full result set.
+var ErrStreamWithAggregation = errors.New("cannot combine --stream with --scope or --annotate-hierarchy")
+
+// ErrCreatedWithinWithCreatedAfter is returned when both --created-within and --created-after are specified.
+var ErrCreatedWithinWithCreatedAfter = errors.New("cannot combine --created-within with --created-after")
+
+// ErrStreamWithMultipleParents is returned when --stream is combined with more than one parent.
+var ErrStreamWithMultipleParents = errors.New("cannot combine --stream with multiple parents")
+
+// ErrStreamWithSort is returned when --stream is combined with --sort-by, which needs the full result set.
+var ErrStreamWithSort = errors.New("cannot combine --stream with --sort-by")
+
+// ErrStreamWithCountBy is returned when --stream is combined with --count-by, which needs the full result set.
+var ErrStreamWithCountBy = errors.New("cannot combine --stream with --count-by")
+
+// ErrParentsWithListingFlags is returned when --parents is combined with flags that shape the folder listing
+// the parents replace.
+var ErrParentsWithListingFlags = errors.New(
+	"cannot combine --parents with --stream, --interactive, --state-file, --count-by, --columns, --fields-file, " +
+		"--sort-by or --group-by")
+
+// ErrStreamWithClipboard is returned when --stream is combined with --clipboard.
+var ErrStreamWithClipboard = errors.New("cannot combine --stream with --clipboard")
+
+// ErrIDPrefixRequiresIDFormat is returned when --id-prefix is used with an output format other than id.
+var ErrIDPrefixRequiresIDFormat = errors.New("--id-prefix requires --format id")
+
+// ErrQueryWithParent is returned when --query is combined with a parent flag, whose listings do not search.
+var ErrQueryWithParent = errors.New(
+	"cannot combine --query with --parent-folder, --parent-organization or --parent-organization-name")
+
+// ErrParentOrganizationNameWithParent is returned when --parent-organization-name is combined with another
+// parent flag.
+var ErrParentOrganizationNameWithParent = errors.New(
+	"cannot combine --parent-organization-name with --parent-folder or --parent-organization")
+
+// ErrOrganizationNotFound is returned when no accessible organization has the requested display name.
+var ErrOrganizationNotFound = errors.New("no accessible organization has this display name")
+
+// ErrAmbiguousOrganization is returned when several accessible organizations share the requested display name.
+var ErrAmbiguousOrganization = errors.New("several accessible organizations have this display name")
+
+// ErrOrganizationDomainNotFound is returned when no accessible organization has the requested domain.
+var ErrOrganizationDomainNotFound = errors.New("no accessible organization has this domain")
+
+// ErrAmbiguousOrganizationDomain is returned when several accessible organizations match the requested domain.
+var ErrAmbiguousOrganizationDomain = errors.New("several accessible organizations have this domain")
+
+// ErrFromFileWithAPIFlags is returned when --from-file is combined with flags that only work against the API.
+var ErrFromFileWithAPIFlags = errors.New(
+	"cannot combine --from-file with --stream, --scope, --query, --parent-organization-name or a domain in " +
+		"--parent-organization")
+
+// ErrFromFileWithRawJSON is returned when --from-file is combined with --format rawjson, since exported
+// folders do not keep the API responses.
+var ErrFromFileWithRawJSON = errors.New("cannot combine --from-file with --format rawjson")
+
+// ErrInvalidBackend is returned when an unknown --backend value is specified.
+var ErrInvalidBackend = errors.New("invalid backend")
+
+// ErrAssetBackendRequiresParent is returned when the asset backend is used without a parent, since Cloud Asset
+// Inventory searches within one organization or folder.
+var ErrAssetBackendRequiresParent = errors.New(
+	"--backend asset requires --parent-folder, --parent-organization or --parent-organization-name")
+
+// ErrAssetBackendWithFlags is returned when the asset backend is combined with flags that only work with the
+// Resource Manager API.
+var ErrAssetBackendWithFlags = errors.New(
+	"cannot combine --backend asset with --scope, --query, --from-file, --endpoint or --endpoint-region")
+
+// ErrAssetBackendWithClientFlags is returned when the asset backend is combined with flags that configure only the
+// Resource Manager API clients, which the asset backend's REST client would silently ignore.
+var ErrAssetBackendWithClientFlags = errors.New(
+	"cannot combine --backend asset with --proxy, --qps, --max-retries or --trace")
+
+// resourceManagerClientFlags are the global flags that configure the Resource Manager API clients only.
+var resourceManagerClientFlags = []string{"proxy", "qps", "max-retries", "trace"}
+
+// Backends selected with --backend.
+const (
+	backendResourceManager = "resourcemanager"
+	backendAsset           = "asset"
+)
+
+// progressInterval is the least time between two --progress-json progress events.
+const progressInterval = time.Second
+
+// scopeAll lists every accessible folder and annotates whether each parent is visible to the caller.
+const scopeAll = "all"
+
+// FolderGetter retrieves a single folder by its resource name.
+type FolderGetter interface {
+	GetFolder(ctx context.Context, name string) (*folders.Folder, error)
+}
+
+// OrganizationGetter retrieves a single organization by its resource name.
+type OrganizationGetter interface {
+	GetOrganization(ctx context.Context, name string) (*organizations.Organization, error)
+}
+
+// OrganizationSearcher searches the organizations accessible to the caller.
+type OrganizationSearcher interface {
+	SearchOrganizations(
+		ctx context.Context,
+		opts *organizations.OrgFetchOptions,
+	) ([]*organizations.Organization, error)
+}
+
+// foldersOptions holds the flag values of the folders command.
+type foldersOptions struct {
+	outputFlags
+	clientFlags
+	parentFolder       string
+	parentOrganization string
+	parentOrgName      string
+	scope              string
+	annotateHierarchy  bool
+	query              string
+	querySet           bool
+	clientFlagsSet     bool
+	selectParentType   bool
+	stream             bool
+	createdAfter       string
+	updatedAfter       string
+	createdWithin      string
+	olderThan          string
+	idPrefix           string
+	continueOnError    bool
+	interactive        bool
+	fromFile           string
+	backend            string
+	stateFile          string
+	progressJSON       bool
+	explainQuery       bool
+	parentsOnly        bool
+	useGCloudConfig    bool
+	stdin              io.Reader
+}
+
+// foldersExample is the example section of the folders command help.
+const foldersExample = `  # List all accessible folders
+  gcphelper folders
+
+  # List folders from an organization given by its display name
+  gcphelper folders --parent-organization-name "Acme Corp"
+
+  # List folders from an organization given by its primary domain
+  gcphelper folders --parent-organization example.com
+
+  # List folders under several parents, reporting failed parents at the end
+  gcphelper folders --parent-folder 111,222,333 --continue-on-error
+
+  # List only folder IDs for scripting
+  gcphelper --format id folders
+
+  # Pipe folder IDs to other commands
+  gcphelper -f id folders | xargs -I {} gcloud resource-manager folders describe {}
+
+  # List folders with verbose output
+  gcphelper --verbose folders
+
+  # List everything you can see and whether each parent is accessible
+  gcphelper --verbose folders --scope all
+
+  # Show each folder's depth in the hierarchy
+  gcphelper folders --parent-organization 123456789 --annotate-hierarchy
+
+  # Show whether each folder's parent is an organization or a folder
+  gcphelper folders --select-parent-type
+
+  # List folders changed since a given date for an incremental sync
+  gcphelper folders --updated-after 2024-01-01
+
+  # List folders created in the last 30 days
+  gcphelper folders --created-within 30d
+
+  # Print only folder IDs starting with 12, e.g. to shard work across jobs
+  gcphelper -f id folders --id-prefix 12
+
+  # Search server-side with the SearchFolders query language
+  gcphelper folders --query 'displayName:prod*'
+
+  # Search Cloud Asset Inventory instead of the Resource Manager API
+  gcphelper folders --backend asset --parent-organization 123456789
+
+  # Write folders as they are fetched instead of after the full listing
+  gcphelper --format jsonl folders --stream
+
+  # Export only the folders that are new or changed since the previous run
+  gcphelper --format jsonl folders --parent-organization 123456789 --state-file folder-etags.json
+
+  # Export the hierarchy once, then filter it offline without API calls
+  gcphelper --format json --output folders.json folders
+  gcphelper folders --from-file folders.json --parent-folder 987654321 --filter 'displayName~prod'`
+
 // NewFoldersCommand creates and returns the folders command.
 func NewFoldersCommand(log logger.Logger) *cobra.Command {
-	var (
-		parentFolder       string
-		parentOrganization string
-	)
+	var opts foldersOptions
 
 	cmd := &cobra.Command{
 		Use:     "folders",
 		Aliases: []string{"folder"},
 		Short:   "List Google Cloud folders",
+		Annotations: withExampleFlags(requiresIAM(
+			[]string{"roles/resourcemanager.folderViewer", "roles/resourcemanager.organizationViewer"},
+			"resourcemanager.folders.get", "resourcemanager.folders.list", "resourcemanager.organizations.get",
+		), "format", "parent-organization", "parent-folder", "filter"),
 		Long: `List Google Cloud folders using the SearchFolders API to discover all accessible folders.
 
 This command uses the SearchFolders API which efficiently finds all folders you have
 access to regardless of organizational hierarchy. This can discover folders even
 when you don't have permissions on intermediate parent resources.
 
-You can filter results by specifying a parent folder or organization.
+You can filter results by specifying a parent folder or organization.`,
+		Example: foldersExample,
+		RunE: func(command *cobra.Command, _ []string) error {
+			opts.querySet = command.Flags().Changed("query")
+			opts.clientFlagsSet = anyFlagChanged(command.Flags(), resourceManagerClientFlags...)
+			opts.stdin = command.InOrStdin()
+			opts.outputFlags = globalOutputFlags()
+			opts.clientFlags = globalClientFlags()
+			// machine-readable progress replaces the spinner
+			opts.noSpinner = opts.noSpinner || opts.progressJSON
 
-Examples:
-  # List all accessible folders
-  gcphelper folders
+			return runFoldersCommand(command.Context(), command.OutOrStdout(), command.ErrOrStderr(), opts, log)
+		},
+	}
+
+	registerFoldersScopeFlags(cmd.Flags(), &opts)
+	registerFoldersListingFlags(cmd.Flags(), &opts)
 
-  # List folders from a specific organization
-  gcphelper folders --parent-organization 123456789
+	cmd.AddCommand(newFoldersGraphCommand(log))
+	cmd.AddCommand(newFoldersDescribeCommand(log))
+	cmd.AddCommand(newFoldersResolveCommand(log))
+	cmd.AddCommand(newFoldersStaleCommand(log))
 
-  # List folders under a specific parent folder
-  gcphelper folders --parent-folder 987654321
+	return cmd
+}
 
-  # List folders in JSON format
-  gcphelper --format json folders
+// registerFoldersScopeFlags registers the folders command flags choosing the folders to list.
+func registerFoldersScopeFlags(flags *pflag.FlagSet, opts *foldersOptions) {
+	flags.StringVarP(&opts.parentFolder, "parent-folder", "p", "",
+		"Parent folder ID to filter folders by; separate multiple IDs with commas")
+	flags.StringVarP(&opts.parentOrganization, "parent-organization", "o", "",
+		"Parent organization ID or primary domain, e.g. example.com, to filter folders by; separate multiple with commas")
+	setFlagExample(flags, "parent-folder", "987654321")
+	setFlagExample(flags, "parent-organization", "123456789")
+	flags.StringVar(&opts.parentOrgName, "parent-organization-name", "",
+		"Display name of the parent organization to filter folders by, resolved to its ID")
+	flags.BoolVar(&opts.continueOnError, "continue-on-error", false,
+		"With multiple parents, list the remaining parents when one fails and report all failures at the end")
+	flags.StringVar(&opts.scope, "scope", "",
+		"Discovery scope; 'all' lists every accessible folder and, with --verbose, whether its parent is accessible")
+	flags.StringVar(&opts.query, "query", "",
+		"Raw SearchFolders query clauses AND-combined with the default 'state:ACTIVE' query, e.g. 'displayName:prod*'")
+	flags.BoolVar(&opts.useGCloudConfig, "use-gcloud-config", false,
+		"Without a parent flag, list the folders of the organization of the gcloud CLI's default project")
+}
 
-  # List only folder IDs for scripting
-  gcphelper --format id folders
+// registerFoldersListingFlags registers the folders command flags shaping how the folders are fetched and
+// listed.
+func registerFoldersListingFlags(flags *pflag.FlagSet, opts *foldersOptions) {
+	flags.BoolVar(&opts.annotateHierarchy, "annotate-hierarchy", false,
+		"Add a Depth column with each folder's depth below the highest listed ancestor; unlisted parents give depth 0")
+	flags.BoolVar(&opts.selectParentType, "select-parent-type", false,
+		"Add a Parent Type column telling whether each folder's parent is an organization or a folder")
+	flags.StringVar(&opts.createdAfter, "created-after", "",
+		"Only list folders created after this RFC3339 timestamp or YYYY-MM-DD date")
+	flags.StringVar(&opts.updatedAfter, "updated-after", "",
+		"Only list folders updated after this RFC3339 timestamp or YYYY-MM-DD date")
+	flags.StringVar(&opts.createdWithin, "created-within", "",
+		"Only list folders created within this duration of now, e.g. 720h, 30d or 2w")
+	flags.StringVar(&opts.olderThan, "older-than", "",
+		"Only list folders created longer ago than this duration, e.g. 30d or 2w")
+	flags.StringVar(&opts.idPrefix, "id-prefix", "",
+		"With --format id, only print folder IDs starting with this prefix (e.g. for sharding)")
+	flags.BoolVar(&opts.interactive, "interactive", false,
+		"Pick one folder with a fuzzy search prompt and print its ID; requires a terminal")
+	flags.BoolVar(&opts.stream, "stream", false,
+		"Write folders as they are fetched instead of buffering the full listing (table output is still buffered)")
+	flags.StringVar(&opts.fromFile, "from-file", "",
+		"Read folders from a file exported with --format json or jsonl instead of calling the API")
+	flags.StringVar(&opts.backend, "backend", backendResourceManager,
+		"API that lists folders: resourcemanager, or asset to search Cloud Asset Inventory under a parent")
+	flags.StringVar(&opts.stateFile, "state-file", "",
+		"Only list folders that are new or whose etag changed since the etags recorded in this file, then update it")
+	flags.BoolVar(&opts.explainQuery, "explain-query", false,
+		"Write the SearchFolders query, or the parents listed, and the page size to stderr before fetching")
+	flags.BoolVar(&opts.progressJSON, "progress-json", false,
+		"Write JSON progress events to stderr while fetching, and a done event with the total, instead of the spinner")
+	flags.BoolVar(&opts.parentsOnly, "parents", false,
+		"Output the distinct parents of the listed folders, sorted by name, instead of the folders")
+}
 
-  # Pipe folder IDs to other commands
-  gcphelper -f id folders | xargs -I {} gcloud resource-manager folders describe {}
+// validate checks the folders command flags for invalid values and combinations.
+func (o foldersOptions) validate() error {
+	if o.parentFolder != "" && o.parentOrganization != "" {
+		return ErrMutuallyExclusiveFlags
+	}
 
-  # List folders with verbose output
-  gcphelper --verbose folders`,
-		RunE: func(_ *cobra.Command, _ []string) error {
-			return runFoldersCommand(parentFolder, parentOrganization, globalFormat, globalVerbose, log)
-		},
+	if o.scope != "" && o.scope != scopeAll {
+		return fmt.Errorf("%w: %s (supported: %s)", ErrInvalidScope, o.scope, scopeAll)
 	}
 
-	cmd.Flags().StringVarP(&parentFolder, "parent-folder", "p", "", "Parent folder ID to filter folders by")
-	cmd.Flags().StringVarP(&parentOrganization, "parent-organization", "o", "",
-		"Parent organization ID to filter folders by")
+	if o.parentOrgName != "" && (o.parentFolder != "" || o.parentOrganization != "") {
+		return ErrParentOrganizationNameWithParent
+	}
 
-	return cmd
+	if o.scope != "" && (o.parentFolder != "" || o.parentOrganization != "" || o.parentOrgName != "") {
+		return ErrScopeWithParent
+	}
+
+	if err := o.validateListing(); err != nil {
+		return err
+	}
+
+	if o.querySet {
+		if len(o.parents()) > 0 || o.parentOrgName != "" {
+			return ErrQueryWithParent
+		}
+		if err := folders.ValidateQuery(o.query); err != nil {
+			return err
+		}
+	}
+
+	if o.fromFile != "" && (o.stream || o.scope != "" || o.querySet || o.parentOrgName != "" || o.hasDomainParent()) {
+		return ErrFromFileWithAPIFlags
+	}
+	if o.fromFile != "" && o.format == string(output.FormatRawJSON) {
+		return ErrFromFileWithRawJSON
+	}
+
+	if err := o.validateBackend(); err != nil {
+		return err
+	}
+
+	if o.stateFile != "" && (o.stream || o.interactive || o.backend == backendAsset) {
+		return ErrStateFileWithFlags
+	}
+
+	if o.idPrefix != "" && o.format != string(output.FormatID) {
+		return ErrIDPrefixRequiresIDFormat
+	}
+
+	if _, err := o.timeFilter(time.Now()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateListing checks the flags that change how the folders are listed, such as --stream and --parents,
+// against the flags they cannot be combined with.
+func (o foldersOptions) validateListing() error {
+	if o.stream && (o.scope != "" || o.annotateHierarchy) {
+		return ErrStreamWithAggregation
+	}
+
+	if o.stream && len(o.parents()) > 1 {
+		return ErrStreamWithMultipleParents
+	}
+
+	if o.stream && o.clipboard {
+		return ErrStreamWithClipboard
+	}
+
+	if o.stream && o.sortBy != "" {
+		return ErrStreamWithSort
+	}
+
+	if o.stream && o.countBy != "" {
+		return ErrStreamWithCountBy
+	}
+
+	if o.stream && o.interactive {
+		return ErrInteractiveWithStream
+	}
+
+	if o.parentsOnly && (o.stream || o.interactive || o.stateFile != "" || o.countBy != "" || o.columns != "" ||
+		o.fieldsFile != "" || o.sortBy != "" || o.groupBy != "") {
+		return ErrParentsWithListingFlags
+	}
+
+	return nil
+}
+
+// validateBackend checks the --backend value and the flags the asset backend cannot be combined with.
+func (o foldersOptions) validateBackend() error {
+	if o.backend == backendResourceManager {
+		return nil
+	}
+	if o.backend != backendAsset {
+		return fmt.Errorf("%w: %s (supported: %s, %s)", ErrInvalidBackend, o.backend, backendResourceManager,
+			backendAsset)
+	}
+
+	if o.scope != "" || o.querySet || o.fromFile != "" || o.endpoint != "" || o.endpointRegion != "" {
+		return ErrAssetBackendWithFlags
+	}
+	if o.clientFlagsSet {
+		return ErrAssetBackendWithClientFlags
+	}
+	if len(o.parents()) == 0 && o.parentOrgName == "" {
+		return ErrAssetBackendRequiresParent
+	}
+
+	return nil
+}
+
+// hasDomainParent reports whether --parent-organization names an organization by its domain, which is
+// resolved to its ID with an API call.
+func (o foldersOptions) hasDomainParent() bool {
+	if o.parentOrganization == "" {
+		return false
+	}
+	for _, parent := range o.parents() {
+		if IsOrganizationDomain(strings.TrimPrefix(parent, "organizations/")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parents returns the resource names of the requested parent folders or organizations.
+func (o foldersOptions) parents() []string {
+	prefix, ids := "folders/", o.parentFolder
+	if o.parentOrganization != "" {
+		prefix, ids = "organizations/", o.parentOrganization
+	}
+
+	var parents []string
+	for _, id := range strings.Split(ids, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			parents = append(parents, prefix+id)
+		}
+	}
+
+	return parents
+}
+
+// outputOptions validates the output flags and returns the rendering options for the folders command.
+func (o foldersOptions) outputOptions(
+	stdout io.Writer,
+	timeFilter output.TimeFilter,
+	parent string,
+) (OutputOptions, error) {
+	renderOpts, err := o.renderOptions(stdout, output.ResourceTypeFolders)
+	if err != nil {
+		return OutputOptions{}, err
+	}
+	renderOpts.TimeFilter = timeFilter
+	renderOpts.Parent = parent
+	renderOpts.IDPrefix = o.idPrefix
+	renderOpts.Parents = o.parentsOnly
+	if o.selectParentType {
+		renderOpts.Columns = append(renderOpts.Columns, output.ParentTypeColumn())
+	}
+
+	return renderOpts, nil
+}
+
+// timeFilter builds the time filter from the absolute --created-after and --updated-after flags
+// and the --created-within and --older-than durations, which are measured back from now.
+func (o foldersOptions) timeFilter(now time.Time) (output.TimeFilter, error) {
+	if o.createdWithin != "" && o.createdAfter != "" {
+		return output.TimeFilter{}, ErrCreatedWithinWithCreatedAfter
+	}
+
+	createdAfter, err := output.ParseTimeThreshold(o.createdAfter)
+	if err != nil {
+		return output.TimeFilter{}, fmt.Errorf("invalid --created-after value: %w", err)
+	}
+
+	updatedAfter, err := output.ParseTimeThreshold(o.updatedAfter)
+	if err != nil {
+		return output.TimeFilter{}, fmt.Errorf("invalid --updated-after value: %w", err)
+	}
+
+	if o.createdWithin != "" {
+		within, err := durationx.Parse(o.createdWithin)
+		if err != nil {
+			return output.TimeFilter{}, fmt.Errorf("invalid --created-within value: %w", err)
+		}
+		createdAfter = now.Add(-within)
+	}
+
+	var createdBefore time.Time
+	if o.olderThan != "" {
+		age, err := durationx.Parse(o.olderThan)
+		if err != nil {
+			return output.TimeFilter{}, fmt.Errorf("invalid --older-than value: %w", err)
+		}
+		createdBefore = now.Add(-age)
+	}
+
+	return output.TimeFilter{
+		CreatedAfter:  createdAfter,
+		CreatedBefore: createdBefore,
+		UpdatedAfter:  updatedAfter,
+	}, nil
 }
 
-func runFoldersCommand(parentFolder, parentOrganization, format string, verbose bool, log logger.Logger) error {
-	ctx := context.Background()
+func runFoldersCommand(ctx context.Context, stdout, stderr io.Writer, opts foldersOptions, log logger.Logger) error {
+	// validate flags before any API client is created
+	renderOpts, state, err := opts.prepare(stdout)
+	if err != nil {
+		return err
+	}
+	if err := validateOnly(); err != nil {
+		return err
+	}
+
+	// render a previously exported listing without calling the API
+	if opts.fromFile != "" {
+		return runFoldersFromFile(ctx, stdout, stderr, opts, opts.parents(), state, renderOpts)
+	}
+
+	return runFoldersFromAPI(ctx, stdout, stderr, opts, state, renderOpts, log)
+}
+
+// runFoldersFromAPI fetches the folders of the requested parents, resolving parents given by name or
+// domain first, and renders them, or streams them with --stream.
+func runFoldersFromAPI(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	opts foldersOptions,
+	state map[string]string,
+	renderOpts OutputOptions,
+	log logger.Logger,
+) error {
+	clientOpts, err := opts.clientOptions()
+	if err != nil {
+		return err
+	}
+	parents, err := resolveNamedParents(ctx, opts, log, clientOpts, opts.parents())
+	if err != nil {
+		return err
+	}
 
 	// create folders service
-	service, err := folders.NewServiceFromContextWithLogger(ctx, log)
+	service, err := newFoldersService(ctx, opts, log, clientOpts)
 	if err != nil {
 		return fmt.Errorf("failed to create folders service: %w", err)
 	}
-	defer func() {
-		if closeErr := service.Close(); closeErr != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to close service: %v\n", closeErr)
-		}
-	}()
+	setSpinner(service, !opts.noSpinner)
+	defer cleanup.CloseAndLog(log, "failed to close service", service)
 
-	// validate mutually exclusive flags
-	if parentFolder != "" && parentOrganization != "" {
-		return ErrMutuallyExclusiveFlags
+	if parents, err = gcloudScopedParents(ctx, stderr, opts, service, log, clientOpts, parents); err != nil {
+		return err
 	}
+	renderOpts.Parent = strings.Join(parents, ", ")
 
-	// configure fetch options
-	opts := folders.NewFetchOptions()
-	if parentFolder != "" {
-		opts.Parent = "folders/" + parentFolder
-	} else if parentOrganization != "" {
-		opts.Parent = "organizations/" + parentOrganization
+	fetchOpts, fetched := opts.fetchOptions(stderr, parents, renderOpts)
+	if opts.stream {
+		if err := streamFolders(ctx, stdout, stderr, service, fetchOpts, renderOpts); err != nil {
+			return err
+		}
+		fetched()
+
+		return nil
 	}
 
 	// fetch folders using SearchFolders API
-	folderList, err := service.ListFolders(ctx, opts)
+	folderList, partialErr, err := fetchFolders(ctx, service, fetchOpts, parents, opts.continueOnError)
 	if err != nil {
-		return HandleFoldersError(err, opts.Parent)
+		return HandleFoldersError(err, renderOpts.Parent)
 	}
+	fetched()
+
+	// let the user pick a single folder instead of rendering the list
+	if opts.interactive {
+		return SelectFolder(ctx, opts.stdin, stderr, stdout, folderList, renderOpts)
+	}
+
+	columns, err := listingColumns(ctx, opts, service, folderList, log, clientOpts)
+	if err != nil {
+		return err
+	}
+	renderOpts.Columns = append(renderOpts.Columns, columns...)
 
 	// output results
-	return OutputFolders(folderList, format, verbose)
+	if err := outputFolderList(ctx, stdout, stderr, folderList, opts, state, renderOpts); err != nil {
+		return err
+	}
+
+	return reportPartialFailures(stderr, partialErr)
+}
+
+// prepare validates the flags and loads the --state-file etags before any API client is created, and
+// returns the options to render the folders with.
+func (o foldersOptions) prepare(stdout io.Writer) (OutputOptions, map[string]string, error) {
+	if err := o.validate(); err != nil {
+		return OutputOptions{}, nil, err
+	}
+	timeFilter, err := o.timeFilter(time.Now())
+	if err != nil {
+		return OutputOptions{}, nil, err
+	}
+	renderOpts, err := o.outputOptions(stdout, timeFilter, strings.Join(o.parents(), ", "))
+	if err != nil {
+		return OutputOptions{}, nil, err
+	}
+	if o.interactive && !isTerminal(stdout) {
+		return OutputOptions{}, nil, ErrInteractiveRequiresTerminal
+	}
+	if o.stateFile == "" {
+		return renderOpts, nil, nil
+	}
+
+	state, err := LoadEtagState(o.stateFile)
+	if err != nil {
+		return OutputOptions{}, nil, err
+	}
+
+	return renderOpts, state, nil
+}
+
+// fetchOptions returns the options to fetch the folders of parents with, writing the query to stderr
+// with --explain-query, and a function to call once the folders are fetched, which writes the done event
+// of --progress-json.
+func (o foldersOptions) fetchOptions(
+	stderr io.Writer,
+	parents []string,
+	renderOpts OutputOptions,
+) (*folders.FetchOptions, func()) {
+	fetchOpts := folders.NewFetchOptions()
+	fetchOpts.RequestReason = o.requestReason
+	fetchOpts.Query = o.query
+	fetchOpts.Raw = renderOpts.Format == string(output.FormatRawJSON)
+	if len(parents) == 1 {
+		fetchOpts.Parent = parents[0]
+	}
+	if o.explainQuery {
+		ExplainQuery(stderr, parents, fetchOpts, o.stream, o.backend, renderOpts.Filter)
+	}
+	if !o.progressJSON {
+		return fetchOpts, func() {}
+	}
+
+	reporter := progress.NewJSONReporter(stderr, progressInterval)
+	fetchOpts.Progress = reporter.Report
+
+	return fetchOpts, reporter.Done
+}
+
+// resolveNamedParents returns parents with the organization named by --parent-organization-name, or the
+// organizations given by their domain in --parent-organization, replaced by their resource names.
+func resolveNamedParents(
+	ctx context.Context,
+	opts foldersOptions,
+	log logger.Logger,
+	clientOpts []option.ClientOption,
+	parents []string,
+) ([]string, error) {
+	if opts.parentOrgName != "" {
+		org, err := resolveParentOrganization(ctx, opts, log, clientOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{org.Name}, nil
+	}
+	if opts.hasDomainParent() {
+		return resolveParentDomains(ctx, opts, log, clientOpts, parents)
+	}
+
+	return parents, nil
 }
 
-func OutputFolders(folderList []*folders.Folder, format string, verbose bool) error {
-	formatter := output.NewFormatterWithType(os.Stdout, verbose, "folders")
-	resources := output.FoldersToResources(folderList)
-	headers := output.FolderHeaders()
+// listingColumns returns the computed columns added to a folder listing: whether each parent is
+// accessible, for the all-scope audit view with --verbose, and the depths with --annotate-hierarchy.
+func listingColumns(
+	ctx context.Context,
+	opts foldersOptions,
+	service *folders.Service,
+	folderList []*folders.Folder,
+	log logger.Logger,
+	clientOpts []option.ClientOption,
+) ([]output.Column, error) {
+	var columns []output.Column
+	if opts.scope == scopeAll && opts.verbose {
+		// folder lookups are shared through a resolver seeded with the listed folders
+		lookupCtx := reqmeta.WithRequestReason(ctx, opts.requestReason)
+		resolver := folders.NewAncestryResolver(service, folderList...)
+		column, err := parentAccessibleColumn(lookupCtx, folderList, resolver, log, clientOpts)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+	if opts.annotateHierarchy {
+		columns = append(columns, output.DepthColumn(output.ComputeDepths(folderList)))
+	}
+
+	return columns, nil
+}
+
+// reportPartialFailures writes the parents that failed under --continue-on-error, after the successful
+// results, and returns their error, or nil when every parent was listed.
+func reportPartialFailures(stderr io.Writer, partialErr *folders.PartialError) error {
+	if partialErr == nil {
+		return nil
+	}
+	PrintPartialFailures(stderr, partialErr)
+
+	return partialErr
+}
+
+// newFoldersService creates the folders service for the --backend flag. The asset backend uses the Cloud Asset
+// REST API, which takes the request reason as a client option; the gRPC client options do not apply to it.
+func newFoldersService(
+	ctx context.Context,
+	opts foldersOptions,
+	log logger.Logger,
+	clientOpts []option.ClientOption,
+) (*folders.Service, error) {
+	if opts.backend != backendAsset {
+		return folders.NewServiceFromContextWithLogger(ctx, log, clientOpts...)
+	}
+
+	var assetOpts []option.ClientOption
+	if opts.requestReason != "" {
+		assetOpts = append(assetOpts, option.WithRequestReason(opts.requestReason))
+	}
+
+	return folders.NewAssetServiceFromContextWithLogger(ctx, log, assetOpts...)
+}
+
+// runFoldersFromFile renders the folders loaded from the --from-file file, keeping only the direct
+// children of the requested parents as a parent listing would. Depths are computed from the whole file.
+func runFoldersFromFile(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	opts foldersOptions,
+	parents []string,
+	state map[string]string,
+	renderOpts OutputOptions,
+) error {
+	all, err := folders.LoadFoldersFile(opts.fromFile)
+	if err != nil {
+		return err
+	}
+
+	folderList := all
+	if len(parents) > 0 {
+		folderList = folders.ChildrenOf(all, parents...)
+	}
+
+	if opts.interactive {
+		return SelectFolder(ctx, opts.stdin, stderr, stdout, folderList, renderOpts)
+	}
+	if opts.annotateHierarchy {
+		renderOpts.Columns = append(renderOpts.Columns, output.DepthColumn(output.ComputeDepths(all)))
+	}
+
+	return outputFolderList(ctx, stdout, stderr, folderList, opts, state, renderOpts)
+}
+
+// outputFolderList renders the listed folders, or with --state-file only those that changed since the
+// recorded state.
+func outputFolderList(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	folderList []*folders.Folder,
+	opts foldersOptions,
+	state map[string]string,
+	renderOpts OutputOptions,
+) error {
+	if opts.stateFile == "" {
+		return OutputFolders(ctx, stdout, stderr, folderList, renderOpts)
+	}
+
+	return outputFolderChanges(ctx, stdout, stderr, folderList, opts.stateFile, state, renderOpts)
+}
+
+// fetchFolders searches all accessible folders with SearchFolders when no parent is given, and
+// otherwise lists the direct children of each parent in turn with the ListFolders API. With
+// continueOnError, a partial failure is returned separately from the fetched folders.
+func fetchFolders(
+	ctx context.Context,
+	service *folders.Service,
+	fetchOpts *folders.FetchOptions,
+	parents []string,
+	continueOnError bool,
+) ([]*folders.Folder, *folders.PartialError, error) {
+	switch len(parents) {
+	case 0:
+		folderList, err := service.ListFolders(ctx, fetchOpts)
+
+		return folderList, nil, err
+	case 1:
+		folderList, err := service.ListFoldersFromParent(ctx, parents[0], fetchOpts)
+
+		return folderList, nil, err
+	}
+
+	folderList, err := service.ListFoldersFromParents(ctx, parents, fetchOpts, continueOnError)
+	var partialErr *folders.PartialError
+	if errors.As(err, &partialErr) {
+		return folderList, partialErr, nil
+	}
+
+	return folderList, nil, err
+}
+
+// PrintPartialFailures writes a summary of the parents whose folders could not be listed.
+func PrintPartialFailures(w io.Writer, partialErr *folders.PartialError) {
+	fmt.Fprintf(w, "Errors: %s:\n", partialErr.Error())
+	for _, failure := range partialErr.Failures {
+		fmt.Fprintf(w, "  %s: %v\n", failure.Parent, failure.Err)
+	}
+}
+
+// OutputFolders renders folders to stdout and status messages to stderr. Cancelling ctx stops rendering
+// that waits, such as paging.
+func OutputFolders(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	folderList []*folders.Folder,
+	opts OutputOptions,
+) error {
+	return renderResources(ctx, stdout, stderr, folderList, output.ResourceTypeFolders, opts)
+}
 
-	if err := formatter.Format(resources, output.Format(format), headers); err != nil {
+// streamFolders renders folders as the service delivers them. A fetch error that occurs after output
+// has started is returned once the already written output has been terminated.
+func streamFolders(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	service *folders.Service,
+	fetchOpts *folders.FetchOptions,
+	opts OutputOptions,
+) error {
+	transform, err := newResourceTransform(opts)
+	if err != nil {
+		return fmt.Errorf("failed to filter folders: %w", err)
+	}
+
+	desc, err := lookupResourceType(output.ResourceTypeFolders)
+	if err != nil {
+		return err
+	}
+	selector, headers, err := streamSelection(desc, opts)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	folderCh, errCh := service.StreamFolders(ctx, fetchOpts)
+	resources := make(chan output.Resource)
+	var streamed atomic.Int64
+	go func() {
+		defer close(resources)
+		for folder := range folderCh {
+			if opts.Verbose {
+				warnMalformedID(stderr, folder)
+			}
+			if !transform.keep(folder) {
+				continue
+			}
+			resource := transform.rewrite(folder)
+			if selector != nil {
+				resource = selector.Select(resource)
+			}
+			resource = output.Annotate(resource, opts.Columns...)
+			select {
+			case resources <- resource:
+				streamed.Add(1)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	formatter := newFormatter(stdout, stderr, desc.Name, opts)
+	err = formatter.FormatStream(resources, output.Format(opts.Format), headers)
+	recordResults(int(streamed.Load()), opts.Parent)
+	if err != nil {
 		return fmt.Errorf("failed to format folders output: %w", err)
 	}
 
-	return nil
+	if err := <-errCh; err != nil {
+		return HandleFoldersError(err, fetchOpts.Parent)
+	}
+
+	return writeSummaryLine(stderr, desc.Name, int(streamed.Load()), opts)
+}
+
+// streamSelection returns the selector of the --fields of streamed resources, nil without --fields, and
+// the headers of the stream, which include the computed columns.
+func streamSelection(desc output.ResourceDescriptor, opts OutputOptions) (*output.FieldSelector, []string, error) {
+	headers := desc.Headers
+	var selector *output.FieldSelector
+	if len(opts.Fields) > 0 {
+		var err error
+		if selector, err = output.NewFieldSelector(headers, opts.Fields, opts.fieldColumns(desc)...); err != nil {
+			return nil, nil, fmt.Errorf("failed to select %s fields: %w", desc.Name, err)
+		}
+		headers = selector.Headers()
+	}
+
+	return selector, output.ColumnHeaders(headers, opts.Columns...), nil
+}
+
+// ParentAccessibility reports, for each distinct parent of the given folders, whether the caller
+// can read it. Parents for which the lookup returns PermissionDenied are reported as inaccessible.
+func ParentAccessibility(
+	ctx context.Context,
+	folderList []*folders.Folder,
+	folderGetter FolderGetter,
+	orgGetter OrganizationGetter,
+) (map[string]bool, error) {
+	accessible := make(map[string]bool)
+	for _, folder := range folderList {
+		parent := folder.Parent
+		if _, ok := accessible[parent]; ok {
+			continue
+		}
+
+		var err error
+		switch {
+		case strings.HasPrefix(parent, "folders/"):
+			_, err = folderGetter.GetFolder(ctx, parent)
+		case strings.HasPrefix(parent, "organizations/"):
+			_, err = orgGetter.GetOrganization(ctx, parent)
+		default:
+			accessible[parent] = false
+
+			continue
+		}
+
+		switch {
+		case err == nil:
+			accessible[parent] = true
+		case status.Code(err) == codes.PermissionDenied:
+			accessible[parent] = false
+		default:
+			return nil, fmt.Errorf("failed to check access to parent %s: %w", parent, err)
+		}
+	}
+
+	return accessible, nil
+}
+
+// resolveParentOrganization looks up the organization named by --parent-organization-name.
+func resolveParentOrganization(
+	ctx context.Context,
+	opts foldersOptions,
+	log logger.Logger,
+	clientOpts []option.ClientOption,
+) (*organizations.Organization, error) {
+	orgService, err := organizations.NewServiceFromContextWithLogger(ctx, log, clientOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create organizations service: %w", err)
+	}
+	defer cleanup.CloseAndLog(log, "failed to close service", orgService)
+	setSpinner(orgService, !opts.noSpinner)
+
+	return ResolveOrganizationByName(reqmeta.WithRequestReason(ctx, opts.requestReason), orgService, opts.parentOrgName)
+}
+
+// resolveParentDomains resolves the organizations given by their domain in --parent-organization.
+func resolveParentDomains(
+	ctx context.Context,
+	opts foldersOptions,
+	log logger.Logger,
+	clientOpts []option.ClientOption,
+	parents []string,
+) ([]string, error) {
+	orgService, err := organizations.NewServiceFromContextWithLogger(ctx, log, clientOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create organizations service: %w", err)
+	}
+	defer cleanup.CloseAndLog(log, "failed to close service", orgService)
+	setSpinner(orgService, !opts.noSpinner)
+
+	return ResolveParentDomains(reqmeta.WithRequestReason(ctx, opts.requestReason), orgService, parents)
+}
+
+// IsOrganizationDomain reports whether a --parent-organization value is a domain such as example.com
+// rather than a numeric organization ID.
+func IsOrganizationDomain(value string) bool {
+	return strings.ContainsFunc(value, func(r rune) bool { return r < '0' || r > '9' })
+}
+
+// ResolveParentDomains returns parents with each organization given by its domain, such as
+// "organizations/example.com", replaced by the resource name of the accessible organization with that
+// primary domain. Organizations given by ID are kept as they are without an API call.
+func ResolveParentDomains(ctx context.Context, searcher OrganizationSearcher, parents []string) ([]string, error) {
+	resolved := make([]string, len(parents))
+	for i, parent := range parents {
+		domain, ok := strings.CutPrefix(parent, "organizations/")
+		if !ok || !IsOrganizationDomain(domain) {
+			resolved[i] = parent
+
+			continue
+		}
+
+		org, err := ResolveOrganizationByDomain(ctx, searcher, domain)
+		if err != nil {
+			return nil, err
+		}
+		resolved[i] = org.Name
+	}
+
+	return resolved, nil
+}
+
+// ResolveOrganizationByDomain returns the accessible organization whose primary domain is domain, found
+// with a domain-filtered organization search. The error lists the candidates when more than one
+// organization matches.
+func ResolveOrganizationByDomain(
+	ctx context.Context,
+	searcher OrganizationSearcher,
+	domain string,
+) (*organizations.Organization, error) {
+	orgList, err := searcher.SearchOrganizations(ctx, &organizations.OrgFetchOptions{Domain: domain})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve organization domain %q: %w", domain, err)
+	}
+
+	switch len(orgList) {
+	case 1:
+		return orgList[0], nil
+	case 0:
+		return nil, fmt.Errorf("%w: %q", ErrOrganizationDomainNotFound, domain)
+	default:
+		return nil, fmt.Errorf("%w: %q matches %s; use one of these IDs instead",
+			ErrAmbiguousOrganizationDomain, domain, describeOrganizations(orgList))
+	}
+}
+
+// ResolveOrganizationByName returns the accessible organization whose display name matches name,
+// ignoring case and surrounding spaces. The error lists the candidates when no organization or more
+// than one organization matches.
+func ResolveOrganizationByName(
+	ctx context.Context,
+	searcher OrganizationSearcher,
+	name string,
+) (*organizations.Organization, error) {
+	orgList, err := searcher.SearchOrganizations(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve organization %q: %w", name, err)
+	}
+
+	var matches []*organizations.Organization
+	for _, org := range orgList {
+		if strings.EqualFold(strings.TrimSpace(org.DisplayName), strings.TrimSpace(name)) {
+			matches = append(matches, org)
+		}
+	}
+
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		return nil, fmt.Errorf("%w: %q (accessible organizations: %s)",
+			ErrOrganizationNotFound, name, describeOrganizations(orgList))
+	default:
+		return nil, fmt.Errorf("%w: %q matches %s; use --parent-organization with one of these IDs",
+			ErrAmbiguousOrganization, name, describeOrganizations(matches))
+	}
+}
+
+// describeOrganizations lists organizations as "Display Name (ID)" for error messages.
+func describeOrganizations(orgList []*organizations.Organization) string {
+	if len(orgList) == 0 {
+		return "none"
+	}
+
+	described := make([]string, len(orgList))
+	for i, org := range orgList {
+		described[i] = fmt.Sprintf("%s (%s)", org.DisplayName, org.ID)
+	}
+
+	return strings.Join(described, ", ")
+}
+
+// parentAccessibleColumn builds the "Parent Accessible" column for the given folders.
+func parentAccessibleColumn(
+	ctx context.Context,
+	folderList []*folders.Folder,
+	folderGetter FolderGetter,
+	log logger.Logger,
+	clientOpts []option.ClientOption,
+) (output.Column, error) {
+	orgService, err := organizations.NewServiceFromContextWithLogger(ctx, log, clientOpts...)
+	if err != nil {
+		return output.Column{}, fmt.Errorf("failed to create organizations service: %w", err)
+	}
+	defer cleanup.CloseAndLog(log, "failed to close service", orgService)
+
+	// memoize organization lookups so each organization parent is fetched once
+	accessible, err := ParentAccessibility(ctx, folderList, folderGetter, organizations.NewNameCache(orgService))
+	if err != nil {
+		return output.Column{}, err
+	}
+
+	// index by folder ID since computed columns only see the generic resource
+	byFolder := make(map[string]bool, len(folderList))
+	for _, folder := range folderList {
+		byFolder[folder.ID] = accessible[folder.Parent]
+	}
+
+	return output.Column{
+		Header: "Parent Accessible",
+		Field:  "parent_accessible",
+		Value: func(r output.Resource) interface{} {
+			return byFolder[r.GetID()]
+		},
+	}, nil
 }
 
 // HandleFoldersError provides enhanced error handling with helpful messages.
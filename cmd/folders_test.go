@@ -2,24 +2,41 @@ package cmd_test
 
 import (
 	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"io"
+	"net"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	resourcemanagerpb "cloud.google.com/go/resourcemanager/apiv3/resourcemanagerpb"
 	"github.com/andreygrechin/gcphelper/cmd"
+	"github.com/andreygrechin/gcphelper/internal/durationx"
 	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/andreygrechin/gcphelper/internal/progress"
 	"github.com/andreygrechin/gcphelper/pkg/folders"
+	foldersmocks "github.com/andreygrechin/gcphelper/pkg/folders/mocks"
+	"github.com/andreygrechin/gcphelper/pkg/organizations"
+	orgmocks "github.com/andreygrechin/gcphelper/pkg/organizations/mocks"
+	"github.com/andreygrechin/gcphelper/pkg/output"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 func TestOutputFoldersInvalidFormat(t *testing.T) {
-	err := cmd.OutputFolders(nil, "invalid", false)
-	require.Error(t, err)
+	err := cmd.OutputFolders(t.Context(), io.Discard, io.Discard, nil, cmd.OutputOptions{Format: "invalid"})
+	require.ErrorIs(t, err, output.ErrUnsupportedOutputFormat)
 	assert.Contains(t, err.Error(), "unsupported output format")
 }
 
@@ -105,35 +122,15 @@ func TestOutputFoldersIDFormat(t *testing.T) {
 
 	for name, tc := range testCases {
 		t.Run(name, func(t *testing.T) {
-			// capture stdout and stderr
-			originalStdout := os.Stdout
-			originalStderr := os.Stderr
-			defer func() {
-				os.Stdout = originalStdout
-				os.Stderr = originalStderr
-			}()
-
-			stdoutReader, stdoutWriter, _ := os.Pipe()
-			stderrReader, stderrWriter, _ := os.Pipe()
-			os.Stdout = stdoutWriter
-			os.Stderr = stderrWriter
+			var stdout, stderr bytes.Buffer
 
 			// run the function
-			err := cmd.OutputFolders(tc.folders, "id", tc.verbose)
+			err := cmd.OutputFolders(t.Context(), &stdout, &stderr, tc.folders, cmd.OutputOptions{Format: "id", Verbose: tc.verbose})
 			require.NoError(t, err)
 
-			// close writers and read output
-			_ = stdoutWriter.Close()
-			_ = stderrWriter.Close()
-
-			stdoutBuf := new(bytes.Buffer)
-			stderrBuf := new(bytes.Buffer)
-			_, _ = io.Copy(stdoutBuf, stdoutReader)
-			_, _ = io.Copy(stderrBuf, stderrReader)
-
 			// verify output
-			assert.Equal(t, tc.wantOut, stdoutBuf.String(), "stdout should match expected")
-			assert.Equal(t, tc.wantErr, stderrBuf.String(), "stderr should match expected")
+			assert.Equal(t, tc.wantOut, stdout.String(), "stdout should match expected")
+			assert.Equal(t, tc.wantErr, stderr.String(), "stderr should match expected")
 		})
 	}
 }
@@ -170,3 +167,1467 @@ func TestHandleFoldersError(t *testing.T) {
 		})
 	}
 }
+
+func TestParentAccessibility(t *testing.T) {
+	folderList := []*folders.Folder{
+		{ID: "1", Parent: "organizations/100"},
+		{ID: "2", Parent: "organizations/200"},
+		{ID: "3", Parent: "folders/1"},
+		{ID: "4", Parent: "folders/999"},
+		{ID: "5", Parent: "folders/1"},
+	}
+
+	testCases := map[string]struct {
+		setupMocks func(*foldersmocks.MockFetcher, *orgmocks.MockFetcher)
+		want       map[string]bool
+		wantErr    string
+	}{
+		"mixed accessible and inaccessible parents": {
+			setupMocks: func(f *foldersmocks.MockFetcher, o *orgmocks.MockFetcher) {
+				o.On("GetOrganization", mock.Anything, "organizations/100").
+					Return(&organizations.Organization{ID: "100"}, nil).Once()
+				o.On("GetOrganization", mock.Anything, "organizations/200").
+					Return(nil, status.Error(codes.PermissionDenied, "denied")).Once()
+				f.On("GetFolder", mock.Anything, "folders/1").Return(&folders.Folder{ID: "1"}, nil).Once()
+				f.On("GetFolder", mock.Anything, "folders/999").
+					Return(nil, status.Error(codes.PermissionDenied, "denied")).Once()
+			},
+			want: map[string]bool{
+				"organizations/100": true,
+				"organizations/200": false,
+				"folders/1":         true,
+				"folders/999":       false,
+			},
+		},
+		"unexpected lookup error": {
+			setupMocks: func(f *foldersmocks.MockFetcher, o *orgmocks.MockFetcher) {
+				o.On("GetOrganization", mock.Anything, "organizations/100").Return(nil, errTestNetwork)
+			},
+			wantErr: "failed to check access to parent organizations/100",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			folderFetcher := foldersmocks.NewMockFetcher(t)
+			orgFetcher := orgmocks.NewMockFetcher(t)
+			tc.setupMocks(folderFetcher, orgFetcher)
+
+			folderService := folders.NewServiceWithLogger(folderFetcher, logger.NewNoOpLogger())
+			orgService := organizations.NewServiceWithLogger(orgFetcher, logger.NewNoOpLogger())
+
+			got, err := cmd.ParentAccessibility(t.Context(), folderList, folderService, orgService)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestResolveOrganizationByName(t *testing.T) {
+	orgList := []*organizations.Organization{
+		{ID: "100", Name: "organizations/100", DisplayName: "Acme Corp", State: "ACTIVE"},
+		{ID: "200", Name: "organizations/200", DisplayName: "Globex", State: "ACTIVE"},
+		{ID: "300", Name: "organizations/300", DisplayName: "globex", State: "ACTIVE"},
+		{ID: "400", Name: "organizations/400", DisplayName: "Initech", State: "DELETE_REQUESTED"},
+	}
+
+	testCases := map[string]struct {
+		name       string
+		searchErr  error
+		want       string
+		wantErr    error
+		wantErrMsg string
+	}{
+		"unique match": {
+			name: "Acme Corp",
+			want: "organizations/100",
+		},
+		"case and spaces ignored": {
+			name: "  acme corp ",
+			want: "organizations/100",
+		},
+		"no match lists accessible organizations": {
+			name:       "Umbrella",
+			wantErr:    cmd.ErrOrganizationNotFound,
+			wantErrMsg: "Acme Corp (100), Globex (200), globex (300)",
+		},
+		"deleted organizations are not candidates": {
+			name:    "Initech",
+			wantErr: cmd.ErrOrganizationNotFound,
+		},
+		"ambiguous match lists candidates": {
+			name:       "GLOBEX",
+			wantErr:    cmd.ErrAmbiguousOrganization,
+			wantErrMsg: "Globex (200), globex (300)",
+		},
+		"search failure": {
+			name:      "Acme Corp",
+			searchErr: errTestNetwork,
+			wantErr:   errTestNetwork,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			orgFetcher := orgmocks.NewMockFetcher(t)
+			if tc.searchErr != nil {
+				orgFetcher.EXPECT().SearchOrganizations(mock.Anything).Return(nil, tc.searchErr).Once()
+			} else {
+				orgFetcher.EXPECT().SearchOrganizations(mock.Anything).Return(orgList, nil).Once()
+			}
+			orgService := organizations.NewServiceWithLogger(orgFetcher, logger.NewNoOpLogger())
+
+			got, err := cmd.ResolveOrganizationByName(t.Context(), orgService, tc.name)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				assert.Contains(t, err.Error(), tc.wantErrMsg)
+				assert.Nil(t, got)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got.Name)
+		})
+	}
+}
+
+func TestResolveParentDomains(t *testing.T) {
+	acme := &organizations.Organization{ID: "100", Name: "organizations/100", DisplayName: "acme.com", State: "ACTIVE"}
+	globex := &organizations.Organization{ID: "200", Name: "organizations/200", DisplayName: "globex.com", State: "ACTIVE"}
+
+	testCases := map[string]struct {
+		parents    []string
+		domain     string
+		found      []*organizations.Organization
+		searchErr  error
+		want       []string
+		wantErr    error
+		wantErrMsg string
+	}{
+		"numeric IDs are kept without a search": {
+			parents: []string{"organizations/123", "organizations/456"},
+			want:    []string{"organizations/123", "organizations/456"},
+		},
+		"domain resolved to its organization": {
+			parents: []string{"organizations/123", "organizations/acme.com"},
+			domain:  "acme.com",
+			found:   []*organizations.Organization{acme},
+			want:    []string{"organizations/123", "organizations/100"},
+		},
+		"no organization has the domain": {
+			parents:    []string{"organizations/umbrella.com"},
+			domain:     "umbrella.com",
+			wantErr:    cmd.ErrOrganizationDomainNotFound,
+			wantErrMsg: `"umbrella.com"`,
+		},
+		"ambiguous domain lists candidates": {
+			parents:    []string{"organizations/acme.com"},
+			domain:     "acme.com",
+			found:      []*organizations.Organization{acme, globex},
+			wantErr:    cmd.ErrAmbiguousOrganizationDomain,
+			wantErrMsg: "acme.com (100), globex.com (200)",
+		},
+		"search failure": {
+			parents:   []string{"organizations/acme.com"},
+			domain:    "acme.com",
+			searchErr: errTestNetwork,
+			wantErr:   errTestNetwork,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			orgFetcher := orgmocks.NewMockFetcher(t)
+			if tc.domain != "" {
+				orgFetcher.EXPECT().SearchOrganizationsByDomain(mock.Anything, tc.domain).
+					Return(tc.found, tc.searchErr).Once()
+			}
+			orgService := organizations.NewServiceWithLogger(orgFetcher, logger.NewNoOpLogger())
+
+			got, err := cmd.ResolveParentDomains(t.Context(), orgService, tc.parents)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				assert.Contains(t, err.Error(), tc.wantErrMsg)
+				assert.Nil(t, got)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestIsOrganizationDomain(t *testing.T) {
+	testCases := map[string]struct {
+		value string
+		want  bool
+	}{
+		"numeric ID": {value: "123456789", want: false},
+		"domain":     {value: "example.com", want: true},
+		"subdomain":  {value: "corp.example.co.uk", want: true},
+		"non-digit":  {value: "12a", want: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, cmd.IsOrganizationDomain(tc.value))
+		})
+	}
+}
+
+func TestFoldersCommandScopeValidation(t *testing.T) {
+	testCases := map[string]struct {
+		args    []string
+		wantErr error
+	}{
+		"unknown scope": {
+			args:    []string{"--scope", "everything"},
+			wantErr: cmd.ErrInvalidScope,
+		},
+		"scope with parent": {
+			args:    []string{"--scope", "all", "--parent-folder", "123"},
+			wantErr: cmd.ErrScopeWithParent,
+		},
+		"stream with hierarchy annotation": {
+			args:    []string{"--stream", "--annotate-hierarchy"},
+			wantErr: cmd.ErrStreamWithAggregation,
+		},
+		"unparseable updated-after": {
+			args:    []string{"--updated-after", "last week"},
+			wantErr: output.ErrInvalidTime,
+		},
+		"stream with multiple parents": {
+			args:    []string{"--stream", "--parent-folder", "1,2"},
+			wantErr: cmd.ErrStreamWithMultipleParents,
+		},
+		"id prefix without id format": {
+			args:    []string{"--id-prefix", "12"},
+			wantErr: cmd.ErrIDPrefixRequiresIDFormat,
+		},
+		"unparseable created-within": {
+			args:    []string{"--created-within", "30 days"},
+			wantErr: durationx.ErrInvalidDuration,
+		},
+		"unparseable older-than": {
+			args:    []string{"--older-than", "1y"},
+			wantErr: durationx.ErrInvalidDuration,
+		},
+		"created-within with created-after": {
+			args:    []string{"--created-within", "30d", "--created-after", "2024-01-01"},
+			wantErr: cmd.ErrCreatedWithinWithCreatedAfter,
+		},
+		"unparseable created-after": {
+			args:    []string{"--created-after", "2024-13-01"},
+			wantErr: output.ErrInvalidTime,
+		},
+		"empty query": {
+			args:    []string{"--query", ""},
+			wantErr: folders.ErrInvalidQuery,
+		},
+		"query restricting state": {
+			args:    []string{"--query", "state:DELETE_REQUESTED"},
+			wantErr: folders.ErrInvalidQuery,
+		},
+		"query with parent": {
+			args:    []string{"--query", "displayName:prod*", "--parent-organization", "123"},
+			wantErr: cmd.ErrQueryWithParent,
+		},
+		"interactive with stream": {
+			args:    []string{"--interactive", "--stream"},
+			wantErr: cmd.ErrInteractiveWithStream,
+		},
+		"organization name with organization ID": {
+			args:    []string{"--parent-organization-name", "Acme Corp", "--parent-organization", "123"},
+			wantErr: cmd.ErrParentOrganizationNameWithParent,
+		},
+		"organization name with scope": {
+			args:    []string{"--parent-organization-name", "Acme Corp", "--scope", "all"},
+			wantErr: cmd.ErrScopeWithParent,
+		},
+		"organization name with query": {
+			args:    []string{"--parent-organization-name", "Acme Corp", "--query", "displayName:prod*"},
+			wantErr: cmd.ErrQueryWithParent,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			foldersCmd := cmd.NewFoldersCommand(logger.NewNoOpLogger())
+			foldersCmd.SetArgs(tc.args)
+			foldersCmd.SilenceUsage = true
+			foldersCmd.SilenceErrors = true
+
+			err := foldersCmd.Execute()
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestOutputFoldersFilter(t *testing.T) {
+	folderList := []*folders.Folder{
+		{ID: "1", DisplayName: "prod-web", State: "ACTIVE"},
+		{ID: "2", DisplayName: "prod-db", State: "DELETE_REQUESTED"},
+		{ID: "3", DisplayName: "staging", State: "ACTIVE"},
+	}
+
+	testCases := map[string]struct {
+		filter  string
+		wantOut string
+		wantErr error
+	}{
+		"composite filter": {
+			filter:  "state=ACTIVE AND displayName~prod OR id=3",
+			wantOut: "1\n3\n",
+		},
+		"invalid filter": {
+			filter:  "state=ACTIVE AND",
+			wantErr: output.ErrInvalidFilter,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var stdout bytes.Buffer
+
+			err := cmd.OutputFolders(t.Context(), &stdout, io.Discard, folderList, cmd.OutputOptions{Format: "id", Filter: tc.filter})
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantOut, stdout.String())
+		})
+	}
+}
+
+func TestOutputFoldersIDPrefix(t *testing.T) {
+	folderList := []*folders.Folder{
+		{ID: "123456"},
+		{ID: "129001"},
+		{ID: "213456"},
+		{ID: "120000"},
+		{ID: "31"},
+	}
+
+	testCases := map[string]struct {
+		prefix  string
+		wantOut string
+	}{
+		"two-digit prefix": {
+			prefix:  "12",
+			wantOut: "123456\n129001\n120000\n",
+		},
+		"longer prefix": {
+			prefix:  "1290",
+			wantOut: "129001\n",
+		},
+		"no matches": {
+			prefix:  "9",
+			wantOut: "",
+		},
+		"empty prefix keeps all": {
+			prefix:  "",
+			wantOut: "123456\n129001\n213456\n120000\n31\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var stdout bytes.Buffer
+
+			err := cmd.OutputFolders(t.Context(), &stdout, io.Discard, folderList, cmd.OutputOptions{Format: "id", IDPrefix: tc.prefix})
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantOut, stdout.String())
+		})
+	}
+}
+
+func TestOutputFoldersClipboard(t *testing.T) {
+	folderList := []*folders.Folder{{ID: "1"}, {ID: "2"}}
+	errNoClipboard := errors.New("clipboard is not available")
+
+	testCases := map[string]struct {
+		clipboardErr  error
+		verbose       bool
+		wantClipboard string
+		wantOut       string
+		wantErrOut    string
+	}{
+		"copies instead of writing stdout": {
+			wantClipboard: "1\n2\n",
+		},
+		"verbose confirms the copy": {
+			verbose:       true,
+			wantClipboard: "1\n2\n",
+			wantErrOut:    "Copied folders output to the clipboard.\n",
+		},
+		"falls back to stdout when unavailable": {
+			clipboardErr:  errNoClipboard,
+			wantClipboard: "1\n2\n",
+			wantOut:       "1\n2\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr, copied bytes.Buffer
+			clipboard := func(data []byte) error {
+				copied.Write(data)
+
+				return tc.clipboardErr
+			}
+
+			err := cmd.OutputFolders(t.Context(), &stdout, &stderr, folderList, cmd.OutputOptions{
+				Format:    "id",
+				Verbose:   tc.verbose,
+				Clipboard: clipboard,
+			})
+			if tc.clipboardErr != nil {
+				require.ErrorIs(t, err, tc.clipboardErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.wantClipboard, copied.String())
+			assert.Equal(t, tc.wantOut, stdout.String())
+			assert.Equal(t, tc.wantErrOut, stderr.String())
+		})
+	}
+}
+
+func TestOutputFoldersPager(t *testing.T) {
+	folderList := []*folders.Folder{{ID: "1"}, {ID: "2"}}
+	errPager := errors.New("pager failed")
+
+	// a cancelled context shows that the caller's context reaches the pager
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	var stdout, stderr bytes.Buffer
+	var paged []byte
+	pager := func(pagerCtx context.Context, out, _ io.Writer, data []byte) error {
+		require.ErrorIs(t, pagerCtx.Err(), context.Canceled, "the pager should get the caller's context")
+		assert.Same(t, &stdout, out, "the pager should write to the command's stdout")
+		paged = data
+
+		return errPager
+	}
+
+	err := cmd.OutputFolders(ctx, &stdout, &stderr, folderList, cmd.OutputOptions{Format: "id", Pager: pager})
+	require.ErrorIs(t, err, errPager)
+	assert.Equal(t, "1\n2\n", string(paged), "the whole output should be handed to the pager at once")
+	assert.Empty(t, stdout.String())
+}
+
+func TestOutputFoldersPagerStatusOrder(t *testing.T) {
+	folderList := []*folders.Folder{{ID: "1", DisplayName: "Engineering", State: "ACTIVE"}}
+
+	tests := map[string]struct {
+		pager func(out *bytes.Buffer) func(context.Context, io.Writer, io.Writer, []byte) error
+	}{
+		"written directly": {},
+		"paged": {
+			pager: func(out *bytes.Buffer) func(context.Context, io.Writer, io.Writer, []byte) error {
+				return func(_ context.Context, stdout, _ io.Writer, data []byte) error {
+					assert.NotContains(t, out.String(), "Summary", "status messages should wait for the pager")
+					_, err := stdout.Write(data)
+
+					return err
+				}
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			// one writer for both streams records the order a terminal shows them in
+			var out bytes.Buffer
+			opts := cmd.OutputOptions{Format: "table", Verbose: true}
+			if tt.pager != nil {
+				opts.Pager = tt.pager(&out)
+			}
+
+			require.NoError(t, cmd.OutputFolders(t.Context(), &out, &out, folderList, opts))
+			table := strings.Index(out.String(), "Engineering")
+			summary := strings.Index(out.String(), "Summary")
+			require.NotEqual(t, -1, table)
+			require.NotEqual(t, -1, summary)
+			assert.Less(t, table, summary, "the summary panel should follow the table")
+		})
+	}
+}
+
+func TestFoldersPager(t *testing.T) {
+	path := writeFolderExport(t, []*folders.Folder{
+		{ID: "1", Name: "folders/1", DisplayName: "Engineering", Parent: "organizations/9", State: "ACTIVE"},
+	})
+	// the upper-casing pager shows whether the output went through it
+	t.Setenv("PAGER", "tr a-z A-Z")
+
+	tests := map[string]struct {
+		args []string
+		want string
+	}{
+		"forced pager is invoked": {
+			args: []string{"--pager", "-f", "csv", "--columns", "id,display_name", "folders", "--from-file", path},
+			want: "ID,DISPLAY NAME\n1,ENGINEERING\n",
+		},
+		"redirected output is not paged": {
+			args: []string{"-f", "csv", "--columns", "id,display_name", "folders", "--from-file", path},
+			want: "ID,Display Name\n1,Engineering\n",
+		},
+		"no pager writes directly": {
+			args: []string{"--no-pager", "-f", "csv", "--columns", "id,display_name", "folders", "--from-file", path},
+			want: "ID,Display Name\n1,Engineering\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			rootCmd.SetOut(&stdout)
+			rootCmd.SetErr(io.Discard)
+			rootCmd.SetArgs(tt.args)
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			require.NoError(t, rootCmd.Execute())
+			assert.Equal(t, tt.want, stdout.String())
+		})
+	}
+}
+
+func TestFoldersPagerRedirectedStatusOrder(t *testing.T) {
+	path := writeFolderExport(t, []*folders.Folder{
+		{ID: "1", Name: "folders/1", DisplayName: "Engineering", Parent: "organizations/9", State: "ACTIVE"},
+	})
+
+	// output that is not a terminal is written as it is formatted, ahead of the summary panel
+	var out bytes.Buffer
+	rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(&out)
+	rootCmd.SetArgs([]string{"--verbose", "folders", "--from-file", path})
+	t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+	require.NoError(t, rootCmd.Execute())
+	table := strings.Index(out.String(), "Engineering")
+	summary := strings.Index(out.String(), "Summary")
+	require.NotEqual(t, -1, table)
+	require.NotEqual(t, -1, summary)
+	assert.Less(t, table, summary, "the summary panel should follow the table")
+}
+
+func TestOutputFoldersMalformedNames(t *testing.T) {
+	folderList := []*folders.Folder{
+		folders.FolderFromProto(&resourcemanagerpb.Folder{Name: "folders/1"}),
+		folders.FolderFromProto(&resourcemanagerpb.Folder{Name: "2"}),
+	}
+
+	testCases := map[string]struct {
+		verbose    bool
+		wantErrOut string
+	}{
+		"quiet by default": {},
+		"verbose warns": {
+			verbose:    true,
+			wantErrOut: `Warning: unexpected folder name: malformed resource name: "2" does not start with "folders/"`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			err := cmd.OutputFolders(t.Context(), &stdout, &stderr, folderList, cmd.OutputOptions{
+				Format:  "id",
+				Verbose: tc.verbose,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, "1\n2\n", stdout.String(), "the raw name is kept as the ID")
+			if tc.wantErrOut == "" {
+				assert.Empty(t, stderr.String())
+
+				return
+			}
+			assert.Contains(t, stderr.String(), tc.wantErrOut)
+		})
+	}
+}
+
+func TestPrintPartialFailures(t *testing.T) {
+	partialErr := &folders.PartialError{
+		Failures: []folders.ParentError{
+			{Parent: "folders/2", Err: errTestNetwork},
+			{Parent: "folders/5", Err: status.Error(codes.PermissionDenied, "denied")},
+		},
+		Total: 4,
+	}
+
+	var stderr bytes.Buffer
+	cmd.PrintPartialFailures(&stderr, partialErr)
+
+	assert.Equal(t, "Errors: failed to list folders from 2 of 4 parents:\n"+
+		"  folders/2: network error\n"+
+		"  folders/5: rpc error: code = PermissionDenied desc = denied\n", stderr.String())
+}
+
+func TestResolveFields(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+		return path
+	}
+
+	standard := writeFile("standard.txt", "  id\n\ndisplay_name , state\n\t\n  parent  \n")
+	unknown := writeFile("unknown.txt", "id\nlabels\n")
+	blank := writeFile("blank.txt", "\n  \n")
+
+	testCases := map[string]struct {
+		columns    string
+		fieldsFile string
+		want       []string
+		wantErr    error
+	}{
+		"nothing selected": {
+			want: nil,
+		},
+		"fields file with whitespace and blank lines": {
+			fieldsFile: standard,
+			want:       []string{"id", "display_name", "state", "parent"},
+		},
+		"columns override fields file": {
+			columns:    "state,id",
+			fieldsFile: standard,
+			want:       []string{"state", "id"},
+		},
+		"blank fields file selects nothing": {
+			fieldsFile: blank,
+			want:       nil,
+		},
+		"unknown field in file": {
+			fieldsFile: unknown,
+			wantErr:    output.ErrUnknownField,
+		},
+		"missing fields file": {
+			fieldsFile: filepath.Join(dir, "missing.txt"),
+			wantErr:    os.ErrNotExist,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			fields, err := cmd.ResolveFields(output.ResourceTypeFolders, tc.columns, tc.fieldsFile)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, fields)
+		})
+	}
+}
+
+func TestOutputFoldersFields(t *testing.T) {
+	folderList := []*folders.Folder{{ID: "1", DisplayName: "prod", State: "ACTIVE"}}
+
+	var stdout bytes.Buffer
+	err := cmd.OutputFolders(t.Context(), &stdout, io.Discard, folderList, cmd.OutputOptions{
+		Format: "csv",
+		Fields: []string{"display_name", "id"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Display Name,ID\nprod,1\n", stdout.String())
+}
+
+func TestOutputOptionsSetTemplate(t *testing.T) {
+	templateFile := filepath.Join(t.TempDir(), "ids.tmpl")
+	require.NoError(t, os.WriteFile(templateFile, []byte("{{range .}}{{.id}};{{end}}"), 0o600))
+
+	testCases := map[string]struct {
+		format  string
+		text    string
+		file    string
+		wantOut string
+		wantErr error
+	}{
+		"inline template": {
+			format:  "table",
+			text:    "{{len .}} folders",
+			wantOut: "2 folders",
+		},
+		"template file": {
+			format:  "json",
+			file:    templateFile,
+			wantOut: "1;2;",
+		},
+		"both template flags": {
+			text:    "{{.}}",
+			file:    templateFile,
+			wantErr: cmd.ErrTemplateWithTemplateFile,
+		},
+		"template format without template": {
+			format:  "template",
+			wantErr: output.ErrMissingTemplate,
+		},
+		"unparseable template": {
+			text:    "{{range .}}",
+			wantErr: output.ErrInvalidTemplate,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			opts := cmd.OutputOptions{Format: tc.format}
+			err := opts.SetTemplate(tc.text, tc.file)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "template", opts.Format)
+
+			var stdout bytes.Buffer
+			err = cmd.OutputFolders(t.Context(), &stdout, io.Discard, []*folders.Folder{{ID: "1"}, {ID: "2"}}, opts)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantOut, stdout.String())
+		})
+	}
+}
+
+func TestOutputOptionsSetTruncate(t *testing.T) {
+	testCases := map[string]struct {
+		width   int
+		want    int
+		wantErr error
+	}{
+		"disabled": {width: 0, want: 0},
+		"width":    {width: 30, want: 30},
+		"negative": {width: -1, wantErr: cmd.ErrInvalidTruncate},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var opts cmd.OutputOptions
+			err := opts.SetTruncate(tc.width)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, opts.Truncate)
+		})
+	}
+}
+
+func TestOutputFoldersSortBy(t *testing.T) {
+	folderList := []*folders.Folder{
+		{ID: "3", DisplayName: "beta", State: "ACTIVE"},
+		{ID: "1", DisplayName: "gamma", State: "DELETE_REQUESTED"},
+		{ID: "2", DisplayName: "alpha", State: "ACTIVE"},
+	}
+
+	var stdout bytes.Buffer
+	err := cmd.OutputFolders(t.Context(), &stdout, &bytes.Buffer{}, folderList, cmd.OutputOptions{
+		Format: string(output.FormatID),
+		SortBy: "state,displayName",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "2\n3\n1\n", stdout.String())
+}
+
+func TestFoldersCommandSortByValidation(t *testing.T) {
+	testCases := map[string]struct {
+		args    []string
+		wantErr error
+	}{
+		"unknown sort field": {
+			args:    []string{"--sort-by", "state,size", "folders"},
+			wantErr: output.ErrInvalidSortKey,
+		},
+		"sort with stream": {
+			args:    []string{"--sort-by", "state", "folders", "--stream"},
+			wantErr: cmd.ErrStreamWithSort,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			rootCmd.SetOut(&bytes.Buffer{})
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs(tc.args)
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			err := rootCmd.Execute()
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestFoldersCommandCountByValidation(t *testing.T) {
+	testCases := map[string]struct {
+		args    []string
+		wantErr error
+	}{
+		"unknown count field": {
+			args:    []string{"--count-by", "display_name", "folders"},
+			wantErr: output.ErrInvalidCountBy,
+		},
+		"count with columns": {
+			args:    []string{"--count-by", "state", "--columns", "id", "folders"},
+			wantErr: cmd.ErrCountByWithListingFlags,
+		},
+		"count with sort": {
+			args:    []string{"--count-by", "state", "--sort-by", "id", "organizations"},
+			wantErr: cmd.ErrCountByWithListingFlags,
+		},
+		"count with stream": {
+			args:    []string{"--count-by", "state", "folders", "--stream"},
+			wantErr: cmd.ErrStreamWithCountBy,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			rootCmd.SetOut(&bytes.Buffer{})
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs(tc.args)
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			err := rootCmd.Execute()
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestFoldersCommandBackendValidation(t *testing.T) {
+	testCases := map[string]struct {
+		args    []string
+		wantErr error
+	}{
+		"unknown backend": {
+			args:    []string{"folders", "--backend", "bigquery", "--parent-folder", "1"},
+			wantErr: cmd.ErrInvalidBackend,
+		},
+		"asset without parent": {
+			args:    []string{"folders", "--backend", "asset"},
+			wantErr: cmd.ErrAssetBackendRequiresParent,
+		},
+		"asset with scope": {
+			args:    []string{"folders", "--backend", "asset", "--scope", "all"},
+			wantErr: cmd.ErrAssetBackendWithFlags,
+		},
+		"asset with query": {
+			args:    []string{"folders", "--backend", "asset", "--query", "displayName:prod*"},
+			wantErr: cmd.ErrAssetBackendWithFlags,
+		},
+		"asset with endpoint": {
+			args:    []string{"--endpoint-region", "eu", "folders", "--backend", "asset", "--parent-folder", "1"},
+			wantErr: cmd.ErrAssetBackendWithFlags,
+		},
+		"asset with proxy": {
+			args:    []string{"--proxy", "http://proxy:3128", "folders", "--backend", "asset", "--parent-folder", "1"},
+			wantErr: cmd.ErrAssetBackendWithClientFlags,
+		},
+		"asset with qps": {
+			args:    []string{"--qps", "5", "folders", "--backend", "asset", "--parent-folder", "1"},
+			wantErr: cmd.ErrAssetBackendWithClientFlags,
+		},
+		"asset with max retries": {
+			args:    []string{"--max-retries", "0", "folders", "--backend", "asset", "--parent-folder", "1"},
+			wantErr: cmd.ErrAssetBackendWithClientFlags,
+		},
+		"asset with trace": {
+			args:    []string{"--trace", "folders", "--backend", "asset", "--parent-folder", "1"},
+			wantErr: cmd.ErrAssetBackendWithClientFlags,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			rootCmd.SetOut(&bytes.Buffer{})
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs(tc.args)
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			err := rootCmd.Execute()
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestFoldersGraphCommandValidation(t *testing.T) {
+	testCases := map[string]struct {
+		args    []string
+		wantErr error
+	}{
+		"both parent flags": {
+			args:    []string{"folders", "graph", "--parent-folder", "1", "--parent-organization", "2"},
+			wantErr: cmd.ErrMutuallyExclusiveFlags,
+		},
+		"recursive without parent": {
+			args:    []string{"folders", "graph", "--recursive"},
+			wantErr: cmd.ErrRecursiveRequiresParent,
+		},
+		"format without a graph form": {
+			args:    []string{"--format", "json", "folders", "graph"},
+			wantErr: output.ErrUnsupportedOutputFormat,
+		},
+		"graph format for a folder listing": {
+			args:    []string{"--format", "dot", "folders", "--from-file", "folders.json"},
+			wantErr: output.ErrUnsupportedOutputFormat,
+		},
+		"graph output extension for a folder listing": {
+			args:    []string{"--output", filepath.Join(t.TempDir(), "folders.mmd"), "folders", "--from-file", "folders.json"},
+			wantErr: output.ErrUnsupportedOutputFormat,
+		},
+		"graph format for folder describe": {
+			args:    []string{"--format", "mermaid", "folders", "describe", "123"},
+			wantErr: output.ErrUnsupportedOutputFormat,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			rootCmd.SetOut(&bytes.Buffer{})
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs(tc.args)
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			err := rootCmd.Execute()
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+// writeFolderExport writes folderList as the JSON output of the folders command and returns its path.
+func writeFolderExport(t *testing.T, folderList []*folders.Folder) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	require.NoError(t, cmd.OutputFolders(t.Context(), &buf, io.Discard, folderList, cmd.OutputOptions{Format: "json"}))
+	path := filepath.Join(t.TempDir(), "folders.json")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o600))
+
+	return path
+}
+
+func TestFoldersFromFile(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	path := writeFolderExport(t, []*folders.Folder{
+		{ID: "1", Name: "folders/1", DisplayName: "Engineering", Parent: "organizations/9", State: "ACTIVE",
+			CreateTime: created},
+		{ID: "2", Name: "folders/2", DisplayName: "Backend", Parent: "folders/1", State: "ACTIVE"},
+		{ID: "3", Name: "folders/3", DisplayName: "Prod", Parent: "folders/2", State: "DELETE_REQUESTED"},
+		{ID: "4", Name: "folders/4", DisplayName: "Finance", Parent: "organizations/9", State: "ACTIVE"},
+	})
+
+	testCases := map[string]struct {
+		args []string
+		want string
+	}{
+		"full listing": {
+			args: []string{"--format", "id", "folders", "--from-file", path},
+			want: "1\n2\n3\n4\n",
+		},
+		"parent, filter and sort": {
+			args: []string{
+				"--format", "id", "--filter", "state=ACTIVE", "--sort-by", "id:desc",
+				"folders", "--from-file", path, "--parent-organization", "9",
+			},
+			want: "4\n1\n",
+		},
+		"depths from the whole file": {
+			args: []string{
+				"--format", "csv", "folders", "--from-file", path, "--parent-folder", "2", "--annotate-hierarchy",
+			},
+			want: "ID,Display Name,Parent,State,Create Time,Update Time,Depth\n3,Prod,folders/2,DELETE_REQUESTED,,,2\n",
+		},
+		"timestamps survive the round trip": {
+			args: []string{"--format", "csv", "--columns", "id,create_time", "folders", "--from-file", path,
+				"--parent-organization", "9"},
+			want: "ID,Create Time\n1,2024-01-02 03:04:05\n4,\n",
+		},
+		"default columns omit the name": {
+			args: []string{"--format", "csv", "folders", "--from-file", path, "--parent-folder", "2"},
+			want: "ID,Display Name,Parent,State,Create Time,Update Time\n3,Prod,folders/2,DELETE_REQUESTED,,\n",
+		},
+		"wide columns include the name": {
+			args: []string{"--format", "csv", "--wide", "folders", "--from-file", path, "--parent-folder", "2"},
+			want: "ID,Display Name,Parent,State,Create Time,Update Time,Name,Parent Type,Etag,Age\n" +
+				"3,Prod,folders/2,DELETE_REQUESTED,,,folders/3,folder,,\n",
+		},
+		"count by state": {
+			args: []string{"--format", "csv", "--count-by", "state", "folders", "--from-file", path},
+			want: "State,Count\nACTIVE,3\nDELETE_REQUESTED,1\n",
+		},
+		"human counts keep csv plain": {
+			args: []string{"--format", "csv", "--human", "--count-by", "state", "folders", "--from-file", path},
+			want: "State,Count\nACTIVE,3\nDELETE_REQUESTED,1\n",
+		},
+		"count children by parent": {
+			args: []string{"--format", "json", "--compact", "--count-by", "parent", "folders", "--from-file", path,
+				"--parent-folder", "1,2"},
+			want: `[{"parent":"folders/1","count":1},{"parent":"folders/2","count":1}]` + "\n",
+		},
+		"subtree graph": {
+			args: []string{"folders", "graph", "--from-file", path, "--parent-folder", "1", "--recursive"},
+			want: "digraph folders {\n" +
+				"  rankdir=LR;\n" +
+				"  node [shape=box];\n" +
+				"  \"folders/1\" [label=\"folders/1\", style=dashed];\n" +
+				"  \"folders/2\" [label=\"Backend (2)\"];\n" +
+				"  \"folders/3\" [label=\"Prod (3)\"];\n" +
+				"  \"folders/1\" -> \"folders/2\";\n" +
+				"  \"folders/2\" -> \"folders/3\";\n" +
+				"}\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			rootCmd.SetOut(&stdout)
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs(tc.args)
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			require.NoError(t, rootCmd.Execute())
+			assert.Equal(t, tc.want, stdout.String())
+		})
+	}
+}
+
+func TestFoldersFromFileValidation(t *testing.T) {
+	invalid := filepath.Join(t.TempDir(), "invalid.json")
+	require.NoError(t, os.WriteFile(invalid, []byte(`{"folders": []}`), 0o600))
+
+	testCases := map[string]struct {
+		args    []string
+		wantErr error
+	}{
+		"from file with stream": {
+			args:    []string{"folders", "--from-file", "folders.json", "--stream"},
+			wantErr: cmd.ErrFromFileWithAPIFlags,
+		},
+		"from file with organization domain": {
+			args:    []string{"folders", "--from-file", "folders.json", "--parent-organization", "example.com"},
+			wantErr: cmd.ErrFromFileWithAPIFlags,
+		},
+		"from file with query": {
+			args:    []string{"folders", "--from-file", "folders.json", "--query", "displayName:prod*"},
+			wantErr: cmd.ErrFromFileWithAPIFlags,
+		},
+		"from file with rawjson": {
+			args:    []string{"--format", "rawjson", "folders", "--from-file", "folders.json"},
+			wantErr: cmd.ErrFromFileWithRawJSON,
+		},
+		"from file with rawjson validate only": {
+			args:    []string{"--validate-only", "--format", "rawjson", "folders", "--from-file", "folders.json"},
+			wantErr: cmd.ErrFromFileWithRawJSON,
+		},
+		"missing file": {
+			args:    []string{"folders", "--from-file", filepath.Join(t.TempDir(), "missing.json")},
+			wantErr: os.ErrNotExist,
+		},
+		"invalid schema": {
+			args:    []string{"folders", "graph", "--from-file", invalid},
+			wantErr: folders.ErrInvalidFolderFile,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			rootCmd.SetOut(&bytes.Buffer{})
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs(tc.args)
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			err := rootCmd.Execute()
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestFoldersRedactDisplayNames(t *testing.T) {
+	path := writeFolderExport(t, []*folders.Folder{
+		{ID: "111", Name: "folders/111", DisplayName: "Finance", Parent: "organizations/9", State: "ACTIVE"},
+		{ID: "222", Name: "folders/222", DisplayName: "Legal", Parent: "folders/111", State: "ACTIVE"},
+	})
+
+	testCases := map[string]struct {
+		format string
+	}{
+		"table": {format: "table"},
+		"json":  {format: "json"},
+		"csv":   {format: "csv"},
+		"jsonl": {format: "jsonl"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			rootCmd.SetOut(&stdout)
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs([]string{
+				"--format", tc.format, "--redact-display-names", "folders", "--from-file", path,
+			})
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			require.NoError(t, rootCmd.Execute())
+			out := stdout.String()
+			assert.NotContains(t, out, "Finance")
+			assert.NotContains(t, out, "Legal")
+			assert.Contains(t, out, output.RedactDisplayName("Finance"))
+			assert.Contains(t, out, output.RedactDisplayName("Legal"))
+			assert.Contains(t, out, "111")
+			assert.Contains(t, out, "folders/111")
+		})
+	}
+}
+
+func TestFoldersRenameDisplayNames(t *testing.T) {
+	path := writeFolderExport(t, []*folders.Folder{
+		{ID: "111", Name: "folders/111", DisplayName: "CC1234-Finance", Parent: "organizations/9", State: "ACTIVE"},
+		{ID: "222", Name: "folders/222", DisplayName: "Legal", Parent: "folders/111", State: "ACTIVE"},
+	})
+
+	testCases := map[string]struct {
+		args    []string
+		want    []string
+		wantErr error
+	}{
+		"strip prefix in table": {
+			args: []string{"--strip-prefix", "CC1234-", "folders", "--from-file", path},
+			want: []string{"Finance", "Legal"},
+		},
+		"name regex in csv": {
+			args: []string{"-f", "csv", "--name-regex", "^CC[0-9]+-", "folders", "--from-file", path},
+			want: []string{"111,Finance,", "222,Legal,"},
+		},
+		"name regex in json": {
+			args: []string{"-f", "json", "--name-regex", "^CC[0-9]+-", "folders", "--from-file", path},
+			want: []string{`"display_name": "Finance"`, `"display_name": "Legal"`},
+		},
+		"name regex with replacement": {
+			args: []string{
+				"-f", "value", "--columns", "display_name", "--name-regex", `^(CC[0-9]+)-(.*)$`,
+				"--name-replacement", "$2 [$1]", "folders", "--from-file", path,
+			},
+			want: []string{"Finance [CC1234]", "Legal"},
+		},
+		"filters match the original names": {
+			args: []string{
+				"-f", "value", "--columns", "display_name", "--filter", "displayName~CC1234", "--strip-prefix", "CC1234-",
+				"folders", "--from-file", path,
+			},
+			want: []string{"Finance"},
+		},
+		"invalid regex fails at startup": {
+			args:    []string{"--name-regex", "^(CC", "folders", "--from-file", path},
+			wantErr: output.ErrInvalidNameRegex,
+		},
+		"replacement without regex": {
+			args:    []string{"--name-replacement", "x", "folders", "--from-file", path},
+			wantErr: cmd.ErrNameReplacementWithoutRegex,
+		},
+		"strip prefix with name regex": {
+			args:    []string{"--strip-prefix", "CC", "--name-regex", "x", "folders", "--from-file", path},
+			wantErr: cmd.ErrStripPrefixWithNameRegex,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			rootCmd.SetOut(&stdout)
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs(tc.args)
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			err := rootCmd.Execute()
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			out := stdout.String()
+			assert.NotContains(t, out, "CC1234-")
+			for _, want := range tc.want {
+				assert.Contains(t, out, want)
+			}
+		})
+	}
+}
+
+func TestFoldersEnvFormat(t *testing.T) {
+	path := writeFolderExport(t, []*folders.Folder{
+		{ID: "111", Name: "folders/111", DisplayName: "Finance", Parent: "organizations/9", State: "ACTIVE"},
+		{ID: "222", Name: "folders/222", DisplayName: "finance", Parent: "folders/111", State: "ACTIVE"},
+	})
+
+	testCases := map[string]struct {
+		args []string
+		want string
+	}{
+		"numbered": {
+			args: []string{"-f", "env", "folders", "--from-file", path},
+			want: "export FOLDER_1=111\nexport FOLDER_2=222\n",
+		},
+		"by display name": {
+			args: []string{"-f", "env", "--env-names", "folders", "--from-file", path},
+			want: "export FOLDER_FINANCE=111\nexport FOLDER_FINANCE_2=222\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			rootCmd.SetOut(&stdout)
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs(tc.args)
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			require.NoError(t, rootCmd.Execute())
+			assert.Equal(t, tc.want, stdout.String())
+		})
+	}
+}
+
+func TestFoldersVerboseSummaryLine(t *testing.T) {
+	path := writeFolderExport(t, []*folders.Folder{
+		{ID: "1", Name: "folders/1", DisplayName: "Engineering", Parent: "organizations/9", State: "ACTIVE"},
+		{ID: "2", Name: "folders/2", DisplayName: "Backend", Parent: "folders/1", State: "ACTIVE"},
+		{ID: "3", Name: "folders/3", DisplayName: "Old", Parent: "folders/1", State: "DELETE_REQUESTED"},
+	})
+
+	testCases := map[string]struct {
+		args        []string
+		wantCount   int
+		wantSummary bool
+	}{
+		"json": {
+			args:        []string{"-v", "-f", "json", "folders", "--from-file", path},
+			wantCount:   3,
+			wantSummary: true,
+		},
+		"json counts filtered folders": {
+			args:        []string{"-v", "-f", "json", "--filter", "state=ACTIVE", "folders", "--from-file", path},
+			wantCount:   2,
+			wantSummary: true,
+		},
+		"json without verbose": {
+			args: []string{"-f", "json", "folders", "--from-file", path},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			rootCmd.SetOut(&stdout)
+			rootCmd.SetErr(&stderr)
+			rootCmd.SetArgs(tc.args)
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			require.NoError(t, rootCmd.Execute())
+
+			// stdout holds only the JSON array
+			var listed []struct {
+				ID string `json:"id"`
+			}
+			require.NoError(t, json.Unmarshal(stdout.Bytes(), &listed))
+			if tc.wantSummary {
+				assert.Len(t, listed, tc.wantCount)
+			}
+
+			if !tc.wantSummary {
+				assert.NotContains(t, stderr.String(), "elapsed_ms")
+
+				return
+			}
+			var summary output.SummaryLine
+			lines := strings.Split(strings.TrimSpace(stderr.String()), "\n")
+			require.NoError(t, json.Unmarshal([]byte(lines[len(lines)-1]), &summary))
+			assert.Equal(t, "folders", summary.Resource)
+			assert.Equal(t, tc.wantCount, summary.Count)
+			assert.GreaterOrEqual(t, summary.ElapsedMS, int64(0))
+		})
+	}
+}
+
+func TestFoldersParents(t *testing.T) {
+	path := writeFolderExport(t, []*folders.Folder{
+		{ID: "1", Name: "folders/1", DisplayName: "Engineering", Parent: "organizations/9", State: "ACTIVE"},
+		{ID: "2", Name: "folders/2", DisplayName: "Backend", Parent: "folders/1", State: "ACTIVE"},
+		{ID: "3", Name: "folders/3", DisplayName: "Frontend", Parent: "folders/1", State: "ACTIVE"},
+		{ID: "4", Name: "folders/4", DisplayName: "Prod", Parent: "folders/3", State: "DELETE_REQUESTED"},
+	})
+
+	testCases := map[string]struct {
+		args    []string
+		want    string
+		wantErr error
+	}{
+		"one parent per line": {
+			args: []string{"--format", "id", "folders", "--from-file", path, "--parents"},
+			want: "folders/1\nfolders/3\norganizations/9\n",
+		},
+		"filters apply before the parents are collected": {
+			args: []string{"--format", "id", "--filter", "state=ACTIVE", "folders", "--from-file", path, "--parents"},
+			want: "folders/1\norganizations/9\n",
+		},
+		"json": {
+			args: []string{"--format", "json", "--compact", "folders", "--from-file", path, "--parents"},
+			want: `[{"parent":"folders/1"},{"parent":"folders/3"},{"parent":"organizations/9"}]` + "\n",
+		},
+		"listing flags rejected": {
+			args:    []string{"--sort-by", "id", "folders", "--from-file", path, "--parents"},
+			wantErr: cmd.ErrParentsWithListingFlags,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			rootCmd.SetOut(&stdout)
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs(tc.args)
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			err := rootCmd.Execute()
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, stdout.String())
+		})
+	}
+}
+
+// signalSpinner reports on its channels when it is started and stopped.
+type signalSpinner struct {
+	started chan struct{}
+	stopped chan struct{}
+}
+
+func (s *signalSpinner) Start() { s.started <- struct{}{} }
+func (s *signalSpinner) Stop()  { s.stopped <- struct{}{} }
+
+// writeServiceAccountKey writes service account credentials with a fresh key, so that clients can be
+// created without contacting Google, and returns the path of the file.
+func writeServiceAccountKey(t *testing.T) string {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	data, err := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"project_id":     "test-project",
+		"private_key_id": "test-key",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"client_email":   "test@test-project.iam.gserviceaccount.com",
+		"client_id":      "1",
+		"token_uri":      "https://oauth2.googleapis.com/token",
+	})
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	return path
+}
+
+// hangingEndpoint returns the address of a server that accepts connections and never answers, so that
+// API calls to it block until they are cancelled.
+func hangingEndpoint(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { _ = conn.Close() })
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestFoldersSpinnerStopsOnCancel(t *testing.T) {
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", writeServiceAccountKey(t))
+
+	spin := &signalSpinner{started: make(chan struct{}, 1), stopped: make(chan struct{}, 1)}
+	t.Cleanup(cmd.SetSpinnerFactory(func(string) progress.Spinner { return spin }))
+
+	rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"--endpoint", hangingEndpoint(t), "--max-retries", "0", "folders", "--parent-folder", "123"})
+	t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	errs := make(chan error, 1)
+	go func() { errs <- rootCmd.ExecuteContext(ctx) }()
+
+	select {
+	case <-spin.started:
+	case <-time.After(10 * time.Second):
+		t.Fatal("spinner not started")
+	}
+	select {
+	case <-spin.stopped:
+		t.Fatal("spinner stopped before the command was cancelled")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	cancel()
+	select {
+	case <-spin.stopped:
+	case <-time.After(10 * time.Second):
+		t.Fatal("spinner not stopped after the command was cancelled")
+	}
+	select {
+	case err := <-errs:
+		require.Error(t, err)
+		assert.Equal(t, codes.Canceled, status.Code(err))
+	case <-time.After(10 * time.Second):
+		t.Fatal("command not finished after it was cancelled")
+	}
+}
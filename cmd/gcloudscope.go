@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/andreygrechin/gcphelper/internal/cleanup"
+	"github.com/andreygrechin/gcphelper/internal/gcloudconfig"
+	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/projects"
+	"go.uber.org/zap"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GCloudProject returns the default project of the active gcloud CLI configuration, reading the
+// environment with getenv. It returns an empty project, and no error, when there is no configuration or
+// it sets no project, so that callers fall back to an unscoped listing.
+func GCloudProject(getenv func(key string) string) (string, error) {
+	var cfg *gcloudconfig.Config
+	dir, err := gcloudconfig.Dir(getenv)
+	if err == nil {
+		cfg, err = gcloudconfig.Load(dir, getenv)
+	}
+	if errors.Is(err, gcloudconfig.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return cfg.Project, nil
+}
+
+// ResolveProjectScope returns the parent that scopes a listing to the organization of project: the
+// organization containing it, directly or through folders. When the caller cannot read the folders up to
+// the organization, the highest readable folder is returned instead, and an empty parent when the project
+// belongs to no organization or folder.
+func ResolveProjectScope(
+	ctx context.Context,
+	project string,
+	projectGetter projects.Getter,
+	folderGetter FolderGetter,
+) (string, error) {
+	found, err := projectGetter.GetProject(ctx, project)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(found.Parent, "folders/") {
+		if strings.HasPrefix(found.Parent, "organizations/") {
+			return found.Parent, nil
+		}
+
+		return "", nil
+	}
+
+	ancestry, err := folders.NewAncestryResolver(folderGetter).Ancestry(ctx, found.Parent)
+	if status.Code(err) == codes.PermissionDenied {
+		return found.Parent, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to find the organization of project %s: %w", project, err)
+	}
+
+	top := ancestry[len(ancestry)-1]
+	if strings.HasPrefix(top.Parent, "organizations/") {
+		return top.Parent, nil
+	}
+
+	return top.Name, nil
+}
+
+// gcloudScopedParents returns parents, or with --use-gcloud-config the parent inferred from the gcloud
+// CLI's default project when the listing is otherwise unscoped.
+func gcloudScopedParents(
+	ctx context.Context,
+	stderr io.Writer,
+	opts foldersOptions,
+	folderGetter FolderGetter,
+	log logger.Logger,
+	clientOpts []option.ClientOption,
+	parents []string,
+) ([]string, error) {
+	if !opts.useGCloudConfig || len(parents) > 0 || opts.parentOrgName != "" || opts.scope != "" || opts.querySet {
+		return parents, nil
+	}
+
+	parent, err := gcloudConfigParent(ctx, stderr, opts, folderGetter, log, clientOpts)
+	if err != nil {
+		return nil, err
+	}
+	if parent == "" {
+		return parents, nil
+	}
+
+	return []string{parent}, nil
+}
+
+// gcloudConfigParent returns the parent inferred from the gcloud CLI's default project for
+// --use-gcloud-config, or an empty parent when no default project is configured.
+func gcloudConfigParent(
+	ctx context.Context,
+	stderr io.Writer,
+	opts foldersOptions,
+	folderGetter FolderGetter,
+	log logger.Logger,
+	clientOpts []option.ClientOption,
+) (string, error) {
+	project, err := GCloudProject(os.Getenv)
+	if err != nil {
+		return "", err
+	}
+	if project == "" {
+		if opts.verbose {
+			fmt.Fprintln(stderr, "No default project in the gcloud configuration; listing all accessible folders.")
+		}
+
+		return "", nil
+	}
+
+	client, err := projects.NewClientFromContext(ctx, clientOpts...)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup.CloseAndLog(log, "failed to close projects client", client)
+
+	parent, err := ResolveProjectScope(ctx, project, client, folderGetter)
+	if err != nil {
+		return "", err
+	}
+	log.Debug("inferred parent from the gcloud configuration", zap.String("project", project),
+		zap.String("parent", parent))
+	if opts.verbose && parent != "" {
+		fmt.Fprintf(stderr, "Listing folders under %s, the parent of gcloud project %s.\n", parent, project)
+	}
+
+	return parent, nil
+}
@@ -0,0 +1,139 @@
+package cmd_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/cmd"
+	"github.com/andreygrechin/gcphelper/internal/gcloudconfig"
+	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	foldersmocks "github.com/andreygrechin/gcphelper/pkg/folders/mocks"
+	"github.com/andreygrechin/gcphelper/pkg/projects"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// projectGetterFunc adapts a function to projects.Getter.
+type projectGetterFunc func(ctx context.Context, project string) (*projects.Project, error)
+
+func (f projectGetterFunc) GetProject(ctx context.Context, project string) (*projects.Project, error) {
+	return f(ctx, project)
+}
+
+func TestGCloudProject(t *testing.T) {
+	tests := map[string]struct {
+		config      string
+		wantProject string
+	}{
+		"default project": {
+			config:      "[core]\naccount = jane@example.com\nproject = acme-prod\n",
+			wantProject: "acme-prod",
+		},
+		"no project set": {
+			config: "[core]\naccount = jane@example.com\n",
+		},
+		"no configuration": {},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.config != "" {
+				require.NoError(t, os.MkdirAll(filepath.Join(dir, "configurations"), 0o700))
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "configurations", "config_default"),
+					[]byte(tt.config), 0o600))
+			}
+			getenv := func(key string) string {
+				if key == gcloudconfig.EnvConfigDir {
+					return dir
+				}
+
+				return ""
+			}
+
+			project, err := cmd.GCloudProject(getenv)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantProject, project)
+		})
+	}
+}
+
+func TestResolveProjectScope(t *testing.T) {
+	denied := status.Error(codes.PermissionDenied, "denied")
+
+	tests := map[string]struct {
+		parent     string
+		getErr     error
+		folders    map[string]*folders.Folder
+		folderErrs map[string]error
+		want       string
+		wantErr    error
+	}{
+		"project directly under an organization": {
+			parent: "organizations/9",
+			want:   "organizations/9",
+		},
+		"project in nested folders": {
+			parent: "folders/2",
+			folders: map[string]*folders.Folder{
+				"folders/2": {ID: "2", Name: "folders/2", Parent: "folders/1"},
+				"folders/1": {ID: "1", Name: "folders/1", Parent: "organizations/9"},
+			},
+			want: "organizations/9",
+		},
+		"unreadable ancestor keeps the highest readable folder": {
+			parent: "folders/2",
+			folders: map[string]*folders.Folder{
+				"folders/2": {ID: "2", Name: "folders/2", Parent: "folders/1"},
+			},
+			folderErrs: map[string]error{"folders/1": denied},
+			want:       "folders/2",
+		},
+		"unreadable parent folder": {
+			parent:     "folders/2",
+			folderErrs: map[string]error{"folders/2": denied},
+			want:       "folders/2",
+		},
+		"project without a parent": {},
+		"project lookup fails": {
+			getErr:  errTestNetwork,
+			wantErr: errTestNetwork,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			projectGetter := projectGetterFunc(func(_ context.Context, project string) (*projects.Project, error) {
+				assert.Equal(t, "acme-prod", project)
+				if tt.getErr != nil {
+					return nil, tt.getErr
+				}
+
+				return &projects.Project{ProjectID: "acme-prod", Name: "projects/123", Parent: tt.parent}, nil
+			})
+			folderFetcher := foldersmocks.NewMockFetcher(t)
+			for folderName, folder := range tt.folders {
+				folderFetcher.EXPECT().GetFolder(mock.Anything, folderName).Return(folder, nil).Once()
+			}
+			for folderName, err := range tt.folderErrs {
+				folderFetcher.EXPECT().GetFolder(mock.Anything, folderName).Return(nil, err).Once()
+			}
+			service := folders.NewServiceWithLogger(folderFetcher, logger.NewNoOpLogger())
+
+			got, err := cmd.ResolveProjectScope(t.Context(), "acme-prod", projectGetter, service)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/andreygrechin/gcphelper/internal/cleanup"
+	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// ErrRecursiveRequiresParent is returned when --recursive is used without a parent flag.
+var ErrRecursiveRequiresParent = errors.New("--recursive requires --parent-folder or --parent-organization")
+
+// graphFormatsAnnotation marks the commands that accept the graph formats, dot and mermaid.
+const graphFormatsAnnotation = "gcphelper/graph-formats"
+
+// withGraphFormats records on a command's annotations that it accepts the graph formats.
+func withGraphFormats(annotations map[string]string) map[string]string {
+	annotations[graphFormatsAnnotation] = "true"
+
+	return annotations
+}
+
+// checkGraphFormat rejects a graph format selected for a command that does not draw graphs, so that
+// it fails while flags are validated rather than after the listing was fetched.
+func checkGraphFormat(command *cobra.Command, format output.Format) error {
+	if !output.IsGraphFormat(format) || command.Annotations[graphFormatsAnnotation] != "" {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s draws folder hierarchies and is only supported by folders graph",
+		output.ErrUnsupportedOutputFormat, format)
+}
+
+// graphOptions holds the flag values of the folders graph command.
+type graphOptions struct {
+	outputFlags
+	clientFlags
+	parentFolder       string
+	parentOrganization string
+	recursive          bool
+	fromFile           string
+}
+
+// newFoldersGraphCommand creates the "folders graph" command.
+func newFoldersGraphCommand(log logger.Logger) *cobra.Command {
+	var opts graphOptions
+
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Draw the folder hierarchy as a Graphviz DOT or Mermaid graph",
+		Annotations: withGraphFormats(requiresIAM(
+			[]string{"roles/resourcemanager.folderViewer"},
+			"resourcemanager.folders.get", "resourcemanager.folders.list",
+		)),
+		Long: `Draw the folder hierarchy as a Graphviz DOT or Mermaid graph.
+
+This command fetches folders and writes a directed graph with an edge from each
+parent to its child folder. Folders are labeled with their display name and ID.
+Organizations are drawn as root nodes, and parents that are not part of the
+listing, such as folders you cannot see, with dashed borders.
+
+The graph is written in Graphviz DOT unless --format mermaid selects a Mermaid
+flowchart, which GitHub and GitLab render in Markdown files and wikis.
+
+Without a parent flag all accessible folders are searched. With a parent flag
+only its direct children are listed, unless --recursive walks the whole subtree.
+
+Examples:
+  # Draw every accessible folder
+  gcphelper folders graph | dot -Tsvg > folders.svg
+
+  # Draw the full hierarchy of an organization
+  gcphelper folders graph --parent-organization 123456789 --recursive
+
+  # Save the graph to a file
+  gcphelper --output graph.dot folders graph --parent-folder 987654321 --recursive
+
+  # Draw the hierarchy as a Mermaid flowchart for a README
+  gcphelper --format mermaid folders graph --parent-organization 123456789 --recursive
+
+  # Draw a subtree of a previously exported listing without API calls
+  gcphelper folders graph --from-file folders.json --parent-folder 987654321 --recursive`,
+		RunE: func(command *cobra.Command, _ []string) error {
+			opts.outputFlags = globalOutputFlags()
+			opts.clientFlags = globalClientFlags()
+
+			return runFoldersGraphCommand(command.Context(), command.OutOrStdout(), opts, log)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.parentFolder, "parent-folder", "p", "",
+		"Parent folder ID whose child folders are drawn")
+	cmd.Flags().StringVarP(&opts.parentOrganization, "parent-organization", "o", "",
+		"Parent organization ID whose child folders are drawn")
+	cmd.Flags().BoolVar(&opts.recursive, "recursive", false,
+		"Draw every descendant of the parent instead of its direct children, with one API call per folder")
+	cmd.Flags().StringVar(&opts.fromFile, "from-file", "",
+		"Read folders from a file exported with --format json or jsonl instead of calling the API")
+
+	return cmd
+}
+
+// validate checks the folders graph command flags for invalid combinations.
+func (o graphOptions) validate() error {
+	if o.parentFolder != "" && o.parentOrganization != "" {
+		return ErrMutuallyExclusiveFlags
+	}
+
+	if o.recursive && o.parent() == "" {
+		return ErrRecursiveRequiresParent
+	}
+
+	if _, err := o.graphFormat(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// graphFormat returns the graph format selected by --format. The default table format, which has no
+// graph form, selects DOT.
+func (o graphOptions) graphFormat() (output.Format, error) {
+	switch format := output.Format(o.format); format {
+	case output.FormatTable, output.FormatDOT:
+		return output.FormatDOT, nil
+	case output.FormatMermaid:
+		return output.FormatMermaid, nil
+	default:
+		return "", fmt.Errorf("%w: %s (supported graph formats: %s, %s)",
+			output.ErrUnsupportedOutputFormat, format, output.FormatDOT, output.FormatMermaid)
+	}
+}
+
+// parent returns the resource name of the requested parent, or an empty string when none is set.
+func (o graphOptions) parent() string {
+	switch {
+	case o.parentFolder != "":
+		return "folders/" + o.parentFolder
+	case o.parentOrganization != "":
+		return "organizations/" + o.parentOrganization
+	default:
+		return ""
+	}
+}
+
+func runFoldersGraphCommand(ctx context.Context, stdout io.Writer, opts graphOptions, log logger.Logger) error {
+	// validate flags before any API client is created
+	if err := opts.validate(); err != nil {
+		return err
+	}
+	format, err := opts.graphFormat()
+	if err != nil {
+		return err
+	}
+
+	if err := validateOnly(); err != nil {
+		return err
+	}
+
+	var folderList []*folders.Folder
+	if opts.fromFile != "" {
+		folderList, err = loadGraphFolders(opts)
+	} else {
+		folderList, err = fetchGraphFolders(ctx, opts, log)
+	}
+	if err != nil {
+		return err
+	}
+
+	recordResults(len(folderList), "")
+
+	if opts.renamer != nil {
+		for i, folder := range folderList {
+			folderList[i] = output.RenameFolder(folder, opts.renamer)
+		}
+	}
+	if opts.redact {
+		for i, folder := range folderList {
+			folderList[i] = output.RedactFolder(folder)
+		}
+	}
+
+	return output.RenderGraph(stdout, folderList, format)
+}
+
+// loadGraphFolders selects the folders to draw from the --from-file file the way the API listings
+// would select them.
+func loadGraphFolders(opts graphOptions) ([]*folders.Folder, error) {
+	folderList, err := folders.LoadFoldersFile(opts.fromFile)
+	if err != nil {
+		return nil, err
+	}
+
+	switch parent := opts.parent(); {
+	case parent == "":
+		return folderList, nil
+	case opts.recursive:
+		return folders.DescendantsOf(folderList, parent), nil
+	default:
+		return folders.ChildrenOf(folderList, parent), nil
+	}
+}
+
+// fetchGraphFolders fetches the folders to draw: all accessible folders, or the children or, with
+// --recursive, the descendants of the parent.
+func fetchGraphFolders(ctx context.Context, opts graphOptions, log logger.Logger) ([]*folders.Folder, error) {
+	clientOpts, err := opts.clientOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	// create folders service
+	service, err := folders.NewServiceFromContextWithLogger(ctx, log, clientOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create folders service: %w", err)
+	}
+	setSpinner(service, !opts.noSpinner)
+	defer cleanup.CloseAndLog(log, "failed to close service", service)
+
+	fetchOpts := folders.NewFetchOptions()
+	fetchOpts.RequestReason = opts.requestReason
+
+	parent := opts.parent()
+	var folderList []*folders.Folder
+	switch {
+	case parent == "":
+		folderList, err = service.ListFolders(ctx, fetchOpts)
+	case opts.recursive:
+		folderList, err = service.ListFolderTree(ctx, parent, fetchOpts)
+	default:
+		folderList, err = service.ListFoldersFromParent(ctx, parent, fetchOpts)
+	}
+	if err != nil {
+		return nil, HandleFoldersError(err, parent)
+	}
+
+	return folderList, nil
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/andreygrechin/gcphelper/internal/cleanup"
+	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/andreygrechin/gcphelper/internal/reqmeta"
+	"github.com/andreygrechin/gcphelper/pkg/iam"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// iamTestOptions holds the flag values of the "iam test" command.
+type iamTestOptions struct {
+	outputFlags
+	clientFlags
+	resource    string
+	permissions []string
+}
+
+// NewIAMCommand creates and returns the iam command and its subcommands.
+func NewIAMCommand(log logger.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "iam",
+		Short: "Inspect IAM access to Google Cloud resources",
+	}
+
+	cmd.AddCommand(newIAMTestCommand(log))
+
+	return cmd
+}
+
+// newIAMTestCommand creates the "iam test" command.
+func newIAMTestCommand(log logger.Logger) *cobra.Command {
+	var opts iamTestOptions
+
+	cmd := &cobra.Command{
+		Use:   "test",
+		Short: "Check which IAM permissions you hold on a folder or organization",
+		Long: `Check which IAM permissions you hold on a folder or organization.
+
+This command calls testIamPermissions on the resource and reports, for each
+requested permission, whether the caller holds it. The resource name picks the
+API: folders/ID uses the folders API and organizations/ID the organizations API.
+Testing permissions requires no IAM permissions of its own.
+
+Examples:
+  # Check folder access before running other commands
+  gcphelper iam test --resource folders/123 \
+    --permissions resourcemanager.folders.list,resourcemanager.folders.get
+
+  # Check organization access and list only the missing permissions
+  gcphelper --filter state=NOT_GRANTED iam test --resource organizations/456 \
+    --permissions resourcemanager.organizations.get,resourcemanager.folders.list
+
+  # Report the results as JSON
+  gcphelper --format json iam test --resource folders/123 --permissions resourcemanager.folders.get`,
+		RunE: func(command *cobra.Command, _ []string) error {
+			opts.outputFlags = globalOutputFlags()
+			opts.clientFlags = globalClientFlags()
+
+			return runIAMTestCommand(command.Context(), command.OutOrStdout(), command.ErrOrStderr(), opts, log)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.resource, "resource", "",
+		"Resource to test permissions on (folders/ID or organizations/ID)")
+	cmd.Flags().StringSliceVar(&opts.permissions, "permissions", nil,
+		"Comma-separated IAM permissions to test (e.g., resourcemanager.folders.list)")
+
+	return cmd
+}
+
+func runIAMTestCommand(ctx context.Context, stdout, stderr io.Writer, opts iamTestOptions, log logger.Logger) error {
+	ctx = reqmeta.WithRequestReason(ctx, opts.requestReason)
+
+	if _, err := iam.ParseResource(opts.resource); err != nil {
+		return err
+	}
+	permissions, err := iam.ParsePermissions(opts.permissions)
+	if err != nil {
+		return err
+	}
+
+	renderOpts, err := opts.renderOptions(stdout, output.ResourceTypePermissions)
+	if err != nil {
+		return err
+	}
+	clientOpts, err := opts.clientOptions()
+	if err != nil {
+		return err
+	}
+	if err := validateOnly(); err != nil {
+		return err
+	}
+
+	// create the client owning the resource
+	tester, err := iam.NewPermissionTester(ctx, opts.resource, clientOpts...)
+	if err != nil {
+		return fmt.Errorf("failed to create IAM client: %w", err)
+	}
+	defer cleanup.CloseAndLog(log, "failed to close client", tester)
+
+	results, err := iam.TestPermissions(ctx, tester, opts.resource, permissions)
+	if err != nil {
+		return err
+	}
+
+	// output results
+	return OutputPermissionResults(ctx, stdout, stderr, results, renderOpts)
+}
+
+// OutputPermissionResults renders permission test results to stdout and status messages to stderr.
+func OutputPermissionResults(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	results []*iam.PermissionResult,
+	opts OutputOptions,
+) error {
+	return renderResources(ctx, stdout, stderr, results, output.ResourceTypePermissions, opts)
+}
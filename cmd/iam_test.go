@@ -0,0 +1,89 @@
+package cmd_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/cmd"
+	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/andreygrechin/gcphelper/pkg/iam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputPermissionResults(t *testing.T) {
+	results := []*iam.PermissionResult{
+		{Resource: "folders/123", Permission: "resourcemanager.folders.get", Granted: true},
+		{Resource: "folders/123", Permission: "resourcemanager.folders.list", Granted: false},
+	}
+
+	testCases := map[string]struct {
+		opts    cmd.OutputOptions
+		wantOut string
+	}{
+		"csv": {
+			opts: cmd.OutputOptions{Format: "csv"},
+			wantOut: "Resource,Permission,Granted\n" +
+				"folders/123,resourcemanager.folders.get,true\n" +
+				"folders/123,resourcemanager.folders.list,false\n",
+		},
+		"jsonl": {
+			opts: cmd.OutputOptions{Format: "jsonl"},
+			wantOut: `{"resource":"folders/123","permission":"resourcemanager.folders.get","granted":true}` + "\n" +
+				`{"resource":"folders/123","permission":"resourcemanager.folders.list","granted":false}` + "\n",
+		},
+		"missing permissions only": {
+			opts:    cmd.OutputOptions{Format: "id", Filter: "state=NOT_GRANTED"},
+			wantOut: "resourcemanager.folders.list\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var stdout bytes.Buffer
+
+			err := cmd.OutputPermissionResults(t.Context(), &stdout, io.Discard, results, tc.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantOut, stdout.String())
+		})
+	}
+}
+
+func TestIAMTestCommandValidation(t *testing.T) {
+	tests := map[string]struct {
+		args    []string
+		wantErr error
+	}{
+		"missing resource": {
+			args:    []string{"iam", "test", "--permissions", "resourcemanager.folders.get"},
+			wantErr: iam.ErrUnsupportedResource,
+		},
+		"project resource": {
+			args:    []string{"iam", "test", "--resource", "projects/p", "--permissions", "resourcemanager.projects.get"},
+			wantErr: iam.ErrUnsupportedResource,
+		},
+		"missing permissions": {
+			args:    []string{"iam", "test", "--resource", "folders/123"},
+			wantErr: iam.ErrNoPermissions,
+		},
+		"blank permissions": {
+			args:    []string{"iam", "test", "--resource", "organizations/456", "--permissions", " , "},
+			wantErr: iam.ErrNoPermissions,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			rootCmd.SetOut(&bytes.Buffer{})
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs(tt.args)
+			// registering the flags again resets the global flag values for later tests
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			err := rootCmd.Execute()
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
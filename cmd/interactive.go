@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/andreygrechin/gcphelper/internal/selector"
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/mattn/go-isatty"
+	"golang.org/x/term"
+)
+
+// ErrInteractiveRequiresTerminal is returned when --interactive is used without a terminal on stdout.
+var ErrInteractiveRequiresTerminal = errors.New(
+	"--interactive requires a terminal on stdout; drop --interactive to print the folder list instead")
+
+// ErrInteractiveWithStream is returned when --interactive is combined with --stream.
+var ErrInteractiveWithStream = errors.New("cannot combine --interactive with --stream")
+
+// isTerminal reports whether w writes to a terminal.
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	return isatty.IsTerminal(file.Fd()) || isatty.IsCygwinTerminal(file.Fd())
+}
+
+// terminalHeight returns the number of lines of the terminal w writes to, or zero when w is not a
+// terminal or its size is unknown.
+func terminalHeight(w io.Writer) int {
+	file, ok := w.(*os.File)
+	if !ok || !isTerminal(w) {
+		return 0
+	}
+	_, height, err := term.GetSize(int(file.Fd()))
+	if err != nil {
+		return 0
+	}
+
+	return height
+}
+
+// SelectFolder lets the user pick one of the folders that pass the --filter and time filters, reading
+// input from in and writing the prompt to prompt, then writes the picked folder's ID to stdout in the
+// selected ID style.
+func SelectFolder(
+	ctx context.Context,
+	in io.Reader,
+	prompt, stdout io.Writer,
+	folderList []*folders.Folder,
+	opts OutputOptions,
+) error {
+	resources := output.FilterByTime(output.FoldersToResources(folderList), opts.TimeFilter)
+	resources, err := output.FilterResources(resources, opts.Filter)
+	if err != nil {
+		return fmt.Errorf("failed to filter folders: %w", err)
+	}
+
+	byID := make(map[string]*folders.Folder, len(folderList))
+	for _, folder := range folderList {
+		byID[folder.ID] = folder
+	}
+	items := make([]selector.Item, len(resources))
+	for i, resource := range resources {
+		items[i] = selector.Item{ID: resource.GetID(), Label: resource.GetDisplayName()}
+	}
+
+	picked, err := selector.Select(in, prompt, items)
+	if err != nil {
+		return fmt.Errorf("failed to select a folder: %w", err)
+	}
+
+	return OutputFolders(ctx, stdout, prompt, []*folders.Folder{byID[picked.ID]}, OutputOptions{
+		Format:  string(output.FormatID),
+		IDStyle: opts.IDStyle,
+	})
+}
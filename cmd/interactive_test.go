@@ -0,0 +1,86 @@
+package cmd_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/cmd"
+	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/andreygrechin/gcphelper/internal/selector"
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectFolder(t *testing.T) {
+	folderList := []*folders.Folder{
+		{ID: "101", Name: "folders/101", DisplayName: "prod-web", State: "ACTIVE"},
+		{ID: "102", Name: "folders/102", DisplayName: "prod-db", State: "DELETE_REQUESTED"},
+		{ID: "103", Name: "folders/103", DisplayName: "staging", State: "ACTIVE"},
+	}
+
+	tests := map[string]struct {
+		input   string
+		opts    cmd.OutputOptions
+		wantOut string
+		wantErr error
+	}{
+		"pick by number": {
+			input:   "2\n",
+			wantOut: "102\n",
+		},
+		"narrow then confirm": {
+			input:   "stag\n\n",
+			wantOut: "103\n",
+		},
+		"full ID style": {
+			input:   "1\n",
+			opts:    cmd.OutputOptions{IDStyle: output.IDStyleFull},
+			wantOut: "folders/101\n",
+		},
+		"filter limits choices": {
+			input:   "2\n",
+			opts:    cmd.OutputOptions{Filter: "state=ACTIVE"},
+			wantOut: "103\n",
+		},
+		"cancelled": {
+			input:   "q\n",
+			wantErr: selector.ErrNoSelection,
+		},
+		"nothing to pick": {
+			input:   "\n",
+			opts:    cmd.OutputOptions{Filter: "id=999"},
+			wantErr: selector.ErrNoItems,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout, prompt bytes.Buffer
+
+			err := cmd.SelectFolder(t.Context(), strings.NewReader(tt.input), &prompt, &stdout, folderList, tt.opts)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, stdout.String())
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOut, stdout.String())
+			assert.Contains(t, prompt.String(), "prod-web", "the prompt should go to the prompt writer")
+		})
+	}
+}
+
+func TestFoldersInteractiveRequiresTerminal(t *testing.T) {
+	foldersCmd := cmd.NewFoldersCommand(logger.NewNoOpLogger())
+	foldersCmd.SetArgs([]string{"--interactive"})
+	foldersCmd.SetOut(&bytes.Buffer{})
+	foldersCmd.SilenceUsage = true
+	foldersCmd.SilenceErrors = true
+
+	err := foldersCmd.Execute()
+	require.ErrorIs(t, err, cmd.ErrInteractiveRequiresTerminal)
+}
@@ -3,9 +3,11 @@ package cmd
 import (
 	"context"
 	"fmt"
-	"os"
+	"io"
 
+	"github.com/andreygrechin/gcphelper/internal/cleanup"
 	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/andreygrechin/gcphelper/internal/reqmeta"
 	"github.com/andreygrechin/gcphelper/pkg/organizations"
 	"github.com/andreygrechin/gcphelper/pkg/output"
 	"github.com/spf13/cobra"
@@ -13,78 +15,118 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// organizationsOptions holds the flag values of the organizations command.
+type organizationsOptions struct {
+	outputFlags
+	clientFlags
+	includeDeleted bool
+}
+
 // NewOrganizationsCommand creates and returns the organizations command.
 func NewOrganizationsCommand(log logger.Logger) *cobra.Command {
+	var includeDeleted bool
+
 	cmd := &cobra.Command{
 		Use:     "organizations",
 		Aliases: []string{"organization", "org"},
 		Short:   "List Google Cloud organizations",
+		Annotations: withExampleFlags(requiresIAM(
+			[]string{"roles/resourcemanager.organizationViewer"},
+			"resourcemanager.organizations.get",
+		), "format", "filter"),
 		Long: `List Google Cloud organizations accessible to the caller.
 
 This command searches for organizations accessible to your credentials and displays
-information about them. This requires the following IAM permissions:
-- resourcemanager.organizations.get (to search organizations)
-
-Examples:
-  # List all accessible organizations
+information about them. Only ACTIVE organizations are listed unless --include-deleted
+is set. This requires the following IAM permissions:
+- resourcemanager.organizations.get (to search organizations)`,
+		Example: `  # List all accessible organizations
   gcphelper organizations
 
-  # List organizations in JSON format
-  gcphelper --format json organizations
-
   # List only organization IDs for scripting
   gcphelper --format id organizations
 
   # Pipe organization IDs to other commands
   gcphelper -f id organizations | xargs -I {} gcloud resource-manager organizations describe {}
 
+  # Include organizations pending deletion
+  gcphelper organizations --include-deleted
+
   # List organizations with verbose output
   gcphelper --verbose organizations
 
   # Use the short alias
   gcphelper org`,
-		RunE: func(_ *cobra.Command, _ []string) error {
-			return runOrganizationsCommand(globalFormat, globalVerbose, log)
+		RunE: func(command *cobra.Command, _ []string) error {
+			opts := organizationsOptionsFromFlags()
+			opts.includeDeleted = includeDeleted
+
+			return runOrganizationsCommand(command.Context(), command.OutOrStdout(), command.ErrOrStderr(), opts, log)
 		},
 	}
 
+	cmd.Flags().BoolVar(&includeDeleted, "include-deleted", false,
+		"Include organizations that are not ACTIVE, such as those pending deletion")
+
+	cmd.AddCommand(newOrganizationsDescribeCommand(log))
+
 	return cmd
 }
 
-func runOrganizationsCommand(format string, verbose bool, log logger.Logger) error {
-	ctx := context.Background()
+// organizationsOptionsFromFlags returns the organizations options set by the global flags.
+func organizationsOptionsFromFlags() organizationsOptions {
+	return organizationsOptions{outputFlags: globalOutputFlags(), clientFlags: globalClientFlags()}
+}
+
+func runOrganizationsCommand(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	opts organizationsOptions,
+	log logger.Logger,
+) error {
+	ctx = reqmeta.WithRequestReason(ctx, opts.requestReason)
+
+	renderOpts, err := opts.renderOptions(stdout, output.ResourceTypeOrganizations)
+	if err != nil {
+		return err
+	}
+	clientOpts, err := opts.clientOptions()
+	if err != nil {
+		return err
+	}
+	if err := validateOnly(); err != nil {
+		return err
+	}
 
 	// create organizations service
-	service, err := organizations.NewServiceFromContextWithLogger(ctx, log)
+	service, err := organizations.NewServiceFromContextWithLogger(ctx, log, clientOpts...)
 	if err != nil {
 		return fmt.Errorf("failed to create organizations service: %w", err)
 	}
-	defer func() {
-		if closeErr := service.Close(); closeErr != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to close service: %v\n", closeErr)
-		}
-	}()
+	defer cleanup.CloseAndLog(log, "failed to close service", service)
+	setSpinner(service, !opts.noSpinner)
 
 	// search for organizations
-	organizationList, err := service.SearchOrganizations(ctx)
+	organizationList, err := service.SearchOrganizations(ctx, &organizations.OrgFetchOptions{
+		IncludeDeleted: opts.includeDeleted,
+	})
 	if err != nil {
 		return HandleOrganizationsError(err)
 	}
 
 	// output results
-	return OutputOrganizations(organizationList, format, verbose)
+	return OutputOrganizations(ctx, stdout, stderr, organizationList, renderOpts)
 }
 
-func OutputOrganizations(organizationList []*organizations.Organization, format string, verbose bool) error {
-	formatter := output.NewFormatterWithType(os.Stdout, verbose, "organizations")
-	resources := output.OrganizationsToResources(organizationList)
-	headers := output.OrganizationHeaders()
-
-	if err := formatter.Format(resources, output.Format(format), headers); err != nil {
-		return fmt.Errorf("failed to format organizations output: %w", err)
-	}
-
-	return nil
+// OutputOrganizations renders organizations to stdout and status messages to stderr. Cancelling ctx stops
+// rendering that waits, such as paging.
+func OutputOrganizations(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	organizationList []*organizations.Organization,
+	opts OutputOptions,
+) error {
+	return renderResources(ctx, stdout, stderr, organizationList, output.ResourceTypeOrganizations, opts)
 }
 
 // HandleOrganizationsError provides enhanced error handling with helpful messages.
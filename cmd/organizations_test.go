@@ -3,20 +3,20 @@ package cmd_test
 import (
 	"bytes"
 	"io"
-	"os"
 	"testing"
 	"time"
 
 	"github.com/andreygrechin/gcphelper/cmd"
 	"github.com/andreygrechin/gcphelper/internal/logger"
 	"github.com/andreygrechin/gcphelper/pkg/organizations"
+	"github.com/andreygrechin/gcphelper/pkg/output"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 func TestOutputOrganizationsInvalidFormat(t *testing.T) {
-	err := cmd.OutputOrganizations(nil, "invalid", false)
-	require.Error(t, err)
+	err := cmd.OutputOrganizations(t.Context(), io.Discard, io.Discard, nil, cmd.OutputOptions{Format: "invalid"})
+	require.ErrorIs(t, err, output.ErrUnsupportedOutputFormat)
 	assert.Contains(t, err.Error(), "unsupported output format")
 }
 
@@ -94,30 +94,15 @@ func TestOutputOrganizationsIDFormat(t *testing.T) {
 
 	for name, tc := range testCases {
 		t.Run(name, func(t *testing.T) {
-			// capture stdout and stderr
-			oldStdout := os.Stdout
-			oldStderr := os.Stderr
-			rOut, wOut, _ := os.Pipe()
-			rErr, wErr, _ := os.Pipe()
-			os.Stdout = wOut
-			os.Stderr = wErr
+			var stdout, stderr bytes.Buffer
 
 			// run the function
-			err := cmd.OutputOrganizations(tc.organizations, "id", tc.verbose)
-
-			// restore stdout/stderr and read output
-			_ = wOut.Close()
-			_ = wErr.Close()
-			os.Stdout = oldStdout
-			os.Stderr = oldStderr
-
-			outBytes, _ := io.ReadAll(rOut)
-			errBytes, _ := io.ReadAll(rErr)
+			err := cmd.OutputOrganizations(t.Context(), &stdout, &stderr, tc.organizations, cmd.OutputOptions{Format: "id", Verbose: tc.verbose})
 
 			// validate results
 			require.NoError(t, err)
-			assert.Equal(t, tc.wantOut, string(outBytes))
-			assert.Equal(t, tc.wantErr, string(errBytes))
+			assert.Equal(t, tc.wantOut, stdout.String())
+			assert.Equal(t, tc.wantErr, stderr.String())
 		})
 	}
 }
@@ -134,24 +119,17 @@ func TestOutputOrganizationsJSONFormat(t *testing.T) {
 		},
 	}
 
-	// capture stdout
-	oldStdout := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
+	var stdout, stderr bytes.Buffer
 
 	// run the function
-	err := cmd.OutputOrganizations(orgList, "json", false)
-
-	// restore stdout and read output
-	_ = w.Close()
-	os.Stdout = oldStdout
-	output, _ := io.ReadAll(r)
+	err := cmd.OutputOrganizations(t.Context(), &stdout, &stderr, orgList, cmd.OutputOptions{Format: "json"})
 
 	// validate results
 	require.NoError(t, err)
-	assert.Contains(t, string(output), `"id": "123456789"`)
-	assert.Contains(t, string(output), `"display_name": "Test Organization"`)
-	assert.Contains(t, string(output), `"state": "ACTIVE"`)
+	output := stdout.String()
+	assert.Contains(t, output, `"id": "123456789"`)
+	assert.Contains(t, output, `"display_name": "Test Organization"`)
+	assert.Contains(t, output, `"state": "ACTIVE"`)
 }
 
 func TestOutputOrganizationsTableFormat(t *testing.T) {
@@ -210,41 +188,32 @@ func TestOutputOrganizationsTableFormat(t *testing.T) {
 				"123456789",
 				"Test Organization",
 				"ACTIVE",
-				"Total organizations: 1",
 			},
-			wantStderr: "",
+			wantStderr: `+---------------------------------+
+| Summary                         |
++---------------------+-----------+
+| Total organizations | 1         |
+| By state            | ACTIVE: 1 |
+| Parent filter       | none      |
++---------------------+-----------+
+`,
 		},
 	}
 
 	for name, tc := range testCases {
 		t.Run(name, func(t *testing.T) {
-			// capture stdout and stderr
-			oldStdout := os.Stdout
-			oldStderr := os.Stderr
-			rOut, wOut, _ := os.Pipe()
-			rErr, wErr, _ := os.Pipe()
-			os.Stdout = wOut
-			os.Stderr = wErr
+			var stdout, stderr bytes.Buffer
 
 			// run the function
-			err := cmd.OutputOrganizations(tc.organizations, "table", tc.verbose)
-
-			// restore stdout/stderr and read output
-			_ = wOut.Close()
-			_ = wErr.Close()
-			os.Stdout = oldStdout
-			os.Stderr = oldStderr
-
-			outBytes, _ := io.ReadAll(rOut)
-			errBytes, _ := io.ReadAll(rErr)
+			err := cmd.OutputOrganizations(t.Context(), &stdout, &stderr, tc.organizations, cmd.OutputOptions{Format: "table", Verbose: tc.verbose})
 
 			// validate results
 			require.NoError(t, err)
-			output := string(outBytes)
+			output := stdout.String()
 			for _, want := range tc.wantContains {
 				assert.Contains(t, output, want)
 			}
-			assert.Equal(t, tc.wantStderr, string(errBytes))
+			assert.Equal(t, tc.wantStderr, stderr.String())
 		})
 	}
 }
@@ -261,20 +230,11 @@ func TestOutputOrganizationsCSVFormat(t *testing.T) {
 		},
 	}
 
-	// capture stdout
-	var buf bytes.Buffer
-	oldStdout := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
+	var stdout, stderr bytes.Buffer
 
 	// run the function
-	err := cmd.OutputOrganizations(orgList, "csv", false)
-
-	// restore stdout and read output
-	_ = w.Close()
-	os.Stdout = oldStdout
-	_, _ = io.Copy(&buf, r)
-	output := buf.String()
+	err := cmd.OutputOrganizations(t.Context(), &stdout, &stderr, orgList, cmd.OutputOptions{Format: "csv"})
+	output := stdout.String()
 
 	// validate results
 	require.NoError(t, err)
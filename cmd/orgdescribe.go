@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/andreygrechin/gcphelper/internal/cleanup"
+	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/andreygrechin/gcphelper/internal/reqmeta"
+	"github.com/andreygrechin/gcphelper/pkg/organizations"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// ErrDomainRequired is returned when organizations describe is run without --domain.
+var ErrDomainRequired = errors.New("--domain is required")
+
+// OrganizationDescriber finds organizations by domain and retrieves them in full.
+type OrganizationDescriber interface {
+	OrganizationSearcher
+	OrganizationGetter
+}
+
+// newOrganizationsDescribeCommand creates the "organizations describe" command.
+func newOrganizationsDescribeCommand(log logger.Logger) *cobra.Command {
+	var (
+		domain string
+		wrap   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "describe --domain DOMAIN",
+		Short: "Show the organization with the given primary domain",
+		Annotations: withExampleFlags(requiresIAM(
+			[]string{"roles/resourcemanager.organizationViewer"},
+			"resourcemanager.organizations.get",
+		), "domain"),
+		Long: `Show the organization with the given primary domain.
+
+This command finds the accessible organization whose primary domain is --domain
+with a domain-filtered organization search, then fetches it with the
+GetOrganization API and writes it in the selected --format, as a JSON object with
+--format json unless --wrap is set. It fails when no accessible organization has
+the domain, and lists the candidates when several do.`,
+		Example: `  # Describe the organization of a domain as JSON
+  gcphelper --format json organizations describe --domain example.com`,
+		Args: cobra.NoArgs,
+		RunE: func(command *cobra.Command, _ []string) error {
+			if domain == "" {
+				return ErrDomainRequired
+			}
+
+			opts := organizationsOptionsFromFlags()
+			opts.format = DescribeFormat(opts.format, 1, wrap)
+
+			return runOrganizationsDescribeCommand(
+				command.Context(), command.OutOrStdout(), command.ErrOrStderr(), domain, opts, log,
+			)
+		},
+	}
+
+	cmd.Flags().StringVar(&domain, "domain", "",
+		"Primary domain of the organization to describe, e.g. example.com")
+	cmd.Flags().BoolVar(&wrap, "wrap", false,
+		"With --format json, write the organization in a one-element array like the list commands")
+	setFlagExample(cmd.Flags(), "domain", "example.com")
+
+	return cmd
+}
+
+func runOrganizationsDescribeCommand(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	domain string,
+	opts organizationsOptions,
+	log logger.Logger,
+) error {
+	ctx = reqmeta.WithRequestReason(ctx, opts.requestReason)
+
+	// validate flags before any API client is created
+	renderOpts, err := opts.renderOptions(stdout, output.ResourceTypeOrganizations)
+	if err != nil {
+		return err
+	}
+	clientOpts, err := opts.clientOptions()
+	if err != nil {
+		return err
+	}
+	if err := validateOnly(); err != nil {
+		return err
+	}
+
+	// create organizations service
+	service, err := organizations.NewServiceFromContextWithLogger(ctx, log, clientOpts...)
+	if err != nil {
+		return fmt.Errorf("failed to create organizations service: %w", err)
+	}
+	defer cleanup.CloseAndLog(log, "failed to close service", service)
+	setSpinner(service, !opts.noSpinner)
+
+	org, err := DescribeOrganizationByDomain(ctx, service, domain)
+	if err != nil {
+		return HandleOrganizationsError(err)
+	}
+
+	// output results
+	return OutputOrganizations(ctx, stdout, stderr, []*organizations.Organization{org}, renderOpts)
+}
+
+// DescribeOrganizationByDomain returns the accessible organization whose primary domain is domain. The
+// organization is found with a domain-filtered search, as by ResolveOrganizationByDomain, and then
+// retrieved in full by its resource name.
+func DescribeOrganizationByDomain(
+	ctx context.Context,
+	describer OrganizationDescriber,
+	domain string,
+) (*organizations.Organization, error) {
+	found, err := ResolveOrganizationByDomain(ctx, describer, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	org, err := describer.GetOrganization(ctx, found.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe organization %s: %w", found.Name, err)
+	}
+
+	return org, nil
+}
@@ -0,0 +1,86 @@
+package cmd_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/cmd"
+	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/andreygrechin/gcphelper/pkg/organizations"
+	orgmocks "github.com/andreygrechin/gcphelper/pkg/organizations/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribeOrganizationByDomain(t *testing.T) {
+	acme := &organizations.Organization{ID: "100", Name: "organizations/100", DisplayName: "acme.com", State: "ACTIVE"}
+	globex := &organizations.Organization{ID: "200", Name: "organizations/200", DisplayName: "globex.com", State: "ACTIVE"}
+	described := &organizations.Organization{
+		ID: "100", Name: "organizations/100", DisplayName: "acme.com", State: "ACTIVE", Etag: "BwX1",
+	}
+
+	testCases := map[string]struct {
+		found      []*organizations.Organization
+		getErr     error
+		wantGet    bool
+		want       *organizations.Organization
+		wantErr    error
+		wantErrMsg string
+	}{
+		"unique match is described in full": {
+			found:   []*organizations.Organization{acme},
+			wantGet: true,
+			want:    described,
+		},
+		"no organization has the domain": {
+			wantErr:    cmd.ErrOrganizationDomainNotFound,
+			wantErrMsg: `"acme.com"`,
+		},
+		"ambiguous domain lists candidates": {
+			found:      []*organizations.Organization{acme, globex},
+			wantErr:    cmd.ErrAmbiguousOrganizationDomain,
+			wantErrMsg: "acme.com (100), globex.com (200)",
+		},
+		"describe failure": {
+			found:      []*organizations.Organization{acme},
+			getErr:     errTestNetwork,
+			wantGet:    true,
+			wantErr:    errTestNetwork,
+			wantErrMsg: "organizations/100",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			orgFetcher := orgmocks.NewMockFetcher(t)
+			orgFetcher.EXPECT().SearchOrganizationsByDomain(mock.Anything, "acme.com").Return(tc.found, nil).Once()
+			if tc.wantGet {
+				orgFetcher.EXPECT().GetOrganization(mock.Anything, "organizations/100").
+					Return(described, tc.getErr).Once()
+			}
+			orgService := organizations.NewServiceWithLogger(orgFetcher, logger.NewNoOpLogger())
+
+			got, err := cmd.DescribeOrganizationByDomain(t.Context(), orgService, "acme.com")
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				assert.Contains(t, err.Error(), tc.wantErrMsg)
+				assert.Nil(t, got)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestOrganizationsDescribeCommandRequiresDomain(t *testing.T) {
+	rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"organizations", "describe"})
+	t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+	require.ErrorIs(t, rootCmd.Execute(), cmd.ErrDomainRequired)
+}
@@ -0,0 +1,585 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/andreygrechin/gcphelper/internal/clipboard"
+	"github.com/andreygrechin/gcphelper/internal/pager"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+)
+
+// ErrInvalidTruncate is returned when the --truncate width is negative.
+var ErrInvalidTruncate = errors.New("invalid --truncate value")
+
+// ErrTemplateWithTemplateFile is returned when both --template and --template-file are specified.
+var ErrTemplateWithTemplateFile = errors.New("cannot combine --template with --template-file")
+
+// ErrCountByWithListingFlags is returned when --count-by is combined with flags that shape the listing
+// the counts replace.
+var ErrCountByWithListingFlags = errors.New("cannot combine --count-by with --columns, --fields-file or --sort-by")
+
+// OutputOptions configures how command results are rendered.
+type OutputOptions struct {
+	Format     string             // Format is the output format (table, json, jsonl, csv, id)
+	Verbose    bool               // Verbose enables status messages and counts on stderr
+	Filter     string             // Filter is a client-side filter expression applied before rendering
+	TimeFilter output.TimeFilter  // TimeFilter keeps resources created or updated after the given thresholds
+	Columns    []output.Column    // Columns are computed columns appended to the default headers
+	Parent     string             // Parent is the parent filter shown in the verbose summary panel
+	IDPrefix   string             // IDPrefix keeps only resources whose IDs start with this prefix
+	Compact    bool               // Compact disables indentation in JSON output
+	Fields     []string           // Fields restricts output to these fields, in this order
+	Location   *time.Location     // Location converts timestamps to this zone; nil keeps the stored zone
+	Template   *output.Template   // Template renders the result set when Format is template
+	IDStyle    output.IDStyle     // IDStyle selects bare IDs or full resource names in ID output
+	GroupBy    output.GroupBy     // GroupBy splits table output into one table per value of this field
+	CountBy    string             // CountBy replaces the listing with the number of resources per value of this field
+	Parents    bool               // Parents replaces the listing with its distinct parents, sorted by name
+	AgeUnit    output.AgeUnit     // AgeUnit encodes the age field in JSON output; empty means seconds
+	Truncate   int                // Truncate shortens table cells to this many characters; zero keeps them
+	SortBy     string             // SortBy orders resources by these comma-separated sort keys
+	NullValue  string             // NullValue is written for missing values and unset timestamps in tables
+	Human      bool               // Human groups the digits of counts in tables and the verbose summary
+	EnvNames   bool               // EnvNames names env output variables after display names instead of numbers
+	Redact     bool               // Redact replaces display names with a hash, keeping IDs and structure
+	Renamer    *output.Renamer    // Renamer, when set, rewrites display names before rendering
+	Clipboard  func([]byte) error // Clipboard, when set, receives the formatted output instead of stdout
+	Pager      pageFunc           // Pager, when set, writes the formatted output to stdout, paging it when long
+}
+
+// pageFunc writes formatted output to stdout, through a pager when the output should be paged, until ctx
+// is cancelled.
+type pageFunc func(ctx context.Context, stdout, stderr io.Writer, data []byte) error
+
+// outputFlags holds the global flags that select and shape the output of a command.
+type outputFlags struct {
+	format       string
+	verbose      bool
+	filter       string
+	compact      bool
+	clipboard    bool
+	pager        pager.Mode
+	columns      string
+	fieldsFile   string
+	timezone     string
+	template     string
+	templateFile string
+	idStyle      string
+	groupBy      string
+	countBy      string
+	truncate     int
+	sortBy       string
+	nullValue    string
+	human        bool
+	envNames     bool
+	redact       bool
+	renamer      *output.Renamer
+	ageUnit      string
+}
+
+// globalOutputFlags returns the output flags set on the root command.
+func globalOutputFlags() outputFlags {
+	return outputFlags{
+		format:       globalFormat,
+		verbose:      globalVerbose,
+		filter:       globalFilter,
+		compact:      globalCompact,
+		clipboard:    globalClipboard,
+		pager:        pagerMode(),
+		columns:      globalColumns,
+		fieldsFile:   globalFieldsFile,
+		timezone:     globalTimezone,
+		template:     globalTemplate,
+		templateFile: globalTemplateFile,
+		idStyle:      globalIDStyle,
+		groupBy:      globalGroupBy,
+		countBy:      globalCountBy,
+		truncate:     globalTruncate,
+		sortBy:       globalSortBy,
+		nullValue:    globalNullValue,
+		human:        globalHuman,
+		envNames:     globalEnvNames,
+		redact:       globalRedactDisplayNames,
+		renamer:      globalRenamer,
+		ageUnit:      globalAgeUnit,
+	}
+}
+
+// renderOptions validates the flags and returns the options to render resources of resourceType to
+// stdout with.
+func (f outputFlags) renderOptions(stdout io.Writer, resourceType string) (OutputOptions, error) {
+	fields, err := ResolveFields(resourceType, f.columns, f.fieldsFile)
+	if err != nil {
+		return OutputOptions{}, err
+	}
+	opts := OutputOptions{
+		Format:    f.format,
+		Verbose:   f.verbose,
+		Filter:    f.filter,
+		Compact:   f.compact,
+		Clipboard: clipboardWriter(f.clipboard),
+		Pager:     pagerWriter(f.pager, stdout),
+		Fields:    fields,
+		NullValue: f.nullValue,
+		Human:     f.human,
+		EnvNames:  f.envNames,
+		Redact:    f.redact,
+		Renamer:   f.renamer,
+	}
+	if err := opts.SetTimezone(f.timezone); err != nil {
+		return OutputOptions{}, err
+	}
+	if err := opts.SetTemplate(f.template, f.templateFile); err != nil {
+		return OutputOptions{}, err
+	}
+	if err := opts.SetIDStyle(f.idStyle); err != nil {
+		return OutputOptions{}, err
+	}
+	if err := opts.SetGroupBy(f.groupBy); err != nil {
+		return OutputOptions{}, err
+	}
+	if err := opts.SetTruncate(f.truncate); err != nil {
+		return OutputOptions{}, err
+	}
+	if err := opts.SetSortBy(f.sortBy); err != nil {
+		return OutputOptions{}, err
+	}
+	if err := opts.SetCountBy(f.countBy); err != nil {
+		return OutputOptions{}, err
+	}
+	if err := opts.SetAgeUnit(f.ageUnit); err != nil {
+		return OutputOptions{}, err
+	}
+
+	return opts, nil
+}
+
+// SetTimezone sets Location from an IANA timezone name. An empty name keeps the stored zone.
+func (o *OutputOptions) SetTimezone(name string) error {
+	if name == "" {
+		return nil
+	}
+
+	loc, err := output.LoadLocation(name)
+	if err != nil {
+		return err
+	}
+	o.Location = loc
+
+	return nil
+}
+
+// SetIDStyle validates and sets the --id-style value.
+func (o *OutputOptions) SetIDStyle(name string) error {
+	style, err := output.ParseIDStyle(name)
+	if err != nil {
+		return err
+	}
+	o.IDStyle = style
+
+	return nil
+}
+
+// SetGroupBy validates and sets the --group-by value.
+func (o *OutputOptions) SetGroupBy(name string) error {
+	field, err := output.ParseGroupBy(name)
+	if err != nil {
+		return err
+	}
+	o.GroupBy = field
+
+	return nil
+}
+
+// SetCountBy validates and sets the --count-by field. Fields and SortBy must be set first, since counts
+// cannot be combined with field selection or sorting.
+func (o *OutputOptions) SetCountBy(name string) error {
+	field, err := output.ParseCountBy(name)
+	if err != nil {
+		return err
+	}
+	if field != "" && (len(o.Fields) > 0 || o.SortBy != "") {
+		return ErrCountByWithListingFlags
+	}
+	o.CountBy = field
+
+	return nil
+}
+
+// SetSortBy validates and sets the --sort-by keys.
+func (o *OutputOptions) SetSortBy(spec string) error {
+	if _, err := output.ParseSortKeys(spec); err != nil {
+		return err
+	}
+	o.SortBy = spec
+
+	return nil
+}
+
+// SetTruncate validates and sets the --truncate width.
+func (o *OutputOptions) SetTruncate(width int) error {
+	if width < 0 {
+		return fmt.Errorf("%w: %d (must be zero or a positive number)", ErrInvalidTruncate, width)
+	}
+	o.Truncate = width
+
+	return nil
+}
+
+// SetAgeUnit validates and sets the --age-unit value.
+func (o *OutputOptions) SetAgeUnit(name string) error {
+	unit, err := output.ParseAgeUnit(name)
+	if err != nil {
+		return err
+	}
+	o.AgeUnit = unit
+
+	return nil
+}
+
+// SetTemplate parses the inline --template text or the --template-file file and switches Format
+// to template output. Requesting template output without either is an error.
+func (o *OutputOptions) SetTemplate(text, file string) error {
+	var tmpl *output.Template
+	var err error
+	switch {
+	case text != "" && file != "":
+		return ErrTemplateWithTemplateFile
+	case text != "":
+		tmpl, err = output.ParseTemplate("--template", text)
+	case file != "":
+		tmpl, err = output.ParseTemplateFile(file)
+	case o.Format == string(output.FormatTemplate):
+		return output.ErrMissingTemplate
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	o.Template = tmpl
+	o.Format = string(output.FormatTemplate)
+
+	return nil
+}
+
+// ResolveFields returns the fields selected with --columns or, when it is not set, listed in the
+// --fields-file file, and checks that they exist for the resource type.
+func ResolveFields(resourceType, columns, fieldsFile string) ([]string, error) {
+	var fields []string
+	switch {
+	case columns != "":
+		fields = output.ParseFieldList(columns)
+	case fieldsFile != "":
+		data, err := os.ReadFile(fieldsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fields file: %w", err)
+		}
+		fields = output.ParseFieldList(string(data))
+	default:
+		return nil, nil
+	}
+
+	desc, err := lookupResourceType(resourceType)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := output.NewFieldSelector(desc.Headers, fields, desc.Fields...); err != nil {
+		return nil, fmt.Errorf("invalid %s fields: %w", resourceType, err)
+	}
+
+	return fields, nil
+}
+
+// clipboardWriter returns the clipboard writer for the --clipboard flag, or nil to write to stdout.
+func clipboardWriter(enabled bool) func([]byte) error {
+	if !enabled {
+		return nil
+	}
+
+	return clipboard.Write
+}
+
+// pagerWriter returns the pager writer for the --pager and --no-pager flags, or nil to write to stdout.
+// Unless paging is forced, output is paged only when stdout is a terminal and the output has more lines
+// than the terminal.
+func pagerWriter(mode pager.Mode, stdout io.Writer) pageFunc {
+	// output to a pipe or file is never paged automatically, so it is written as it is formatted
+	if mode == pager.Never || (mode == pager.Auto && !isTerminal(stdout)) {
+		return nil
+	}
+
+	return func(ctx context.Context, stdout, stderr io.Writer, data []byte) error {
+		p := pager.Pager{
+			Command: pager.Command(os.Getenv),
+			Height:  terminalHeight(stdout),
+			Always:  mode == pager.Always,
+		}
+
+		return p.Write(ctx, stdout, stderr, data)
+	}
+}
+
+// renderResources converts items with the descriptor registered for resourceType, then filters and
+// formats them, writing data to stdout and status messages to stderr. Rendering that waits, such as
+// paging, stops when ctx is cancelled. Paged output is collected before the pager runs, and so are the
+// status messages, which are written once the pager exits so that they follow the output as they would
+// without the pager.
+func renderResources(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	items any,
+	resourceType string,
+	opts OutputOptions,
+) (err error) {
+	status := stderr
+	if opts.Pager != nil && opts.Clipboard == nil {
+		var buf bytes.Buffer
+		defer func() {
+			if _, writeErr := stderr.Write(buf.Bytes()); writeErr != nil {
+				err = errors.Join(err, fmt.Errorf("failed to write status messages: %w", writeErr))
+			}
+		}()
+		status = &buf
+	}
+
+	desc, err := lookupResourceType(resourceType)
+	if err != nil {
+		return err
+	}
+	resources, err := transformResources(status, items, desc, opts)
+	if err != nil {
+		return err
+	}
+	count := len(resources)
+	recordResults(count, opts.Parent)
+	resources, headers, err := shapeResources(resources, desc, opts)
+	if err != nil {
+		return err
+	}
+
+	out := stdout
+	var buf bytes.Buffer
+	if opts.Clipboard != nil || opts.Pager != nil {
+		out = &buf
+	}
+
+	formatter := newFormatter(out, status, resourceType, opts)
+	if err := formatter.Format(resources, output.Format(opts.Format), headers); err != nil {
+		return fmt.Errorf("failed to format %s output: %w", resourceType, err)
+	}
+	if err := writeSummaryLine(status, resourceType, count, opts); err != nil {
+		return err
+	}
+
+	return deliverOutput(ctx, stdout, stderr, buf.Bytes(), resourceType, opts)
+}
+
+// transformResources converts items to resources of the descriptor's type, warning about malformed IDs on
+// status with --verbose, and returns the resources the output options keep, with their display names
+// rewritten.
+func transformResources(
+	status io.Writer,
+	items any,
+	desc output.ResourceDescriptor,
+	opts OutputOptions,
+) ([]output.Resource, error) {
+	resources, err := desc.ToResources(items)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %s: %w", desc.Name, err)
+	}
+	transform, err := newResourceTransform(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter %s: %w", desc.Name, err)
+	}
+
+	kept := make([]output.Resource, 0, len(resources))
+	for _, resource := range resources {
+		if opts.Verbose {
+			warnMalformedID(status, resource)
+		}
+		if transform.keep(resource) {
+			kept = append(kept, transform.rewrite(resource))
+		}
+	}
+
+	return kept, nil
+}
+
+// shapeResources returns the resources to render and their headers: the counts of --count-by or the
+// distinct parents of --parents, which replace the listing and its columns, or else the sorted resources
+// with the selected fields and computed columns.
+func shapeResources(
+	resources []output.Resource,
+	desc output.ResourceDescriptor,
+	opts OutputOptions,
+) ([]output.Resource, []string, error) {
+	switch {
+	case opts.CountBy != "":
+		// the counts, sorted by value, replace the listing and its columns
+		counts, headers := output.CountResources(resources, opts.CountBy)
+
+		return counts, headers, nil
+	case opts.Parents:
+		// the distinct parents, sorted by name, replace the listing and its columns
+		parents, headers := output.ParentResources(resources)
+
+		return parents, headers, nil
+	}
+
+	resources, err := output.SortResources(resources, opts.SortBy)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to sort %s: %w", desc.Name, err)
+	}
+	resources, headers, err := output.SelectFields(resources, desc.Headers, opts.Fields, opts.fieldColumns(desc)...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to select %s fields: %w", desc.Name, err)
+	}
+	resources, headers = output.WithColumns(resources, headers, opts.Columns...)
+
+	return resources, headers, nil
+}
+
+// deliverOutput hands the formatted output collected for --clipboard or the pager to them; output written
+// to stdout directly has been delivered already.
+func deliverOutput(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	data []byte,
+	resourceType string,
+	opts OutputOptions,
+) error {
+	if opts.Clipboard != nil {
+		return copyToClipboard(stdout, stderr, data, resourceType, opts)
+	}
+	if opts.Pager != nil {
+		if err := opts.Pager(ctx, stdout, stderr, data); err != nil {
+			return fmt.Errorf("failed to page %s output: %w", resourceType, err)
+		}
+	}
+
+	return nil
+}
+
+// newFormatter returns a formatter of resourceType resources writing to out and status messages to
+// stderr, configured by opts.
+func newFormatter(out, stderr io.Writer, resourceType string, opts OutputOptions) *output.Formatter {
+	formatter := output.NewFormatter(out, stderr, opts.Verbose, resourceType)
+	formatter.SetParent(opts.Parent)
+	formatter.SetCompact(opts.Compact)
+	formatter.SetLocation(opts.Location)
+	formatter.SetTemplate(opts.Template)
+	formatter.SetIDStyle(opts.IDStyle)
+	formatter.SetGroupBy(opts.GroupBy)
+	formatter.SetTruncate(opts.Truncate)
+	formatter.SetNullValue(opts.NullValue)
+	formatter.SetHuman(opts.Human)
+	formatter.SetEnvNames(opts.EnvNames)
+
+	return formatter
+}
+
+// resourceTransform filters resources and rewrites their display names as the output options ask, one
+// resource at a time, so that rendered listings and streamed folders go through the same steps.
+type resourceTransform struct {
+	opts   OutputOptions
+	filter *output.Filter
+}
+
+// newResourceTransform returns the transform of opts, parsing its --filter expression.
+func newResourceTransform(opts OutputOptions) (resourceTransform, error) {
+	transform := resourceTransform{opts: opts}
+	if strings.TrimSpace(opts.Filter) != "" {
+		filter, err := output.ParseFilter(opts.Filter)
+		if err != nil {
+			return resourceTransform{}, err
+		}
+		transform.filter = filter
+	}
+
+	return transform, nil
+}
+
+// keep reports whether the resource matches the time filter, the ID prefix and the --filter expression.
+func (t resourceTransform) keep(resource output.Resource) bool {
+	return t.opts.TimeFilter.Match(resource) && strings.HasPrefix(resource.GetID(), t.opts.IDPrefix) &&
+		(t.filter == nil || t.filter.Match(resource))
+}
+
+// rewrite returns the resource with its display name renamed and redacted.
+func (t resourceTransform) rewrite(resource output.Resource) output.Resource {
+	if t.opts.Renamer != nil {
+		resource = output.RenameResource(resource, t.opts.Renamer)
+	}
+	if t.opts.Redact {
+		resource = output.RedactResource(resource)
+	}
+
+	return resource
+}
+
+// writeSummaryLine writes the JSON summary of count resources to stderr when --verbose is set with a
+// machine-readable format, which has no human-oriented summary, so that stdout carries only data.
+func writeSummaryLine(stderr io.Writer, resourceType string, count int, opts OutputOptions) error {
+	if !opts.Verbose || !machineReadableFormat(opts.Format) {
+		return nil
+	}
+
+	var elapsed time.Duration
+	if !runStart.IsZero() {
+		elapsed = time.Since(runStart)
+	}
+
+	return output.WriteSummaryLine(stderr, output.SummaryLine{
+		Resource:  resourceType,
+		Count:     count,
+		ElapsedMS: elapsed.Milliseconds(),
+	})
+}
+
+// warnMalformedID tells about a resource whose resource name did not yield a numeric ID, so that the
+// raw name shown as its ID is not mistaken for a real one.
+func warnMalformedID(stderr io.Writer, resource output.Resource) {
+	checker, ok := resource.(interface{ IDError() error })
+	if !ok {
+		return
+	}
+	if err := checker.IDError(); err != nil {
+		fmt.Fprintf(stderr, "Warning: %v; showing the raw name as its ID.\n", err)
+	}
+}
+
+// fieldColumns returns the computed fields of the resource type, with the age field in the chosen unit.
+func (o OutputOptions) fieldColumns(desc output.ResourceDescriptor) []output.Column {
+	if o.AgeUnit == "" {
+		return desc.Fields
+	}
+
+	return output.WithAgeUnit(desc.Fields, o.AgeUnit)
+}
+
+// copyToClipboard hands the formatted output to the clipboard writer. When the clipboard is not
+// available the output is written to stdout instead, so that the fetched data is not lost.
+func copyToClipboard(stdout, stderr io.Writer, data []byte, resourceType string, opts OutputOptions) error {
+	if err := opts.Clipboard(data); err != nil {
+		if _, writeErr := stdout.Write(data); writeErr != nil {
+			return fmt.Errorf("failed to write %s output: %w", resourceType, writeErr)
+		}
+
+		return fmt.Errorf("failed to copy %s output to the clipboard: %w", resourceType, err)
+	}
+
+	if opts.Verbose {
+		fmt.Fprintf(stderr, "Copied %s output to the clipboard.\n", resourceType)
+	}
+
+	return nil
+}
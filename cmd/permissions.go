@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Annotation keys recording the IAM access a command needs, as comma-separated lists.
+const (
+	permissionsAnnotation = "gcphelper/iam-permissions"
+	rolesAnnotation       = "gcphelper/iam-roles"
+)
+
+// requiresIAM returns command annotations recording the IAM permissions a command needs and the
+// predefined roles that grant them.
+func requiresIAM(roles []string, permissions ...string) map[string]string {
+	return map[string]string{
+		permissionsAnnotation: strings.Join(permissions, ","),
+		rolesAnnotation:       strings.Join(roles, ","),
+	}
+}
+
+// CommandPermissions returns the IAM permissions recorded on the command.
+func CommandPermissions(command *cobra.Command) []string {
+	return splitAnnotation(command, permissionsAnnotation)
+}
+
+// CommandRoles returns the predefined IAM roles recorded on the command as granting its permissions.
+func CommandRoles(command *cobra.Command) []string {
+	return splitAnnotation(command, rolesAnnotation)
+}
+
+// ExplainPermissions writes the IAM permissions the command needs and the roles that grant them.
+// It makes no API calls.
+func ExplainPermissions(w io.Writer, command *cobra.Command) {
+	permissions := CommandPermissions(command)
+	if len(permissions) == 0 {
+		fmt.Fprintf(w, "%s requires no IAM permissions.\n", command.CommandPath())
+
+		return
+	}
+
+	fmt.Fprintf(w, "%s requires these IAM permissions:\n", command.CommandPath())
+	for _, permission := range permissions {
+		fmt.Fprintf(w, "  %s\n", permission)
+	}
+
+	if roles := CommandRoles(command); len(roles) > 0 {
+		fmt.Fprintln(w, "\nPredefined roles that grant them:")
+		for _, role := range roles {
+			fmt.Fprintf(w, "  %s\n", role)
+		}
+	}
+}
+
+// explainPermissionsOnFlag wraps the command and its subcommands so that, when --explain-permissions
+// is set, they print their required permissions instead of running.
+func explainPermissionsOnFlag(command *cobra.Command) {
+	for _, child := range command.Commands() {
+		explainPermissionsOnFlag(child)
+	}
+
+	run := command.RunE
+	if run == nil {
+		return
+	}
+	command.RunE = func(command *cobra.Command, args []string) error {
+		if globalExplainPermissions {
+			ExplainPermissions(command.OutOrStdout(), command)
+
+			return nil
+		}
+
+		return run(command, args)
+	}
+}
+
+// splitAnnotation returns the entries of a comma-separated command annotation.
+func splitAnnotation(command *cobra.Command, key string) []string {
+	value := command.Annotations[key]
+	if value == "" {
+		return nil
+	}
+
+	return strings.Split(value, ",")
+}
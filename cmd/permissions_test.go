@@ -0,0 +1,60 @@
+package cmd_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/cmd"
+	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainPermissions(t *testing.T) {
+	tests := map[string]struct {
+		args []string
+		want []string
+	}{
+		"folders": {
+			args: []string{"folders", "--explain-permissions"},
+			want: []string{
+				"gcphelper folders requires these IAM permissions:",
+				"resourcemanager.folders.list",
+				"roles/resourcemanager.folderViewer",
+			},
+		},
+		"organizations": {
+			args: []string{"--explain-permissions", "organizations"},
+			want: []string{"resourcemanager.organizations.get", "roles/resourcemanager.organizationViewer"},
+		},
+		"folder counts": {
+			args: []string{"--explain-permissions", "report", "folder-counts"},
+			want: []string{"gcphelper report folder-counts", "resourcemanager.folders.get"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			// an unknown column would fail the command if it ran, so passing shows no API calls are made
+			args := append([]string{"--columns", "unknown"}, tt.args...)
+			var stdout bytes.Buffer
+			rootCmd.SetOut(&stdout)
+			rootCmd.SetArgs(args)
+			// registering the flags again resets the global flag values for later tests
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			require.NoError(t, rootCmd.Execute())
+			for _, want := range tt.want {
+				assert.Contains(t, stdout.String(), want)
+			}
+		})
+	}
+}
+
+func TestCommandPermissions(t *testing.T) {
+	foldersCmd := cmd.NewFoldersCommand(logger.NewNoOpLogger())
+
+	assert.Contains(t, cmd.CommandPermissions(foldersCmd), "resourcemanager.folders.list")
+	assert.Contains(t, cmd.CommandRoles(foldersCmd), "roles/resourcemanager.folderViewer")
+}
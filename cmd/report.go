@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/andreygrechin/gcphelper/internal/cleanup"
+	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/andreygrechin/gcphelper/internal/reqmeta"
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/organizations"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/andreygrechin/gcphelper/pkg/report"
+	"github.com/spf13/cobra"
+)
+
+// reportOptions holds the flag values of the report commands.
+type reportOptions struct {
+	outputFlags
+	clientFlags
+	concurrency int
+}
+
+// NewReportCommand creates and returns the report command and its subcommands.
+func NewReportCommand(log logger.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Generate summary reports across Google Cloud resources",
+	}
+
+	cmd.AddCommand(newFolderCountsCommand(log))
+
+	return cmd
+}
+
+// newFolderCountsCommand creates the "report folder-counts" command.
+func newFolderCountsCommand(log logger.Logger) *cobra.Command {
+	var opts reportOptions
+
+	cmd := &cobra.Command{
+		Use:   "folder-counts",
+		Short: "Count the folders in each accessible organization",
+		Annotations: requiresIAM(
+			[]string{"roles/resourcemanager.organizationViewer", "roles/resourcemanager.folderViewer"},
+			"resourcemanager.organizations.get", "resourcemanager.folders.get",
+		),
+		Long: `Count the folders in each accessible organization.
+
+This command lists the organizations you can access and walks each organization's
+folder hierarchy to count its folders. Organizations are counted concurrently.
+Organizations whose folders you cannot list are reported with a blank count and
+a "permission denied" note.
+
+Examples:
+  # Count folders per organization
+  gcphelper report folder-counts
+
+  # Export the counts as CSV for capacity reporting
+  gcphelper --format csv report folder-counts
+
+  # Count up to 8 organizations in parallel
+  gcphelper report folder-counts --concurrency 8`,
+		RunE: func(command *cobra.Command, _ []string) error {
+			opts.outputFlags = globalOutputFlags()
+			opts.clientFlags = globalClientFlags()
+
+			return runFolderCountsCommand(command.Context(), command.OutOrStdout(), command.ErrOrStderr(), opts, log)
+		},
+	}
+
+	cmd.Flags().IntVar(&opts.concurrency, "concurrency", report.DefaultConcurrency,
+		"Number of organizations to count in parallel")
+
+	return cmd
+}
+
+func runFolderCountsCommand(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	opts reportOptions,
+	log logger.Logger,
+) error {
+	ctx = reqmeta.WithRequestReason(ctx, opts.requestReason)
+
+	renderOpts, err := opts.renderOptions(stdout, output.ResourceTypeFolderCounts)
+	if err != nil {
+		return err
+	}
+	clientOpts, err := opts.clientOptions()
+	if err != nil {
+		return err
+	}
+	if err := validateOnly(); err != nil {
+		return err
+	}
+
+	// create organizations and folders services
+	orgService, err := organizations.NewServiceFromContextWithLogger(ctx, log, clientOpts...)
+	if err != nil {
+		return fmt.Errorf("failed to create organizations service: %w", err)
+	}
+	defer cleanup.CloseAndLog(log, "failed to close service", orgService)
+	setSpinner(orgService, !opts.noSpinner)
+
+	folderService, err := folders.NewServiceFromContextWithLogger(ctx, log, clientOpts...)
+	if err != nil {
+		return fmt.Errorf("failed to create folders service: %w", err)
+	}
+	defer cleanup.CloseAndLog(log, "failed to close service", folderService)
+	setSpinner(folderService, !opts.noSpinner)
+
+	counts, err := report.CountFoldersByOrganization(ctx, orgService, folderService, opts.concurrency)
+	if err != nil {
+		return HandleOrganizationsError(err)
+	}
+
+	// output results
+	return OutputFolderCounts(ctx, stdout, stderr, counts, renderOpts)
+}
+
+// OutputFolderCounts renders the folder count report to stdout and status messages to stderr.
+func OutputFolderCounts(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	counts []*report.FolderCount,
+	opts OutputOptions,
+) error {
+	return renderResources(ctx, stdout, stderr, counts, output.ResourceTypeFolderCounts, opts)
+}
@@ -0,0 +1,47 @@
+package cmd_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/cmd"
+	"github.com/andreygrechin/gcphelper/pkg/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputFolderCounts(t *testing.T) {
+	count := 4
+	counts := []*report.FolderCount{
+		{OrgID: "1", DisplayName: "Acme", Count: &count},
+		{OrgID: "2", DisplayName: "Denied Corp", Note: "permission denied"},
+	}
+
+	testCases := map[string]struct {
+		format  string
+		wantOut string
+	}{
+		"csv": {
+			format: "csv",
+			wantOut: "Org ID,Display Name,Folder Count,Note\n" +
+				"1,Acme,4,\n" +
+				"2,Denied Corp,,permission denied\n",
+		},
+		"jsonl": {
+			format: "jsonl",
+			wantOut: `{"org_id":"1","display_name":"Acme","folder_count":4}` + "\n" +
+				`{"org_id":"2","display_name":"Denied Corp","folder_count":null,"note":"permission denied"}` + "\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var stdout bytes.Buffer
+
+			err := cmd.OutputFolderCounts(t.Context(), &stdout, io.Discard, counts, cmd.OutputOptions{Format: tc.format})
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantOut, stdout.String())
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/andreygrechin/gcphelper/internal/cleanup"
+	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/andreygrechin/gcphelper/internal/reqmeta"
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// ErrIDFileRequired is returned when folders resolve is run without --id-file.
+var ErrIDFileRequired = errors.New("--id-file is required")
+
+// resolveOptions holds the flag values of the folders resolve command.
+type resolveOptions struct {
+	outputFlags
+	clientFlags
+	idFile      string
+	concurrency int
+}
+
+// newFoldersResolveCommand creates the "folders resolve" command.
+func newFoldersResolveCommand(log logger.Logger) *cobra.Command {
+	var opts resolveOptions
+
+	cmd := &cobra.Command{
+		Use:   "resolve --id-file FILE",
+		Short: "Show the display names of the folder IDs listed in a file",
+		Annotations: requiresIAM(
+			[]string{"roles/resourcemanager.folderViewer"},
+			"resourcemanager.folders.get",
+		),
+		Long: `Show the display names of the folder IDs listed in a file.
+
+This command reads folder IDs from --id-file, separated by whitespace, and looks
+each one up with the GetFolder API, several at a time, writing one ID and display
+name row per ID in the order of the file. IDs may be numbers or "folders/"
+resource names, and an ID listed more than once is fetched once. With "-" as the
+file, IDs are read from stdin.
+
+Folders that cannot be retrieved, for example because they do not exist or you
+lack access, do not stop the command: their row has an empty display name and a
+note in the Error column, such as "NotFound: ...".
+
+Examples:
+  # Show the display names of the folders in a file
+  gcphelper folders resolve --id-file ids.txt
+
+  # Write a CSV mapping of IDs to display names
+  gcphelper --format csv folders resolve --id-file ids.txt > folder-names.csv
+
+  # Show only the IDs that could not be resolved
+  gcphelper --filter 'display_name=""' folders resolve --id-file - < ids.txt`,
+		Args: cobra.NoArgs,
+		RunE: func(command *cobra.Command, _ []string) error {
+			opts.outputFlags = globalOutputFlags()
+			opts.clientFlags = globalClientFlags()
+
+			names, err := FolderNamesFromFile(opts.idFile, command.InOrStdin())
+			if err != nil {
+				return err
+			}
+
+			return runFoldersResolveCommand(command.Context(), command.OutOrStdout(), command.ErrOrStderr(), names, opts, log)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.idFile, "id-file", "",
+		"File with the folder IDs to resolve, separated by whitespace; '-' reads stdin")
+	cmd.Flags().IntVar(&opts.concurrency, "concurrency", folders.DefaultConcurrency,
+		"Number of folders to fetch in parallel")
+
+	return cmd
+}
+
+// FolderNamesFromFile returns the resource names of the whitespace-separated folder IDs in the file at
+// path, or read from stdin when path is "-". IDs may be numbers or "folders/" resource names.
+func FolderNamesFromFile(path string, stdin io.Reader) ([]string, error) {
+	if path == "" {
+		return nil, ErrIDFileRequired
+	}
+	if path == stdinArg {
+		return FolderNames([]string{stdinArg}, stdin)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ID file: %w", err)
+	}
+	defer file.Close()
+
+	ids, err := scanIDs(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read folder IDs from %s: %w", path, err)
+	}
+
+	return folderNamesFromIDs(ids)
+}
+
+func runFoldersResolveCommand(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	names []string,
+	opts resolveOptions,
+	log logger.Logger,
+) error {
+	ctx = reqmeta.WithRequestReason(ctx, opts.requestReason)
+
+	// validate flags before any API client is created
+	renderOpts, err := opts.renderOptions(stdout, output.ResourceTypeFolderNames)
+	if err != nil {
+		return err
+	}
+	clientOpts, err := opts.clientOptions()
+	if err != nil {
+		return err
+	}
+	if err := validateOnly(); err != nil {
+		return err
+	}
+
+	// create folders service
+	service, err := folders.NewServiceFromContextWithLogger(ctx, log, clientOpts...)
+	if err != nil {
+		return fmt.Errorf("failed to create folders service: %w", err)
+	}
+	setSpinner(service, !opts.noSpinner)
+	defer cleanup.CloseAndLog(log, "failed to close service", service)
+
+	resolutions := service.ResolveFolders(ctx, names, opts.concurrency)
+
+	// output results
+	return OutputFolderResolutions(ctx, stdout, stderr, resolutions, renderOpts)
+}
+
+// OutputFolderResolutions renders resolved folder IDs to stdout and status messages to stderr.
+func OutputFolderResolutions(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	resolutions []*folders.Resolution,
+	opts OutputOptions,
+) error {
+	return renderResources(ctx, stdout, stderr, resolutions, output.ResourceTypeFolderNames, opts)
+}
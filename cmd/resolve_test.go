@@ -0,0 +1,129 @@
+package cmd_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/cmd"
+	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFolderNamesFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ids.txt")
+	require.NoError(t, os.WriteFile(path, []byte("123\nfolders/456\n\n789 123\n"), 0o600))
+
+	testCases := map[string]struct {
+		path    string
+		stdin   string
+		want    []string
+		wantErr error
+	}{
+		"file": {
+			path: path,
+			want: []string{"folders/123", "folders/456", "folders/789", "folders/123"},
+		},
+		"stdin": {
+			path:  "-",
+			stdin: "123 456",
+			want:  []string{"folders/123", "folders/456"},
+		},
+		"missing file": {
+			path:    filepath.Join(t.TempDir(), "missing.txt"),
+			wantErr: os.ErrNotExist,
+		},
+		"no file": {
+			wantErr: cmd.ErrIDFileRequired,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := cmd.FolderNamesFromFile(tc.path, strings.NewReader(tc.stdin))
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestFoldersResolveCommandValidation(t *testing.T) {
+	invalid := filepath.Join(t.TempDir(), "ids.txt")
+	require.NoError(t, os.WriteFile(invalid, []byte("123\nprod\n"), 0o600))
+	empty := filepath.Join(t.TempDir(), "empty.txt")
+	require.NoError(t, os.WriteFile(empty, nil, 0o600))
+
+	testCases := map[string]struct {
+		args    []string
+		wantErr error
+	}{
+		"no id file": {
+			args:    []string{"folders", "resolve"},
+			wantErr: cmd.ErrIDFileRequired,
+		},
+		"invalid id": {
+			args:    []string{"folders", "resolve", "--id-file", invalid},
+			wantErr: cmd.ErrInvalidFolderID,
+		},
+		"empty file": {
+			args:    []string{"folders", "resolve", "--id-file", empty},
+			wantErr: cmd.ErrNoFolderIDs,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			rootCmd.SetOut(&bytes.Buffer{})
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs(tc.args)
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			err := rootCmd.Execute()
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestOutputFolderResolutions(t *testing.T) {
+	resolutions := []*folders.Resolution{
+		{ID: "123", Name: "folders/123", DisplayName: "Engineering"},
+		{ID: "404", Name: "folders/404", Error: "NotFound: folder not found"},
+	}
+
+	testCases := map[string]struct {
+		opts cmd.OutputOptions
+		want string
+	}{
+		"csv": {
+			opts: cmd.OutputOptions{Format: "csv"},
+			want: "ID,Display Name,Error\n123,Engineering,\n404,,NotFound: folder not found\n",
+		},
+		"json": {
+			opts: cmd.OutputOptions{Format: "json", Compact: true},
+			want: `[{"id":"123","display_name":"Engineering"},` +
+				`{"id":"404","display_name":"","error":"NotFound: folder not found"}]` + "\n",
+		},
+		"unresolved only": {
+			opts: cmd.OutputOptions{Format: "id", Filter: `display_name=""`},
+			want: "404\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			require.NoError(t, cmd.OutputFolderResolutions(t.Context(), &stdout, &bytes.Buffer{}, resolutions, tc.opts))
+			assert.Equal(t, tc.want, stdout.String())
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"sync"
+	"time"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/iam"
+	"github.com/andreygrechin/gcphelper/pkg/identity"
+	"github.com/andreygrechin/gcphelper/pkg/organizations"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/andreygrechin/gcphelper/pkg/report"
+	"github.com/andreygrechin/gcphelper/pkg/settings"
+)
+
+// registerResourceTypesOnce guards the registration of the resource types the commands render.
+var registerResourceTypesOnce sync.Once
+
+// registerResourceTypes registers the descriptor of every resource type the commands render.
+func registerResourceTypes() {
+	output.Register(output.ResourceTypeFolders, output.FolderHeaders(), output.SliceAdapter[*folders.Folder](),
+		output.ParentTypeColumn(), output.EtagColumn(), output.AgeColumn(time.Now, output.AgeUnitSeconds))
+	output.Register(output.ResourceTypeOrganizations, output.OrganizationHeaders(),
+		output.SliceAdapter[*organizations.Organization](),
+		output.EtagColumn(), output.AgeColumn(time.Now, output.AgeUnitSeconds))
+	output.Register(output.ResourceTypeFolderCounts, report.FolderCountHeaders(),
+		output.SliceAdapter[*report.FolderCount]())
+	output.Register(output.ResourceTypePermissions, iam.PermissionHeaders(),
+		output.SliceAdapter[*iam.PermissionResult]())
+	output.Register(output.ResourceTypeIdentity, identity.Headers(), output.SliceAdapter[*identity.Identity]())
+	output.Register(output.ResourceTypeFolderNames, folders.ResolutionHeaders(),
+		output.SliceAdapter[*folders.Resolution]())
+	output.Register(output.ResourceTypeSettings, settings.Headers(), output.SliceAdapter[*settings.Setting]())
+}
+
+// lookupResourceType returns the descriptor of resourceType, registering the commands' resource types on
+// first use.
+func lookupResourceType(resourceType string) (output.ResourceDescriptor, error) {
+	registerResourceTypesOnce.Do(registerResourceTypes)
+
+	return output.Lookup(resourceType)
+}
@@ -0,0 +1,104 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/cmd"
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/iam"
+	"github.com/andreygrechin/gcphelper/pkg/identity"
+	"github.com/andreygrechin/gcphelper/pkg/organizations"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/andreygrechin/gcphelper/pkg/report"
+	"github.com/andreygrechin/gcphelper/pkg/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupResourceType(t *testing.T) {
+	tests := map[string]struct {
+		name        string
+		items       any
+		wantHeaders []string
+		wantFields  []string
+		wantIDs     []string
+	}{
+		"folders": {
+			name:        output.ResourceTypeFolders,
+			items:       []*folders.Folder{{ID: "1"}, {ID: "2"}},
+			wantHeaders: []string{"ID", "Display Name", "Parent", "State", "Create Time", "Update Time"},
+			wantFields:  []string{"parent_type", "etag", output.AgeField},
+			wantIDs:     []string{"1", "2"},
+		},
+		"organizations": {
+			name:        output.ResourceTypeOrganizations,
+			items:       []*organizations.Organization{{ID: "9"}},
+			wantHeaders: []string{"ID", "Display Name", "State", "Create Time", "Update Time"},
+			wantFields:  []string{"etag", output.AgeField},
+			wantIDs:     []string{"9"},
+		},
+		"folder counts": {
+			name:        output.ResourceTypeFolderCounts,
+			items:       []*report.FolderCount{},
+			wantHeaders: report.FolderCountHeaders(),
+			wantIDs:     []string{},
+		},
+		"permissions": {
+			name:        output.ResourceTypePermissions,
+			items:       []*iam.PermissionResult{},
+			wantHeaders: iam.PermissionHeaders(),
+			wantIDs:     []string{},
+		},
+		"identity": {
+			name:        output.ResourceTypeIdentity,
+			items:       []*identity.Identity{},
+			wantHeaders: identity.Headers(),
+			wantIDs:     []string{},
+		},
+		"folder names": {
+			name:        output.ResourceTypeFolderNames,
+			items:       []*folders.Resolution{},
+			wantHeaders: folders.ResolutionHeaders(),
+			wantIDs:     []string{},
+		},
+		"settings": {
+			name:        output.ResourceTypeSettings,
+			items:       []*settings.Setting{},
+			wantHeaders: settings.Headers(),
+			wantIDs:     []string{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			desc, err := cmd.LookupResourceType(tt.name)
+			require.NoError(t, err)
+			assert.Equal(t, tt.name, desc.Name)
+			assert.Equal(t, tt.wantHeaders, desc.Headers)
+
+			fields := make([]string, 0, len(desc.Fields))
+			for _, field := range desc.Fields {
+				fields = append(fields, field.Field)
+			}
+			assert.ElementsMatch(t, tt.wantFields, fields)
+			assert.NotContains(t, desc.Headers, "Etag", "computed fields should stay out of the default headers")
+			assert.NotContains(t, desc.Headers, "Age", "computed fields should stay out of the default headers")
+
+			resources, err := desc.ToResources(tt.items)
+			require.NoError(t, err)
+			ids := make([]string, 0, len(resources))
+			for _, r := range resources {
+				ids = append(ids, r.GetID())
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
+
+func TestLookupResourceTypeParentTypeOnlyForFolders(t *testing.T) {
+	desc, err := cmd.LookupResourceType(output.ResourceTypeOrganizations)
+	require.NoError(t, err)
+
+	_, err = output.NewFieldSelector(desc.Headers, []string{"parent_type"}, desc.Fields...)
+	require.ErrorIs(t, err, output.ErrUnknownField)
+}
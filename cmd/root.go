@@ -1,11 +1,33 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/andreygrechin/gcphelper/internal/apitrace"
+	"github.com/andreygrechin/gcphelper/internal/endpoint"
+	"github.com/andreygrechin/gcphelper/internal/filelock"
+	"github.com/andreygrechin/gcphelper/internal/grpcdebug"
 	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/andreygrechin/gcphelper/internal/pager"
+	"github.com/andreygrechin/gcphelper/internal/progress"
+	"github.com/andreygrechin/gcphelper/internal/proxy"
+	"github.com/andreygrechin/gcphelper/internal/ratelimit"
+	"github.com/andreygrechin/gcphelper/internal/retry"
+	"github.com/andreygrechin/gcphelper/pkg/output"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"go.uber.org/zap"
+	"google.golang.org/api/option"
 )
 
 type VersionInfo struct {
@@ -16,10 +38,144 @@ type VersionInfo struct {
 
 // Global flags accessible to all subcommands.
 var (
-	globalFormat  string
-	globalVerbose bool
+	globalFormat         string
+	globalVerbose        bool
+	globalRequestReason  string
+	globalFilter         string
+	globalEndpoint       string
+	globalCompact        bool
+	globalClipboard      bool
+	globalColumns        string
+	globalWide           bool
+	globalFieldsFile     string
+	globalTimezone       string
+	globalTemplate       string
+	globalTemplateFile   string
+	globalIDStyle        string
+	globalGroupBy        string
+	globalCountBy        string
+	globalTruncate       int
+	globalSortBy         string
+	globalNullValue      string
+	globalHuman          bool
+	globalEnvNames       bool
+	globalAgeUnit        string
+	globalEndpointRegion string
+	globalQPS            float64
+	globalMaxRetries     int
+	globalProxy          string
+	globalOutput         string
+	globalFormatFile     string
+	globalAppend         bool
+	globalTrace          bool
+	globalAuditLog       string
+	globalNoSpinner      bool
+
+	globalExplainPermissions bool
+	globalRedactDisplayNames bool
+	globalDebugGRPC          bool
+	globalValidateOnly       bool
+	globalPager              bool
+	globalNoPager            bool
+	globalStripPrefix        string
+	globalNameRegex          string
+	globalNameReplacement    string
+
+	// globalRenamer is parsed from --strip-prefix, or --name-regex and --name-replacement, when the
+	// flags are validated; nil keeps display names as they are
+	globalRenamer *output.Renamer
 )
 
+// stdoutOutput is the --output value that writes to stdout, so scripts can always pass an output path.
+const stdoutOutput = "-"
+
+// outputFile is the file opened for --output, closed once the command finishes.
+var outputFile *os.File
+
+// outputTarget is the --output path that outputFile, a temporary file next to it, replaces once the
+// command succeeds. It is empty with --append, which writes to the --output file itself.
+var outputTarget string
+
+// appendBuffer collects the command's output with --append, merged into outputFile once the command
+// succeeds.
+var appendBuffer *bytes.Buffer
+
+// tracer records the API calls of the command when --trace is set.
+var tracer *apitrace.Recorder
+
+// enableGRPCDebug installs gRPC's verbose logger for --debug-grpc and returns a function restoring the
+// default logger.
+var enableGRPCDebug = grpcdebug.Enable
+
+// restoreGRPCLogging restores gRPC's default logger once a command run with --debug-grpc finishes.
+var restoreGRPCLogging func()
+
+// newSpinner creates the progress spinners shown by the services while fetching; nil keeps their
+// terminal spinner.
+var newSpinner func(suffix string) progress.Spinner
+
+// ErrProjectionWithColumns is returned when a gcloud-style --format projection is combined with
+// --columns or --fields-file, which select fields too.
+var ErrProjectionWithColumns = errors.New("cannot combine a --format projection with --columns or --fields-file")
+
+// ErrWideWithColumns is returned when --wide is combined with --columns, --fields-file or a --format
+// projection, which select fields too.
+var ErrWideWithColumns = errors.New("cannot combine --wide with --columns, --fields-file or a --format projection")
+
+// ErrNameReplacementWithoutRegex is returned when --name-replacement is set without --name-regex.
+var ErrNameReplacementWithoutRegex = errors.New("--name-replacement requires --name-regex")
+
+// runStart is when the running command's flags were validated, for the elapsed time in the verbose
+// JSON summary.
+var runStart time.Time
+
+// errValidationPassed ends a command run with --validate-only once its flags are valid, before any API client
+// is created or input file is read. ExecuteCommand reports it as success.
+var errValidationPassed = errors.New("validation passed")
+
+// ErrPanic is returned when a command panics, with the panic value in the message.
+var ErrPanic = errors.New("internal error: command panicked")
+
+// ErrOutputWithClipboard is returned when --output is combined with --clipboard.
+var ErrOutputWithClipboard = errors.New("cannot combine --output with --clipboard")
+
+// ErrAppendWithoutOutput is returned when --append is set without --output.
+var ErrAppendWithoutOutput = errors.New("--append requires --output")
+
+// ErrInvalidFormatFile is returned when the --format-file file is empty or names an unsupported format.
+var ErrInvalidFormatFile = errors.New("invalid format file")
+
+// formatFlag is the --format flag value: a format name or a gcloud-style projection such as
+// "value(id,displayName)". It rejects unsupported formats while flags are parsed, so that an invalid
+// format fails before any API call, with an error wrapping ErrUnsupportedOutputFormat. The graph formats
+// are accepted here and checked against the command in PersistentPreRunE.
+type formatFlag string
+
+// String returns the selected format.
+func (f *formatFlag) String() string {
+	return string(*f)
+}
+
+// Set validates and selects a format or projection.
+func (f *formatFlag) Set(spec string) error {
+	if output.IsGraphFormat(output.Format(strings.TrimSpace(spec))) {
+		*f = formatFlag(strings.TrimSpace(spec))
+
+		return nil
+	}
+	if _, err := output.ParseProjection(spec); err != nil {
+		return err
+	}
+	*f = formatFlag(strings.TrimSpace(spec))
+
+	return nil
+}
+
+// Type returns the flag's value type shown in help output.
+func (f *formatFlag) Type() string {
+	return "string"
+}
+
 // NewRootCommand creates and returns the root command.
 func NewRootCommand(v VersionInfo, log logger.Logger) *cobra.Command {
 	rootCmd := &cobra.Command{
@@ -32,25 +188,582 @@ Features:
 - List all projects in an organization
 - List all folders in an organization
 - List all accessible organizations
+- Report folder counts per organization
+- Check API access with the doctor command
 
 The tool uses Application Default Credentials for authentication.
 Make sure you have authenticated with Google Cloud using:
   gcloud auth application-default login`,
+		PersistentPreRunE: func(command *cobra.Command, args []string) error {
+			auditCommand = command
+			if err := validateGlobalFlags(command, args); err != nil {
+				return err
+			}
+			startTrace(log)
+			startGRPCDebug(command.ErrOrStderr())
+
+			return nil
+		},
+		PersistentPostRunE: func(*cobra.Command, []string) error {
+			finishTrace()
+			finishGRPCDebug()
+
+			return closeOutput()
+		},
 	}
 
 	rootCmd.AddCommand(NewFoldersCommand(log))
 	rootCmd.AddCommand(NewOrganizationsCommand(log))
+	rootCmd.AddCommand(NewReportCommand(log))
+	rootCmd.AddCommand(NewIAMCommand(log))
+	rootCmd.AddCommand(NewDoctorCommand(log))
+	rootCmd.AddCommand(NewAuthCommand(log))
+	rootCmd.AddCommand(NewConfigCommand(log))
 
 	rootCmd.Version = fmt.Sprintf("\n  Version: %s\n  Commit: %s\n  Built: %s", v.Version, v.Commit, v.BuildTime)
 
-	// Add global persistent flags
-	rootCmd.PersistentFlags().StringVarP(&globalFormat, "format", "f", "table", "Output format (table, json, csv, id)")
-	rootCmd.PersistentFlags().BoolVarP(&globalVerbose, "verbose", "v", false,
-		"Show additional output like counts and status messages")
+	registerFormatFlags(rootCmd.PersistentFlags())
+	registerDisplayNameFlags(rootCmd.PersistentFlags())
+	registerClientFlags(rootCmd.PersistentFlags())
+	registerRunFlags(rootCmd.PersistentFlags())
+
+	// answer --explain-permissions before any command validates flags or calls an API
+	explainPermissionsOnFlag(rootCmd)
+	generateExamples(rootCmd)
 
 	return rootCmd
 }
 
+// registerFormatFlags registers the global flags choosing the output format, fields and destination.
+func registerFormatFlags(flags *pflag.FlagSet) {
+	globalFormat = string(output.FormatTable)
+	flags.VarP((*formatFlag)(&globalFormat), "format", "f",
+		"Output format (table, json, jsonl, bq, csv, id, value, template, rawjson, env; dot and mermaid for folders graph), "+
+			"or a gcloud projection like 'value(id)'")
+	flags.StringVar(&globalFormatFile, "format-file", "",
+		"Read the output format, or a projection, from this file unless --format is set")
+	flags.StringVar(&globalOutput, "output", "",
+		"Write the output to this file, or to stdout for '-'; its extension (.json, .jsonl, .csv) selects the format "+
+			"unless --format is set")
+	flags.BoolVar(&globalAppend, "append", false,
+		"Add the output to the existing --output file: JSON arrays are merged and CSV keeps a single header")
+	flags.BoolVar(&globalCompact, "compact", false,
+		"Write JSON without indentation (jsonl is always compact)")
+	flags.BoolVar(&globalClipboard, "clipboard", false,
+		"Copy the formatted output to the system clipboard instead of writing it to stdout")
+	flags.StringVar(&globalColumns, "columns", "",
+		"Comma-separated fields to output, in order, e.g. 'id,display_name,state'")
+	flags.StringVar(&globalFieldsFile, "fields-file", "",
+		"Read the fields to output from a file with one or more comma-separated names per line")
+	flags.BoolVar(&globalWide, "wide", false,
+		"Show every field in table and CSV output, including Name and computed fields (same as --columns all)")
+	flags.StringVar(&globalTimezone, "timezone", "",
+		"Render timestamps in this IANA timezone, e.g. America/New_York (default: UTC)")
+	flags.StringVar(&globalTemplate, "template", "",
+		"Render output with this Go text/template, executed once with the list of resources")
+	flags.StringVar(&globalTemplateFile, "template-file", "",
+		"Render output with the Go text/template in this file")
+	flags.StringVar(&globalIDStyle, "id-style", string(output.IDStyleShort),
+		"How IDs are written in id output and the ID column: short (123) or full (folders/123)")
+	flags.StringVar(&globalGroupBy, "group-by", "",
+		"Group table output by a field (state, parent), with one titled table and row count per group")
+	flags.StringVar(&globalCountBy, "count-by", "",
+		"Output the number of resources per value of a field (state, parent, parent_type) instead of listing them")
+	flags.StringVar(&globalSortBy, "sort-by", "",
+		"Sort by comma-separated fields, later ones breaking ties; add :desc to reverse one, e.g. 'state,createTime:desc'")
+	flags.StringVar(&globalNullValue, "null-value", "",
+		"Text written for missing values and unset timestamps in table, CSV and value output (default: empty)")
+	flags.BoolVar(&globalHuman, "human", false,
+		"Group the digits of counts in tables, group titles and the verbose summary, e.g. 12,345")
+	flags.BoolVar(&globalEnvNames, "env-names", false,
+		"Name the variables of env output after display names, e.g. FOLDER_FINANCE, instead of numbering them")
+	flags.IntVar(&globalTruncate, "truncate", 0,
+		"Shorten table cells longer than this many characters, ending them with an ellipsis (default: no limit)")
+	flags.StringVar(&globalAgeUnit, "age-unit", string(output.AgeUnitSeconds),
+		"Unit of the age field in JSON output: seconds or days (tables show a humanized age such as 3d)")
+	flags.BoolVar(&globalPager, "pager", false,
+		"Page the output through $PAGER (default: less -R) even when it fits the terminal or stdout is redirected")
+	flags.BoolVar(&globalNoPager, "no-pager", false,
+		"Never page the output, even when it is longer than the terminal")
+
+	setFlagExample(flags, "format", string(output.FormatJSON))
+}
+
+// registerDisplayNameFlags registers the global flags filtering the listed resources and rewriting their
+// display names.
+func registerDisplayNameFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&globalFilter, "filter", "",
+		"Client-side filter, e.g. 'state=ACTIVE AND (displayName~prod OR id=123)'")
+	flags.BoolVar(&globalRedactDisplayNames, "redact-display-names", false,
+		"Replace display names with a deterministic hash in every format, keeping IDs, e.g. to share output")
+	flags.StringVar(&globalStripPrefix, "strip-prefix", "",
+		"Remove this prefix from display names in every format, e.g. a cost-center code like 'CC1234-'")
+	flags.StringVar(&globalNameRegex, "name-regex", "",
+		"Replace the matches of this regular expression in display names with --name-replacement in every format")
+	flags.StringVar(&globalNameReplacement, "name-replacement", "",
+		"Replacement for --name-regex matches; $1 or ${name} insert submatches (default: remove the matches)")
+
+	setFlagExample(flags, "filter", "state=ACTIVE")
+}
+
+// registerClientFlags registers the global flags configuring the API clients.
+func registerClientFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&globalNoSpinner, "no-spinner", false,
+		"Never show the progress spinner, even on a terminal; status messages and logs are unaffected")
+	flags.StringVar(&globalRequestReason, "request-reason", "",
+		"Justification sent with API calls in the x-goog-request-reason header")
+	flags.StringVar(&globalEndpointRegion, "endpoint-region", "",
+		"Route API calls through a regional endpoint ("+strings.Join(endpoint.Regions(), ", ")+")")
+	flags.StringVar(&globalEndpoint, "endpoint", "",
+		"Raw API endpoint override (host:port), e.g. for Private Service Connect")
+	flags.Float64Var(&globalQPS, "qps", 0,
+		"Maximum API calls per second, shared by all concurrent requests (default: unlimited)")
+	flags.IntVar(&globalMaxRetries, "max-retries", retry.DefaultMaxRetries,
+		"Retries of API calls rejected for exhausted quota, waiting the server's suggested delay; 0 disables them")
+	flags.StringVar(&globalProxy, "proxy", "",
+		"Route API calls through this HTTP proxy, e.g. http://proxy:3128 (default: the HTTPS_PROXY variable)")
+	flags.BoolVar(&globalTrace, "trace", false,
+		"Log each API call's method, parent or query, start and duration at debug level, and a summary at the end")
+}
+
+// registerRunFlags registers the global flags controlling how a command runs and reports on its run.
+func registerRunFlags(flags *pflag.FlagSet) {
+	flags.BoolVarP(&globalVerbose, "verbose", "v", false,
+		"Show additional output like counts and status messages")
+	flags.StringVar(&globalAuditLog, "audit-log", "",
+		"Append a JSON line recording the command, its flags, result count, duration and error to this file")
+	flags.BoolVar(&globalExplainPermissions, "explain-permissions", false,
+		"Print the IAM permissions and roles the command needs instead of running it")
+	flags.BoolVar(&globalValidateOnly, "validate-only", false,
+		"Validate the flags and exit without calling any API, reading --from-file or writing --output")
+	flags.BoolVar(&globalDebugGRPC, "debug-grpc", false,
+		"Write gRPC's own verbose logs to stderr for deep debugging of API connections")
+	flags.Lookup("debug-grpc").Hidden = true
+}
+
+// validateGlobalFlags checks the global flags before any subcommand runs, so that invalid input fails
+// without a network round-trip. The --format value is also checked while flags are parsed; checking it
+// again here covers values that did not come through the flag parser.
+func validateGlobalFlags(command *cobra.Command, _ []string) error {
+	runStart = time.Now()
+	if globalValidateOnly {
+		// failures are reported by ExecuteCommand, and success ends the run without output
+		command.SilenceErrors = true
+		command.SilenceUsage = true
+	}
+	if err := CheckFlagConflicts(command.Flags()); err != nil {
+		return err
+	}
+
+	format, explicit := globalFormat, command.Flags().Changed("format")
+	if !explicit && globalFormatFile != "" {
+		var err error
+		if format, err = ReadFormatFile(globalFormatFile); err != nil {
+			return err
+		}
+		explicit = true
+	}
+	format, err := ResolveOutputFormat(format, explicit, globalOutput)
+	if err != nil {
+		return err
+	}
+	globalFormat = format
+	silenceHumanErrors(command)
+	if err := checkGraphFormat(command, output.Format(globalFormat)); err != nil {
+		return err
+	}
+
+	if !output.IsGraphFormat(output.Format(globalFormat)) {
+		projection, err := output.ParseProjection(globalFormat)
+		if err != nil {
+			return err
+		}
+		if err := applyProjection(projection); err != nil {
+			return err
+		}
+	}
+	if err := applyWide(); err != nil {
+		return err
+	}
+	if err := parseRenamer(); err != nil {
+		return err
+	}
+	if strings.TrimSpace(globalFilter) != "" {
+		if _, err := output.ParseFilter(globalFilter); err != nil {
+			return fmt.Errorf("invalid --filter: %w", err)
+		}
+	}
+
+	return openOutput(command)
+}
+
+// ReadFormatFile returns the format or projection named in the file at path, such as one written by an
+// upstream pipeline step, with surrounding whitespace removed. It is validated like a --format value.
+func ReadFormatFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read format file: %w", err)
+	}
+
+	var format formatFlag
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("%w %s: no format given", ErrInvalidFormatFile, path)
+	}
+	if err := format.Set(token); err != nil {
+		return "", fmt.Errorf("%w %s: %w", ErrInvalidFormatFile, path, err)
+	}
+
+	return format.String(), nil
+}
+
+// ResolveOutputFormat returns the output format to use. When the format was not set explicitly, the
+// extension of the --output path selects it; an explicit format always wins. Paths with an unknown
+// extension keep the format.
+func ResolveOutputFormat(format string, explicit bool, path string) (string, error) {
+	if explicit || path == "" {
+		return format, nil
+	}
+
+	inferred, ok := output.FormatFromExtension(path)
+	if !ok {
+		return format, nil
+	}
+	if output.IsGraphFormat(inferred) {
+		return string(inferred), nil
+	}
+	if _, err := output.ParseFormat(string(inferred)); err != nil {
+		return "", fmt.Errorf("cannot infer the format of --output %s, set --format instead: %w", path, err)
+	}
+
+	return string(inferred), nil
+}
+
+// openOutput creates a temporary file for the --output file, if set, and makes it the command's output
+// writer; closeOutput renames it over the --output file, so that a failed command leaves an existing
+// file unchanged. With --append the file is opened without truncating it and locked until the command
+// finishes, and the output is collected in appendBuffer to be merged into the file by closeOutput. An --output of "-"
+// keeps the command's own stdout, to which output is always added.
+func openOutput(command *cobra.Command) error {
+	if globalOutput == "" {
+		if globalAppend {
+			return ErrAppendWithoutOutput
+		}
+
+		return nil
+	}
+	if globalOutput == stdoutOutput || globalValidateOnly {
+		return nil
+	}
+	if !globalAppend {
+		file, err := createOutputTemp(globalOutput)
+		if err != nil {
+			return err
+		}
+		outputFile = file
+		outputTarget = globalOutput
+		command.SetOut(file)
+
+		return nil
+	}
+
+	file, err := os.OpenFile(globalOutput, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open output file: %w", err)
+	}
+	// concurrent runs appending to the same file wait for each other
+	if err := filelock.Lock(file); err != nil {
+		return errors.Join(err, file.Close())
+	}
+	outputFile = file
+	appendBuffer = new(bytes.Buffer)
+	command.SetOut(appendBuffer)
+
+	return nil
+}
+
+// createOutputTemp creates the temporary file replacing path, in the same directory so that it can be
+// renamed over path. It keeps the permissions of an existing file at path.
+func createOutputTemp(path string) (*os.File, error) {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open output file: %w", err)
+	}
+	if info, statErr := os.Stat(path); statErr == nil {
+		if err := file.Chmod(info.Mode().Perm()); err != nil {
+			err = fmt.Errorf("failed to open output file: %w", err)
+
+			return nil, errors.Join(err, file.Close(), os.Remove(file.Name()))
+		}
+	}
+
+	return file, nil
+}
+
+// closeOutput closes the --output file, if one is open, first merging the output collected with
+// --append into it, or renaming the temporary file over it.
+func closeOutput() error {
+	if outputFile == nil {
+		return nil
+	}
+
+	var appendErr error
+	if appendBuffer != nil {
+		appendErr = appendOutput(outputFile, appendBuffer.Bytes())
+	}
+	file, target := outputFile, outputTarget
+	err := file.Close()
+	outputFile = nil
+	outputTarget = ""
+	appendBuffer = nil
+	if err != nil {
+		err = fmt.Errorf("failed to close output file: %w", err)
+	}
+	if target == "" {
+		return errors.Join(appendErr, err)
+	}
+
+	if err == nil {
+		err = os.Rename(file.Name(), target)
+	}
+	if err != nil {
+		return errors.Join(fmt.Errorf("failed to write output file: %w", err), os.Remove(file.Name()))
+	}
+
+	return nil
+}
+
+// discardOutput drops the output of a failed command, leaving an existing --output file unchanged: the
+// output collected with --append is dropped, and the temporary file is removed.
+func discardOutput() error {
+	appendBuffer = nil
+	if outputTarget == "" {
+		return nil
+	}
+
+	file := outputFile
+	outputFile = nil
+	outputTarget = ""
+	if err := errors.Join(file.Close(), os.Remove(file.Name())); err != nil {
+		return fmt.Errorf("failed to remove temporary output file: %w", err)
+	}
+
+	return nil
+}
+
+// appendOutput rewrites file with its existing contents merged with added in the selected format.
+func appendOutput(file *os.File, added []byte) error {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read output file: %w", err)
+	}
+	existing, err := io.ReadAll(file)
+	if err != nil {
+		return fmt.Errorf("failed to read output file: %w", err)
+	}
+
+	merged, err := output.AppendOutput(existing, added, output.Format(globalFormat), globalCompact)
+	if err != nil {
+		return err
+	}
+
+	if err := file.Truncate(0); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	if _, err := file.WriteAt(merged, 0); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	return nil
+}
+
+// startTrace starts recording API calls when --trace is set.
+func startTrace(log logger.Logger) {
+	if globalTrace {
+		tracer = apitrace.NewRecorder(log)
+	}
+}
+
+// startGRPCDebug installs gRPC's verbose logger writing to stderr when --debug-grpc is set, before any
+// client is created.
+func startGRPCDebug(stderr io.Writer) {
+	if globalDebugGRPC {
+		restoreGRPCLogging = enableGRPCDebug(stderr)
+	}
+}
+
+// finishGRPCDebug restores gRPC's default logger if --debug-grpc replaced it.
+func finishGRPCDebug() {
+	if restoreGRPCLogging == nil {
+		return
+	}
+
+	restoreGRPCLogging()
+	restoreGRPCLogging = nil
+}
+
+// finishTrace logs the summary of the recorded API calls, if any are being recorded.
+func finishTrace() {
+	if tracer == nil {
+		return
+	}
+
+	tracer.LogSummary()
+	tracer = nil
+}
+
+// applyProjection replaces a gcloud-style --format projection with its format and passes its fields
+// on as --columns, so that commands select them like any other column list.
+func applyProjection(projection output.Projection) error {
+	if len(projection.Fields) > 0 {
+		if globalColumns != "" || globalFieldsFile != "" {
+			return ErrProjectionWithColumns
+		}
+		globalColumns = strings.Join(projection.Fields, ",")
+	}
+	globalFormat = string(projection.Format)
+
+	return nil
+}
+
+// parseRenamer parses the display name renamer of --strip-prefix, or of --name-regex and
+// --name-replacement, so that an invalid regular expression fails before any API call.
+func parseRenamer() error {
+	globalRenamer = nil
+	switch {
+	case globalStripPrefix != "":
+		globalRenamer = output.StripPrefix(globalStripPrefix)
+	case globalNameRegex != "":
+		renamer, err := output.NewRenamer(globalNameRegex, globalNameReplacement)
+		if err != nil {
+			return err
+		}
+		globalRenamer = renamer
+	case globalNameReplacement != "":
+		return ErrNameReplacementWithoutRegex
+	}
+
+	return nil
+}
+
+// applyWide selects every field with --wide for table and CSV output, so that commands show them like
+// any other column list. Counts keep their own columns, and other formats keep their default fields.
+func applyWide() error {
+	if !globalWide {
+		return nil
+	}
+	if globalColumns != "" || globalFieldsFile != "" {
+		return ErrWideWithColumns
+	}
+	format := output.Format(globalFormat)
+	if globalCountBy == "" && (format == output.FormatTable || format == output.FormatCSV) {
+		globalColumns = output.AllFields
+	}
+
+	return nil
+}
+
+// clientOptions returns the API client options for the endpoint, retry, rate limit and proxy flags, tracing
+// every call when --trace is set. Clients created with the same options share one rate limiter.
+func clientOptions(
+	endpointRegion, endpointOverride string,
+	qps float64,
+	maxRetries int,
+	proxyURL string,
+) ([]option.ClientOption, error) {
+	endpointOpts, err := endpoint.ClientOptions(endpointRegion, endpointOverride)
+	if err != nil {
+		return nil, err
+	}
+
+	retryOpts, err := retry.ClientOptions(maxRetries)
+	if err != nil {
+		return nil, err
+	}
+
+	limitOpts, err := ratelimit.ClientOptions(qps)
+	if err != nil {
+		return nil, err
+	}
+
+	proxyOpts, err := proxy.ClientOptions(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// retries wrap the rate limiter, so every attempt waits on it
+	clientOpts := append(append(append(endpointOpts, retryOpts...), limitOpts...), proxyOpts...)
+	if tracer != nil {
+		// traced after waiting on the rate limiter, so durations measure the calls themselves
+		clientOpts = append(clientOpts, tracer.ClientOptions()...)
+	}
+
+	return clientOpts, nil
+}
+
+// clientFlags holds the global flags that configure the API clients and the requests sent with them.
+type clientFlags struct {
+	requestReason  string
+	endpointRegion string
+	endpoint       string
+	qps            float64
+	maxRetries     int
+	proxy          string
+	noSpinner      bool
+}
+
+// globalClientFlags returns the client flags set on the root command.
+func globalClientFlags() clientFlags {
+	return clientFlags{
+		requestReason:  globalRequestReason,
+		endpointRegion: globalEndpointRegion,
+		endpoint:       globalEndpoint,
+		qps:            globalQPS,
+		maxRetries:     globalMaxRetries,
+		proxy:          globalProxy,
+		noSpinner:      globalNoSpinner,
+	}
+}
+
+// clientOptions validates the flags and returns the options to create the API clients with.
+func (f clientFlags) clientOptions() ([]option.ClientOption, error) {
+	return clientOptions(f.endpointRegion, f.endpoint, f.qps, f.maxRetries, f.proxy)
+}
+
+// spinnerService is a service showing a progress spinner while fetching.
+type spinnerService interface {
+	SetSpinner(enabled bool)
+	SetSpinnerFactory(newSpinner func(suffix string) progress.Spinner)
+}
+
+// setSpinner enables or disables the progress spinner of service, created with newSpinner.
+func setSpinner(service spinnerService, enabled bool) {
+	service.SetSpinner(enabled)
+	service.SetSpinnerFactory(newSpinner)
+}
+
+// pagerMode returns when output is paged according to the --pager and --no-pager flags.
+func pagerMode() pager.Mode {
+	switch {
+	case globalPager:
+		return pager.Always
+	case globalNoPager:
+		return pager.Never
+	default:
+		return pager.Auto
+	}
+}
+
+// validateOnly returns errValidationPassed when --validate-only is set, to end the command before it looks up
+// credentials, creates an API client or reads an input file. Every runner calls it once all its flags are
+// validated.
+func validateOnly() error {
+	if globalValidateOnly {
+		return errValidationPassed
+	}
+
+	return nil
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 // Returns an exit code: 0 for success, 1 for error.
@@ -68,9 +781,50 @@ func Execute(v VersionInfo) int {
 		}
 	}()
 
-	rootCmd := NewRootCommand(v, log)
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "error executing root command: %v\n", err)
+	// cancel the running command on Ctrl-C or SIGTERM, so that API calls, the spinner and the pager stop
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return ExecuteCommand(ctx, NewRootCommand(v, log), log, os.Stderr)
+}
+
+// ExecuteCommand runs rootCmd with ctx and reports a failure on stderr, returning the exit code. A panic in the
+// command is logged on log with its stack and reported as a failure wrapping ErrPanic instead of
+// crashing the process, so that deferred cleanup, including closing log, still runs.
+func ExecuteCommand(ctx context.Context, rootCmd *cobra.Command, log logger.Logger, stderr io.Writer) (exitCode int) {
+	start := time.Now()
+	resetAudit()
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+		log.Error("command panicked", zap.Any("panic", recovered), zap.Stack("stack"))
+		// clean up like a failed command
+		finishTrace()
+		finishGRPCDebug()
+		err := errors.Join(fmt.Errorf("%w: %v", ErrPanic, recovered), discardOutput(), closeOutput())
+		err = errors.Join(err, writeAuditLog(auditCommand, start, err))
+		WriteError(stderr, err, globalFormat)
+		exitCode = 1
+	}()
+
+	command, err := rootCmd.ExecuteContextC(ctx)
+	if errors.Is(err, errValidationPassed) {
+		err = nil
+	}
+	// summarize the trace and close the --output file even when the command fails and skips its post-run hook;
+	// a failed command appends nothing
+	finishTrace()
+	finishGRPCDebug()
+	if err != nil {
+		err = errors.Join(err, discardOutput())
+	}
+	err = errors.Join(err, closeOutput())
+	// the audit record is written last, so that it records output errors too
+	err = errors.Join(err, writeAuditLog(command, start, err))
+	if err != nil {
+		WriteError(stderr, err, globalFormat)
 
 		return 1
 	}
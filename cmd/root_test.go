@@ -0,0 +1,833 @@
+package cmd_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/cmd"
+	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRootCommandFormatFlag(t *testing.T) {
+	tests := map[string]struct {
+		args    []string
+		wantErr error
+	}{
+		"unsupported long flag": {
+			args:    []string{"--format", "xml", "folders"},
+			wantErr: output.ErrUnsupportedOutputFormat,
+		},
+		"unsupported short flag after subcommand": {
+			args:    []string{"organizations", "-f", "yaml"},
+			wantErr: output.ErrUnsupportedOutputFormat,
+		},
+		"supported format": {
+			args: []string{"--format", "jsonl", "folders", "--explain-permissions"},
+		},
+		"gcloud value projection": {
+			args: []string{"--format", "value(id,displayName)", "folders", "--explain-permissions"},
+		},
+		"gcloud table projection": {
+			args: []string{"--format=table(id, state)", "organizations", "--explain-permissions"},
+		},
+		"unknown projection wrapper": {
+			args:    []string{"--format", "yaml(id)", "folders"},
+			wantErr: output.ErrUnsupportedOutputFormat,
+		},
+		"projection with columns": {
+			args:    []string{"--format", "value(id)", "--columns", "state", "folders"},
+			wantErr: cmd.ErrProjectionWithColumns,
+		},
+		"projection of all fields": {
+			args: []string{"--format", "table(all)", "folders", "--explain-permissions"},
+		},
+		"wide with projection": {
+			args:    []string{"--format", "table(id)", "--wide", "folders"},
+			wantErr: cmd.ErrWideWithColumns,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			rootCmd.SetOut(&bytes.Buffer{})
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs(tt.args)
+			// registering the flags again resets the global flag values for later tests
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			err := rootCmd.Execute()
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Contains(t, err.Error(), "--format")
+
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRootCommandInvalidFormatSkipsServices(t *testing.T) {
+	// unusable credentials make constructing a service fail, so the format error shows that none was constructed
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", filepath.Join(t.TempDir(), "missing.json"))
+
+	tests := map[string]struct {
+		format          string
+		wantFormatError bool
+	}{
+		"invalid format fails before services": {format: "xml", wantFormatError: true},
+		"valid format reaches services":        {format: "json", wantFormatError: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			rootCmd.SetOut(&bytes.Buffer{})
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs([]string{"--format", tt.format, "organizations"})
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			err := rootCmd.Execute()
+			require.Error(t, err)
+			if tt.wantFormatError {
+				require.ErrorIs(t, err, output.ErrUnsupportedOutputFormat)
+				assert.NotContains(t, err.Error(), "service")
+
+				return
+			}
+			require.NotErrorIs(t, err, output.ErrUnsupportedOutputFormat)
+			assert.Contains(t, err.Error(), "failed to create organizations service")
+		})
+	}
+}
+
+func TestResolveOutputFormat(t *testing.T) {
+	tests := map[string]struct {
+		format   string
+		explicit bool
+		path     string
+		want     string
+		wantErr  error
+	}{
+		"no output file":     {format: "table", want: "table"},
+		"json extension":     {format: "table", path: "folders.json", want: "json"},
+		"jsonl extension":    {format: "table", path: "folders.jsonl", want: "jsonl"},
+		"csv extension":      {format: "table", path: "out/folders.CSV", want: "csv"},
+		"yaml extension":     {format: "table", path: "folders.yaml", wantErr: output.ErrUnsupportedOutputFormat},
+		"yml extension":      {format: "table", path: "folders.yml", wantErr: output.ErrUnsupportedOutputFormat},
+		"tsv extension":      {format: "table", path: "folders.tsv", wantErr: output.ErrUnsupportedOutputFormat},
+		"unknown extension":  {format: "table", path: "folders.txt", want: "table"},
+		"explicit format":    {format: "jsonl", explicit: true, path: "folders.csv", want: "jsonl"},
+		"explicit with yaml": {format: "json", explicit: true, path: "folders.yaml", want: "json"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := cmd.ResolveOutputFormat(tt.format, tt.explicit, tt.path)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReadFormatFile(t *testing.T) {
+	tests := map[string]struct {
+		content string
+		want    string
+		wantErr error
+	}{
+		"format name with surrounding whitespace": {
+			content: "  json\n",
+			want:    "json",
+		},
+		"projection": {
+			content: "value(id)\n",
+			want:    "value(id)",
+		},
+		"unsupported format": {
+			content: "xml\n",
+			wantErr: output.ErrUnsupportedOutputFormat,
+		},
+		"empty file": {
+			content: " \n",
+			wantErr: cmd.ErrInvalidFormatFile,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "FORMAT")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			got, err := cmd.ReadFormatFile(path)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				require.ErrorIs(t, err, cmd.ErrInvalidFormatFile)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRootCommandFormatFile(t *testing.T) {
+	tests := map[string]struct {
+		args       func(formatFile, outputFile string) []string
+		wantStdout string
+		wantFile   string
+	}{
+		"format file selects the format": {
+			args: func(formatFile, _ string) []string {
+				return []string{"--format-file", formatFile, "config", "view"}
+			},
+			wantStdout: `"name": "format"`,
+		},
+		"explicit format wins": {
+			args: func(formatFile, _ string) []string {
+				return []string{"--format", "csv", "--format-file", formatFile, "config", "view"}
+			},
+			wantStdout: "Name,Value,Source",
+		},
+		"format file wins over the output extension": {
+			args: func(formatFile, outputFile string) []string {
+				return []string{"--format-file", formatFile, "--output", outputFile, "config", "view"}
+			},
+			wantFile: `"name": "format"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			formatFile := filepath.Join(dir, "FORMAT")
+			require.NoError(t, os.WriteFile(formatFile, []byte("json\n"), 0o600))
+			outputFile := filepath.Join(dir, "settings.csv")
+
+			var stdout bytes.Buffer
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			rootCmd.SetOut(&stdout)
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs(tt.args(formatFile, outputFile))
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			require.NoError(t, rootCmd.Execute())
+			if tt.wantFile != "" {
+				data, err := os.ReadFile(outputFile)
+				require.NoError(t, err)
+				assert.Contains(t, string(data), tt.wantFile)
+
+				return
+			}
+			assert.Contains(t, stdout.String(), tt.wantStdout)
+		})
+	}
+}
+
+func TestRootCommandOutputFile(t *testing.T) {
+	tests := map[string]struct {
+		args     func(path string) []string
+		file     string
+		wantErr  error
+		wantFile string
+	}{
+		"writes command output to the file": {
+			args: func(path string) []string {
+				return []string{"--output", path, "organizations", "--explain-permissions"}
+			},
+			file:     "permissions.csv",
+			wantFile: "resourcemanager.organizations.get",
+		},
+		"unsupported extension fails before creating the file": {
+			args:    func(path string) []string { return []string{"--output", path, "organizations"} },
+			file:    "organizations.yaml",
+			wantErr: output.ErrUnsupportedOutputFormat,
+		},
+		"clipboard conflicts with output": {
+			args:    func(path string) []string { return []string{"--output", path, "--clipboard", "organizations"} },
+			file:    "organizations.json",
+			wantErr: cmd.ErrOutputWithClipboard,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			var stdout bytes.Buffer
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			rootCmd.SetOut(&stdout)
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs(tt.args(path))
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			err := rootCmd.Execute()
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.NoFileExists(t, path)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Empty(t, stdout.String())
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Contains(t, string(data), tt.wantFile)
+		})
+	}
+}
+
+func TestExecuteCommandOutputFileOnFailure(t *testing.T) {
+	input := writeFolderExport(t, []*folders.Folder{{ID: "1", Name: "folders/1", State: "ACTIVE"}})
+
+	tests := map[string]struct {
+		existing string
+		input    string
+		wantCode int
+		wantFile string
+	}{
+		"failed run leaves the existing file unchanged": {
+			existing: "previous\n",
+			input:    filepath.Join(t.TempDir(), "missing.json"),
+			wantCode: 1,
+			wantFile: "previous\n",
+		},
+		"failed run creates no file": {
+			input:    filepath.Join(t.TempDir(), "missing.json"),
+			wantCode: 1,
+		},
+		"successful run replaces the existing file": {
+			existing: "previous\n",
+			input:    input,
+			wantFile: "1\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "folders.txt")
+			if tt.existing != "" {
+				require.NoError(t, os.WriteFile(path, []byte(tt.existing), 0o600))
+			}
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			rootCmd.SetOut(&bytes.Buffer{})
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs([]string{"--format", "id", "--output", path, "folders", "--from-file", tt.input})
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			assert.Equal(t, tt.wantCode, cmd.ExecuteCommand(t.Context(), rootCmd, logger.NewNoOpLogger(), &bytes.Buffer{}))
+			entries, err := os.ReadDir(dir)
+			require.NoError(t, err)
+			if tt.wantFile == "" {
+				assert.Empty(t, entries, "no temporary file is left behind")
+
+				return
+			}
+			require.Len(t, entries, 1, "no temporary file is left behind")
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFile, string(data))
+		})
+	}
+}
+
+func TestRootCommandOutputStdout(t *testing.T) {
+	tests := map[string]struct {
+		args []string
+	}{
+		"output to stdout": {
+			args: []string{"--output", "-", "organizations", "--explain-permissions"},
+		},
+		"append to stdout": {
+			args: []string{"--output", "-", "--append", "organizations", "--explain-permissions"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			// a file named "-" would be created in the working directory
+			dir := t.TempDir()
+			t.Chdir(dir)
+			var stdout bytes.Buffer
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			rootCmd.SetOut(&stdout)
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs(tt.args)
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			require.NoError(t, rootCmd.Execute())
+			assert.Contains(t, stdout.String(), "resourcemanager.organizations.get")
+			assert.NoFileExists(t, filepath.Join(dir, "-"))
+		})
+	}
+}
+
+func TestRootCommandAppendOutput(t *testing.T) {
+	first := writeFolderExport(t, []*folders.Folder{{ID: "1", Name: "folders/1", State: "ACTIVE"}})
+	second := writeFolderExport(t, []*folders.Folder{{ID: "2", Name: "folders/2", State: "ACTIVE"}})
+
+	tests := map[string]struct {
+		file  string
+		flags []string
+		want  string
+	}{
+		"json arrays merged": {
+			file:  "folders.json",
+			flags: []string{"--compact", "--columns", "id"},
+			want:  "[{\"id\":\"1\"},{\"id\":\"2\"}]\n",
+		},
+		"jsonl lines appended": {
+			file:  "folders.jsonl",
+			flags: []string{"--columns", "id"},
+			want:  "{\"id\":\"1\"}\n{\"id\":\"2\"}\n",
+		},
+		"csv header written once": {
+			file:  "folders.csv",
+			flags: []string{"--columns", "id,state"},
+			want:  "ID,State\n1,ACTIVE\n2,ACTIVE\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			for _, input := range []string{first, second} {
+				rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+				rootCmd.SetOut(&bytes.Buffer{})
+				rootCmd.SetErr(&bytes.Buffer{})
+				args := append([]string{"--output", path, "--append"}, tt.flags...)
+				rootCmd.SetArgs(append(args, "folders", "--from-file", input))
+				t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+				require.NoError(t, rootCmd.Execute())
+			}
+
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestRootCommandAppendOutputValidation(t *testing.T) {
+	input := writeFolderExport(t, []*folders.Folder{{ID: "1", Name: "folders/1", State: "ACTIVE"}})
+
+	tests := map[string]struct {
+		existing string
+		args     func(path string) []string
+		wantErr  error
+	}{
+		"append without output": {
+			args:    func(string) []string { return []string{"--append", "folders", "--from-file", input} },
+			wantErr: cmd.ErrAppendWithoutOutput,
+		},
+		"existing file is not a JSON array": {
+			existing: "{}\n",
+			args: func(path string) []string {
+				return []string{"--output", path, "--append", "folders", "--from-file", input}
+			},
+			wantErr: output.ErrAppendMismatch,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "folders.json")
+			require.NoError(t, os.WriteFile(path, []byte(tt.existing), 0o600))
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			rootCmd.SetOut(&bytes.Buffer{})
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs(tt.args(path))
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			err := rootCmd.Execute()
+			require.ErrorIs(t, err, tt.wantErr)
+			data, readErr := os.ReadFile(path)
+			require.NoError(t, readErr)
+			assert.Equal(t, tt.existing, string(data))
+		})
+	}
+}
+
+func TestExecuteCommandRecoversFromPanic(t *testing.T) {
+	tests := map[string]struct {
+		args       []string
+		wantStderr string
+	}{
+		"human-readable error": {
+			args:       []string{"boom"},
+			wantStderr: "error executing root command: internal error: command panicked: nil formatter\n",
+		},
+		"JSON error": {
+			args:       []string{"--format", "json", "boom"},
+			wantStderr: `{"error":{"code":"UNKNOWN","message":"internal error: command panicked: nil formatter"}}` + "\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.DebugLevel)
+			log := logger.NewZapLoggerForTesting(zap.New(core))
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, log)
+			rootCmd.AddCommand(&cobra.Command{
+				Use:  "boom",
+				RunE: func(*cobra.Command, []string) error { panic("nil formatter") },
+			})
+			rootCmd.SetOut(&bytes.Buffer{})
+			rootCmd.SetArgs(tt.args)
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			var stderr bytes.Buffer
+			exitCode := cmd.ExecuteCommand(t.Context(), rootCmd, log, &stderr)
+
+			assert.Equal(t, 1, exitCode)
+			assert.Equal(t, tt.wantStderr, stderr.String())
+			entries := logs.FilterMessage("command panicked").All()
+			require.Len(t, entries, 1)
+			assert.Equal(t, zapcore.ErrorLevel, entries[0].Level)
+			assert.Equal(t, "nil formatter", entries[0].ContextMap()["panic"])
+			assert.Contains(t, entries[0].ContextMap()["stack"], "TestExecuteCommandRecoversFromPanic")
+		})
+	}
+}
+
+func TestExecuteCommandExitCode(t *testing.T) {
+	tests := map[string]struct {
+		args     []string
+		wantCode int
+	}{
+		"success": {args: []string{"folders", "--explain-permissions"}},
+		"failure": {args: []string{"--format", "xml", "folders"}, wantCode: 1},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			rootCmd.SetOut(&bytes.Buffer{})
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs(tt.args)
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			assert.Equal(t, tt.wantCode, cmd.ExecuteCommand(t.Context(), rootCmd, logger.NewNoOpLogger(), &bytes.Buffer{}))
+		})
+	}
+}
+
+func TestRootCommandFlagConflicts(t *testing.T) {
+	tests := map[string]struct {
+		args     []string
+		wantErr  error
+		wantText string
+	}{
+		"count with columns": {
+			args:     []string{"--count-by", "state", "--columns", "id", "folders"},
+			wantErr:  cmd.ErrCountByWithListingFlags,
+			wantText: "conflicting output flags --count-by and --columns",
+		},
+		"count with group": {
+			args:     []string{"--group-by", "state", "--count-by", "state", "organizations"},
+			wantErr:  cmd.ErrCountByWithGroupBy,
+			wantText: "conflicting output flags --count-by and --group-by",
+		},
+		"columns with fields file": {
+			args:     []string{"--columns", "id", "--fields-file", "fields.txt", "folders"},
+			wantErr:  cmd.ErrColumnsWithFieldsFile,
+			wantText: "conflicting output flags --columns and --fields-file",
+		},
+		"template with template file": {
+			args:     []string{"--template", "{{.}}", "--template-file", "out.tmpl", "folders"},
+			wantErr:  cmd.ErrTemplateWithTemplateFile,
+			wantText: "conflicting output flags --template and --template-file",
+		},
+		"pager with no pager": {
+			args:     []string{"--pager", "--no-pager", "folders"},
+			wantErr:  cmd.ErrPagerWithNoPager,
+			wantText: "conflicting output flags --pager and --no-pager",
+		},
+		"pager with clipboard": {
+			args:     []string{"--pager", "--clipboard", "folders"},
+			wantErr:  cmd.ErrPagerWithOutput,
+			wantText: "conflicting output flags --pager and --clipboard",
+		},
+		"valid combination": {
+			args: []string{
+				"--count-by", "state", "--format", "json", "--filter", "state=ACTIVE", "--human",
+				"folders", "--explain-permissions",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			rootCmd.SetOut(&bytes.Buffer{})
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs(tt.args)
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			err := rootCmd.Execute()
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+
+				return
+			}
+			require.ErrorIs(t, err, cmd.ErrConflictingFlags)
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Contains(t, err.Error(), tt.wantText)
+		})
+	}
+}
+
+func TestRootCommandDebugGRPC(t *testing.T) {
+	tests := map[string]struct {
+		args        []string
+		wantEnabled bool
+	}{
+		"flag set": {
+			args:        []string{"--debug-grpc", "config", "view"},
+			wantEnabled: true,
+		},
+		"flag unset": {
+			args: []string{"config", "view"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stderr bytes.Buffer
+			var enabledWith io.Writer
+			restored := false
+			t.Cleanup(cmd.SetEnableGRPCDebug(func(w io.Writer) func() {
+				enabledWith = w
+
+				return func() { restored = true }
+			}))
+
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			rootCmd.SetOut(io.Discard)
+			rootCmd.SetErr(&stderr)
+			rootCmd.SetArgs(tt.args)
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			assert.Equal(t, 0, cmd.ExecuteCommand(t.Context(), rootCmd, logger.NewNoOpLogger(), &stderr))
+			if !tt.wantEnabled {
+				assert.Nil(t, enabledWith)
+				assert.False(t, restored)
+
+				return
+			}
+			assert.Same(t, &stderr, enabledWith, "gRPC logs should go to the command's stderr")
+			assert.True(t, restored, "the default gRPC logger should be restored after the run")
+		})
+	}
+}
+
+func TestRootCommandDebugGRPCHidden(t *testing.T) {
+	rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+
+	flag := rootCmd.PersistentFlags().Lookup("debug-grpc")
+	require.NotNil(t, flag)
+	assert.True(t, flag.Hidden)
+}
+
+func TestRootCommandValidateOnly(t *testing.T) {
+	// any API client would fail on these credentials, and the export file does not exist, so success shows
+	// that neither was touched
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", filepath.Join(t.TempDir(), "missing-credentials.json"))
+	missingFile := filepath.Join(t.TempDir(), "missing-folders.json")
+
+	tests := map[string]struct {
+		args     []string
+		wantCode int
+	}{
+		"valid folder listing": {
+			args: []string{"--validate-only", "--format", "json", "folders", "--parent-folder", "123"},
+		},
+		"valid filter and sort": {
+			args: []string{
+				"--validate-only", "--filter", "state=ACTIVE", "--sort-by", "displayName", "folders",
+				"--parent-organization", "9",
+			},
+		},
+		"valid from-file listing": {
+			args: []string{"--validate-only", "folders", "--from-file", missingFile},
+		},
+		"valid organizations listing": {
+			args: []string{"--validate-only", "organizations"},
+		},
+		"valid folder describe": {
+			args: []string{"--validate-only", "folders", "describe", "123"},
+		},
+		"mutually exclusive parents": {
+			args:     []string{"--validate-only", "folders", "--parent-folder", "1", "--parent-organization", "2"},
+			wantCode: 1,
+		},
+		"invalid format": {
+			args:     []string{"--validate-only", "--format", "xml", "folders"},
+			wantCode: 1,
+		},
+		"invalid filter": {
+			args:     []string{"--validate-only", "--filter", "state", "folders"},
+			wantCode: 1,
+		},
+		"invalid folder ID": {
+			args:     []string{"--validate-only", "folders", "describe", "abc"},
+			wantCode: 1,
+		},
+		"conflicting global flags": {
+			args:     []string{"--validate-only", "--sort-by", "displayName", "folders", "--stream"},
+			wantCode: 1,
+		},
+		"graph format for folders graph": {
+			args: []string{"--validate-only", "--format", "mermaid", "folders", "graph", "--parent-folder", "1"},
+		},
+		"graph format for folder listing": {
+			args:     []string{"--validate-only", "--format", "dot", "folders", "--parent-folder", "1"},
+			wantCode: 1,
+		},
+		"describe-only json object format": {
+			args:     []string{"--validate-only", "--format", "json-object", "folders", "--parent-folder", "1"},
+			wantCode: 1,
+		},
+		"graph format for organizations": {
+			args:     []string{"--validate-only", "--format", "mermaid", "organizations"},
+			wantCode: 1,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			rootCmd.SetOut(&stdout)
+			rootCmd.SetErr(&stderr)
+			rootCmd.SetArgs(tt.args)
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			assert.Equal(t, tt.wantCode, cmd.ExecuteCommand(t.Context(), rootCmd, logger.NewNoOpLogger(), &stderr), stderr.String())
+			if tt.wantCode == 0 {
+				assert.Empty(t, stdout.String())
+				assert.Empty(t, stderr.String())
+			} else {
+				assert.NotEmpty(t, stderr.String())
+			}
+		})
+	}
+}
+
+func TestRootCommandValidateOnlyEverySubcommand(t *testing.T) {
+	// no credentials can be found and the export file does not exist, so success shows that no subcommand
+	// looked for credentials, called an API or read its input; the folder IDs of an ID file are validated
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", filepath.Join(t.TempDir(), "missing-credentials.json"))
+	t.Setenv("CLOUDSDK_CONFIG", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	idFile := filepath.Join(t.TempDir(), "ids.txt")
+	require.NoError(t, os.WriteFile(idFile, []byte("123\n"), 0o600))
+	missingFile := filepath.Join(t.TempDir(), "missing.json")
+
+	tests := map[string][]string{
+		"auth whoami":            {"auth", "whoami"},
+		"config view":            {"config", "view"},
+		"doctor":                 {"doctor"},
+		"folders":                {"folders", "--parent-folder", "123"},
+		"folders describe":       {"folders", "describe", "123"},
+		"folders graph":          {"folders", "graph", "--parent-folder", "123"},
+		"folders resolve":        {"folders", "resolve", "--id-file", idFile},
+		"folders stale":          {"folders", "stale", "--max-age", "365d", "--from-file", missingFile},
+		"iam test":               {"iam", "test", "--resource", "folders/123", "--permissions", "p.q.r"},
+		"organizations":          {"organizations"},
+		"organizations describe": {"organizations", "describe", "--domain", "example.com"},
+		"report folder-counts":   {"report", "folder-counts"},
+	}
+
+	// every command that runs something is covered
+	var walk func(command *cobra.Command)
+	walk = func(command *cobra.Command) {
+		if command.Runnable() && command.HasParent() && command.Name() != "help" {
+			path := strings.TrimPrefix(command.CommandPath(), "gcphelper ")
+			assert.Contains(t, tests, path, "add %q to the validate-only cases", path)
+		}
+		for _, child := range command.Commands() {
+			walk(child)
+		}
+	}
+	walk(cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()))
+
+	for name, args := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			rootCmd.SetOut(&stdout)
+			rootCmd.SetErr(&stderr)
+			rootCmd.SetArgs(append([]string{"--validate-only"}, args...))
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			assert.Equal(t, 0, cmd.ExecuteCommand(t.Context(), rootCmd, logger.NewNoOpLogger(), &stderr), stderr.String())
+			assert.Empty(t, stdout.String())
+			assert.Empty(t, stderr.String())
+		})
+	}
+}
+
+func TestRootCommandOutputFlagsEveryRenderingCommand(t *testing.T) {
+	idFile := filepath.Join(t.TempDir(), "ids.txt")
+	require.NoError(t, os.WriteFile(idFile, []byte("123\n"), 0o600))
+
+	// every command rendering resources validates the global output flags, including a timezone that
+	// only changes timestamps
+	tests := map[string][]string{
+		"auth whoami":            {"auth", "whoami"},
+		"config view":            {"config", "view"},
+		"folders":                {"folders", "--parent-folder", "123"},
+		"folders describe":       {"folders", "describe", "123"},
+		"folders resolve":        {"folders", "resolve", "--id-file", idFile},
+		"folders stale":          {"folders", "stale", "--max-age", "365d"},
+		"iam test":               {"iam", "test", "--resource", "folders/123", "--permissions", "p.q.r"},
+		"organizations":          {"organizations"},
+		"organizations describe": {"organizations", "describe", "--domain", "example.com"},
+		"report folder-counts":   {"report", "folder-counts"},
+	}
+
+	for name, args := range tests {
+		t.Run(name, func(t *testing.T) {
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			rootCmd.SetOut(io.Discard)
+			rootCmd.SetErr(io.Discard)
+			rootCmd.SetArgs(append([]string{"--validate-only", "--timezone", "Nowhere/Invalid"}, args...))
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			require.ErrorIs(t, rootCmd.Execute(), output.ErrUnknownTimezone)
+		})
+	}
+}
+
+func TestRootCommandValidateOnlyOutputNotCreated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "folders.json")
+	rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+	rootCmd.SetOut(io.Discard)
+	rootCmd.SetErr(io.Discard)
+	rootCmd.SetArgs([]string{"--validate-only", "--output", path, "organizations"})
+	t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+	require.Equal(t, 0, cmd.ExecuteCommand(t.Context(), rootCmd, logger.NewNoOpLogger(), io.Discard))
+	assert.NoFileExists(t, path)
+}
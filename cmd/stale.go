@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/andreygrechin/gcphelper/internal/cleanup"
+	"github.com/andreygrechin/gcphelper/internal/durationx"
+	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/andreygrechin/gcphelper/internal/reqmeta"
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// ErrMaxAgeRequired is returned when folders stale is run without --max-age.
+var ErrMaxAgeRequired = errors.New("--max-age is required")
+
+// staleFilter keeps the folders a cleanup campaign can act on.
+const staleFilter = "state=ACTIVE"
+
+// staleSortBy lists stale folders oldest first.
+const staleSortBy = "create_time"
+
+// staleOptions holds the flag values of the folders stale command.
+type staleOptions struct {
+	outputFlags
+	clientFlags
+	maxAge   string
+	fromFile string
+}
+
+// newFoldersStaleCommand creates the "folders stale" command.
+func newFoldersStaleCommand(log logger.Logger) *cobra.Command {
+	var opts staleOptions
+
+	cmd := &cobra.Command{
+		Use:   "stale --max-age DURATION",
+		Short: "List active folders older than a maximum age, oldest first",
+		Annotations: requiresIAM(
+			[]string{"roles/resourcemanager.folderViewer"},
+			"resourcemanager.folders.get", "resourcemanager.folders.list",
+		),
+		Long: `List active folders older than a maximum age, oldest first.
+
+This command lists the accessible folders that are still ACTIVE and were created
+longer ago than --max-age, such as 365d, as candidates for a cleanup campaign.
+They are sorted by creation time, oldest first, unless --sort-by is set, and the
+table ends with each folder's age. --filter narrows the candidates further.
+
+Examples:
+  # List active folders older than a year
+  gcphelper folders stale --max-age 365d
+
+  # Export the candidates of one organization as CSV
+  gcphelper --format csv --filter 'parent=organizations/123456789' folders stale --max-age 52w
+
+  # Find candidates in a previously exported listing without API calls
+  gcphelper folders stale --max-age 180d --from-file folders.json`,
+		Args: cobra.NoArgs,
+		RunE: func(command *cobra.Command, _ []string) error {
+			opts.outputFlags = globalOutputFlags()
+			opts.clientFlags = globalClientFlags()
+
+			return runFoldersStaleCommand(command.Context(), command.OutOrStdout(), command.ErrOrStderr(), opts, log)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.maxAge, "max-age", "",
+		"List folders created longer ago than this duration, e.g. 365d or 52w")
+	cmd.Flags().StringVar(&opts.fromFile, "from-file", "",
+		"Read folders from a file exported with --format json or jsonl instead of calling the API")
+
+	return cmd
+}
+
+// ParseMaxAge parses the --max-age duration, which is required.
+func ParseMaxAge(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, ErrMaxAgeRequired
+	}
+
+	maxAge, err := durationx.Parse(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid --max-age value: %w", err)
+	}
+
+	return maxAge, nil
+}
+
+func runFoldersStaleCommand(ctx context.Context, stdout, stderr io.Writer, opts staleOptions, log logger.Logger) error {
+	ctx = reqmeta.WithRequestReason(ctx, opts.requestReason)
+
+	// validate flags before any API client is created
+	maxAge, err := ParseMaxAge(opts.maxAge)
+	if err != nil {
+		return err
+	}
+	renderOpts, err := opts.renderOptions(stdout, output.ResourceTypeFolders)
+	if err != nil {
+		return err
+	}
+	// parse the filter before any API call, as the combined stale filter would report it less clearly
+	if _, err := output.FilterResources(nil, opts.filter); err != nil {
+		return fmt.Errorf("failed to filter %s: %w", output.ResourceTypeFolders, err)
+	}
+
+	if err := validateOnly(); err != nil {
+		return err
+	}
+
+	var folderList []*folders.Folder
+	if opts.fromFile != "" {
+		folderList, err = folders.LoadFoldersFile(opts.fromFile)
+	} else {
+		folderList, err = fetchStaleFolders(ctx, opts, log)
+	}
+	if err != nil {
+		return err
+	}
+
+	// output results
+	return OutputStaleFolders(ctx, stdout, stderr, folderList, maxAge, time.Now(), renderOpts)
+}
+
+// fetchStaleFolders fetches all accessible folders, which the stale folders are selected from.
+func fetchStaleFolders(ctx context.Context, opts staleOptions, log logger.Logger) ([]*folders.Folder, error) {
+	clientOpts, err := opts.clientOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	// create folders service
+	service, err := folders.NewServiceFromContextWithLogger(ctx, log, clientOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create folders service: %w", err)
+	}
+	setSpinner(service, !opts.noSpinner)
+	defer cleanup.CloseAndLog(log, "failed to close service", service)
+
+	fetchOpts := folders.NewFetchOptions()
+	fetchOpts.RequestReason = opts.requestReason
+
+	folderList, err := service.ListFolders(ctx, fetchOpts)
+	if err != nil {
+		return nil, HandleFoldersError(err, "")
+	}
+
+	return folderList, nil
+}
+
+// OutputStaleFolders renders the ACTIVE folders created more than maxAge before now, combined with the
+// --filter expression of opts, sorted oldest first unless opts sorts them otherwise. Unless fields are
+// selected, an Age column measured from now follows the default columns.
+func OutputStaleFolders(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	folderList []*folders.Folder,
+	maxAge time.Duration,
+	now time.Time,
+	opts OutputOptions,
+) error {
+	opts.TimeFilter.CreatedBefore = now.Add(-maxAge)
+	if opts.Filter == "" {
+		opts.Filter = staleFilter
+	} else {
+		opts.Filter = staleFilter + " AND (" + opts.Filter + ")"
+	}
+	if opts.SortBy == "" {
+		opts.SortBy = staleSortBy
+	}
+	if len(opts.Fields) == 0 {
+		unit := opts.AgeUnit
+		if unit == "" {
+			unit = output.AgeUnitSeconds
+		}
+		opts.Columns = append(opts.Columns, output.AgeColumn(func() time.Time { return now }, unit))
+	}
+
+	return OutputFolders(ctx, stdout, stderr, folderList, opts)
+}
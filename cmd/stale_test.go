@@ -0,0 +1,126 @@
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/andreygrechin/gcphelper/cmd"
+	"github.com/andreygrechin/gcphelper/internal/durationx"
+	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputStaleFolders(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	folderList := []*folders.Folder{
+		{ID: "1", Name: "folders/1", DisplayName: "Legacy", State: "ACTIVE", CreateTime: now.AddDate(-2, 0, 0)},
+		{ID: "2", Name: "folders/2", DisplayName: "Recent", State: "ACTIVE", CreateTime: now.AddDate(0, -1, 0)},
+		{ID: "3", Name: "folders/3", DisplayName: "Archive", State: "ACTIVE", CreateTime: now.AddDate(-5, 0, 0)},
+		{ID: "4", Name: "folders/4", DisplayName: "Deleted", State: "DELETE_REQUESTED", CreateTime: now.AddDate(-3, 0, 0)},
+		{ID: "5", Name: "folders/5", DisplayName: "Boundary", State: "ACTIVE", CreateTime: now.AddDate(0, 0, -365)},
+	}
+
+	tests := map[string]struct {
+		opts    cmd.OutputOptions
+		wantIDs []string
+		wantAge []float64
+	}{
+		"active folders older than the maximum age, oldest first": {
+			opts:    cmd.OutputOptions{Format: "json", AgeUnit: output.AgeUnitDays},
+			wantIDs: []string{"3", "1"},
+			wantAge: []float64{1826, 731},
+		},
+		"filter narrows the candidates": {
+			opts:    cmd.OutputOptions{Format: "json", Filter: "display_name~Leg"},
+			wantIDs: []string{"1"},
+			wantAge: []float64{731 * 24 * 60 * 60},
+		},
+		"sort-by replaces the oldest-first order": {
+			opts:    cmd.OutputOptions{Format: "json", SortBy: "display_name"},
+			wantIDs: []string{"3", "1"},
+		},
+		"selected fields have no age column": {
+			opts:    cmd.OutputOptions{Format: "json", Fields: []string{"id"}},
+			wantIDs: []string{"3", "1"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			require.NoError(t, cmd.OutputStaleFolders(t.Context(), &stdout, io.Discard, folderList, 365*durationx.Day, now, tt.opts))
+
+			var got []struct {
+				ID  string   `json:"id"`
+				Age *float64 `json:"age"`
+			}
+			require.NoError(t, json.Unmarshal(stdout.Bytes(), &got))
+			ids := make([]string, 0, len(got))
+			var ages []float64
+			for _, folder := range got {
+				ids = append(ids, folder.ID)
+				if folder.Age != nil {
+					ages = append(ages, *folder.Age)
+				}
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+			if len(tt.opts.Fields) > 0 {
+				assert.Empty(t, ages)
+			} else if tt.wantAge != nil {
+				assert.Equal(t, tt.wantAge, ages)
+			}
+		})
+	}
+}
+
+func TestFoldersStaleCommand(t *testing.T) {
+	path := writeFolderExport(t, []*folders.Folder{
+		{ID: "1", Name: "folders/1", DisplayName: "Legacy", State: "ACTIVE", CreateTime: time.Now().AddDate(-2, 0, 0)},
+		{ID: "2", Name: "folders/2", DisplayName: "Recent", State: "ACTIVE", CreateTime: time.Now().AddDate(0, 0, -1)},
+	})
+
+	tests := map[string]struct {
+		args    []string
+		wantErr error
+		want    string
+	}{
+		"lists stale folders from a file": {
+			args: []string{"-f", "id", "folders", "stale", "--max-age", "365d", "--from-file", path},
+			want: "1\n",
+		},
+		"requires max age": {
+			args:    []string{"folders", "stale", "--from-file", path},
+			wantErr: cmd.ErrMaxAgeRequired,
+		},
+		"rejects invalid max age": {
+			args:    []string{"folders", "stale", "--max-age", "a year", "--from-file", path},
+			wantErr: durationx.ErrInvalidDuration,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			rootCmd.SetOut(&stdout)
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs(tt.args)
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			err := rootCmd.Execute()
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, stdout.String())
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+)
+
+// ErrInvalidStateFile is returned when the --state-file file is not a JSON object of folder IDs to etags.
+var ErrInvalidStateFile = errors.New("invalid state file")
+
+// ErrStateFileWithFlags is returned when --state-file is combined with flags that do not list the folders
+// with their etags in one pass.
+var ErrStateFileWithFlags = errors.New("cannot combine --state-file with --stream, --interactive or --backend asset")
+
+// Kinds of change shown in the Change column of folders listed with --state-file.
+const (
+	changeNew     = "new"
+	changeChanged = "changed"
+)
+
+// LoadEtagState reads the folder etags recorded in the state file at path, keyed by folder ID. A file
+// that does not exist yet yields an empty state, so the first run lists every folder as new.
+func LoadEtagState(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	state := map[string]string{}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrInvalidStateFile, path, err)
+	}
+
+	return state, nil
+}
+
+// SaveEtagState writes state to the state file at path as a JSON object of folder IDs to etags. The
+// file is replaced in one step, so an interrupted run leaves the previous state intact.
+func SaveEtagState(path string, state map[string]string) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	_, err = tmp.Write(append(data, '\n'))
+	err = errors.Join(err, tmp.Close())
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		return errors.Join(fmt.Errorf("failed to write state file: %w", err), os.Remove(tmp.Name()))
+	}
+
+	return nil
+}
+
+// ChangedFolders returns the folders that are not in state or whose etag differs from the recorded one,
+// in their listed order, and the kind of change of each, keyed by folder ID.
+func ChangedFolders(folderList []*folders.Folder, state map[string]string) ([]*folders.Folder, map[string]string) {
+	var changed []*folders.Folder
+	kinds := make(map[string]string)
+	for _, folder := range folderList {
+		etag, seen := state[folder.ID]
+		switch {
+		case !seen:
+			kinds[folder.ID] = changeNew
+		case etag != folder.Etag:
+			kinds[folder.ID] = changeChanged
+		default:
+			continue
+		}
+		changed = append(changed, folder)
+	}
+
+	return changed, kinds
+}
+
+// FilterFolders returns the folders that pass the time filters, --id-prefix and --filter of opts, which
+// are the ones rendering writes, in their listed order.
+func FilterFolders(folderList []*folders.Folder, opts OutputOptions) ([]*folders.Folder, error) {
+	resources := output.FilterByTime(output.FoldersToResources(folderList), opts.TimeFilter)
+	resources = output.FilterByIDPrefix(resources, opts.IDPrefix)
+	resources, err := output.FilterResources(resources, opts.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter folders: %w", err)
+	}
+
+	kept := make(map[string]bool, len(resources))
+	for _, resource := range resources {
+		kept[resource.GetID()] = true
+	}
+	filtered := make([]*folders.Folder, 0, len(resources))
+	for _, folder := range folderList {
+		if kept[folder.ID] {
+			filtered = append(filtered, folder)
+		}
+	}
+
+	return filtered, nil
+}
+
+// changeColumn returns the "Change" computed column telling whether each folder is new or changed.
+func changeColumn(kinds map[string]string) output.Column {
+	return output.Column{
+		Header: "Change",
+		Field:  "change",
+		Value: func(r output.Resource) interface{} {
+			return kinds[r.GetID()]
+		},
+	}
+}
+
+// outputFolderChanges renders the folders that are new or changed since the --state-file state, with a
+// Change column, and then records the etags of the listed folders that pass the --filter, --id-prefix and
+// time filters in the state file. Folders the filters hide are not recorded, so that they are reported
+// as new once a run shows them, and folders recorded earlier but not listed in this run, for example
+// under another parent, keep their etags.
+func outputFolderChanges(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	folderList []*folders.Folder,
+	path string,
+	state map[string]string,
+	opts OutputOptions,
+) error {
+	shown, err := FilterFolders(folderList, opts)
+	if err != nil {
+		return err
+	}
+	changed, kinds := ChangedFolders(shown, state)
+	opts.Columns = append(opts.Columns, changeColumn(kinds))
+	if err := OutputFolders(ctx, stdout, stderr, changed, opts); err != nil {
+		return err
+	}
+
+	updated := maps.Clone(state)
+	for _, folder := range shown {
+		updated[folder.ID] = folder.Etag
+	}
+
+	return SaveEtagState(path, updated)
+}
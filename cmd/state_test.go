@@ -0,0 +1,258 @@
+package cmd_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/cmd"
+	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFoldersStateFile(t *testing.T) {
+	export := writeFolderExport(t, []*folders.Folder{
+		{ID: "1", Name: "folders/1", DisplayName: "Unchanged", Parent: "organizations/9", State: "ACTIVE", Etag: "e1"},
+		{ID: "2", Name: "folders/2", DisplayName: "Changed", Parent: "organizations/9", State: "ACTIVE", Etag: "e2-new"},
+		{ID: "3", Name: "folders/3", DisplayName: "New", Parent: "organizations/9", State: "ACTIVE", Etag: "e3"},
+	})
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, os.WriteFile(statePath, []byte(`{"1": "e1", "2": "e2-old", "99": "e99"}`), 0o600))
+
+	runs := []struct {
+		name string
+		want string
+	}{
+		{
+			name: "changed and new folders",
+			want: "ID,Display Name,Change\n2,Changed,changed\n3,New,new\n",
+		},
+		{
+			name: "nothing changed since the previous run",
+			want: "ID,Display Name,Change\n",
+		},
+	}
+	for _, run := range runs {
+		var stdout bytes.Buffer
+		rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+		rootCmd.SetOut(&stdout)
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetArgs([]string{
+			"--format", "csv", "--columns", "id,display_name",
+			"folders", "--from-file", export, "--state-file", statePath,
+		})
+		t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+		require.NoError(t, rootCmd.Execute(), run.name)
+		assert.Equal(t, run.want, stdout.String(), run.name)
+	}
+
+	// folders not listed in the run keep their recorded etags
+	state, err := cmd.LoadEtagState(statePath)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"1": "e1", "2": "e2-new", "3": "e3", "99": "e99"}, state)
+}
+
+func TestFoldersStateFileFilteredFolders(t *testing.T) {
+	export := writeFolderExport(t, []*folders.Folder{
+		{ID: "1", Name: "folders/1", DisplayName: "prod", Parent: "organizations/9", State: "ACTIVE", Etag: "e1"},
+		{ID: "2", Name: "folders/2", DisplayName: "dev", Parent: "organizations/9", State: "ACTIVE", Etag: "e2"},
+	})
+	statePath := filepath.Join(t.TempDir(), "state.json")
+
+	runs := []struct {
+		name   string
+		filter string
+		want   string
+	}{
+		{
+			name:   "only the filtered folder is written",
+			filter: "displayName=prod",
+			want:   "ID,Change\n1,new\n",
+		},
+		{
+			name:   "a folder hidden earlier is new once shown",
+			filter: "displayName=dev",
+			want:   "ID,Change\n2,new\n",
+		},
+		{
+			name: "every folder was written before",
+			want: "ID,Change\n",
+		},
+	}
+	for _, run := range runs {
+		var stdout bytes.Buffer
+		rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+		rootCmd.SetOut(&stdout)
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetArgs([]string{
+			"--format", "csv", "--columns", "id", "--filter", run.filter,
+			"folders", "--from-file", export, "--state-file", statePath,
+		})
+		t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+		require.NoError(t, rootCmd.Execute(), run.name)
+		assert.Equal(t, run.want, stdout.String(), run.name)
+	}
+}
+
+func TestFilterFolders(t *testing.T) {
+	folderList := []*folders.Folder{
+		{ID: "11", DisplayName: "prod"},
+		{ID: "12", DisplayName: "dev"},
+		{ID: "21", DisplayName: "prod-eu"},
+	}
+
+	testCases := map[string]struct {
+		opts    cmd.OutputOptions
+		wantIDs []string
+	}{
+		"no filters":     {wantIDs: []string{"11", "12", "21"}},
+		"filter":         {opts: cmd.OutputOptions{Filter: "displayName~prod"}, wantIDs: []string{"11", "21"}},
+		"id prefix":      {opts: cmd.OutputOptions{IDPrefix: "1"}, wantIDs: []string{"11", "12"}},
+		"both":           {opts: cmd.OutputOptions{Filter: "displayName~prod", IDPrefix: "1"}, wantIDs: []string{"11"}},
+		"nothing passes": {opts: cmd.OutputOptions{Filter: "id=99"}, wantIDs: []string{}},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := cmd.FilterFolders(folderList, tc.opts)
+			require.NoError(t, err)
+
+			ids := make([]string, 0, len(got))
+			for _, folder := range got {
+				ids = append(ids, folder.ID)
+			}
+			assert.Equal(t, tc.wantIDs, ids)
+		})
+	}
+}
+
+func TestFoldersStateFileValidation(t *testing.T) {
+	invalid := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, os.WriteFile(invalid, []byte("not json"), 0o600))
+
+	testCases := map[string]struct {
+		args    []string
+		wantErr error
+	}{
+		"state file with stream": {
+			args:    []string{"folders", "--stream", "--state-file", "state.json"},
+			wantErr: cmd.ErrStateFileWithFlags,
+		},
+		"state file with asset backend": {
+			args:    []string{"folders", "--backend", "asset", "--parent-folder", "1", "--state-file", "state.json"},
+			wantErr: cmd.ErrStateFileWithFlags,
+		},
+		"invalid state file": {
+			args:    []string{"folders", "--state-file", invalid},
+			wantErr: cmd.ErrInvalidStateFile,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			rootCmd := cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger())
+			rootCmd.SetOut(&bytes.Buffer{})
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs(tc.args)
+			t.Cleanup(func() { cmd.NewRootCommand(cmd.VersionInfo{}, logger.NewNoOpLogger()) })
+
+			err := rootCmd.Execute()
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestChangedFolders(t *testing.T) {
+	folderList := []*folders.Folder{
+		{ID: "1", Etag: "a"},
+		{ID: "2", Etag: "b"},
+		{ID: "3", Etag: "c"},
+	}
+
+	testCases := map[string]struct {
+		state     map[string]string
+		wantIDs   []string
+		wantKinds map[string]string
+	}{
+		"empty state": {
+			state:     map[string]string{},
+			wantIDs:   []string{"1", "2", "3"},
+			wantKinds: map[string]string{"1": "new", "2": "new", "3": "new"},
+		},
+		"unchanged": {
+			state:     map[string]string{"1": "a", "2": "b", "3": "c"},
+			wantKinds: map[string]string{},
+		},
+		"changed and new": {
+			state:     map[string]string{"1": "a", "2": "old"},
+			wantIDs:   []string{"2", "3"},
+			wantKinds: map[string]string{"2": "changed", "3": "new"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			changed, kinds := cmd.ChangedFolders(folderList, tc.state)
+
+			var ids []string
+			for _, folder := range changed {
+				ids = append(ids, folder.ID)
+			}
+			assert.Equal(t, tc.wantIDs, ids)
+			assert.Equal(t, tc.wantKinds, kinds)
+		})
+	}
+}
+
+func TestLoadEtagState(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.json")
+	require.NoError(t, os.WriteFile(valid, []byte(`{"1": "a"}`), 0o600))
+	invalid := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalid, []byte(`["1"]`), 0o600))
+
+	testCases := map[string]struct {
+		path    string
+		want    map[string]string
+		wantErr error
+	}{
+		"valid":   {path: valid, want: map[string]string{"1": "a"}},
+		"missing": {path: filepath.Join(dir, "missing.json"), want: map[string]string{}},
+		"invalid": {path: invalid, wantErr: cmd.ErrInvalidStateFile},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := cmd.LoadEtagState(tc.path)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestSaveEtagState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, cmd.SaveEtagState(path, map[string]string{"2": "b", "1": "a"}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "{\n  \"1\": \"a\",\n  \"2\": \"b\"\n}\n", string(data))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary files are left behind")
+}
@@ -0,0 +1,196 @@
+// Package apitrace logs every outgoing Google API call for diagnosing latency.
+package apitrace
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/andreygrechin/gcphelper/internal/logger"
+	"go.uber.org/zap"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+)
+
+// Recorder logs each gRPC call at debug level and counts the calls per method. It is safe for
+// concurrent use, so all clients of a command can share one recorder.
+type Recorder struct {
+	log logger.Logger
+
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// NewRecorder creates a recorder that writes its traces to log.
+func NewRecorder(log logger.Logger) *Recorder {
+	if log == nil {
+		log = logger.NewNoOpLogger()
+	}
+
+	return &Recorder{
+		log:    log,
+		counts: make(map[string]int),
+	}
+}
+
+// ClientOptions returns client options that trace every unary and streaming call made by a client.
+// Each retry attempt is traced as a separate call.
+func (r *Recorder) ClientOptions() []option.ClientOption {
+	return []option.ClientOption{
+		option.WithGRPCDialOption(grpc.WithChainUnaryInterceptor(r.UnaryInterceptor())),
+		option.WithGRPCDialOption(grpc.WithChainStreamInterceptor(r.StreamInterceptor())),
+	}
+}
+
+// UnaryInterceptor returns a gRPC client interceptor that traces each unary call.
+func (r *Recorder) UnaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		r.record(method, req, start, err)
+
+		return err
+	}
+}
+
+// StreamInterceptor returns a gRPC client interceptor that traces each streaming call, from its
+// start until the stream ends.
+func (r *Recorder) StreamInterceptor() grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		start := time.Now()
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			r.record(method, nil, start, err)
+
+			return nil, err
+		}
+
+		return &tracedStream{ClientStream: stream, recorder: r, method: method, start: start}, nil
+	}
+}
+
+// Counts returns the number of calls recorded per method.
+func (r *Recorder) Counts() map[string]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counts := make(map[string]int, len(r.counts))
+	for method, count := range r.counts {
+		counts[method] = count
+	}
+
+	return counts
+}
+
+// LogSummary logs the total number of calls and the number of calls per method, in method order.
+func (r *Recorder) LogSummary() {
+	counts := r.Counts()
+	methods := make([]string, 0, len(counts))
+	total := 0
+	for method, count := range counts {
+		methods = append(methods, method)
+		total += count
+	}
+	sort.Strings(methods)
+
+	r.log.Debug("API call summary", zap.Int("calls", total))
+	for _, method := range methods {
+		r.log.Debug("API calls by method", zap.String("method", method), zap.Int("calls", counts[method]))
+	}
+}
+
+// record counts one call and logs its trace.
+func (r *Recorder) record(method string, req any, start time.Time, err error) {
+	r.mu.Lock()
+	r.counts[method]++
+	r.mu.Unlock()
+
+	fields := []zap.Field{
+		zap.String("method", method),
+		zap.Time("start", start),
+		zap.Duration("duration", time.Since(start)),
+	}
+	fields = append(fields, requestFields(req)...)
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	r.log.Debug("API call", fields...)
+}
+
+// requestFields returns the parent, query and resource name of a request that has them.
+func requestFields(req any) []zap.Field {
+	var fields []zap.Field
+	if r, ok := req.(interface{ GetParent() string }); ok && r.GetParent() != "" {
+		fields = append(fields, zap.String("parent", r.GetParent()))
+	}
+	if r, ok := req.(interface{ GetQuery() string }); ok && r.GetQuery() != "" {
+		fields = append(fields, zap.String("query", r.GetQuery()))
+	}
+	if r, ok := req.(interface{ GetName() string }); ok && r.GetName() != "" {
+		fields = append(fields, zap.String("name", r.GetName()))
+	}
+
+	return fields
+}
+
+// tracedStream records a streaming call once the stream ends.
+type tracedStream struct {
+	grpc.ClientStream
+
+	recorder *Recorder
+	method   string
+	start    time.Time
+	once     sync.Once
+
+	mu  sync.Mutex
+	req any
+}
+
+// SendMsg remembers the first request, whose parent and query are included in the trace.
+func (s *tracedStream) SendMsg(m any) error {
+	s.mu.Lock()
+	if s.req == nil {
+		s.req = m
+	}
+	s.mu.Unlock()
+
+	return s.ClientStream.SendMsg(m)
+}
+
+// RecvMsg records the call when the stream ends, either cleanly or with an error.
+func (s *tracedStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.once.Do(func() {
+			s.mu.Lock()
+			req := s.req
+			s.mu.Unlock()
+
+			if errors.Is(err, io.EOF) {
+				s.recorder.record(s.method, req, s.start, nil)
+
+				return
+			}
+			s.recorder.record(s.method, req, s.start, err)
+		})
+	}
+
+	return err
+}
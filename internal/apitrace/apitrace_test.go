@@ -0,0 +1,140 @@
+package apitrace_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	resourcemanagerpb "cloud.google.com/go/resourcemanager/apiv3/resourcemanagerpb"
+	"github.com/andreygrechin/gcphelper/internal/apitrace"
+	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+)
+
+// Test error variables for err113 compliance.
+var errTestUnavailable = errors.New("unavailable")
+
+const (
+	listFolders   = "/google.cloud.resourcemanager.v3.Folders/ListFolders"
+	searchFolders = "/google.cloud.resourcemanager.v3.Folders/SearchFolders"
+)
+
+func newObservedRecorder() (*apitrace.Recorder, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	return apitrace.NewRecorder(logger.NewZapLoggerForTesting(zap.New(core))), logs
+}
+
+func TestUnaryInterceptorRecordsCall(t *testing.T) {
+	tests := map[string]struct {
+		err     error
+		wantErr bool
+	}{
+		"successful call": {},
+		"failed call":     {err: errTestUnavailable, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			recorder, logs := newObservedRecorder()
+			invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+				return tt.err
+			}
+			req := &resourcemanagerpb.ListFoldersRequest{Parent: "organizations/123"}
+
+			err := recorder.UnaryInterceptor()(t.Context(), listFolders, req, nil, nil, invoker)
+			require.ErrorIs(t, err, tt.err)
+
+			assert.Equal(t, map[string]int{listFolders: 1}, recorder.Counts())
+			entries := logs.FilterMessage("API call").All()
+			require.Len(t, entries, 1)
+			fields := entries[0].ContextMap()
+			assert.Equal(t, listFolders, fields["method"])
+			assert.Equal(t, "organizations/123", fields["parent"])
+			assert.Contains(t, fields, "start")
+			assert.Contains(t, fields, "duration")
+			_, hasErr := fields["error"]
+			assert.Equal(t, tt.wantErr, hasErr)
+		})
+	}
+}
+
+// fakeStream is a client stream that returns io.EOF after the given number of messages.
+type fakeStream struct {
+	grpc.ClientStream
+
+	messages int
+}
+
+func (s *fakeStream) SendMsg(any) error {
+	return nil
+}
+
+func (s *fakeStream) RecvMsg(any) error {
+	if s.messages == 0 {
+		return io.EOF
+	}
+	s.messages--
+
+	return nil
+}
+
+func TestStreamInterceptorRecordsCallWhenStreamEnds(t *testing.T) {
+	recorder, logs := newObservedRecorder()
+	streamer := func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (
+		grpc.ClientStream, error,
+	) {
+		return &fakeStream{messages: 2}, nil
+	}
+
+	stream, err := recorder.StreamInterceptor()(t.Context(), &grpc.StreamDesc{}, nil, searchFolders, streamer)
+	require.NoError(t, err)
+	require.NoError(t, stream.SendMsg(&resourcemanagerpb.SearchFoldersRequest{Query: "state:ACTIVE"}))
+
+	require.NoError(t, stream.RecvMsg(nil))
+	require.NoError(t, stream.RecvMsg(nil))
+	assert.Empty(t, recorder.Counts(), "the call should be recorded once the stream ends")
+
+	require.ErrorIs(t, stream.RecvMsg(nil), io.EOF)
+	require.ErrorIs(t, stream.RecvMsg(nil), io.EOF)
+	assert.Equal(t, map[string]int{searchFolders: 1}, recorder.Counts())
+
+	entries := logs.FilterMessage("API call").All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "state:ACTIVE", entries[0].ContextMap()["query"])
+	assert.NotContains(t, entries[0].ContextMap(), "error")
+}
+
+func TestLogSummary(t *testing.T) {
+	recorder, logs := newObservedRecorder()
+	invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		return nil
+	}
+	for _, method := range []string{searchFolders, listFolders, listFolders} {
+		require.NoError(t, recorder.UnaryInterceptor()(t.Context(), method, nil, nil, nil, invoker))
+	}
+
+	recorder.LogSummary()
+
+	summary := logs.FilterMessage("API call summary").All()
+	require.Len(t, summary, 1)
+	assert.EqualValues(t, 3, summary[0].ContextMap()["calls"])
+
+	byMethod := logs.FilterMessage("API calls by method").All()
+	require.Len(t, byMethod, 2)
+	assert.Equal(t, listFolders, byMethod[0].ContextMap()["method"])
+	assert.EqualValues(t, 2, byMethod[0].ContextMap()["calls"])
+	assert.Equal(t, searchFolders, byMethod[1].ContextMap()["method"])
+	assert.EqualValues(t, 1, byMethod[1].ContextMap()["calls"])
+}
+
+func TestClientOptions(t *testing.T) {
+	recorder, _ := newObservedRecorder()
+	assert.Len(t, recorder.ClientOptions(), 2)
+}
@@ -0,0 +1,53 @@
+// Package auditlog appends one NDJSON record per command invocation to a file, recording what was
+// queried for compliance reviews. It is separate from the diagnostic zap log.
+package auditlog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/andreygrechin/gcphelper/internal/filelock"
+)
+
+// Record describes one command invocation.
+type Record struct {
+	Timestamp   time.Time         `json:"timestamp"`        // Timestamp is when the command started
+	Command     string            `json:"command"`          // Command is the command path, e.g. "gcphelper folders"
+	Args        []string          `json:"args,omitempty"`   // Args are the positional arguments, e.g. folder IDs
+	Flags       map[string]string `json:"flags"`            // Flags are the flags set on the command line
+	Parent      string            `json:"parent,omitempty"` // Parent is the parent the listing was limited to
+	Query       string            `json:"query,omitempty"`  // Query is the search query, if any
+	ResultCount *int              `json:"result_count"`     // ResultCount is nil when no results were written
+	DurationMS  int64             `json:"duration_ms"`      // DurationMS is the run time in milliseconds
+	Error       string            `json:"error,omitempty"`  // Error is the error the command failed with
+}
+
+// Append writes record as one JSON line at the end of the file at path, creating the file if needed.
+// The file is locked while writing, so records of concurrent runs sharing the file never interleave.
+func Append(path string, record *Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	if err := filelock.Lock(file); err != nil {
+		return errors.Join(err, file.Close())
+	}
+
+	_, err = file.Write(append(data, '\n'))
+	if closeErr := file.Close(); closeErr != nil {
+		err = errors.Join(err, closeErr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package auditlog_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andreygrechin/gcphelper/internal/auditlog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	count := 3
+	records := []*auditlog.Record{
+		{
+			Timestamp:   time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			Command:     "gcphelper folders",
+			Flags:       map[string]string{"parent-folder": "123"},
+			Parent:      "folders/123",
+			ResultCount: &count,
+			DurationMS:  42,
+		},
+		{
+			Timestamp: time.Date(2024, 1, 2, 3, 5, 0, 0, time.UTC),
+			Command:   "gcphelper folders describe",
+			Args:      []string{"404"},
+			Flags:     map[string]string{},
+			Error:     "not found",
+		},
+	}
+	for _, record := range records {
+		require.NoError(t, auditlog.Append(path, record))
+	}
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t,
+		`{"timestamp":"2024-01-02T03:04:05Z","command":"gcphelper folders","flags":{"parent-folder":"123"},`+
+			`"parent":"folders/123","result_count":3,"duration_ms":42}`+"\n"+
+			`{"timestamp":"2024-01-02T03:05:00Z","command":"gcphelper folders describe","args":["404"],"flags":{},`+
+			`"result_count":null,"duration_ms":0,"error":"not found"}`+"\n",
+		string(data))
+}
+
+func TestAppendConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	writers := 20
+
+	var wg sync.WaitGroup
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, auditlog.Append(path, &auditlog.Record{Command: "gcphelper " + strconv.Itoa(i)}))
+		}()
+	}
+	wg.Wait()
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = file.Close() })
+
+	lines := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record auditlog.Record
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		lines++
+	}
+	require.NoError(t, scanner.Err())
+	assert.Equal(t, writers, lines)
+}
+
+func TestAppendInvalidPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "audit.log")
+
+	err := auditlog.Append(path, &auditlog.Record{Command: "gcphelper"})
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,48 @@
+// Package cleanup releases resources and aggregates the errors returned by their Close methods.
+package cleanup
+
+import (
+	"errors"
+	"io"
+	"reflect"
+
+	"github.com/andreygrechin/gcphelper/internal/logger"
+	"go.uber.org/zap"
+)
+
+// CloseAll closes every closer in order, continuing past failures, and returns the joined errors.
+// Nil closers, including typed nil pointers, are skipped.
+func CloseAll(closers ...io.Closer) error {
+	var errs []error
+	for _, closer := range closers {
+		if isNil(closer) {
+			continue
+		}
+		if err := closer.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// CloseAndLog closes the closers with CloseAll and reports any failure as a warning on log,
+// for use in deferred cleanup where the error cannot be returned.
+func CloseAndLog(log logger.Logger, msg string, closers ...io.Closer) {
+	err := CloseAll(closers...)
+	if err == nil || log == nil {
+		return
+	}
+
+	log.Warn(msg, zap.Error(err))
+}
+
+func isNil(closer io.Closer) bool {
+	if closer == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(closer)
+
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
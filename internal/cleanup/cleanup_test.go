@@ -0,0 +1,111 @@
+package cleanup_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/internal/cleanup"
+	"github.com/andreygrechin/gcphelper/internal/logger/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Test error variables for err113 compliance.
+var (
+	errCleanupTestFirst  = errors.New("first close failed")
+	errCleanupTestSecond = errors.New("second close failed")
+)
+
+// fakeCloser records whether it was closed and returns a preset error.
+type fakeCloser struct {
+	err    error
+	closed bool
+}
+
+func (f *fakeCloser) Close() error {
+	f.closed = true
+
+	return f.err
+}
+
+func TestCloseAll(t *testing.T) {
+	tests := map[string]struct {
+		closers  []*fakeCloser
+		wantErrs []error
+	}{
+		"all succeed": {
+			closers: []*fakeCloser{{}, {}},
+		},
+		"single failure": {
+			closers:  []*fakeCloser{{err: errCleanupTestFirst}, {}},
+			wantErrs: []error{errCleanupTestFirst},
+		},
+		"aggregates every failure and closes the rest": {
+			closers:  []*fakeCloser{{err: errCleanupTestFirst}, {}, {err: errCleanupTestSecond}},
+			wantErrs: []error{errCleanupTestFirst, errCleanupTestSecond},
+		},
+		"no closers": {},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			closers := make([]io.Closer, len(tt.closers))
+			for i, c := range tt.closers {
+				closers[i] = c
+			}
+
+			err := cleanup.CloseAll(closers...)
+
+			for _, c := range tt.closers {
+				assert.True(t, c.closed)
+			}
+			if len(tt.wantErrs) == 0 {
+				require.NoError(t, err)
+
+				return
+			}
+			for _, want := range tt.wantErrs {
+				require.ErrorIs(t, err, want)
+			}
+		})
+	}
+}
+
+func TestCloseAllSkipsNil(t *testing.T) {
+	var typedNil *fakeCloser
+	closer := &fakeCloser{}
+
+	err := cleanup.CloseAll(nil, typedNil, closer)
+
+	require.NoError(t, err)
+	assert.True(t, closer.closed)
+}
+
+func TestCloseAndLog(t *testing.T) {
+	tests := map[string]struct {
+		closeErr error
+		wantWarn bool
+	}{
+		"logs failure as warning": {
+			closeErr: errCleanupTestFirst,
+			wantWarn: true,
+		},
+		"stays quiet on success": {
+			closeErr: nil,
+			wantWarn: false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			log := mocks.NewMockLogger(t)
+			if tt.wantWarn {
+				log.On("Warn", "failed to close service", mock.Anything).Once()
+			}
+
+			cleanup.CloseAndLog(log, "failed to close service", &fakeCloser{err: tt.closeErr})
+		})
+	}
+}
@@ -0,0 +1,86 @@
+// Package clipboard copies text to the system clipboard through the platform's clipboard utility.
+package clipboard
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrUnavailable is returned when no clipboard utility can be used in the current environment.
+var ErrUnavailable = errors.New("clipboard is not available")
+
+// utility is a command that reads text on stdin and places it on the clipboard.
+type utility struct {
+	name string
+	args []string
+}
+
+// Copier copies text to the clipboard with the first available utility for its platform.
+type Copier struct {
+	GOOS     string                            // GOOS selects the platform's clipboard utilities
+	Getenv   func(key string) string           // Getenv reads environment variables such as DISPLAY
+	LookPath func(file string) (string, error) // LookPath resolves a utility name to an executable
+}
+
+// Write copies text to the system clipboard of the running platform.
+func Write(text []byte) error {
+	return Copier{GOOS: runtime.GOOS, Getenv: os.Getenv, LookPath: exec.LookPath}.Write(text)
+}
+
+// Write copies text to the clipboard. It returns ErrUnavailable when no clipboard utility is
+// installed or, on Linux and other Unix systems, when there is no graphical session to own the clipboard.
+func (c Copier) Write(text []byte) error {
+	utilities := c.utilities()
+
+	names := make([]string, 0, len(utilities))
+	for _, u := range utilities {
+		names = append(names, u.name)
+
+		path, err := c.LookPath(u.name)
+		if err != nil {
+			continue
+		}
+
+		cmd := exec.Command(path, u.args...)
+		cmd.Stdin = bytes.NewReader(text)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to copy to clipboard with %s: %w: %s", u.name, err, strings.TrimSpace(string(out)))
+		}
+
+		return nil
+	}
+
+	if len(names) == 0 {
+		return fmt.Errorf("%w: no graphical session found (DISPLAY and WAYLAND_DISPLAY are unset)", ErrUnavailable)
+	}
+
+	return fmt.Errorf("%w: install one of %s", ErrUnavailable, strings.Join(names, ", "))
+}
+
+// utilities returns the clipboard utilities to try, in order of preference.
+func (c Copier) utilities() []utility {
+	switch c.GOOS {
+	case "darwin":
+		return []utility{{name: "pbcopy"}}
+	case "windows":
+		return []utility{{name: "clip.exe"}}
+	}
+
+	var utilities []utility
+	if c.Getenv("WAYLAND_DISPLAY") != "" {
+		utilities = append(utilities, utility{name: "wl-copy"})
+	}
+	if c.Getenv("DISPLAY") != "" {
+		utilities = append(utilities,
+			utility{name: "xclip", args: []string{"-selection", "clipboard"}},
+			utility{name: "xsel", args: []string{"--clipboard", "--input"}},
+		)
+	}
+
+	return utilities
+}
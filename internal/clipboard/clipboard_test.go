@@ -0,0 +1,83 @@
+package clipboard_test
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/internal/clipboard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errNotFound = errors.New("executable file not found")
+
+func TestCopierWrite(t *testing.T) {
+	tests := map[string]struct {
+		goos      string
+		env       map[string]string
+		available map[string]string
+		wantErr   error
+		wantMsg   string
+	}{
+		"linux without a graphical session": {
+			goos:    "linux",
+			wantErr: clipboard.ErrUnavailable,
+			wantMsg: "DISPLAY and WAYLAND_DISPLAY are unset",
+		},
+		"linux with display but no utility": {
+			goos:    "linux",
+			env:     map[string]string{"DISPLAY": ":0"},
+			wantErr: clipboard.ErrUnavailable,
+			wantMsg: "install one of xclip, xsel",
+		},
+		"wayland prefers wl-copy": {
+			goos:      "linux",
+			env:       map[string]string{"WAYLAND_DISPLAY": "wayland-0", "DISPLAY": ":0"},
+			available: map[string]string{"wl-copy": "true"},
+		},
+		"darwin without pbcopy": {
+			goos:    "darwin",
+			wantErr: clipboard.ErrUnavailable,
+			wantMsg: "install one of pbcopy",
+		},
+		"darwin with pbcopy": {
+			goos:      "darwin",
+			available: map[string]string{"pbcopy": "true"},
+		},
+		"utility failure": {
+			goos:      "darwin",
+			available: map[string]string{"pbcopy": "false"},
+			wantMsg:   "failed to copy to clipboard with pbcopy",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			copier := clipboard.Copier{
+				GOOS:   tt.goos,
+				Getenv: func(key string) string { return tt.env[key] },
+				LookPath: func(file string) (string, error) {
+					if target, ok := tt.available[file]; ok {
+						return exec.LookPath(target)
+					}
+
+					return "", errNotFound
+				},
+			}
+
+			err := copier.Write([]byte("123\n"))
+			if tt.wantErr == nil && tt.wantMsg == "" {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.Error(t, err)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Contains(t, err.Error(), tt.wantMsg)
+		})
+	}
+}
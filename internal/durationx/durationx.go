@@ -0,0 +1,75 @@
+// Package durationx parses durations with day and week units in addition to those of time.ParseDuration.
+package durationx
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidDuration is returned when a duration string cannot be parsed.
+var ErrInvalidDuration = errors.New("invalid duration")
+
+const (
+	// Day is the length of a "d" unit.
+	Day = 24 * time.Hour
+	// Week is the length of a "w" unit.
+	Week = 7 * Day
+)
+
+// Parse parses a duration such as "30d", "2w", "720h" or "1w2d12h". It accepts every unit of
+// time.ParseDuration plus "d" (24h) and "w" (7d). Negative durations are rejected.
+func Parse(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, fmt.Errorf("%w: empty value", ErrInvalidDuration)
+	}
+
+	var total time.Duration
+	rest := s
+	for rest != "" {
+		end := strings.IndexFunc(rest, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+		if end <= 0 {
+			return 0, fmt.Errorf("%w: %q (expected a number followed by a unit, e.g. 30d)", ErrInvalidDuration, s)
+		}
+		unitEnd := end
+		for unitEnd < len(rest) && ((rest[unitEnd] < '0' || rest[unitEnd] > '9') && rest[unitEnd] != '.') {
+			unitEnd++
+		}
+
+		segment, err := parseSegment(rest[:end], rest[end:unitEnd])
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q: %w", ErrInvalidDuration, s, err)
+		}
+		total += segment
+		rest = rest[unitEnd:]
+	}
+
+	return total, nil
+}
+
+// parseSegment converts a single number and unit pair to a duration.
+func parseSegment(number, unit string) (time.Duration, error) {
+	var scale time.Duration
+	switch unit {
+	case "d":
+		scale = Day
+	case "w":
+		scale = Week
+	default:
+		d, err := time.ParseDuration(number + unit)
+		if err != nil {
+			return 0, fmt.Errorf("unknown unit %q", unit)
+		}
+
+		return d, nil
+	}
+
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", number)
+	}
+
+	return time.Duration(value * float64(scale)), nil
+}
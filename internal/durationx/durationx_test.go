@@ -0,0 +1,46 @@
+package durationx_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andreygrechin/gcphelper/internal/durationx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		"hours":             {input: "720h", want: 720 * time.Hour},
+		"days":              {input: "30d", want: 30 * 24 * time.Hour},
+		"weeks":             {input: "2w", want: 14 * 24 * time.Hour},
+		"fractional days":   {input: "1.5d", want: 36 * time.Hour},
+		"combined units":    {input: "1w2d12h", want: (9*24 + 12) * time.Hour},
+		"minutes":           {input: "90m", want: 90 * time.Minute},
+		"milliseconds":      {input: "250ms", want: 250 * time.Millisecond},
+		"empty":             {input: "", wantErr: true},
+		"missing unit":      {input: "30", wantErr: true},
+		"unknown unit":      {input: "3y", wantErr: true},
+		"missing number":    {input: "d", wantErr: true},
+		"negative duration": {input: "-1d", wantErr: true},
+		"trailing garbage":  {input: "1d!", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := durationx.Parse(tt.input)
+			if tt.wantErr {
+				require.ErrorIs(t, err, durationx.ErrInvalidDuration)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
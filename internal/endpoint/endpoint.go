@@ -0,0 +1,77 @@
+// Package endpoint selects the Resource Manager API endpoint for regional or custom routing.
+package endpoint
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/api/option"
+)
+
+// ErrUnknownRegion is returned when an endpoint region is not in the known region list.
+var ErrUnknownRegion = errors.New("unknown endpoint region")
+
+// ErrRegionWithOverride is returned when both an endpoint region and a raw endpoint are given.
+var ErrRegionWithOverride = errors.New("cannot combine --endpoint-region with --endpoint")
+
+// DefaultEndpoint is the global Resource Manager endpoint used when no region is selected.
+const DefaultEndpoint = "cloudresourcemanager.googleapis.com:443"
+
+// regionalEndpoints maps supported region names to their Resource Manager endpoints.
+var regionalEndpoints = map[string]string{
+	"global":       DefaultEndpoint,
+	"us":           "cloudresourcemanager.us.rep.googleapis.com:443",
+	"eu":           "cloudresourcemanager.eu.rep.googleapis.com:443",
+	"europe-west3": "cloudresourcemanager.europe-west3.rep.googleapis.com:443",
+	"europe-west9": "cloudresourcemanager.europe-west9.rep.googleapis.com:443",
+	"me-central2":  "cloudresourcemanager.me-central2.rep.googleapis.com:443",
+	"us-central1":  "cloudresourcemanager.us-central1.rep.googleapis.com:443",
+	"us-east4":     "cloudresourcemanager.us-east4.rep.googleapis.com:443",
+}
+
+// Resolve returns the endpoint for the given region or raw override. It returns an empty string
+// when neither is set, leaving the client library default in place.
+func Resolve(region, override string) (string, error) {
+	switch {
+	case region != "" && override != "":
+		return "", ErrRegionWithOverride
+	case override != "":
+		return override, nil
+	case region == "":
+		return "", nil
+	}
+
+	ep, ok := regionalEndpoints[strings.ToLower(region)]
+	if !ok {
+		return "", fmt.Errorf("%w: %s (supported: %s)", ErrUnknownRegion, region, strings.Join(Regions(), ", "))
+	}
+
+	return ep, nil
+}
+
+// ClientOptions returns the API client options selecting the endpoint for the given region or
+// raw override, or no options when neither is set.
+func ClientOptions(region, override string) ([]option.ClientOption, error) {
+	ep, err := Resolve(region, override)
+	if err != nil {
+		return nil, err
+	}
+	if ep == "" {
+		return nil, nil
+	}
+
+	return []option.ClientOption{option.WithEndpoint(ep)}, nil
+}
+
+// Regions returns the supported region names in sorted order.
+func Regions() []string {
+	regions := make([]string, 0, len(regionalEndpoints))
+	for region := range regionalEndpoints {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+
+	return regions
+}
@@ -0,0 +1,62 @@
+package endpoint_test
+
+import (
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/internal/endpoint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/option"
+)
+
+func TestClientOptions(t *testing.T) {
+	tests := map[string]struct {
+		region   string
+		override string
+		want     []option.ClientOption
+		wantErr  error
+	}{
+		"no selection keeps library default": {
+			want: nil,
+		},
+		"eu multi-region": {
+			region: "eu",
+			want:   []option.ClientOption{option.WithEndpoint("cloudresourcemanager.eu.rep.googleapis.com:443")},
+		},
+		"region name is case-insensitive": {
+			region: "US-Central1",
+			want:   []option.ClientOption{option.WithEndpoint("cloudresourcemanager.us-central1.rep.googleapis.com:443")},
+		},
+		"global region": {
+			region: "global",
+			want:   []option.ClientOption{option.WithEndpoint(endpoint.DefaultEndpoint)},
+		},
+		"raw override": {
+			override: "private.googleapis.com:443",
+			want:     []option.ClientOption{option.WithEndpoint("private.googleapis.com:443")},
+		},
+		"unknown region": {
+			region:  "mars-north1",
+			wantErr: endpoint.ErrUnknownRegion,
+		},
+		"region with override": {
+			region:   "eu",
+			override: "private.googleapis.com:443",
+			wantErr:  endpoint.ErrRegionWithOverride,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := endpoint.ClientOptions(tt.region, tt.override)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
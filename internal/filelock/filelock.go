@@ -0,0 +1,3 @@
+// Package filelock takes exclusive advisory locks on open files, so that concurrent gcphelper runs
+// updating the same file do not interleave their writes.
+package filelock
@@ -0,0 +1,42 @@
+package filelock_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/andreygrechin/gcphelper/internal/filelock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.json")
+
+	first, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	require.NoError(t, err)
+	require.NoError(t, filelock.Lock(first))
+
+	second, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = second.Close() })
+
+	locked := make(chan error, 1)
+	go func() { locked <- filelock.Lock(second) }()
+
+	select {
+	case <-locked:
+		t.Fatal("second lock acquired while the first was held")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// closing the first file releases its lock
+	require.NoError(t, first.Close())
+	select {
+	case err := <-locked:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("second lock not acquired after the first was released")
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+
+package filelock
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// Lock blocks until it holds an exclusive lock on file. The lock is released when file is closed.
+func Lock(file *os.File) error {
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+		return fmt.Errorf("failed to lock %s: %w", file.Name(), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+//go:build windows
+
+package filelock
+
+import (
+	"fmt"
+	"math"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// Lock blocks until it holds an exclusive lock on file. The lock is released when file is closed.
+func Lock(file *os.File) error {
+	// lock the whole file, whatever its size
+	overlapped := new(windows.Overlapped)
+	err := windows.LockFileEx(windows.Handle(file.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK, 0,
+		math.MaxUint32, math.MaxUint32, overlapped)
+	if err != nil {
+		return fmt.Errorf("failed to lock %s: %w", file.Name(), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+// Package gcloudconfig reads properties, such as the default project, from the active gcloud CLI
+// configuration.
+package gcloudconfig
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// ErrNotFound is returned when the active gcloud configuration does not exist, for example because the
+// gcloud CLI is not installed or was never initialized.
+var ErrNotFound = errors.New("gcloud configuration not found")
+
+// Environment variables the gcloud CLI reads to locate its configuration and override properties.
+const (
+	EnvConfigDir        = "CLOUDSDK_CONFIG"             // EnvConfigDir replaces the configuration directory
+	EnvActiveConfigName = "CLOUDSDK_ACTIVE_CONFIG_NAME" // EnvActiveConfigName selects the active configuration
+	EnvCoreProject      = "CLOUDSDK_CORE_PROJECT"       // EnvCoreProject overrides the core/project property
+)
+
+// defaultConfigName is the configuration gcloud uses when none was activated.
+const defaultConfigName = "default"
+
+// Config is the active gcloud configuration.
+type Config struct {
+	Name       string            // Name is the configuration name, e.g. "default"
+	Path       string            // Path is the configuration file that was read
+	Project    string            // Project is the core/project property, after the environment override
+	Properties map[string]string // Properties holds every property in the file, keyed by "section/name"
+}
+
+// Dir returns the gcloud configuration directory: CLOUDSDK_CONFIG when set, and otherwise
+// %APPDATA%\gcloud on Windows and ~/.config/gcloud elsewhere. Without a home directory it returns an
+// error wrapping ErrNotFound.
+func Dir(getenv func(key string) string) (string, error) {
+	if dir := getenv(EnvConfigDir); dir != "" {
+		return dir, nil
+	}
+	if runtime.GOOS == "windows" {
+		if appData := getenv("APPDATA"); appData != "" {
+			return filepath.Join(appData, "gcloud"), nil
+		}
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("%w: no home directory: %w", ErrNotFound, err)
+	}
+
+	return filepath.Join(home, ".config", "gcloud"), nil
+}
+
+// Load reads the active configuration in dir: the one named by CLOUDSDK_ACTIVE_CONFIG_NAME, or by the
+// active_config file, or "default". It returns an error wrapping ErrNotFound when the configuration file
+// does not exist.
+func Load(dir string, getenv func(key string) string) (*Config, error) {
+	name, err := activeName(dir, getenv)
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(dir, "configurations", "config_"+name)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gcloud configuration: %w", err)
+	}
+
+	properties, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gcloud configuration %s: %w", path, err)
+	}
+
+	project := properties["core/project"]
+	if override := getenv(EnvCoreProject); override != "" {
+		project = override
+	}
+
+	return &Config{Name: name, Path: path, Project: project, Properties: properties}, nil
+}
+
+// Parse reads the INI-style properties of a gcloud configuration file, keyed by "section/name", e.g.
+// "core/project". Blank lines and lines starting with '#' or ';' are skipped.
+func Parse(r io.Reader) (map[string]string, error) {
+	properties := make(map[string]string)
+	section := ""
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			section = strings.TrimSpace(line[1 : len(line)-1])
+		default:
+			key, value, ok := strings.Cut(line, "=")
+			if !ok || section == "" {
+				continue
+			}
+			properties[section+"/"+strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan properties: %w", err)
+	}
+
+	return properties, nil
+}
+
+// activeName returns the name of the active configuration in dir.
+func activeName(dir string, getenv func(key string) string) (string, error) {
+	if name := getenv(EnvActiveConfigName); name != "" {
+		return name, nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "active_config"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return defaultConfigName, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read the active gcloud configuration: %w", err)
+	}
+	if name := strings.TrimSpace(string(data)); name != "" {
+		return name, nil
+	}
+
+	return defaultConfigName, nil
+}
@@ -0,0 +1,120 @@
+package gcloudconfig_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/internal/gcloudconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	input := `
+; comment
+[core]
+account = jane@example.com
+project=acme-prod
+# another comment
+
+[compute]
+region = europe-west3
+orphan line
+`
+
+	properties, err := gcloudconfig.Parse(strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"core/account":   "jane@example.com",
+		"core/project":   "acme-prod",
+		"compute/region": "europe-west3",
+	}, properties)
+}
+
+func TestLoad(t *testing.T) {
+	tests := map[string]struct {
+		dir         string
+		env         map[string]string
+		wantName    string
+		wantProject string
+		wantErr     error
+	}{
+		"active config file": {
+			dir:         "testdata",
+			wantName:    "staging",
+			wantProject: "acme-staging",
+		},
+		"active config from the environment": {
+			dir:         "testdata",
+			env:         map[string]string{gcloudconfig.EnvActiveConfigName: "default"},
+			wantName:    "default",
+			wantProject: "acme-billing-prod",
+		},
+		"project overridden by the environment": {
+			dir:         "testdata",
+			env:         map[string]string{gcloudconfig.EnvCoreProject: "acme-sandbox"},
+			wantName:    "staging",
+			wantProject: "acme-sandbox",
+		},
+		"default config without active config file": {
+			dir:     t.TempDir(),
+			wantErr: gcloudconfig.ErrNotFound,
+		},
+		"missing named config": {
+			dir:     "testdata",
+			env:     map[string]string{gcloudconfig.EnvActiveConfigName: "missing"},
+			wantErr: gcloudconfig.ErrNotFound,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+
+			cfg, err := gcloudconfig.Load(tt.dir, getenv)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, cfg.Name)
+			assert.Equal(t, tt.wantProject, cfg.Project)
+			assert.Equal(t, filepath.Join(tt.dir, "configurations", "config_"+tt.wantName), cfg.Path)
+		})
+	}
+}
+
+func TestLoad_Properties(t *testing.T) {
+	getenv := func(key string) string {
+		if key == gcloudconfig.EnvActiveConfigName {
+			return "default"
+		}
+
+		return ""
+	}
+
+	cfg, err := gcloudconfig.Load("testdata", getenv)
+	require.NoError(t, err)
+	assert.Equal(t, "europe-west3", cfg.Properties["compute/region"])
+	assert.Equal(t, "jane@example.com", cfg.Properties["core/account"])
+}
+
+func TestDir(t *testing.T) {
+	getenv := func(key string) string {
+		if key == gcloudconfig.EnvConfigDir {
+			return "/etc/gcloud"
+		}
+
+		return ""
+	}
+
+	dir, err := gcloudconfig.Dir(getenv)
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/gcloud", dir)
+
+	dir, err = gcloudconfig.Dir(func(string) string { return "" })
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(dir, filepath.Join(".config", "gcloud")), dir)
+}
@@ -0,0 +1,26 @@
+// Package grpcdebug switches gRPC's own logging to its most verbose level for deep debugging.
+package grpcdebug
+
+import (
+	"io"
+	"os"
+
+	"google.golang.org/grpc/grpclog"
+)
+
+// verbosity is the gRPC log verbosity installed by Enable, high enough to include every V-level message,
+// like GRPC_GO_LOG_VERBOSITY_LEVEL=99.
+const verbosity = 99
+
+// Enable installs a gRPC logger that writes info, warning and error messages of every verbosity to w,
+// like GRPC_GO_LOG_SEVERITY_LEVEL=info with GRPC_GO_LOG_VERBOSITY_LEVEL=99. The environment variables
+// are only read when gRPC is loaded, so the logger is installed directly instead. It must be called
+// before any gRPC client is created. The returned function restores gRPC's default logger, which
+// writes only errors to stderr.
+func Enable(w io.Writer) (restore func()) {
+	grpclog.SetLoggerV2(grpclog.NewLoggerV2WithVerbosity(w, w, w, verbosity))
+
+	return func() {
+		grpclog.SetLoggerV2(grpclog.NewLoggerV2(io.Discard, io.Discard, os.Stderr))
+	}
+}
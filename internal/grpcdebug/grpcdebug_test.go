@@ -0,0 +1,26 @@
+package grpcdebug_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/internal/grpcdebug"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/grpclog"
+)
+
+func TestEnable(t *testing.T) {
+	var buf bytes.Buffer
+	restore := grpcdebug.Enable(&buf)
+
+	assert.True(t, grpclog.V(2), "every verbosity level should be enabled")
+	grpclog.Info("connecting")
+	assert.Contains(t, buf.String(), "connecting")
+
+	restore()
+	buf.Reset()
+
+	assert.False(t, grpclog.V(2), "the default logger should be restored")
+	grpclog.Info("connected")
+	assert.Empty(t, buf.String())
+}
@@ -0,0 +1,91 @@
+// Package pager shows output that does not fit on the terminal through the user's pager program.
+package pager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// DefaultCommand is the pager used when PAGER is unset. The -R option passes ANSI escape sequences
+// through, so colored output stays readable.
+const DefaultCommand = "less -R"
+
+// Mode selects when output is paged.
+type Mode int
+
+const (
+	// Auto pages output with more lines than the terminal when stdout is a terminal.
+	Auto Mode = iota
+	// Always pages output of any length, even when stdout is not a terminal.
+	Always
+	// Never writes output directly.
+	Never
+)
+
+// Command returns the pager command line: the PAGER environment variable read with getenv, or
+// DefaultCommand when it is unset or blank.
+func Command(getenv func(key string) string) string {
+	if command := strings.TrimSpace(getenv("PAGER")); command != "" {
+		return command
+	}
+
+	return DefaultCommand
+}
+
+// Pager pipes output through a pager program when it is longer than the terminal.
+type Pager struct {
+	Command string // Command is the pager command line, split on whitespace, e.g. "less -R"
+	Height  int    // Height is the terminal height in lines; zero means unknown, and output is not paged
+	Always  bool   // Always pages output of any length
+}
+
+// Write writes data to stdout, through the pager when data has more lines than Height or Always is set.
+// The pager's own messages go to stderr. When the pager program cannot be found, data is written
+// directly, so that the output is not lost. Cancelling ctx kills a running pager.
+func (p Pager) Write(ctx context.Context, stdout, stderr io.Writer, data []byte) error {
+	if !p.Always && (p.Height <= 0 || Lines(data) <= p.Height) {
+		return write(stdout, data)
+	}
+
+	fields := strings.Fields(p.Command)
+	if len(fields) == 0 {
+		return write(stdout, data)
+	}
+	path, err := exec.LookPath(fields[0])
+	if err != nil {
+		return write(stdout, data)
+	}
+
+	cmd := exec.CommandContext(ctx, path, fields[1:]...)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run pager %s: %w", fields[0], err)
+	}
+
+	return nil
+}
+
+// Lines returns the number of lines in data, counting a final line without a newline.
+func Lines(data []byte) int {
+	lines := bytes.Count(data, []byte("\n"))
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		lines++
+	}
+
+	return lines
+}
+
+// write writes data to w.
+func write(w io.Writer, data []byte) error {
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+
+	return nil
+}
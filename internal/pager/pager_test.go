@@ -0,0 +1,131 @@
+package pager_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/internal/pager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand(t *testing.T) {
+	tests := map[string]struct {
+		env  string
+		want string
+	}{
+		"unset":       {want: pager.DefaultCommand},
+		"blank":       {env: "  ", want: pager.DefaultCommand},
+		"from PAGER":  {env: "more", want: "more"},
+		"with option": {env: " less -SR ", want: "less -SR"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			getenv := func(key string) string {
+				if key == "PAGER" {
+					return tt.env
+				}
+
+				return ""
+			}
+			assert.Equal(t, tt.want, pager.Command(getenv))
+		})
+	}
+}
+
+func TestPagerWrite(t *testing.T) {
+	// the upper-casing pager shows whether the output went through it
+	const upper = "tr a-z A-Z"
+	small := "one\ntwo\n"
+	large := strings.Repeat("row\n", 10)
+
+	tests := map[string]struct {
+		pager pager.Pager
+		data  string
+		want  string
+	}{
+		"large output is paged": {
+			pager: pager.Pager{Command: upper, Height: 5},
+			data:  large,
+			want:  strings.Repeat("ROW\n", 10),
+		},
+		"small output is written directly": {
+			pager: pager.Pager{Command: upper, Height: 5},
+			data:  small,
+			want:  small,
+		},
+		"output filling the terminal is written directly": {
+			pager: pager.Pager{Command: upper, Height: 10},
+			data:  large,
+			want:  large,
+		},
+		"unknown height is not paged": {
+			pager: pager.Pager{Command: upper},
+			data:  large,
+			want:  large,
+		},
+		"always pages small output": {
+			pager: pager.Pager{Command: upper, Always: true},
+			data:  small,
+			want:  "ONE\nTWO\n",
+		},
+		"missing pager falls back to direct output": {
+			pager: pager.Pager{Command: "gcphelper-missing-pager", Always: true},
+			data:  small,
+			want:  small,
+		},
+		"empty command falls back to direct output": {
+			pager: pager.Pager{Always: true},
+			data:  small,
+			want:  small,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			require.NoError(t, tt.pager.Write(t.Context(), &stdout, &bytes.Buffer{}, []byte(tt.data)))
+			assert.Equal(t, tt.want, stdout.String())
+		})
+	}
+}
+
+func TestPagerWrite_PagerFails(t *testing.T) {
+	p := pager.Pager{Command: "false", Always: true}
+
+	err := p.Write(t.Context(), &bytes.Buffer{}, &bytes.Buffer{}, []byte("row\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to run pager false")
+}
+
+func TestLines(t *testing.T) {
+	tests := map[string]struct {
+		data string
+		want int
+	}{
+		"empty":                {want: 0},
+		"one line":             {data: "a\n", want: 1},
+		"final line unended":   {data: "a\nb", want: 2},
+		"blank lines included": {data: "a\n\n\n", want: 3},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, pager.Lines([]byte(tt.data)))
+		})
+	}
+}
+
+func TestPagerWrite_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	p := pager.Pager{Command: "cat", Always: true}
+
+	var stdout bytes.Buffer
+	err := p.Write(ctx, &stdout, &bytes.Buffer{}, []byte("row\n"))
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, stdout.String())
+}
@@ -0,0 +1,70 @@
+package progress
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Event is a machine-readable progress event, written as one JSON line.
+type Event struct {
+	Event     string `json:"event"`             // Event is "progress" while fetching and "done" at the end
+	Fetched   *int   `json:"fetched,omitempty"` // Fetched is the number of results fetched so far
+	Total     *int   `json:"total,omitempty"`   // Total is the number of results fetched in all
+	ElapsedMS int64  `json:"elapsed_ms"`        // ElapsedMS is the time since the reporter was created
+}
+
+// Event names.
+const (
+	EventProgress = "progress" // EventProgress reports the results fetched so far
+	EventDone     = "done"     // EventDone reports the total once fetching has finished
+)
+
+// JSONReporter writes progress events as JSON lines, for consumers such as CI dashboards that cannot
+// read a spinner. Progress events are written at most once per interval; the first one is written
+// immediately. It is safe for concurrent use.
+type JSONReporter struct {
+	mu       sync.Mutex
+	enc      *json.Encoder
+	interval time.Duration
+	start    time.Time
+	last     time.Time
+	fetched  int
+	reported bool
+}
+
+// NewJSONReporter returns a reporter writing to w at most one progress event per interval.
+func NewJSONReporter(w io.Writer, interval time.Duration) *JSONReporter {
+	return &JSONReporter{enc: json.NewEncoder(w), interval: interval, start: time.Now()}
+}
+
+// Report records that fetched results have been fetched so far and writes a progress event when the
+// interval has passed since the previous one.
+func (r *JSONReporter) Report(fetched int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.fetched = fetched
+	now := time.Now()
+	if r.reported && now.Sub(r.last) < r.interval {
+		return
+	}
+	r.reported = true
+	r.last = now
+	r.write(Event{Event: EventProgress, Fetched: &fetched, ElapsedMS: now.Sub(r.start).Milliseconds()})
+}
+
+// Done writes the final event with the number of results fetched last reported.
+func (r *JSONReporter) Done() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	total := r.fetched
+	r.write(Event{Event: EventDone, Total: &total, ElapsedMS: time.Since(r.start).Milliseconds()})
+}
+
+// write encodes the event, ignoring write errors as progress output must not fail the operation.
+func (r *JSONReporter) write(event Event) {
+	_ = r.enc.Encode(event)
+}
@@ -0,0 +1,91 @@
+package progress_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/andreygrechin/gcphelper/internal/progress"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decodeEvents decodes the JSON lines written by a reporter, rejecting unknown fields.
+func decodeEvents(t *testing.T, data []byte) []progress.Event {
+	t.Helper()
+
+	var events []progress.Event
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var event progress.Event
+		dec := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		dec.DisallowUnknownFields()
+		require.NoError(t, dec.Decode(&event), "line %q", scanner.Text())
+		events = append(events, event)
+	}
+	require.NoError(t, scanner.Err())
+
+	return events
+}
+
+func TestJSONReporter(t *testing.T) {
+	// pages of results as a paged API would deliver them
+	pages := [][]string{{"1", "2", "3"}, {"4", "5"}, {"6"}}
+
+	tests := map[string]struct {
+		interval    time.Duration
+		wantFetched []int
+	}{
+		"every report without an interval": {
+			interval:    0,
+			wantFetched: []int{3, 5, 6},
+		},
+		"first report only within the interval": {
+			interval:    time.Hour,
+			wantFetched: []int{3},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			reporter := progress.NewJSONReporter(&buf, tt.interval)
+
+			fetched := 0
+			for _, page := range pages {
+				fetched += len(page)
+				reporter.Report(fetched)
+			}
+			reporter.Done()
+
+			events := decodeEvents(t, buf.Bytes())
+			require.Len(t, events, len(tt.wantFetched)+1)
+			for i, want := range tt.wantFetched {
+				assert.Equal(t, progress.EventProgress, events[i].Event)
+				require.NotNil(t, events[i].Fetched)
+				assert.Equal(t, want, *events[i].Fetched)
+				assert.Nil(t, events[i].Total)
+				assert.GreaterOrEqual(t, events[i].ElapsedMS, int64(0))
+			}
+			done := events[len(events)-1]
+			assert.Equal(t, progress.EventDone, done.Event)
+			require.NotNil(t, done.Total)
+			assert.Equal(t, 6, *done.Total)
+			assert.Nil(t, done.Fetched)
+		})
+	}
+}
+
+func TestJSONReporterEventFields(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := progress.NewJSONReporter(&buf, 0)
+	reporter.Report(0)
+
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &fields))
+	assert.JSONEq(t, `"progress"`, string(fields["event"]))
+	assert.JSONEq(t, `0`, string(fields["fetched"]), "a zero count is still written")
+	assert.Contains(t, fields, "elapsed_ms")
+}
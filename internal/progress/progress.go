@@ -0,0 +1,39 @@
+// Package progress shows progress indicators that are cleared as soon as the operation is cancelled.
+package progress
+
+import (
+	"context"
+	"sync"
+)
+
+// Spinner is a progress indicator that can be started and stopped, such as *spinner.Spinner.
+type Spinner interface {
+	Start()
+	Stop()
+}
+
+// Start starts the spinner and returns a function that stops it. The spinner is also stopped as soon
+// as ctx is done, so that cancellation clears the terminal without waiting for the operation in
+// progress to return. The spinner is stopped at most once, however often the function is called.
+func Start(ctx context.Context, spin Spinner) func() {
+	spin.Start()
+
+	var once sync.Once
+	done := make(chan struct{})
+	stop := func() {
+		once.Do(func() {
+			close(done)
+			spin.Stop()
+		})
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			stop()
+		case <-done:
+		}
+	}()
+
+	return stop
+}
@@ -0,0 +1,53 @@
+package progress_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andreygrechin/gcphelper/internal/progress"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSpinner counts how often it is started and stopped.
+type fakeSpinner struct {
+	starts atomic.Int32
+	stops  atomic.Int32
+}
+
+func (s *fakeSpinner) Start() { s.starts.Add(1) }
+func (s *fakeSpinner) Stop()  { s.stops.Add(1) }
+
+func TestStartStopsOnCancellation(t *testing.T) {
+	spin := &fakeSpinner{}
+	ctx, cancel := context.WithCancel(t.Context())
+
+	stop := progress.Start(ctx, spin)
+	assert.Equal(t, int32(1), spin.starts.Load())
+	assert.Equal(t, int32(0), spin.stops.Load())
+
+	// the spinner stops before the operation returns and calls stop
+	cancel()
+	assert.Eventually(t, func() bool { return spin.stops.Load() == 1 }, time.Second, time.Millisecond)
+
+	// the deferred stop of the finished operation does not stop it again
+	stop()
+	stop()
+	assert.Equal(t, int32(1), spin.stops.Load())
+}
+
+func TestStartStopsOnce(t *testing.T) {
+	spin := &fakeSpinner{}
+	ctx, cancel := context.WithCancel(t.Context())
+
+	stop := progress.Start(ctx, spin)
+	stop()
+	assert.Equal(t, int32(1), spin.stops.Load())
+
+	// cancelling after a normal stop leaves the spinner stopped once
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+	stop()
+	assert.Equal(t, int32(1), spin.stops.Load())
+}
@@ -0,0 +1,134 @@
+// Package proxy routes the gRPC connections of the API clients through an HTTP proxy.
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+)
+
+// ErrInvalidProxy is returned when a proxy URL cannot be parsed or uses an unsupported scheme.
+var ErrInvalidProxy = errors.New("invalid proxy URL")
+
+// ErrProxyConnect is returned when the proxy refuses to open a tunnel to the API endpoint.
+var ErrProxyConnect = errors.New("proxy refused the connection")
+
+// ClientOptions returns client options that dial the API endpoints through proxyURL, or through the
+// proxy selected by the HTTPS_PROXY and NO_PROXY environment variables when proxyURL is empty. It
+// returns no options when no proxy is configured, leaving connections direct.
+func ClientOptions(proxyURL string) ([]option.ClientOption, error) {
+	config := httpproxy.FromEnvironment()
+	if proxyURL != "" {
+		if _, err := parse(proxyURL); err != nil {
+			return nil, err
+		}
+		config = &httpproxy.Config{HTTPSProxy: proxyURL}
+	}
+	if config.HTTPSProxy == "" {
+		return nil, nil
+	}
+
+	return []option.ClientOption{
+		option.WithGRPCDialOption(grpc.WithContextDialer(Dialer(config.ProxyFunc()))),
+	}, nil
+}
+
+// Dialer returns a gRPC dialer that opens an HTTP CONNECT tunnel to each address through the proxy
+// that proxyFor selects for it, and dials addresses without a proxy directly.
+func Dialer(proxyFor func(*url.URL) (*url.URL, error)) func(context.Context, string) (net.Conn, error) {
+	return func(ctx context.Context, addr string) (net.Conn, error) {
+		// API endpoints are always reached over TLS
+		proxyURL, err := proxyFor(&url.URL{Scheme: "https", Host: addr})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidProxy, err)
+		}
+		if proxyURL == nil {
+			return dial(ctx, addr)
+		}
+		if _, err := parse(proxyURL.String()); err != nil {
+			return nil, err
+		}
+
+		return connect(ctx, proxyURL, addr)
+	}
+}
+
+// parse returns the proxy URL, rejecting schemes other than http, the only one that CONNECT tunnels
+// are opened with.
+func parse(rawURL string) (*url.URL, error) {
+	proxyURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidProxy, err)
+	}
+	if proxyURL.Scheme != "http" || proxyURL.Host == "" {
+		return nil, fmt.Errorf("%w: %s (expected http://host:port)", ErrInvalidProxy, rawURL)
+	}
+
+	return proxyURL, nil
+}
+
+// connect dials the proxy and asks it to tunnel the connection to addr.
+func connect(ctx context.Context, proxyURL *url.URL, addr string) (net.Conn, error) {
+	conn, err := dial(ctx, proxyURL.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Host: addr},
+		Host:   addr,
+		Header: http.Header{},
+	}
+	if user := proxyURL.User; user != nil {
+		password, _ := user.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(user.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+		defer func() { _ = conn.SetDeadline(time.Time{}) }()
+	}
+	if err := req.Write(conn); err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to send CONNECT to proxy %s: %w", proxyURL.Host, err), conn.Close())
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to read proxy %s response: %w", proxyURL.Host, err), conn.Close())
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Join(fmt.Errorf("%w: %s to %s: %s", ErrProxyConnect, proxyURL.Host, addr, resp.Status),
+			conn.Close())
+	}
+	// the endpoint only speaks once the client starts the TLS handshake, so nothing may follow the response
+	if reader.Buffered() > 0 {
+		return nil, errors.Join(fmt.Errorf("%w: %s sent data after its response", ErrProxyConnect, proxyURL.Host),
+			conn.Close())
+	}
+
+	return conn, nil
+}
+
+// dial opens a TCP connection to addr.
+func dial(ctx context.Context, addr string) (net.Conn, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %w", addr, err)
+	}
+
+	return conn, nil
+}
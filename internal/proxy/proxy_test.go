@@ -0,0 +1,128 @@
+package proxy_test
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/internal/proxy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientOptions(t *testing.T) {
+	tests := map[string]struct {
+		proxyURL   string
+		httpsProxy string
+		wantOpts   bool
+		wantErr    error
+	}{
+		"no proxy":              {},
+		"proxy flag":            {proxyURL: "http://proxy.example.com:3128", wantOpts: true},
+		"https proxy env":       {httpsProxy: "http://proxy.example.com:3128", wantOpts: true},
+		"flag overrides env":    {proxyURL: "http://flag.example.com:3128", httpsProxy: "bad", wantOpts: true},
+		"unsupported scheme":    {proxyURL: "socks5://proxy.example.com:1080", wantErr: proxy.ErrInvalidProxy},
+		"missing host":          {proxyURL: "http://", wantErr: proxy.ErrInvalidProxy},
+		"unparsable proxy flag": {proxyURL: "http://[::1", wantErr: proxy.ErrInvalidProxy},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("HTTPS_PROXY", tt.httpsProxy)
+			t.Setenv("https_proxy", "")
+
+			opts, err := proxy.ClientOptions(tt.proxyURL)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			if tt.wantOpts {
+				assert.Len(t, opts, 1)
+			} else {
+				assert.Empty(t, opts)
+			}
+		})
+	}
+}
+
+// serveProxy accepts one connection on listener, answers its CONNECT request with status and sends
+// the requested target and Proxy-Authorization header on requests.
+func serveProxy(t *testing.T, listener net.Listener, status int, requests chan<- *http.Request) {
+	t.Helper()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		req, err := http.ReadRequest(bufio.NewReader(conn))
+		if err != nil {
+			return
+		}
+		requests <- req
+		resp := &http.Response{StatusCode: status, ProtoMajor: 1, ProtoMinor: 1}
+		_ = resp.Write(conn)
+	}()
+}
+
+func TestDialer(t *testing.T) {
+	tests := map[string]struct {
+		status   int
+		user     *url.Userinfo
+		wantAuth string
+		wantErr  error
+	}{
+		"tunnel opened": {status: http.StatusOK},
+		"basic auth": {
+			status:   http.StatusOK,
+			user:     url.UserPassword("alice", "secret"),
+			wantAuth: "Basic YWxpY2U6c2VjcmV0",
+		},
+		"tunnel refused": {status: http.StatusForbidden, wantErr: proxy.ErrProxyConnect},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = listener.Close() })
+			requests := make(chan *http.Request, 1)
+			serveProxy(t, listener, tt.status, requests)
+
+			proxyURL := &url.URL{Scheme: "http", Host: listener.Addr().String(), User: tt.user}
+			dial := proxy.Dialer(func(*url.URL) (*url.URL, error) { return proxyURL, nil })
+
+			conn, err := dial(t.Context(), "cloudresourcemanager.googleapis.com:443")
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = conn.Close() })
+
+			req := <-requests
+			assert.Equal(t, http.MethodConnect, req.Method)
+			assert.Equal(t, "cloudresourcemanager.googleapis.com:443", req.Host)
+			assert.Equal(t, tt.wantAuth, req.Header.Get("Proxy-Authorization"))
+		})
+	}
+}
+
+func TestDialerWithoutProxy(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	// addresses the proxy function excludes, as NO_PROXY does, are dialed directly
+	dial := proxy.Dialer(func(*url.URL) (*url.URL, error) { return nil, nil })
+	conn, err := dial(t.Context(), listener.Addr().String())
+	require.NoError(t, err)
+	assert.NoError(t, conn.Close())
+}
@@ -0,0 +1,52 @@
+// Package ratelimit throttles outgoing Google API calls to stay within request quotas.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+)
+
+// ErrInvalidQPS is returned when the requested rate is negative or not a finite number.
+var ErrInvalidQPS = errors.New("invalid QPS")
+
+// ClientOptions returns client options that make every API call, including each page of a listing
+// and each retry attempt, wait on a single limiter allowing qps calls per second. All clients created
+// with the returned options share the limiter. A qps of zero returns no options, leaving calls unlimited.
+func ClientOptions(qps float64) ([]option.ClientOption, error) {
+	if qps < 0 || math.IsNaN(qps) || math.IsInf(qps, 0) {
+		return nil, fmt.Errorf("%w: %v (must be zero or a positive number)", ErrInvalidQPS, qps)
+	}
+	if qps == 0 {
+		return nil, nil
+	}
+
+	limiter := rate.NewLimiter(rate.Limit(qps), 1)
+
+	return []option.ClientOption{
+		option.WithGRPCDialOption(grpc.WithChainUnaryInterceptor(UnaryInterceptor(limiter))),
+	}, nil
+}
+
+// UnaryInterceptor returns a gRPC client interceptor that waits on limiter before each call.
+func UnaryInterceptor(limiter *rate.Limiter) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		if err := limiter.Wait(ctx); err != nil {
+			return fmt.Errorf("failed to wait for rate limit before %s: %w", method, err)
+		}
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,97 @@
+package ratelimit_test
+
+import (
+	"context"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andreygrechin/gcphelper/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+)
+
+func TestUnaryInterceptorThrottles(t *testing.T) {
+	const (
+		qps     = 50
+		calls   = 6
+		workers = 3
+	)
+	limiter := rate.NewLimiter(qps, 1)
+	interceptor := ratelimit.UnaryInterceptor(limiter)
+
+	var mu sync.Mutex
+	invoked := 0
+	invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		mu.Lock()
+		defer mu.Unlock()
+		invoked++
+
+		return nil
+	}
+
+	// concurrent workers share the limiter, so the total rate is still qps
+	start := time.Now()
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range calls / workers {
+				assert.NoError(t, interceptor(t.Context(), "/test.Service/List", nil, nil, nil, invoker))
+			}
+		}()
+	}
+	wg.Wait()
+
+	// the first call is immediate and each later one waits 1/qps
+	minimum := time.Duration(calls-1) * time.Second / qps
+	assert.GreaterOrEqual(t, time.Since(start), minimum)
+	assert.Equal(t, calls, invoked)
+}
+
+func TestUnaryInterceptorCancelled(t *testing.T) {
+	limiter := rate.NewLimiter(0.001, 1)
+	interceptor := ratelimit.UnaryInterceptor(limiter)
+	invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		return nil
+	}
+
+	require.NoError(t, interceptor(t.Context(), "/test.Service/Get", nil, nil, nil, invoker))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	err := interceptor(ctx, "/test.Service/Get", nil, nil, nil, invoker)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "/test.Service/Get")
+}
+
+func TestClientOptions(t *testing.T) {
+	tests := map[string]struct {
+		qps      float64
+		wantOpts int
+		wantErr  error
+	}{
+		"unlimited":    {qps: 0, wantOpts: 0},
+		"limited":      {qps: 2.5, wantOpts: 1},
+		"negative":     {qps: -1, wantErr: ratelimit.ErrInvalidQPS},
+		"not a number": {qps: math.NaN(), wantErr: ratelimit.ErrInvalidQPS},
+		"infinite":     {qps: math.Inf(1), wantErr: ratelimit.ErrInvalidQPS},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts, err := ratelimit.ClientOptions(tt.qps)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, opts, tt.wantOpts)
+		})
+	}
+}
@@ -0,0 +1,21 @@
+// Package reqmeta attaches Google API request metadata to outgoing gRPC calls.
+package reqmeta
+
+import (
+	"context"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// RequestReasonHeader is the metadata key carrying the justification for a request.
+const RequestReasonHeader = "x-goog-request-reason"
+
+// WithRequestReason returns a context that attaches reason to outgoing gRPC calls.
+// The context is returned unchanged when reason is empty.
+func WithRequestReason(ctx context.Context, reason string) context.Context {
+	if reason == "" {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, RequestReasonHeader, reason)
+}
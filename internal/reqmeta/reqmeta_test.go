@@ -0,0 +1,34 @@
+package reqmeta_test
+
+import (
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/internal/reqmeta"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestWithRequestReason(t *testing.T) {
+	tests := map[string]struct {
+		reason string
+		want   []string
+	}{
+		"attaches reason": {
+			reason: "ticket-123",
+			want:   []string{"ticket-123"},
+		},
+		"empty reason adds no header": {
+			reason: "",
+			want:   nil,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := reqmeta.WithRequestReason(t.Context(), tt.reason)
+
+			md, _ := metadata.FromOutgoingContext(ctx)
+			assert.Equal(t, tt.want, md.Get(reqmeta.RequestReasonHeader))
+		})
+	}
+}
@@ -0,0 +1,27 @@
+// Package resourcename extracts numeric IDs from Resource Manager resource names such as "folders/123".
+package resourcename
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrMalformedName is returned when a resource name does not have the expected "prefix/123" form.
+var ErrMalformedName = errors.New("malformed resource name")
+
+// ExtractID returns the ID of a resource name with the given prefix, such as "123" for "folders/123" and
+// "folders/". The prefix must be present and the remainder must be a non-empty number.
+func ExtractID(name, prefix string) (string, error) {
+	id, ok := strings.CutPrefix(name, prefix)
+	switch {
+	case !ok:
+		return "", fmt.Errorf("%w: %q does not start with %q", ErrMalformedName, name, prefix)
+	case id == "":
+		return "", fmt.Errorf("%w: %q has no ID after %q", ErrMalformedName, name, prefix)
+	case strings.Trim(id, "0123456789") != "":
+		return "", fmt.Errorf("%w: %q has a non-numeric ID %q", ErrMalformedName, name, id)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,41 @@
+package resourcename_test
+
+import (
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/internal/resourcename"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractID(t *testing.T) {
+	tests := map[string]struct {
+		name    string
+		prefix  string
+		want    string
+		wantErr string
+	}{
+		"folder":         {name: "folders/123456789", prefix: "folders/", want: "123456789"},
+		"organization":   {name: "organizations/42", prefix: "organizations/", want: "42"},
+		"missing prefix": {name: "123456789", prefix: "folders/", wantErr: `does not start with "folders/"`},
+		"other prefix":   {name: "organizations/42", prefix: "folders/", wantErr: `does not start with "folders/"`},
+		"empty id":       {name: "folders/", prefix: "folders/", wantErr: `has no ID after "folders/"`},
+		"empty name":     {name: "", prefix: "folders/", wantErr: `does not start with "folders/"`},
+		"non-numeric id": {name: "folders/abc", prefix: "folders/", wantErr: `non-numeric ID "abc"`},
+		"nested path":    {name: "folders/1/2", prefix: "folders/", wantErr: `non-numeric ID "1/2"`},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := resourcename.ExtractID(tt.name, tt.prefix)
+			if tt.wantErr != "" {
+				require.ErrorIs(t, err, resourcename.ErrMalformedName)
+				assert.Contains(t, err.Error(), tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// Package retry retries Google API calls rejected for exhausted quota, honoring the server's suggested delay.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/api/option"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrInvalidMaxRetries is returned when the requested number of retries is negative.
+var ErrInvalidMaxRetries = errors.New("invalid max retries")
+
+// DefaultMaxRetries is the number of retries used unless --max-retries says otherwise.
+const DefaultMaxRetries = 3
+
+// Backoff bounds used when the server does not suggest a delay.
+const (
+	initialBackoff = time.Second
+	maxBackoff     = 32 * time.Second
+)
+
+// SleepFunc waits for d, returning early with an error when ctx is done.
+type SleepFunc func(ctx context.Context, d time.Duration) error
+
+// ClientOptions returns client options that retry calls failing with ResourceExhausted up to
+// maxRetries times. Zero retries returns no options.
+func ClientOptions(maxRetries int) ([]option.ClientOption, error) {
+	if maxRetries < 0 {
+		return nil, fmt.Errorf("%w: %d (must be zero or a positive number)", ErrInvalidMaxRetries, maxRetries)
+	}
+	if maxRetries == 0 {
+		return nil, nil
+	}
+
+	return []option.ClientOption{
+		option.WithGRPCDialOption(grpc.WithChainUnaryInterceptor(UnaryInterceptor(maxRetries, Sleep))),
+	}, nil
+}
+
+// UnaryInterceptor returns a gRPC client interceptor that retries calls failing with ResourceExhausted
+// up to maxRetries times, waiting with sleep before each retry for the delay returned by Delay. The last
+// error is returned unchanged once the retries are used up or the context is done.
+func UnaryInterceptor(maxRetries int, sleep SleepFunc) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		for attempt := 0; ; attempt++ {
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			if attempt == maxRetries || status.Code(err) != codes.ResourceExhausted {
+				return err
+			}
+			if sleepErr := sleep(ctx, Delay(err, attempt)); sleepErr != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Delay returns how long to wait before retrying after err on the given zero-based attempt: the
+// RetryDelay of a RetryInfo detail when the status carries one, and otherwise an exponential backoff
+// starting at one second and capped at 32 seconds.
+func Delay(err error, attempt int) time.Duration {
+	if st, ok := status.FromError(err); ok {
+		for _, detail := range st.Details() {
+			if info, ok := detail.(*errdetails.RetryInfo); ok && info.GetRetryDelay() != nil {
+				return max(info.GetRetryDelay().AsDuration(), 0)
+			}
+		}
+	}
+
+	backoff := initialBackoff
+	for range attempt {
+		backoff *= 2
+		if backoff >= maxBackoff {
+			return maxBackoff
+		}
+	}
+
+	return backoff
+}
+
+// Sleep waits for d or until ctx is done, whichever comes first.
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("retry wait cancelled: %w", ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
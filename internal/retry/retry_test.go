@@ -0,0 +1,148 @@
+package retry_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/andreygrechin/gcphelper/internal/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const method = "/google.cloud.resourcemanager.v3.Folders/SearchFolders"
+
+// exhausted returns a ResourceExhausted error, with a RetryInfo detail when delay is positive.
+func exhausted(t *testing.T, delay time.Duration) error {
+	t.Helper()
+
+	st := status.New(codes.ResourceExhausted, "quota exceeded")
+	if delay > 0 {
+		var err error
+		st, err = st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(delay)})
+		require.NoError(t, err)
+	}
+
+	return st.Err()
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	tests := map[string]struct {
+		errs       func(t *testing.T) []error
+		maxRetries int
+		wantWaits  []time.Duration
+		wantCalls  int
+		wantCode   codes.Code
+	}{
+		"waits the RetryInfo delay": {
+			errs: func(t *testing.T) []error {
+				return []error{exhausted(t, 7*time.Second), nil}
+			},
+			maxRetries: 3,
+			wantWaits:  []time.Duration{7 * time.Second},
+			wantCalls:  2,
+			wantCode:   codes.OK,
+		},
+		"exponential backoff without RetryInfo": {
+			errs: func(t *testing.T) []error {
+				return []error{exhausted(t, 0), exhausted(t, 0), exhausted(t, 0), nil}
+			},
+			maxRetries: 3,
+			wantWaits:  []time.Duration{time.Second, 2 * time.Second, 4 * time.Second},
+			wantCalls:  4,
+			wantCode:   codes.OK,
+		},
+		"gives up after max retries": {
+			errs: func(t *testing.T) []error {
+				return []error{exhausted(t, time.Second), exhausted(t, time.Second), exhausted(t, time.Second)}
+			},
+			maxRetries: 1,
+			wantWaits:  []time.Duration{time.Second},
+			wantCalls:  2,
+			wantCode:   codes.ResourceExhausted,
+		},
+		"other codes are not retried": {
+			errs: func(*testing.T) []error {
+				return []error{status.Error(codes.PermissionDenied, "denied")}
+			},
+			maxRetries: 3,
+			wantCalls:  1,
+			wantCode:   codes.PermissionDenied,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			errs := tt.errs(t)
+			calls := 0
+			invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+				err := errs[calls]
+				calls++
+
+				return err
+			}
+			var waits []time.Duration
+			sleep := func(_ context.Context, d time.Duration) error {
+				waits = append(waits, d)
+
+				return nil
+			}
+
+			err := retry.UnaryInterceptor(tt.maxRetries, sleep)(t.Context(), method, nil, nil, nil, invoker)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Equal(t, tt.wantCalls, calls)
+			assert.Equal(t, tt.wantWaits, waits)
+		})
+	}
+}
+
+func TestUnaryInterceptorStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	calls := 0
+	invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		calls++
+
+		return exhausted(t, time.Hour)
+	}
+
+	err := retry.UnaryInterceptor(3, retry.Sleep)(ctx, method, nil, nil, nil, invoker)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, 1, calls)
+}
+
+func TestDelay(t *testing.T) {
+	assert.Equal(t, 90*time.Second, retry.Delay(exhausted(t, 90*time.Second), 0))
+	assert.Equal(t, 8*time.Second, retry.Delay(exhausted(t, 0), 3))
+	assert.Equal(t, 32*time.Second, retry.Delay(exhausted(t, 0), 10))
+}
+
+func TestClientOptions(t *testing.T) {
+	tests := map[string]struct {
+		maxRetries int
+		wantOpts   int
+		wantErr    error
+	}{
+		"disabled": {maxRetries: 0, wantOpts: 0},
+		"enabled":  {maxRetries: 3, wantOpts: 1},
+		"negative": {maxRetries: -1, wantErr: retry.ErrInvalidMaxRetries},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts, err := retry.ClientOptions(tt.maxRetries)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, opts, tt.wantOpts)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+// Package selector implements a line-based fuzzy selector for picking one item in a terminal.
+package selector
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrNoSelection is returned when the selection is cancelled or the input ends before an item is picked.
+var ErrNoSelection = errors.New("no item selected")
+
+// ErrNoItems is returned when there are no items to select from.
+var ErrNoItems = errors.New("no items to select")
+
+// maxShown is the number of matches listed per prompt.
+const maxShown = 20
+
+// Item is one selectable entry.
+type Item struct {
+	ID    string // ID is returned to the caller when the item is picked
+	Label string // Label is shown next to the ID and matched against the query
+}
+
+// Match reports whether query fuzzy-matches s: every rune of the query, ignoring spaces, appears in s
+// in order, case-insensitively. An empty query matches everything.
+func Match(query, s string) bool {
+	target := []rune(strings.ToLower(s))
+	pos := 0
+	for _, r := range strings.ToLower(query) {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		for pos < len(target) && target[pos] != r {
+			pos++
+		}
+		if pos == len(target) {
+			return false
+		}
+		pos++
+	}
+
+	return true
+}
+
+// Filter returns the items whose label or ID fuzzy-match query, preserving order.
+func Filter(items []Item, query string) []Item {
+	matched := make([]Item, 0, len(items))
+	for _, item := range items {
+		if Match(query, item.Label) || Match(query, item.ID) {
+			matched = append(matched, item)
+		}
+	}
+
+	return matched
+}
+
+// Select lists the items on out and reads lines from in until one is picked. A number picks the
+// listed match with that number, an empty line picks the only match, "q" cancels, and any other text
+// narrows the matches to those fuzzy-matching it.
+func Select(in io.Reader, out io.Writer, items []Item) (Item, error) {
+	if len(items) == 0 {
+		return Item{}, ErrNoItems
+	}
+
+	scanner := bufio.NewScanner(in)
+	query := ""
+	matches := items
+	for {
+		printMatches(out, query, matches)
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return Item{}, fmt.Errorf("failed to read selection: %w", err)
+			}
+
+			return Item{}, ErrNoSelection
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "q":
+			return Item{}, ErrNoSelection
+		case line == "" && len(matches) == 1:
+			return matches[0], nil
+		case line == "":
+			continue
+		}
+
+		if n, err := strconv.Atoi(line); err == nil {
+			if n >= 1 && n <= min(len(matches), maxShown) {
+				return matches[n-1], nil
+			}
+			fmt.Fprintf(out, "No match numbered %d.\n", n)
+
+			continue
+		}
+
+		query = line
+		matches = Filter(items, query)
+	}
+}
+
+// printMatches lists the numbered matches for the query, followed by the prompt.
+func printMatches(out io.Writer, query string, matches []Item) {
+	if query != "" {
+		fmt.Fprintf(out, "\nMatches for %q:\n", query)
+	}
+	for i, item := range matches[:min(len(matches), maxShown)] {
+		fmt.Fprintf(out, "%3d) %s  %s\n", i+1, item.ID, item.Label)
+	}
+	if len(matches) > maxShown {
+		fmt.Fprintf(out, "     ... %d more, type to narrow\n", len(matches)-maxShown)
+	}
+
+	switch len(matches) {
+	case 0:
+		fmt.Fprint(out, "No matches. Type to search again, or q to quit: ")
+	case 1:
+		fmt.Fprint(out, "Press Enter to select, type to search again, or q to quit: ")
+	default:
+		fmt.Fprint(out, "Type to search, a number to select, or q to quit: ")
+	}
+}
@@ -0,0 +1,84 @@
+package selector_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/internal/selector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatch(t *testing.T) {
+	tests := map[string]struct {
+		query string
+		s     string
+		want  bool
+	}{
+		"empty query":      {query: "", s: "anything", want: true},
+		"substring":        {query: "prod", s: "team-production", want: true},
+		"scattered runes":  {query: "tmpd", s: "team-production", want: true},
+		"case-insensitive": {query: "PROD", s: "production", want: true},
+		"spaces ignored":   {query: "te pr", s: "team-production", want: true},
+		"out of order":     {query: "dorp", s: "production", want: false},
+		"missing rune":     {query: "prodx", s: "production", want: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, selector.Match(tt.query, tt.s))
+		})
+	}
+}
+
+func TestSelect(t *testing.T) {
+	items := []selector.Item{
+		{ID: "1", Label: "production"},
+		{ID: "2", Label: "staging"},
+		{ID: "3", Label: "prod-eu"},
+	}
+
+	tests := map[string]struct {
+		input   string
+		want    selector.Item
+		wantErr error
+	}{
+		"number picks a listed item": {input: "2\n", want: items[1]},
+		"query then number":          {input: "prod\n2\n", want: items[2]},
+		"enter picks the only match": {input: "stag\n\n", want: items[1]},
+		"enter with several matches": {input: "\nprodeu\n\n", want: items[2]},
+		"out of range number":        {input: "7\n1\n", want: items[0]},
+		"no matches then retry":      {input: "xyz\nstaging\n\n", want: items[1]},
+		"quit":                       {input: "q\n", wantErr: selector.ErrNoSelection},
+		"end of input":               {input: "prod\n", wantErr: selector.ErrNoSelection},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := selector.Select(strings.NewReader(tt.input), &bytes.Buffer{}, items)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSelectNoItems(t *testing.T) {
+	_, err := selector.Select(strings.NewReader("1\n"), &bytes.Buffer{}, nil)
+	require.ErrorIs(t, err, selector.ErrNoItems)
+}
+
+func TestSelectListsMatches(t *testing.T) {
+	items := []selector.Item{{ID: "1", Label: "production"}, {ID: "2", Label: "staging"}}
+	var out bytes.Buffer
+
+	_, err := selector.Select(strings.NewReader("stag\n\n"), &out, items)
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "  1) 1  production\n  2) 2  staging\n")
+	assert.Contains(t, out.String(), "Matches for \"stag\":\n  1) 2  staging\n")
+}
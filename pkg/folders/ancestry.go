@@ -0,0 +1,145 @@
+package folders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/singleflight"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrAncestryCycle is returned when a folder's parents lead back to the folder itself.
+var ErrAncestryCycle = errors.New("folder ancestry contains a cycle")
+
+// Getter retrieves a single folder by its resource name.
+type Getter interface {
+	GetFolder(ctx context.Context, name string) (*Folder, error)
+}
+
+// AncestryResolver resolves the folder ancestry of folders, memoizing every folder lookup for the
+// lifetime of a command so that ancestors shared by many folders are fetched once. It is safe for
+// concurrent use, and concurrent lookups of the same folder share a single underlying call.
+type AncestryResolver struct {
+	getter Getter
+	group  singleflight.Group
+
+	mu      sync.Mutex
+	entries map[string]folderEntry
+}
+
+// folderEntry is the memoized result of one folder lookup.
+type folderEntry struct {
+	folder *Folder
+	err    error
+}
+
+// NewAncestryResolver creates a resolver backed by getter. The known folders, typically those already
+// listed by the command, are cached up front and never fetched.
+func NewAncestryResolver(getter Getter, known ...*Folder) *AncestryResolver {
+	r := &AncestryResolver{
+		getter:  getter,
+		entries: make(map[string]folderEntry, len(known)),
+	}
+	for _, folder := range known {
+		r.entries[folderName(folder.ID)] = folderEntry{folder: folder}
+	}
+
+	return r
+}
+
+// GetFolder returns the folder with the given ID ("123") or resource name ("folders/123"), fetching
+// it on first use. Failed lookups, including PermissionDenied, are memoized too, except for context
+// cancellation and deadline errors.
+func (r *AncestryResolver) GetFolder(ctx context.Context, id string) (*Folder, error) {
+	name := folderName(id)
+
+	if entry, ok := r.lookup(name); ok {
+		return entry.folder, entry.err
+	}
+
+	result, _, _ := r.group.Do(name, func() (any, error) {
+		// a concurrent call may have filled the entry between the lookup and Do
+		if entry, ok := r.lookup(name); ok {
+			return entry, nil
+		}
+
+		folder, err := r.getter.GetFolder(ctx, name)
+		entry := folderEntry{folder: folder, err: err}
+		if !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+			r.mu.Lock()
+			r.entries[name] = entry
+			r.mu.Unlock()
+		}
+
+		return entry, nil
+	})
+	entry, _ := result.(folderEntry)
+
+	return entry.folder, entry.err
+}
+
+// Ancestry returns the folder with the given ID or resource name followed by its folder ancestors,
+// nearest first, ending with the highest folder below the organization. When the caller cannot read
+// an ancestor, the ancestry ends with the highest folder that could be read.
+func (r *AncestryResolver) Ancestry(ctx context.Context, id string) ([]*Folder, error) {
+	folder, err := r.GetFolder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ancestry := []*Folder{folder}
+	visited := map[string]bool{folderName(folder.ID): true}
+	for strings.HasPrefix(folder.Parent, folderPrefix) {
+		if visited[folder.Parent] {
+			return nil, fmt.Errorf("%w: %s", ErrAncestryCycle, folderName(id))
+		}
+		visited[folder.Parent] = true
+
+		parent, err := r.GetFolder(ctx, folder.Parent)
+		if status.Code(err) == codes.PermissionDenied {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		ancestry = append(ancestry, parent)
+		folder = parent
+	}
+
+	return ancestry, nil
+}
+
+// Depth returns the number of folder ancestors of the folder with the given ID or resource name, so
+// that folders directly below an organization have depth 0.
+func (r *AncestryResolver) Depth(ctx context.Context, id string) (int, error) {
+	ancestry, err := r.Ancestry(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(ancestry) - 1, nil
+}
+
+// lookup returns the memoized entry for name, if any.
+func (r *AncestryResolver) lookup(name string) (folderEntry, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.entries[name]
+
+	return entry, ok
+}
+
+// folderName returns the resource name for a folder ID or resource name.
+func folderName(id string) string {
+	if strings.HasPrefix(id, folderPrefix) {
+		return id
+	}
+
+	return folderPrefix + id
+}
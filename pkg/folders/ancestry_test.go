@@ -0,0 +1,180 @@
+package folders_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Test error variables for err113 compliance.
+var errAncestryTestAPIError = errors.New("API error")
+
+// folderTree serves folders by resource name, counting lookups per name.
+type folderTree struct {
+	mu      sync.Mutex
+	folders map[string]*folders.Folder
+	errs    map[string]error
+	calls   map[string]int
+}
+
+func newFolderTree(folderList ...*folders.Folder) *folderTree {
+	tree := &folderTree{
+		folders: make(map[string]*folders.Folder),
+		errs:    make(map[string]error),
+		calls:   make(map[string]int),
+	}
+	for _, folder := range folderList {
+		tree.folders[folder.Name] = folder
+	}
+
+	return tree
+}
+
+func (t *folderTree) GetFolder(_ context.Context, name string) (*folders.Folder, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.calls[name]++
+	if err, ok := t.errs[name]; ok {
+		return nil, err
+	}
+	folder, ok := t.folders[name]
+	if !ok {
+		return nil, status.Error(codes.NotFound, name+" not found")
+	}
+
+	return folder, nil
+}
+
+func testFolder(id, parent string) *folders.Folder {
+	return &folders.Folder{ID: id, Name: "folders/" + id, Parent: parent}
+}
+
+func TestAncestryResolverSharedAncestorsFetchedOnce(t *testing.T) {
+	// org/1 > 10 > 20 > {30, 31}, and 20 > 21
+	root := testFolder("10", "organizations/1")
+	middle := testFolder("20", "folders/10")
+	leaves := []*folders.Folder{
+		testFolder("30", "folders/20"),
+		testFolder("31", "folders/20"),
+		testFolder("21", "folders/10"),
+	}
+	tree := newFolderTree(root, middle)
+	resolver := folders.NewAncestryResolver(tree, leaves...)
+
+	for _, leaf := range leaves {
+		_, err := resolver.Ancestry(t.Context(), leaf.ID)
+		require.NoError(t, err)
+	}
+
+	ancestry, err := resolver.Ancestry(t.Context(), "folders/30")
+	require.NoError(t, err)
+	assert.Equal(t, []*folders.Folder{leaves[0], middle, root}, ancestry)
+
+	assert.Equal(t, map[string]int{"folders/10": 1, "folders/20": 1}, tree.calls,
+		"shared ancestors should be fetched once and known folders never")
+}
+
+func TestAncestryResolverDepth(t *testing.T) {
+	tree := newFolderTree(
+		testFolder("10", "organizations/1"),
+		testFolder("20", "folders/10"),
+		testFolder("30", "folders/20"),
+	)
+	resolver := folders.NewAncestryResolver(tree)
+
+	tests := map[string]struct {
+		id   string
+		want int
+	}{
+		"top-level folder": {id: "10", want: 0},
+		"nested folder":    {id: "20", want: 1},
+		"resource name":    {id: "folders/30", want: 2},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := resolver.Depth(t.Context(), tt.id)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAncestryResolverDeniedAncestorIsCached(t *testing.T) {
+	tree := newFolderTree()
+	tree.errs["folders/10"] = status.Error(codes.PermissionDenied, "denied")
+	leaves := []*folders.Folder{testFolder("20", "folders/10"), testFolder("21", "folders/10")}
+	resolver := folders.NewAncestryResolver(tree, leaves...)
+
+	for _, leaf := range leaves {
+		ancestry, err := resolver.Ancestry(t.Context(), leaf.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []*folders.Folder{leaf}, ancestry, "ancestry should end below the denied folder")
+	}
+
+	_, err := resolver.GetFolder(t.Context(), "10")
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Equal(t, 1, tree.calls["folders/10"], "denied lookups should not be retried")
+}
+
+func TestAncestryResolverErrors(t *testing.T) {
+	testCases := map[string]struct {
+		known   []*folders.Folder
+		errs    map[string]error
+		id      string
+		wantErr error
+	}{
+		"cycle": {
+			known:   []*folders.Folder{testFolder("1", "folders/2"), testFolder("2", "folders/3"), testFolder("3", "folders/1")},
+			id:      "1",
+			wantErr: folders.ErrAncestryCycle,
+		},
+		"self parent": {
+			known:   []*folders.Folder{testFolder("1", "folders/1")},
+			id:      "1",
+			wantErr: folders.ErrAncestryCycle,
+		},
+		"API error": {
+			known:   []*folders.Folder{testFolder("1", "folders/2")},
+			errs:    map[string]error{"folders/2": errAncestryTestAPIError},
+			id:      "1",
+			wantErr: errAncestryTestAPIError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tree := newFolderTree()
+			for parent, err := range tc.errs {
+				tree.errs[parent] = err
+			}
+			resolver := folders.NewAncestryResolver(tree, tc.known...)
+
+			_, err := resolver.Ancestry(t.Context(), tc.id)
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestAncestryResolverCanceledLookupNotCached(t *testing.T) {
+	tree := newFolderTree(testFolder("10", "organizations/1"))
+	tree.errs["folders/10"] = context.Canceled
+	resolver := folders.NewAncestryResolver(tree)
+
+	_, err := resolver.GetFolder(t.Context(), "10")
+	require.ErrorIs(t, err, context.Canceled)
+
+	delete(tree.errs, "folders/10")
+	folder, err := resolver.GetFolder(t.Context(), "10")
+	require.NoError(t, err)
+	assert.Equal(t, "10", folder.ID)
+	assert.Equal(t, 2, tree.calls["folders/10"])
+}
@@ -0,0 +1,206 @@
+package folders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/andreygrechin/gcphelper/internal/resourcename"
+	cloudasset "google.golang.org/api/cloudasset/v1"
+	"google.golang.org/api/option"
+)
+
+// ErrAssetScopeRequired is returned when folders are searched in Cloud Asset Inventory without a parent,
+// since every search runs within one organization or folder.
+var ErrAssetScopeRequired = errors.New("searching Cloud Asset Inventory requires a parent organization or folder")
+
+// ErrAssetQuery is returned when raw SearchFolders query clauses are passed to the asset backend, which
+// uses a different query language.
+var ErrAssetQuery = errors.New("SearchFolders queries are not supported by the Cloud Asset Inventory backend")
+
+// ErrAssetFolderNotFound is returned when a folder is not found in Cloud Asset Inventory.
+var ErrAssetFolderNotFound = errors.New("folder not found in Cloud Asset Inventory")
+
+const (
+	// AssetTypeFolder is the Cloud Asset Inventory type of folders.
+	AssetTypeFolder = "cloudresourcemanager.googleapis.com/Folder"
+
+	// assetNamePrefix turns a resource name into the full resource name used by Cloud Asset Inventory.
+	assetNamePrefix = "//cloudresourcemanager.googleapis.com/"
+)
+
+// AssetSearcher searches resources in Cloud Asset Inventory.
+type AssetSearcher interface {
+	// SearchAllResources calls fn for each resource of assetType within scope (e.g., "organizations/123")
+	// that matches query, stopping at the first error.
+	SearchAllResources(
+		ctx context.Context,
+		scope, assetType, query string,
+		fn func(*cloudasset.ResourceSearchResult) error,
+	) error
+}
+
+// AssetClient implements the Fetcher interface using the Cloud Asset Inventory SearchAllResources API,
+// which finds folders in large hierarchies faster than per-parent Resource Manager calls. Searches run
+// within a parent, so listing every accessible folder is not supported.
+type AssetClient struct {
+	searcher AssetSearcher
+}
+
+// NewAssetClient creates a folders client that searches Cloud Asset Inventory with searcher.
+func NewAssetClient(searcher AssetSearcher) *AssetClient {
+	return &AssetClient{searcher: searcher}
+}
+
+// NewAssetClientFromContext creates a Cloud Asset Inventory folders client using application default
+// credentials. Client options are passed to the underlying REST API client.
+func NewAssetClientFromContext(ctx context.Context, clientOpts ...option.ClientOption) (*AssetClient, error) {
+	service, err := cloudasset.NewService(ctx, clientOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cloud asset client: %w", err)
+	}
+
+	return NewAssetClient(&assetService{service: service}), nil
+}
+
+// WalkFolders calls fn for each active direct child folder of opts.Parent as it is fetched, stopping at
+// the first error returned by the API or by fn.
+func (c *AssetClient) WalkFolders(ctx context.Context, opts *FetchOptions, fn func(*Folder) error) error {
+	if opts == nil {
+		opts = NewFetchOptions()
+	}
+	if opts.Query != "" {
+		return ErrAssetQuery
+	}
+	if opts.Parent == "" {
+		return ErrAssetScopeRequired
+	}
+
+	return c.walkChildren(ctx, opts.Parent, fn)
+}
+
+// ListFoldersFromParent lists the active direct child folders of a specific parent resource. It requires
+// the cloudasset.assets.searchAllResources permission on the parent.
+func (c *AssetClient) ListFoldersFromParent(ctx context.Context, parent string, _ *FetchOptions) ([]*Folder, error) {
+	var folders []*Folder
+	err := c.walkChildren(ctx, parent, func(folder *Folder) error {
+		folders = append(folders, folder)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return folders, nil
+}
+
+// GetFolder retrieves a single folder by its resource name (e.g., "folders/123"), searching within the
+// folder itself.
+func (c *AssetClient) GetFolder(ctx context.Context, name string) (*Folder, error) {
+	var found *Folder
+	query := "name=" + quoteAssetValue(assetNamePrefix+name)
+	err := c.searcher.SearchAllResources(ctx, name, AssetTypeFolder, query,
+		func(result *cloudasset.ResourceSearchResult) error {
+			if found == nil && result.Name == assetNamePrefix+name {
+				found = FolderFromAsset(result)
+			}
+
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get folder %s: %w", name, err)
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAssetFolderNotFound, name)
+	}
+
+	return found, nil
+}
+
+// Close releases any resources held by the fetcher. The REST client holds none.
+func (c *AssetClient) Close() error {
+	return nil
+}
+
+// walkChildren passes each active folder whose parent is parent to fn.
+func (c *AssetClient) walkChildren(ctx context.Context, parent string, fn func(*Folder) error) error {
+	err := c.searcher.SearchAllResources(ctx, parent, AssetTypeFolder, AssetChildrenQuery(parent),
+		func(result *cloudasset.ResourceSearchResult) error {
+			return fn(FolderFromAsset(result))
+		})
+	if err != nil {
+		return fmt.Errorf("failed to search folders of %s: %w", parent, err)
+	}
+
+	return nil
+}
+
+// AssetChildrenQuery returns the SearchAllResources query for the active direct children of parent.
+func AssetChildrenQuery(parent string) string {
+	return "parentFullResourceName=" + quoteAssetValue(assetNamePrefix+parent) + " AND state:ACTIVE"
+}
+
+// quoteAssetValue quotes a value for a SearchAllResources query.
+func quoteAssetValue(value string) string {
+	return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+}
+
+// FolderFromAsset converts a Cloud Asset Inventory search result to our Folder type. Like FolderFromProto,
+// a name that is not "folders/" followed by a numeric ID is kept as the ID too. Search results carry no
+// etag, and timestamps that cannot be parsed are left unset.
+func FolderFromAsset(result *cloudasset.ResourceSearchResult) *Folder {
+	name := strings.TrimPrefix(result.Name, assetNamePrefix)
+	id, err := resourcename.ExtractID(name, folderPrefix)
+	if err != nil {
+		id = name
+		err = fmt.Errorf("unexpected folder name: %w", err)
+	}
+
+	folder := &Folder{
+		ID:          id,
+		Name:        name,
+		DisplayName: result.DisplayName,
+		Parent:      strings.TrimPrefix(result.ParentFullResourceName, assetNamePrefix),
+		State:       result.State,
+		idErr:       err,
+	}
+	if created, err := time.Parse(time.RFC3339Nano, result.CreateTime); err == nil {
+		folder.CreateTime = created
+	}
+	if updated, err := time.Parse(time.RFC3339Nano, result.UpdateTime); err == nil {
+		folder.UpdateTime = updated
+	}
+
+	return folder
+}
+
+// assetService implements AssetSearcher with the Cloud Asset REST API.
+type assetService struct {
+	service *cloudasset.Service
+}
+
+// SearchAllResources pages through the SearchAllResources results, passing each one to fn.
+func (s *assetService) SearchAllResources(
+	ctx context.Context,
+	scope, assetType, query string,
+	fn func(*cloudasset.ResourceSearchResult) error,
+) error {
+	call := s.service.V1.SearchAllResources(scope).AssetTypes(assetType).Query(query)
+	err := call.Pages(ctx, func(resp *cloudasset.SearchAllResourcesResponse) error {
+		for _, result := range resp.Results {
+			if err := fn(result); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to search %s assets in %s: %w", assetType, scope, err)
+	}
+
+	return nil
+}
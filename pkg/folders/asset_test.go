@@ -0,0 +1,203 @@
+package folders_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cloudasset "google.golang.org/api/cloudasset/v1"
+)
+
+// Test error variables for err113 compliance.
+var errAssetTestAPIError = errors.New("asset API error")
+
+// assetSearch records one SearchAllResources call.
+type assetSearch struct {
+	scope     string
+	assetType string
+	query     string
+}
+
+// fakeAssetSearcher serves canned search results and records the searches made.
+type fakeAssetSearcher struct {
+	results  []*cloudasset.ResourceSearchResult
+	err      error
+	searches []assetSearch
+}
+
+func (f *fakeAssetSearcher) SearchAllResources(
+	_ context.Context,
+	scope, assetType, query string,
+	fn func(*cloudasset.ResourceSearchResult) error,
+) error {
+	f.searches = append(f.searches, assetSearch{scope: scope, assetType: assetType, query: query})
+	if f.err != nil {
+		return f.err
+	}
+	for _, result := range f.results {
+		if err := fn(result); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func TestFolderFromAsset(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := map[string]struct {
+		result    *cloudasset.ResourceSearchResult
+		want      *folders.Folder
+		wantIDErr bool
+	}{
+		"folder": {
+			result: &cloudasset.ResourceSearchResult{
+				Name:                   "//cloudresourcemanager.googleapis.com/folders/123",
+				DisplayName:            "Engineering",
+				ParentFullResourceName: "//cloudresourcemanager.googleapis.com/organizations/9",
+				State:                  "ACTIVE",
+				CreateTime:             "2024-01-02T03:04:05Z",
+				UpdateTime:             "not a time",
+			},
+			want: &folders.Folder{
+				ID: "123", Name: "folders/123", DisplayName: "Engineering", Parent: "organizations/9",
+				State: "ACTIVE", CreateTime: created,
+			},
+		},
+		"malformed name": {
+			result:    &cloudasset.ResourceSearchResult{Name: "//cloudresourcemanager.googleapis.com/projects/p"},
+			want:      &folders.Folder{ID: "projects/p", Name: "projects/p"},
+			wantIDErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := folders.FolderFromAsset(tt.result)
+			if tt.wantIDErr {
+				require.Error(t, got.IDError())
+			} else {
+				require.NoError(t, got.IDError())
+			}
+			assert.Equal(t, tt.want.ID, got.ID)
+			assert.Equal(t, tt.want.Name, got.Name)
+			assert.Equal(t, tt.want.DisplayName, got.DisplayName)
+			assert.Equal(t, tt.want.Parent, got.Parent)
+			assert.Equal(t, tt.want.State, got.State)
+			assert.Equal(t, tt.want.CreateTime, got.CreateTime)
+			assert.True(t, got.UpdateTime.IsZero())
+		})
+	}
+}
+
+func TestAssetClient_ListFoldersFromParent(t *testing.T) {
+	searcher := &fakeAssetSearcher{results: []*cloudasset.ResourceSearchResult{
+		{Name: "//cloudresourcemanager.googleapis.com/folders/1", DisplayName: "One", State: "ACTIVE"},
+		{Name: "//cloudresourcemanager.googleapis.com/folders/2", DisplayName: "Two", State: "ACTIVE"},
+	}}
+	client := folders.NewAssetClient(searcher)
+
+	got, err := client.ListFoldersFromParent(t.Context(), "organizations/9", nil)
+	require.NoError(t, err)
+
+	require.Len(t, got, 2)
+	assert.Equal(t, "1", got[0].ID)
+	assert.Equal(t, "Two", got[1].DisplayName)
+	assert.Equal(t, []assetSearch{{
+		scope:     "organizations/9",
+		assetType: folders.AssetTypeFolder,
+		query:     `parentFullResourceName="//cloudresourcemanager.googleapis.com/organizations/9" AND state:ACTIVE`,
+	}}, searcher.searches)
+}
+
+func TestAssetClient_WalkFolders(t *testing.T) {
+	tests := map[string]struct {
+		opts      *folders.FetchOptions
+		searchErr error
+		wantIDs   []string
+		wantErr   error
+	}{
+		"children of the parent": {
+			opts:    &folders.FetchOptions{Parent: "folders/5"},
+			wantIDs: []string{"1"},
+		},
+		"no parent": {
+			opts:    folders.NewFetchOptions(),
+			wantErr: folders.ErrAssetScopeRequired,
+		},
+		"raw query": {
+			opts:    &folders.FetchOptions{Parent: "folders/5", Query: "displayName:prod*"},
+			wantErr: folders.ErrAssetQuery,
+		},
+		"search fails": {
+			opts:      &folders.FetchOptions{Parent: "folders/5"},
+			searchErr: errAssetTestAPIError,
+			wantErr:   errAssetTestAPIError,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			searcher := &fakeAssetSearcher{
+				results: []*cloudasset.ResourceSearchResult{{Name: "//cloudresourcemanager.googleapis.com/folders/1"}},
+				err:     tt.searchErr,
+			}
+			service := folders.NewServiceWithLogger(folders.NewAssetClient(searcher), nil)
+			service.SetSpinner(false)
+
+			got, err := service.ListFolders(t.Context(), tt.opts)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			ids := make([]string, len(got))
+			for i, folder := range got {
+				ids[i] = folder.ID
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
+
+func TestAssetClient_GetFolder(t *testing.T) {
+	tests := map[string]struct {
+		results []*cloudasset.ResourceSearchResult
+		wantErr error
+	}{
+		"found": {
+			results: []*cloudasset.ResourceSearchResult{
+				{Name: "//cloudresourcemanager.googleapis.com/folders/1", DisplayName: "One"},
+			},
+		},
+		"not found": {
+			wantErr: folders.ErrAssetFolderNotFound,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			searcher := &fakeAssetSearcher{results: tt.results}
+			client := folders.NewAssetClient(searcher)
+
+			got, err := client.GetFolder(t.Context(), "folders/1")
+			require.Len(t, searcher.searches, 1)
+			assert.Equal(t, "folders/1", searcher.searches[0].scope)
+			assert.Equal(t, `name="//cloudresourcemanager.googleapis.com/folders/1"`, searcher.searches[0].query)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "One", got.DisplayName)
+			require.NoError(t, client.Close())
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package folders
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Error is returned by Service methods and keeps the gRPC status code of the underlying failure
+// reachable through errors.As.
+type Error struct {
+	Op  string // Op describes the failed operation (e.g., "list folders")
+	Err error  // Err is the underlying error
+}
+
+// Error returns the error message in the "failed to <op>: <cause>" form.
+func (e *Error) Error() string {
+	return "failed to " + e.Op + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Code returns the gRPC status code of the underlying error, or codes.Unknown if it carries none.
+func (e *Error) Code() codes.Code {
+	return status.Code(e.Err)
+}
+
+// StatusError is a gRPC error reported by its status code and message alone, e.g.
+// "PermissionDenied: The caller does not have permission", without the transport details of its
+// text. The status stays reachable through status.FromError and the underlying error through Unwrap.
+type StatusError struct {
+	Err error // Err is the underlying gRPC error
+}
+
+// Error returns the status code and message of the underlying error.
+func (e *StatusError) Error() string {
+	st := e.GRPCStatus()
+
+	return st.Code().String() + ": " + st.Message()
+}
+
+// Unwrap returns the underlying error.
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// GRPCStatus returns the status of the first gRPC error in the chain of the underlying error, so that
+// wrapping text added on the way is not repeated in the message.
+func (e *StatusError) GRPCStatus() *status.Status {
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if errors.As(e.Err, &grpcErr) {
+		return grpcErr.GRPCStatus()
+	}
+
+	return status.Convert(e.Err)
+}
+
+// iterationError returns the error that ended an iteration over API results: a StatusError for a gRPC
+// error, and otherwise the error wrapped with what was being iterated.
+func iterationError(err error, what string) error {
+	if _, ok := status.FromError(err); !ok {
+		return fmt.Errorf("failed to iterate %s: %w", what, err)
+	}
+
+	return &StatusError{Err: err}
+}
+
+// ParentError records the failure to list folders under one parent.
+type ParentError struct {
+	Parent string // Parent is the resource name whose folders could not be listed
+	Err    error  // Err is the underlying error
+}
+
+// PartialError is returned when folders were listed from some parents but not others.
+// The folders that were fetched are returned alongside it.
+type PartialError struct {
+	Failures []ParentError // Failures lists the failed parents in request order
+	Total    int           // Total is the number of parents requested
+}
+
+// Error summarizes how many parents failed.
+func (e *PartialError) Error() string {
+	return fmt.Sprintf("failed to list folders from %d of %d parents", len(e.Failures), e.Total)
+}
+
+// Unwrap returns the per-parent errors so that errors.Is and errors.As match any of them.
+func (e *PartialError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, failure := range e.Failures {
+		errs[i] = failure.Err
+	}
+
+	return errs
+}
+
+// FolderError records the failure to get one folder.
+type FolderError struct {
+	Name string // Name is the resource name of the folder, e.g. "folders/123"
+	Err  error  // Err is the underlying error
+}
+
+// LookupError is returned when some of the requested folders could not be retrieved.
+// The folders that were retrieved are returned alongside it.
+type LookupError struct {
+	Failures []FolderError // Failures lists the failed folders in request order
+	Total    int           // Total is the number of distinct folders requested
+}
+
+// Error summarizes how many folders could not be retrieved.
+func (e *LookupError) Error() string {
+	return fmt.Sprintf("failed to get %d of %d folders", len(e.Failures), e.Total)
+}
+
+// Unwrap returns the per-folder errors so that errors.Is and errors.As match any of them.
+func (e *LookupError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, failure := range e.Failures {
+		errs[i] = failure.Err
+	}
+
+	return errs
+}
@@ -0,0 +1,46 @@
+package folders_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatusError(t *testing.T) {
+	tests := map[string]struct {
+		err         error
+		wantMessage string
+		wantCode    codes.Code
+	}{
+		"permission denied": {
+			err:         status.Error(codes.PermissionDenied, "The caller does not have permission"),
+			wantMessage: "failed to list folders: PermissionDenied: The caller does not have permission",
+			wantCode:    codes.PermissionDenied,
+		},
+		"wrapped status": {
+			err:         fmt.Errorf("transport: %w", status.Error(codes.Unavailable, "connection reset")),
+			wantMessage: "failed to list folders: Unavailable: connection reset",
+			wantCode:    codes.Unavailable,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			statusErr := &folders.StatusError{Err: tt.err}
+			err := &folders.Error{Op: "list folders", Err: statusErr}
+
+			assert.Equal(t, tt.wantMessage, err.Error())
+			assert.Equal(t, tt.wantCode, err.Code())
+			st, ok := status.FromError(err)
+			require.True(t, ok)
+			assert.Equal(t, tt.wantCode, st.Code())
+			assert.Equal(t, tt.err, errors.Unwrap(statusErr))
+		})
+	}
+}
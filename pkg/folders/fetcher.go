@@ -4,20 +4,33 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
+	iampb "cloud.google.com/go/iam/apiv1/iampb"
 	resourcemanager "cloud.google.com/go/resourcemanager/apiv3"
 	resourcemanagerpb "cloud.google.com/go/resourcemanager/apiv3/resourcemanagerpb"
 	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
 )
 
+// ErrInvalidQuery is returned when raw SearchFolders query clauses are empty or conflict with the
+// generated query.
+var ErrInvalidQuery = errors.New("invalid folder query")
+
+// activeQuery restricts searches to folders that are not pending deletion.
+const activeQuery = "state:ACTIVE"
+
 // Fetcher defines the interface for fetching folders from Google Cloud.
 type Fetcher interface {
-	// ListFolders lists all accessible folders.
-	ListFolders(ctx context.Context, opts *FetchOptions) ([]*Folder, error)
-
-	// ListFoldersFromParent lists folders under a specific parent resource.
+	// ListFoldersFromParent lists the direct child folders of a specific parent resource.
 	ListFoldersFromParent(ctx context.Context, parent string, opts *FetchOptions) ([]*Folder, error)
 
+	// WalkFolders calls fn for each accessible folder as it is fetched, stopping at the first error.
+	WalkFolders(ctx context.Context, opts *FetchOptions, fn func(*Folder) error) error
+
+	// GetFolder retrieves a single folder by its resource name (e.g., "folders/123").
+	GetFolder(ctx context.Context, name string) (*Folder, error)
+
 	// Close releases any resources held by the fetcher.
 	Close() error
 }
@@ -28,8 +41,9 @@ type Client struct {
 }
 
 // NewClientFromContext creates a new folders client using application default credentials.
-func NewClientFromContext(ctx context.Context) (*Client, error) {
-	c, err := resourcemanager.NewFoldersClient(ctx)
+// Client options such as option.WithEndpoint are passed to the underlying API client.
+func NewClientFromContext(ctx context.Context, clientOpts ...option.ClientOption) (*Client, error) {
+	c, err := resourcemanager.NewFoldersClient(ctx, clientOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create folders client: %w", err)
 	}
@@ -39,20 +53,62 @@ func NewClientFromContext(ctx context.Context) (*Client, error) {
 	}, nil
 }
 
-// ListFolders lists all accessible folders, optionally filtered by parent.
-func (c *Client) ListFolders(ctx context.Context, opts *FetchOptions) ([]*Folder, error) {
+// WalkFolders calls fn for each accessible folder as it is fetched, stopping at the first error
+// returned by the API or by fn.
+func (c *Client) WalkFolders(ctx context.Context, opts *FetchOptions, fn func(*Folder) error) error {
 	if opts == nil {
 		opts = NewFetchOptions()
 	}
 
-	// use SearchFolders API with parent filtering
-	return c.searchAllAccessibleFolders(ctx, opts)
+	return c.walkAllAccessibleFolders(ctx, opts, fn)
+}
+
+// ListFoldersFromParent lists the active direct child folders of a specific parent resource.
+// This method uses the direct ListFolders API for the specified parent, which requires the
+// resourcemanager.folders.list permission on the parent.
+func (c *Client) ListFoldersFromParent(ctx context.Context, parent string, opts *FetchOptions) ([]*Folder, error) {
+	if opts == nil {
+		opts = NewFetchOptions()
+	}
+
+	it := c.foldersClient.ListFolders(ctx, &resourcemanagerpb.ListFoldersRequest{Parent: parent})
+
+	var folders []*Folder
+	for {
+		folder, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			return folders, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate folders of %s: %w", parent, err)
+		}
+
+		folders = append(folders, folderFromResponse(folder, opts))
+	}
+}
+
+// GetFolder retrieves a single folder by its resource name (e.g., "folders/123").
+func (c *Client) GetFolder(ctx context.Context, name string) (*Folder, error) {
+	folder, err := c.foldersClient.GetFolder(ctx, &resourcemanagerpb.GetFolderRequest{Name: name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get folder %s: %w", name, err)
+	}
+
+	return FolderFromProto(folder), nil
 }
 
-// ListFoldersFromParent lists folders under a specific parent resource.
-// This method uses the direct ListFolders API for the specified parent.
-func (c *Client) ListFoldersFromParent(ctx context.Context, parent string, _ *FetchOptions) ([]*Folder, error) {
-	return c.searchAllAccessibleFolders(ctx, &FetchOptions{Parent: parent})
+// TestIamPermissions returns the subset of permissions the caller holds on the folder with the given
+// resource name.
+func (c *Client) TestIamPermissions(ctx context.Context, resource string, permissions []string) ([]string, error) {
+	resp, err := c.foldersClient.TestIamPermissions(ctx, &iampb.TestIamPermissionsRequest{
+		Resource:    resource,
+		Permissions: permissions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to test IAM permissions on %s: %w", resource, err)
+	}
+
+	return resp.GetPermissions(), nil
 }
 
 // Close releases any resources held by the fetcher.
@@ -64,29 +120,74 @@ func (c *Client) Close() error {
 	return nil
 }
 
-// searchAllAccessibleFolders lists folders accessible to the current user, optionally filtered by parent.
-func (c *Client) searchAllAccessibleFolders(ctx context.Context, opts *FetchOptions) ([]*Folder, error) {
-	req := &resourcemanagerpb.SearchFoldersRequest{
-		Query: "state:ACTIVE",
-	}
+// SearchQuery returns the SearchFolders query for opts: ACTIVE folders, restricted to opts.Parent when
+// set, with the raw opts.Query clauses AND-combined at the end.
+func SearchQuery(opts *FetchOptions) string {
+	query := activeQuery
 	if opts.Parent != "" {
-		req.Query += " AND parent:" + opts.Parent
+		query += " AND parent:" + opts.Parent
+	}
+	if extra := strings.TrimSpace(opts.Query); extra != "" {
+		query += " AND " + extra
+	}
+
+	return query
+}
+
+// ValidateQuery checks raw SearchFolders query clauses before they are AND-combined with the generated
+// query. The clauses must not be empty, must not start or end with an operator, and must not restrict
+// the state, which is always ACTIVE.
+func ValidateQuery(query string) error {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return fmt.Errorf("%w: query is empty", ErrInvalidQuery)
+	}
+
+	words := strings.Fields(strings.ToUpper(query))
+	for _, operator := range []string{"AND", "OR"} {
+		if words[0] == operator || words[len(words)-1] == operator {
+			return fmt.Errorf("%w: %q starts or ends with %s", ErrInvalidQuery, query, operator)
+		}
+	}
+
+	lower := strings.ToLower(query)
+	if strings.Contains(lower, "state:") || strings.Contains(lower, "state=") {
+		return fmt.Errorf("%w: %q restricts the state, which is always %s", ErrInvalidQuery, query, activeQuery)
+	}
+
+	return nil
+}
+
+// walkAllAccessibleFolders iterates folders accessible to the current user, optionally filtered by parent,
+// passing each one to fn.
+func (c *Client) walkAllAccessibleFolders(ctx context.Context, opts *FetchOptions, fn func(*Folder) error) error {
+	req := &resourcemanagerpb.SearchFoldersRequest{
+		Query: SearchQuery(opts),
 	}
 
 	it := c.foldersClient.SearchFolders(ctx, req)
 
-	var folders []*Folder
 	for {
 		folder, err := it.Next()
 		if errors.Is(err, iterator.Done) {
-			break
+			return nil
 		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to iterate all folders: %w", err)
+			return iterationError(err, "all folders")
+		}
+
+		if err := fn(folderFromResponse(folder, opts)); err != nil {
+			return err
 		}
+	}
+}
 
-		folders = append(folders, FolderFromProto(folder))
+// folderFromResponse converts an API response, keeping the response itself when opts.Raw is set.
+func folderFromResponse(pb *resourcemanagerpb.Folder, opts *FetchOptions) *Folder {
+	folder := FolderFromProto(pb)
+	if opts.Raw {
+		folder.Raw = pb
 	}
 
-	return folders, nil
+	return folder
 }
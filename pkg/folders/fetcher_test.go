@@ -34,3 +34,65 @@ func TestClient_BasicFunctionality(t *testing.T) {
 	require.NotNil(t, opts)
 	assert.Equal(t, "organizations/123456789", opts.Parent)
 }
+
+func TestSearchQuery(t *testing.T) {
+	tests := map[string]struct {
+		opts *folders.FetchOptions
+		want string
+	}{
+		"default": {
+			opts: &folders.FetchOptions{},
+			want: "state:ACTIVE",
+		},
+		"parent": {
+			opts: &folders.FetchOptions{Parent: "organizations/123"},
+			want: "state:ACTIVE AND parent:organizations/123",
+		},
+		"raw query": {
+			opts: &folders.FetchOptions{Query: "displayName:prod*"},
+			want: "state:ACTIVE AND displayName:prod*",
+		},
+		"raw query with parent": {
+			opts: &folders.FetchOptions{Parent: "folders/456", Query: "  displayName:prod* AND displayName:*web  "},
+			want: "state:ACTIVE AND parent:folders/456 AND displayName:prod* AND displayName:*web",
+		},
+		"blank raw query is ignored": {
+			opts: &folders.FetchOptions{Query: "   "},
+			want: "state:ACTIVE",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, folders.SearchQuery(tt.opts))
+		})
+	}
+}
+
+func TestValidateQuery(t *testing.T) {
+	tests := map[string]struct {
+		query   string
+		wantErr bool
+	}{
+		"display name prefix":  {query: "displayName:prod*"},
+		"several clauses":      {query: "displayName:prod* AND parent:folders/1"},
+		"empty":                {query: "  ", wantErr: true},
+		"leading operator":     {query: "AND displayName:prod*", wantErr: true},
+		"trailing operator":    {query: "displayName:prod* or", wantErr: true},
+		"state restriction":    {query: "State:DELETE_REQUESTED", wantErr: true},
+		"state equality":       {query: "displayName:x AND state=ACTIVE", wantErr: true},
+		"display name mention": {query: `displayName:"statement"`},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := folders.ValidateQuery(tt.query)
+			if tt.wantErr {
+				require.ErrorIs(t, err, folders.ErrInvalidQuery)
+
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
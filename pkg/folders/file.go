@@ -0,0 +1,155 @@
+package folders
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ErrInvalidFolderFile is returned when a folder file is not a folder listing exported as JSON or JSONL.
+var ErrInvalidFolderFile = errors.New("invalid folder file")
+
+// Reasons a folder in a folder file is rejected, wrapped with ErrInvalidFolderFile.
+var (
+	errFolderNotObject     = errors.New("not a JSON object")
+	errFolderMissingID     = errors.New(`missing "id" and "name"`)
+	errFolderInvalidParent = errors.New("parent is not an organizations/ or folders/ resource name")
+)
+
+// LoadFoldersFile reads the folders exported to path; see LoadFolders.
+func LoadFoldersFile(path string) ([]*Folder, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read folder file: %w", err)
+	}
+
+	folders, err := LoadFolders(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return folders, nil
+}
+
+// LoadFolders parses folders exported with "gcphelper --format json folders", a JSON array of folder
+// objects, or with --format jsonl, one object per line. Each folder needs an "id" or a "name", and the
+// missing one of the two is derived from the other. Parents must be organization or folder resource
+// names, and each folder may appear only once.
+func LoadFolders(data []byte) ([]*Folder, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: the file is empty", ErrInvalidFolderFile)
+	}
+
+	var elements []json.RawMessage
+	switch data[0] {
+	case '[':
+		if err := json.Unmarshal(data, &elements); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidFolderFile, err)
+		}
+	case '{':
+		dec := json.NewDecoder(bytes.NewReader(data))
+		for {
+			var element json.RawMessage
+			err := dec.Decode(&element)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %w", ErrInvalidFolderFile, len(elements)+1, err)
+			}
+			elements = append(elements, element)
+		}
+	default:
+		return nil, fmt.Errorf("%w: expected a JSON array or JSONL objects", ErrInvalidFolderFile)
+	}
+
+	folders := make([]*Folder, 0, len(elements))
+	seen := make(map[string]int, len(elements))
+	for i, element := range elements {
+		folder, err := parseFolder(element)
+		if err != nil {
+			return nil, fmt.Errorf("%w: folder %d: %w", ErrInvalidFolderFile, i+1, err)
+		}
+		if previous, ok := seen[folder.ID]; ok {
+			return nil, fmt.Errorf("%w: folder %d: duplicate id %s, first seen in folder %d",
+				ErrInvalidFolderFile, i+1, folder.ID, previous)
+		}
+		seen[folder.ID] = i + 1
+		folders = append(folders, folder)
+	}
+
+	return folders, nil
+}
+
+// parseFolder decodes and checks one exported folder object.
+func parseFolder(element json.RawMessage) (*Folder, error) {
+	if len(element) == 0 || element[0] != '{' {
+		return nil, errFolderNotObject
+	}
+
+	var folder Folder
+	if err := json.Unmarshal(element, &folder); err != nil {
+		return nil, fmt.Errorf("failed to decode folder: %w", err)
+	}
+
+	switch {
+	case folder.ID == "" && folder.Name == "":
+		return nil, errFolderMissingID
+	case folder.ID == "":
+		folder.ID = strings.TrimPrefix(folder.Name, folderPrefix)
+	case folder.Name == "":
+		folder.Name = folderPrefix + folder.ID
+	}
+
+	if folder.Parent != "" && ParentType(folder.Parent) == ParentTypeUnknown {
+		return nil, fmt.Errorf("%w: %s", errFolderInvalidParent, folder.Parent)
+	}
+
+	return &folder, nil
+}
+
+// ChildrenOf returns the folders whose parent is one of parents, keeping their order. It matches the
+// direct-children listing of ListFoldersFromParents on folders loaded from a file.
+func ChildrenOf(folderList []*Folder, parents ...string) []*Folder {
+	wanted := make(map[string]bool, len(parents))
+	for _, parent := range parents {
+		wanted[parent] = true
+	}
+
+	children := make([]*Folder, 0)
+	for _, folder := range folderList {
+		if wanted[folder.Parent] {
+			children = append(children, folder)
+		}
+	}
+
+	return children
+}
+
+// DescendantsOf returns every folder below parent, level by level like ListFolderTree, on folders
+// loaded from a file.
+func DescendantsOf(folderList []*Folder, parent string) []*Folder {
+	descendants := make([]*Folder, 0)
+	seen := make(map[string]bool)
+	level := []string{parent}
+
+	for len(level) > 0 {
+		var next []string
+		for _, folder := range ChildrenOf(folderList, level...) {
+			if seen[folder.ID] {
+				continue
+			}
+			seen[folder.ID] = true
+			descendants = append(descendants, folder)
+			next = append(next, folderPrefix+folder.ID)
+		}
+		level = next
+	}
+
+	return descendants
+}
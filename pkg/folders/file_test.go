@@ -0,0 +1,143 @@
+package folders_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const folderFileFixture = `[
+  {
+    "id": "1",
+    "name": "folders/1",
+    "display_name": "Engineering",
+    "parent": "organizations/9",
+    "state": "ACTIVE",
+    "create_time": "2024-01-02T03:04:05Z",
+    "update_time": "2024-02-03T04:05:06Z",
+    "age": 3600
+  },
+  {"name": "folders/2", "display_name": "Backend", "parent": "folders/1"},
+  {"id": "3", "display_name": "Platform", "parent": "folders/2"},
+  {"id": "4", "display_name": "Finance", "parent": "organizations/9"}
+]`
+
+func TestLoadFolders(t *testing.T) {
+	tests := map[string]struct {
+		data     string
+		wantIDs  []string
+		wantErr  string
+		validate func(t *testing.T, folderList []*folders.Folder)
+	}{
+		"json array": {
+			data:    folderFileFixture,
+			wantIDs: []string{"1", "2", "3", "4"},
+			validate: func(t *testing.T, folderList []*folders.Folder) {
+				t.Helper()
+				assert.Equal(t, "Engineering", folderList[0].DisplayName)
+				assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), folderList[0].CreateTime)
+				assert.Equal(t, "2", folderList[1].ID, "the ID is derived from the name")
+				assert.Equal(t, "folders/3", folderList[2].Name, "the name is derived from the ID")
+			},
+		},
+		"jsonl": {
+			data:    "{\"id\": \"1\", \"parent\": \"organizations/9\"}\n{\"id\": \"2\", \"parent\": \"folders/1\"}\n",
+			wantIDs: []string{"1", "2"},
+		},
+		"empty array": {
+			data:    " [] ",
+			wantIDs: []string{},
+		},
+		"empty file": {
+			data:    "\n",
+			wantErr: "the file is empty",
+		},
+		"not a listing": {
+			data:    `"folders"`,
+			wantErr: "expected a JSON array or JSONL objects",
+		},
+		"malformed json": {
+			data:    `[{"id": "1"`,
+			wantErr: "unexpected end of JSON input",
+		},
+		"element is not an object": {
+			data:    `[{"id": "1"}, "2"]`,
+			wantErr: "folder 2: not a JSON object",
+		},
+		"wrong field type": {
+			data:    `[{"id": 1}]`,
+			wantErr: "folder 1: failed to decode folder",
+		},
+		"missing id and name": {
+			data:    `[{"display_name": "Engineering"}]`,
+			wantErr: `folder 1: missing "id" and "name"`,
+		},
+		"unknown parent": {
+			data:    `[{"id": "1", "parent": "projects/5"}]`,
+			wantErr: "folder 1: parent is not an organizations/ or folders/ resource name: projects/5",
+		},
+		"duplicate id": {
+			data:    `[{"id": "1"}, {"name": "folders/1"}]`,
+			wantErr: "folder 2: duplicate id 1, first seen in folder 1",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			folderList, err := folders.LoadFolders([]byte(tt.data))
+			if tt.wantErr != "" {
+				require.ErrorIs(t, err, folders.ErrInvalidFolderFile)
+				assert.Contains(t, err.Error(), tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			ids := make([]string, 0, len(folderList))
+			for _, folder := range folderList {
+				ids = append(ids, folder.ID)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+			if tt.validate != nil {
+				tt.validate(t, folderList)
+			}
+		})
+	}
+}
+
+func TestLoadFoldersFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "folders.json")
+	require.NoError(t, os.WriteFile(path, []byte(folderFileFixture), 0o600))
+
+	folderList, err := folders.LoadFoldersFile(path)
+	require.NoError(t, err)
+	assert.Len(t, folderList, 4)
+
+	_, err = folders.LoadFoldersFile(filepath.Join(t.TempDir(), "missing.json"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestChildrenOfAndDescendantsOf(t *testing.T) {
+	folderList, err := folders.LoadFolders([]byte(folderFileFixture))
+	require.NoError(t, err)
+
+	ids := func(folderList []*folders.Folder) []string {
+		ids := make([]string, 0, len(folderList))
+		for _, folder := range folderList {
+			ids = append(ids, folder.ID)
+		}
+
+		return ids
+	}
+
+	assert.Equal(t, []string{"1", "4"}, ids(folders.ChildrenOf(folderList, "organizations/9")))
+	assert.Equal(t, []string{"2", "3"}, ids(folders.ChildrenOf(folderList, "folders/1", "folders/2")))
+	assert.Empty(t, folders.ChildrenOf(folderList, "folders/4"))
+	assert.Equal(t, []string{"1", "4", "2", "3"}, ids(folders.DescendantsOf(folderList, "organizations/9")))
+	assert.Equal(t, []string{"3"}, ids(folders.DescendantsOf(folderList, "folders/2")))
+}
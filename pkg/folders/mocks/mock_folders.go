@@ -8,8 +8,111 @@ import (
 	"context"
 	"github.com/andreygrechin/gcphelper/pkg/folders"
 	mock "github.com/stretchr/testify/mock"
+	"google.golang.org/api/cloudasset/v1"
 )
 
+// NewMockAssetSearcher creates a new instance of MockAssetSearcher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAssetSearcher(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAssetSearcher {
+	mock := &MockAssetSearcher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockAssetSearcher is an autogenerated mock type for the AssetSearcher type
+type MockAssetSearcher struct {
+	mock.Mock
+}
+
+type MockAssetSearcher_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAssetSearcher) EXPECT() *MockAssetSearcher_Expecter {
+	return &MockAssetSearcher_Expecter{mock: &_m.Mock}
+}
+
+// SearchAllResources provides a mock function for the type MockAssetSearcher
+func (_mock *MockAssetSearcher) SearchAllResources(ctx context.Context, scope string, assetType string, query string, fn func(*cloudasset.ResourceSearchResult) error) error {
+	ret := _mock.Called(ctx, scope, assetType, query, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchAllResources")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string, func(*cloudasset.ResourceSearchResult) error) error); ok {
+		r0 = returnFunc(ctx, scope, assetType, query, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockAssetSearcher_SearchAllResources_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SearchAllResources'
+type MockAssetSearcher_SearchAllResources_Call struct {
+	*mock.Call
+}
+
+// SearchAllResources is a helper method to define mock.On call
+//   - ctx context.Context
+//   - scope string
+//   - assetType string
+//   - query string
+//   - fn func(*cloudasset.ResourceSearchResult) error
+func (_e *MockAssetSearcher_Expecter) SearchAllResources(ctx interface{}, scope interface{}, assetType interface{}, query interface{}, fn interface{}) *MockAssetSearcher_SearchAllResources_Call {
+	return &MockAssetSearcher_SearchAllResources_Call{Call: _e.mock.On("SearchAllResources", ctx, scope, assetType, query, fn)}
+}
+
+func (_c *MockAssetSearcher_SearchAllResources_Call) Run(run func(ctx context.Context, scope string, assetType string, query string, fn func(*cloudasset.ResourceSearchResult) error)) *MockAssetSearcher_SearchAllResources_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		var arg4 func(*cloudasset.ResourceSearchResult) error
+		if args[4] != nil {
+			arg4 = args[4].(func(*cloudasset.ResourceSearchResult) error)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAssetSearcher_SearchAllResources_Call) Return(err error) *MockAssetSearcher_SearchAllResources_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockAssetSearcher_SearchAllResources_Call) RunAndReturn(run func(ctx context.Context, scope string, assetType string, query string, fn func(*cloudasset.ResourceSearchResult) error) error) *MockAssetSearcher_SearchAllResources_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockFetcher creates a new instance of MockFetcher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockFetcher(t interface {
@@ -81,55 +184,55 @@ func (_c *MockFetcher_Close_Call) RunAndReturn(run func() error) *MockFetcher_Cl
 	return _c
 }
 
-// ListFolders provides a mock function for the type MockFetcher
-func (_mock *MockFetcher) ListFolders(ctx context.Context, opts *folders.FetchOptions) ([]*folders.Folder, error) {
-	ret := _mock.Called(ctx, opts)
+// GetFolder provides a mock function for the type MockFetcher
+func (_mock *MockFetcher) GetFolder(ctx context.Context, name string) (*folders.Folder, error) {
+	ret := _mock.Called(ctx, name)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListFolders")
+		panic("no return value specified for GetFolder")
 	}
 
-	var r0 []*folders.Folder
+	var r0 *folders.Folder
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *folders.FetchOptions) ([]*folders.Folder, error)); ok {
-		return returnFunc(ctx, opts)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*folders.Folder, error)); ok {
+		return returnFunc(ctx, name)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *folders.FetchOptions) []*folders.Folder); ok {
-		r0 = returnFunc(ctx, opts)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *folders.Folder); ok {
+		r0 = returnFunc(ctx, name)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*folders.Folder)
+			r0 = ret.Get(0).(*folders.Folder)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, *folders.FetchOptions) error); ok {
-		r1 = returnFunc(ctx, opts)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, name)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockFetcher_ListFolders_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListFolders'
-type MockFetcher_ListFolders_Call struct {
+// MockFetcher_GetFolder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFolder'
+type MockFetcher_GetFolder_Call struct {
 	*mock.Call
 }
 
-// ListFolders is a helper method to define mock.On call
+// GetFolder is a helper method to define mock.On call
 //   - ctx context.Context
-//   - opts *folders.FetchOptions
-func (_e *MockFetcher_Expecter) ListFolders(ctx interface{}, opts interface{}) *MockFetcher_ListFolders_Call {
-	return &MockFetcher_ListFolders_Call{Call: _e.mock.On("ListFolders", ctx, opts)}
+//   - name string
+func (_e *MockFetcher_Expecter) GetFolder(ctx interface{}, name interface{}) *MockFetcher_GetFolder_Call {
+	return &MockFetcher_GetFolder_Call{Call: _e.mock.On("GetFolder", ctx, name)}
 }
 
-func (_c *MockFetcher_ListFolders_Call) Run(run func(ctx context.Context, opts *folders.FetchOptions)) *MockFetcher_ListFolders_Call {
+func (_c *MockFetcher_GetFolder_Call) Run(run func(ctx context.Context, name string)) *MockFetcher_GetFolder_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *folders.FetchOptions
+		var arg1 string
 		if args[1] != nil {
-			arg1 = args[1].(*folders.FetchOptions)
+			arg1 = args[1].(string)
 		}
 		run(
 			arg0,
@@ -139,12 +242,12 @@ func (_c *MockFetcher_ListFolders_Call) Run(run func(ctx context.Context, opts *
 	return _c
 }
 
-func (_c *MockFetcher_ListFolders_Call) Return(folders1 []*folders.Folder, err error) *MockFetcher_ListFolders_Call {
-	_c.Call.Return(folders1, err)
+func (_c *MockFetcher_GetFolder_Call) Return(folder *folders.Folder, err error) *MockFetcher_GetFolder_Call {
+	_c.Call.Return(folder, err)
 	return _c
 }
 
-func (_c *MockFetcher_ListFolders_Call) RunAndReturn(run func(ctx context.Context, opts *folders.FetchOptions) ([]*folders.Folder, error)) *MockFetcher_ListFolders_Call {
+func (_c *MockFetcher_GetFolder_Call) RunAndReturn(run func(ctx context.Context, name string) (*folders.Folder, error)) *MockFetcher_GetFolder_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -222,3 +325,161 @@ func (_c *MockFetcher_ListFoldersFromParent_Call) RunAndReturn(run func(ctx cont
 	_c.Call.Return(run)
 	return _c
 }
+
+// WalkFolders provides a mock function for the type MockFetcher
+func (_mock *MockFetcher) WalkFolders(ctx context.Context, opts *folders.FetchOptions, fn func(*folders.Folder) error) error {
+	ret := _mock.Called(ctx, opts, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WalkFolders")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *folders.FetchOptions, func(*folders.Folder) error) error); ok {
+		r0 = returnFunc(ctx, opts, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockFetcher_WalkFolders_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WalkFolders'
+type MockFetcher_WalkFolders_Call struct {
+	*mock.Call
+}
+
+// WalkFolders is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opts *folders.FetchOptions
+//   - fn func(*folders.Folder) error
+func (_e *MockFetcher_Expecter) WalkFolders(ctx interface{}, opts interface{}, fn interface{}) *MockFetcher_WalkFolders_Call {
+	return &MockFetcher_WalkFolders_Call{Call: _e.mock.On("WalkFolders", ctx, opts, fn)}
+}
+
+func (_c *MockFetcher_WalkFolders_Call) Run(run func(ctx context.Context, opts *folders.FetchOptions, fn func(*folders.Folder) error)) *MockFetcher_WalkFolders_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *folders.FetchOptions
+		if args[1] != nil {
+			arg1 = args[1].(*folders.FetchOptions)
+		}
+		var arg2 func(*folders.Folder) error
+		if args[2] != nil {
+			arg2 = args[2].(func(*folders.Folder) error)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockFetcher_WalkFolders_Call) Return(err error) *MockFetcher_WalkFolders_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockFetcher_WalkFolders_Call) RunAndReturn(run func(ctx context.Context, opts *folders.FetchOptions, fn func(*folders.Folder) error) error) *MockFetcher_WalkFolders_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockGetter creates a new instance of MockGetter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockGetter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockGetter {
+	mock := &MockGetter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockGetter is an autogenerated mock type for the Getter type
+type MockGetter struct {
+	mock.Mock
+}
+
+type MockGetter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockGetter) EXPECT() *MockGetter_Expecter {
+	return &MockGetter_Expecter{mock: &_m.Mock}
+}
+
+// GetFolder provides a mock function for the type MockGetter
+func (_mock *MockGetter) GetFolder(ctx context.Context, name string) (*folders.Folder, error) {
+	ret := _mock.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFolder")
+	}
+
+	var r0 *folders.Folder
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*folders.Folder, error)); ok {
+		return returnFunc(ctx, name)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *folders.Folder); ok {
+		r0 = returnFunc(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*folders.Folder)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockGetter_GetFolder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFolder'
+type MockGetter_GetFolder_Call struct {
+	*mock.Call
+}
+
+// GetFolder is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+func (_e *MockGetter_Expecter) GetFolder(ctx interface{}, name interface{}) *MockGetter_GetFolder_Call {
+	return &MockGetter_GetFolder_Call{Call: _e.mock.On("GetFolder", ctx, name)}
+}
+
+func (_c *MockGetter_GetFolder_Call) Run(run func(ctx context.Context, name string)) *MockGetter_GetFolder_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockGetter_GetFolder_Call) Return(folder *folders.Folder, err error) *MockGetter_GetFolder_Call {
+	_c.Call.Return(folder, err)
+	return _c
+}
+
+func (_c *MockGetter_GetFolder_Call) RunAndReturn(run func(ctx context.Context, name string) (*folders.Folder, error)) *MockGetter_GetFolder_Call {
+	_c.Call.Return(run)
+	return _c
+}
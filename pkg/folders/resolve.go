@@ -0,0 +1,109 @@
+package folders
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"google.golang.org/grpc/status"
+)
+
+// Resolution is the display name found for one folder ID, or why it could not be found.
+type Resolution struct {
+	ID          string `json:"id"`              // ID is the folder's ID ("123456789")
+	Name        string `json:"-"`               // Name is the folder's resource name ("folders/123456789")
+	DisplayName string `json:"display_name"`    // DisplayName is empty when the folder could not be retrieved
+	Error       string `json:"error,omitempty"` // Error is why the folder could not be retrieved, e.g. "NotFound"
+}
+
+// ResolutionHeaders returns the table headers for resolved folder IDs.
+func ResolutionHeaders() []string {
+	return []string{"ID", "Display Name", "Error"}
+}
+
+// GetID returns the folder's ID.
+func (r *Resolution) GetID() string {
+	return r.ID
+}
+
+// GetName returns the folder's resource name.
+func (r *Resolution) GetName() string {
+	return r.Name
+}
+
+// GetDisplayName returns the folder's display name, empty when it could not be retrieved.
+func (r *Resolution) GetDisplayName() string {
+	return r.DisplayName
+}
+
+// GetState returns an empty string since resolutions carry no lifecycle state.
+func (r *Resolution) GetState() string {
+	return ""
+}
+
+// GetCreateTime returns the zero time since resolutions carry no timestamps.
+func (r *Resolution) GetCreateTime() time.Time {
+	return time.Time{}
+}
+
+// GetUpdateTime returns the zero time since resolutions carry no timestamps.
+func (r *Resolution) GetUpdateTime() time.Time {
+	return time.Time{}
+}
+
+// TableRow returns the ID, display name and error note as a table row.
+func (r *Resolution) TableRow() []interface{} {
+	return table.Row{r.ID, r.DisplayName, r.Error}
+}
+
+// ResolveFolders looks up the display names of the folders with the given resource names, at most
+// concurrency at a time, and returns one Resolution per name in the order of names. A name given more
+// than once is fetched once. Folders that cannot be retrieved, for example because they do not exist or
+// are not accessible, get a Resolution with an error note instead of failing the whole lookup.
+func (s *Service) ResolveFolders(ctx context.Context, names []string, concurrency int) []*Resolution {
+	found, err := s.GetFolders(ctx, names, concurrency, true)
+
+	byName := make(map[string]*Folder, len(found))
+	for _, folder := range found {
+		byName[folder.Name] = folder
+	}
+	failures := make(map[string]error)
+	var lookupErr *LookupError
+	if errors.As(err, &lookupErr) {
+		for _, failure := range lookupErr.Failures {
+			failures[failure.Name] = failure.Err
+		}
+	}
+
+	resolutions := make([]*Resolution, len(names))
+	for i, name := range names {
+		resolution := &Resolution{ID: strings.TrimPrefix(name, folderPrefix), Name: name}
+		if folder, ok := byName[name]; ok {
+			resolution.DisplayName = folder.DisplayName
+		} else {
+			resolution.Error = resolutionNote(failures[name])
+		}
+		resolutions[i] = resolution
+	}
+
+	return resolutions
+}
+
+// resolutionNote describes why a folder could not be retrieved: its gRPC code and message, or the full
+// error text for errors without a status.
+func resolutionNote(err error) string {
+	if err == nil {
+		return "not retrieved"
+	}
+
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &grpcErr) {
+		st := grpcErr.GRPCStatus()
+
+		return st.Code().String() + ": " + st.Message()
+	}
+
+	return err.Error()
+}
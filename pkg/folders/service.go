@@ -2,23 +2,38 @@ package folders
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"iter"
 	"time"
 
 	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/andreygrechin/gcphelper/internal/progress"
+	"github.com/andreygrechin/gcphelper/internal/reqmeta"
 	"github.com/briandowns/spinner"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/api/option"
 )
 
+// errStopIteration stops the fetcher walk when an iterator consumer breaks out of the loop.
+var errStopIteration = errors.New("iteration stopped")
+
 const (
 	spinnerSpeed = 100 * time.Millisecond
 	spinnerStyle = 11
 )
 
+// DefaultConcurrency is the number of folders GetFolders retrieves in parallel when none is specified.
+const DefaultConcurrency = 4
+
 // Service provides high-level operations for working with Google Cloud folders.
 type Service struct {
 	fetcher Fetcher
 	logger  logger.Logger
+
+	noSpinner  bool
+	newSpinner func(suffix string) progress.Spinner
 }
 
 // NewServiceWithLogger creates a new folders service with the provided fetcher and logger.
@@ -30,8 +45,30 @@ func NewServiceWithLogger(fetcher Fetcher, log logger.Logger) *Service {
 }
 
 // NewServiceFromContextWithLogger creates a new folders service using application default credentials with logger.
-func NewServiceFromContextWithLogger(ctx context.Context, log logger.Logger) (*Service, error) {
-	client, err := NewClientFromContext(ctx)
+func NewServiceFromContextWithLogger(
+	ctx context.Context,
+	log logger.Logger,
+	clientOpts ...option.ClientOption,
+) (*Service, error) {
+	client, err := NewClientFromContext(ctx, clientOpts...)
+	if err != nil {
+		return nil, err
+	}
+	if log == nil {
+		log = logger.NewNoOpLogger()
+	}
+
+	return NewServiceWithLogger(client, log), nil
+}
+
+// NewAssetServiceFromContextWithLogger creates a new folders service that searches Cloud Asset Inventory
+// instead of calling the Resource Manager API, using application default credentials with logger.
+func NewAssetServiceFromContextWithLogger(
+	ctx context.Context,
+	log logger.Logger,
+	clientOpts ...option.ClientOption,
+) (*Service, error) {
+	client, err := NewAssetClientFromContext(ctx, clientOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -42,6 +79,40 @@ func NewServiceFromContextWithLogger(ctx context.Context, log logger.Logger) (*S
 	return NewServiceWithLogger(client, log), nil
 }
 
+// SetSpinner enables or disables the progress spinner shown while fetching. The spinner is enabled by
+// default and is only drawn when stdout is a terminal.
+func (s *Service) SetSpinner(enabled bool) {
+	s.noSpinner = !enabled
+}
+
+// SetSpinnerFactory replaces how progress spinners are created, for example to observe them in tests.
+func (s *Service) SetSpinnerFactory(newSpinner func(suffix string) progress.Spinner) {
+	s.newSpinner = newSpinner
+}
+
+// startSpinner shows a spinner with the given suffix, unless spinners are disabled, and returns a
+// function that stops it.
+func (s *Service) startSpinner(ctx context.Context, suffix string) func() {
+	if s.noSpinner {
+		return func() {}
+	}
+
+	newSpinner := s.newSpinner
+	if newSpinner == nil {
+		newSpinner = terminalSpinner
+	}
+
+	return progress.Start(ctx, newSpinner(suffix))
+}
+
+// terminalSpinner creates the animated terminal spinner.
+func terminalSpinner(suffix string) progress.Spinner {
+	spin := spinner.New(spinner.CharSets[spinnerStyle], spinnerSpeed)
+	spin.Suffix = suffix
+
+	return spin
+}
+
 // ListFolders lists all accessible folders.
 func (s *Service) ListFolders(ctx context.Context, opts *FetchOptions) ([]*Folder, error) {
 	if opts == nil {
@@ -57,18 +128,18 @@ func (s *Service) ListFolders(ctx context.Context, opts *FetchOptions) ([]*Folde
 	}
 
 	// show progress indicator for potentially long-running operations
-	spin := spinner.New(spinner.CharSets[spinnerStyle], spinnerSpeed)
+	suffix := " Fetching folders..."
 	if opts.Parent != "" {
-		spin.Suffix = fmt.Sprintf(" Fetching folders from parent %s...", opts.Parent)
-	} else {
-		spin.Suffix = " Fetching folders..."
+		suffix = fmt.Sprintf(" Fetching folders from parent %s...", opts.Parent)
 	}
-	spin.Start()
-	defer spin.Stop()
+	defer s.startSpinner(ctx, suffix)()
 
-	folders, err := s.fetcher.ListFolders(ctx, opts)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list folders: %w", err)
+	folders := make([]*Folder, 0)
+	for folder, err := range s.ListFoldersIter(ctx, opts) {
+		if err != nil {
+			return nil, err
+		}
+		folders = append(folders, folder)
 	}
 
 	if s.logger != nil {
@@ -78,6 +149,320 @@ func (s *Service) ListFolders(ctx context.Context, opts *FetchOptions) ([]*Folde
 	return folders, nil
 }
 
+// ListFoldersFromParent lists the direct child folders of parent with the ListFolders API.
+// Unlike ListFolders, which uses SearchFolders and returns only the folders the caller can get,
+// it requires list permission on the parent and returns every active child, with strongly
+// consistent results.
+func (s *Service) ListFoldersFromParent(ctx context.Context, parent string, opts *FetchOptions) ([]*Folder, error) {
+	if opts == nil {
+		opts = NewFetchOptions()
+	}
+
+	if s.logger != nil {
+		s.logger.Debug("listing folders under parent", zap.String("parent", parent))
+	}
+
+	defer s.startSpinner(ctx, fmt.Sprintf(" Listing folders under %s...", parent))()
+
+	folders, err := s.listFromParent(ctx, parent, opts)
+	if err != nil {
+		return nil, err
+	}
+	opts.reportProgress(len(folders))
+
+	if s.logger != nil {
+		s.logger.Debug("successfully listed folders", zap.Int("count", len(folders)))
+	}
+
+	return folders, nil
+}
+
+// ListFoldersFromParents lists the direct child folders of each of the given parents in turn, skipping
+// duplicate IDs.
+// By default the first failing parent aborts the listing. With continueOnError, failures are collected
+// and the folders from the remaining parents are returned together with a *PartialError.
+func (s *Service) ListFoldersFromParents(
+	ctx context.Context,
+	parents []string,
+	opts *FetchOptions,
+	continueOnError bool,
+) ([]*Folder, error) {
+	if opts == nil {
+		opts = NewFetchOptions()
+	}
+
+	if s.logger != nil {
+		s.logger.Debug("fetching folders from parents", zap.Strings("parents", parents))
+	}
+
+	defer s.startSpinner(ctx, fmt.Sprintf(" Fetching folders from %d parents...", len(parents)))()
+
+	folders := make([]*Folder, 0)
+	seen := make(map[string]struct{})
+	partial := &PartialError{Total: len(parents)}
+
+	for _, parent := range parents {
+		children, err := s.listFromParent(ctx, parent, opts)
+		if err != nil {
+			if !continueOnError {
+				return nil, err
+			}
+			if s.logger != nil {
+				s.logger.Debug("failed to fetch folders from parent", zap.String("parent", parent), zap.Error(err))
+			}
+			partial.Failures = append(partial.Failures, ParentError{Parent: parent, Err: err})
+
+			continue
+		}
+
+		for _, folder := range children {
+			if _, ok := seen[folder.ID]; ok {
+				continue
+			}
+			seen[folder.ID] = struct{}{}
+			folders = append(folders, folder)
+		}
+		opts.reportProgress(len(folders))
+	}
+
+	if len(partial.Failures) > 0 {
+		return folders, partial
+	}
+
+	return folders, nil
+}
+
+// ListFolderTree lists every descendant folder of parent by walking the hierarchy breadth-first with
+// the ListFolders API, one call per folder. Folders are returned level by level, parents before their
+// children, and a folder reached twice is listed once.
+func (s *Service) ListFolderTree(ctx context.Context, parent string, opts *FetchOptions) ([]*Folder, error) {
+	if opts == nil {
+		opts = NewFetchOptions()
+	}
+
+	if s.logger != nil {
+		s.logger.Debug("listing folder tree under parent", zap.String("parent", parent))
+	}
+
+	defer s.startSpinner(ctx, fmt.Sprintf(" Listing folder tree under %s...", parent))()
+
+	folders := make([]*Folder, 0)
+	seen := make(map[string]struct{})
+	queue := []string{parent}
+
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+
+		children, err := s.listFromParent(ctx, next, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, folder := range children {
+			if _, ok := seen[folder.ID]; ok {
+				continue
+			}
+			seen[folder.ID] = struct{}{}
+			folders = append(folders, folder)
+			queue = append(queue, folderPrefix+folder.ID)
+		}
+		opts.reportProgress(len(folders))
+	}
+
+	if s.logger != nil {
+		s.logger.Debug("successfully listed folder tree", zap.Int("count", len(folders)))
+	}
+
+	return folders, nil
+}
+
+// listFromParent lists the direct child folders of parent, attaching the request reason.
+func (s *Service) listFromParent(ctx context.Context, parent string, opts *FetchOptions) ([]*Folder, error) {
+	ctx = reqmeta.WithRequestReason(ctx, opts.RequestReason)
+
+	folders, err := s.fetcher.ListFoldersFromParent(ctx, parent, opts)
+	if err != nil {
+		return nil, &Error{Op: "list folders", Err: err}
+	}
+	for _, folder := range folders {
+		s.warnMalformedID(folder)
+	}
+
+	return folders, nil
+}
+
+// warnMalformedID logs a warning when a fetched folder's name is not "folders/" followed by a numeric ID,
+// in which case the raw name is kept as its ID.
+func (s *Service) warnMalformedID(folder *Folder) {
+	if s.logger == nil || folder == nil {
+		return
+	}
+	if err := folder.IDError(); err != nil {
+		s.logger.Warn("keeping the raw folder name as its ID", zap.String("name", folder.Name), zap.Error(err))
+	}
+}
+
+// ListFoldersIter returns an iterator over all accessible folders that yields each folder as it is
+// fetched, skipping duplicate IDs, and reports the number fetched so far to opts.Progress. A fetch
+// error is yielded once as the final element. Breaking out of the loop stops fetching, and a cancelled
+// ctx ends iteration with the context error.
+func (s *Service) ListFoldersIter(ctx context.Context, opts *FetchOptions) iter.Seq2[*Folder, error] {
+	if opts == nil {
+		opts = NewFetchOptions()
+	}
+
+	return func(yield func(*Folder, error) bool) {
+		ctx := reqmeta.WithRequestReason(ctx, opts.RequestReason)
+		seen := make(map[string]struct{})
+
+		err := s.fetcher.WalkFolders(ctx, opts, func(folder *Folder) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if _, ok := seen[folder.ID]; ok {
+				return nil
+			}
+			seen[folder.ID] = struct{}{}
+			s.warnMalformedID(folder)
+			opts.reportProgress(len(seen))
+
+			if !yield(folder, nil) {
+				return errStopIteration
+			}
+
+			return nil
+		})
+		if err != nil && !errors.Is(err, errStopIteration) {
+			yield(nil, &Error{Op: "list folders", Err: err})
+		}
+	}
+}
+
+// StreamFolders fetches folders in the background and delivers them on the returned folder channel
+// as they arrive, without collecting the full result set. Duplicate IDs are dropped. The folder channel
+// is closed when fetching ends, after which the error channel yields the fetch error, if any.
+// Cancel ctx to stop fetching early.
+func (s *Service) StreamFolders(ctx context.Context, opts *FetchOptions) (<-chan *Folder, <-chan error) {
+	if opts == nil {
+		opts = NewFetchOptions()
+	}
+
+	folderCh := make(chan *Folder)
+	errCh := make(chan error, 1)
+
+	go func() {
+		defer close(errCh)
+		defer close(folderCh)
+
+		if s.logger != nil {
+			s.logger.Debug("streaming folders", zap.String("parent", opts.Parent))
+		}
+
+		count := 0
+		for folder, err := range s.ListFoldersIter(ctx, opts) {
+			if err != nil {
+				errCh <- err
+
+				return
+			}
+
+			select {
+			case folderCh <- folder:
+				count++
+			case <-ctx.Done():
+				errCh <- &Error{Op: "list folders", Err: ctx.Err()}
+
+				return
+			}
+		}
+
+		if s.logger != nil {
+			s.logger.Debug("successfully streamed folders", zap.Int("count", count))
+		}
+	}()
+
+	return folderCh, errCh
+}
+
+// GetFolder retrieves a single folder by its resource name (e.g., "folders/123").
+func (s *Service) GetFolder(ctx context.Context, name string) (*Folder, error) {
+	if s.logger != nil {
+		s.logger.Debug("fetching folder", zap.String("name", name))
+	}
+
+	folder, err := s.fetcher.GetFolder(ctx, name)
+	if err != nil {
+		return nil, &Error{Op: "get folder", Err: err}
+	}
+	s.warnMalformedID(folder)
+
+	return folder, nil
+}
+
+// GetFolders retrieves the folders with the given resource names, at most concurrency at a time, and
+// returns them in the order of names. A name given more than once is fetched once.
+// By default the first failure cancels the remaining lookups and is returned. With continueOnError,
+// failures are collected and the folders that were found are returned together with a *LookupError.
+func (s *Service) GetFolders(
+	ctx context.Context,
+	names []string,
+	concurrency int,
+	continueOnError bool,
+) ([]*Folder, error) {
+	if concurrency < 1 {
+		concurrency = DefaultConcurrency
+	}
+
+	unique := make([]string, 0, len(names))
+	seen := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		unique = append(unique, name)
+	}
+
+	defer s.startSpinner(ctx, fmt.Sprintf(" Fetching %d folders...", len(unique)))()
+
+	found := make([]*Folder, len(unique))
+	errs := make([]error, len(unique))
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(concurrency)
+	for i, name := range unique {
+		group.Go(func() error {
+			folder, err := s.GetFolder(groupCtx, name)
+			if err != nil && !continueOnError {
+				return err
+			}
+			found[i], errs[i] = folder, err
+
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	folders := make([]*Folder, 0, len(unique))
+	partial := &LookupError{Total: len(unique)}
+	for i, name := range unique {
+		if errs[i] != nil {
+			partial.Failures = append(partial.Failures, FolderError{Name: name, Err: errs[i]})
+
+			continue
+		}
+		folders = append(folders, found[i])
+	}
+
+	if len(partial.Failures) > 0 {
+		return folders, partial
+	}
+
+	return folders, nil
+}
+
 // Close releases any resources held by the service.
 func (s *Service) Close() error {
 	if s.fetcher != nil {
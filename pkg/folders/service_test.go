@@ -1,16 +1,28 @@
 package folders_test
 
 import (
+	"context"
 	"errors"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	resourcemanagerpb "cloud.google.com/go/resourcemanager/apiv3/resourcemanagerpb"
 	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/andreygrechin/gcphelper/internal/progress"
 	"github.com/andreygrechin/gcphelper/pkg/folders"
 	"github.com/andreygrechin/gcphelper/pkg/folders/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 // Test error variables for err113 compliance.
@@ -19,6 +31,22 @@ var (
 	errServiceTestOrgNotFound = errors.New("org not found")
 )
 
+// walkFolders returns a WalkFolders implementation that visits folderList and then returns err.
+func walkFolders(
+	folderList []*folders.Folder,
+	err error,
+) func(context.Context, *folders.FetchOptions, func(*folders.Folder) error) error {
+	return func(_ context.Context, _ *folders.FetchOptions, fn func(*folders.Folder) error) error {
+		for _, folder := range folderList {
+			if fnErr := fn(folder); fnErr != nil {
+				return fnErr
+			}
+		}
+
+		return err
+	}
+}
+
 func TestService_ListFolders(t *testing.T) {
 	expectedFolders := []*folders.Folder{
 		{
@@ -51,9 +79,9 @@ func TestService_ListFolders(t *testing.T) {
 		"successful fetch with default options": {
 			opts: nil,
 			setupMock: func(m *mocks.MockFetcher) {
-				m.On("ListFolders", mock.Anything, mock.MatchedBy(func(opts *folders.FetchOptions) bool {
+				m.On("WalkFolders", mock.Anything, mock.MatchedBy(func(opts *folders.FetchOptions) bool {
 					return opts.Parent == ""
-				})).Return(expectedFolders, nil)
+				}), mock.Anything).Return(walkFolders(expectedFolders, nil))
 			},
 			want:    expectedFolders,
 			wantErr: false,
@@ -63,9 +91,9 @@ func TestService_ListFolders(t *testing.T) {
 				Parent: "organizations/123456789",
 			},
 			setupMock: func(m *mocks.MockFetcher) {
-				m.On("ListFolders", mock.Anything, mock.MatchedBy(func(opts *folders.FetchOptions) bool {
+				m.On("WalkFolders", mock.Anything, mock.MatchedBy(func(opts *folders.FetchOptions) bool {
 					return opts.Parent == "organizations/123456789"
-				})).Return(expectedFolders, nil)
+				}), mock.Anything).Return(walkFolders(expectedFolders, nil))
 			},
 			want:    expectedFolders,
 			wantErr: false,
@@ -73,7 +101,8 @@ func TestService_ListFolders(t *testing.T) {
 		"fetcher returns error": {
 			opts: nil,
 			setupMock: func(m *mocks.MockFetcher) {
-				m.On("ListFolders", mock.Anything, mock.Anything).Return(nil, errServiceTestAPIError)
+				m.On("WalkFolders", mock.Anything, mock.Anything, mock.Anything).
+					Return(walkFolders(nil, errServiceTestAPIError))
 			},
 			want:        nil,
 			wantErr:     true,
@@ -130,9 +159,9 @@ func TestService_ListFoldersWithParent(t *testing.T) {
 		"successful fetch with organization parent": {
 			parent: "organizations/987654321",
 			setupMock: func(m *mocks.MockFetcher) {
-				m.On("ListFolders", mock.Anything, mock.MatchedBy(func(opts *folders.FetchOptions) bool {
+				m.On("WalkFolders", mock.Anything, mock.MatchedBy(func(opts *folders.FetchOptions) bool {
 					return opts.Parent == "organizations/987654321"
-				})).Return(expectedFolders, nil)
+				}), mock.Anything).Return(walkFolders(expectedFolders, nil))
 			},
 			want:    expectedFolders,
 			wantErr: false,
@@ -140,9 +169,9 @@ func TestService_ListFoldersWithParent(t *testing.T) {
 		"successful fetch with folder parent": {
 			parent: "folders/123456789",
 			setupMock: func(m *mocks.MockFetcher) {
-				m.On("ListFolders", mock.Anything, mock.MatchedBy(func(opts *folders.FetchOptions) bool {
+				m.On("WalkFolders", mock.Anything, mock.MatchedBy(func(opts *folders.FetchOptions) bool {
 					return opts.Parent == "folders/123456789"
-				})).Return(expectedFolders, nil)
+				}), mock.Anything).Return(walkFolders(expectedFolders, nil))
 			},
 			want:    expectedFolders,
 			wantErr: false,
@@ -150,7 +179,8 @@ func TestService_ListFoldersWithParent(t *testing.T) {
 		"fetcher returns error": {
 			parent: "organizations/987654321",
 			setupMock: func(m *mocks.MockFetcher) {
-				m.On("ListFolders", mock.Anything, mock.Anything).Return(nil, errServiceTestOrgNotFound)
+				m.On("WalkFolders", mock.Anything, mock.Anything, mock.Anything).
+					Return(walkFolders(nil, errServiceTestOrgNotFound))
 			},
 			want:        nil,
 			wantErr:     true,
@@ -186,3 +216,691 @@ func TestService_ListFoldersWithParent(t *testing.T) {
 		})
 	}
 }
+
+func TestService_ListFoldersDeduplicates(t *testing.T) {
+	first := &folders.Folder{ID: "111", DisplayName: "Shared", Parent: "organizations/1"}
+	second := &folders.Folder{ID: "222", DisplayName: "Unique", Parent: "folders/111"}
+	duplicate := &folders.Folder{ID: "111", DisplayName: "Shared", Parent: "organizations/2"}
+	third := &folders.Folder{ID: "333", DisplayName: "Other", Parent: "organizations/2"}
+
+	tests := map[string]struct {
+		fetched []*folders.Folder
+		want    []*folders.Folder
+	}{
+		"removes duplicates across parents keeping first occurrence": {
+			fetched: []*folders.Folder{first, second, duplicate, third},
+			want:    []*folders.Folder{first, second, third},
+		},
+		"keeps unique folders in order": {
+			fetched: []*folders.Folder{third, second, first},
+			want:    []*folders.Folder{third, second, first},
+		},
+		"handles empty result": {
+			fetched: []*folders.Folder{},
+			want:    []*folders.Folder{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockFetcher := mocks.NewMockFetcher(t)
+			service := folders.NewServiceWithLogger(mockFetcher, logger.NewNoOpLogger())
+
+			mockFetcher.On("WalkFolders", mock.Anything, mock.Anything, mock.Anything).
+				Return(walkFolders(tt.fetched, nil))
+
+			got, err := service.ListFolders(t.Context(), nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestService_ErrorCode(t *testing.T) {
+	tests := map[string]struct {
+		setupMock   func(*mocks.MockFetcher)
+		call        func(*folders.Service) error
+		wantCode    codes.Code
+		errContains string
+	}{
+		"list folders permission denied": {
+			setupMock: func(m *mocks.MockFetcher) {
+				m.On("WalkFolders", mock.Anything, mock.Anything, mock.Anything).
+					Return(walkFolders(nil, status.Error(codes.PermissionDenied, "denied")))
+			},
+			call: func(s *folders.Service) error {
+				_, err := s.ListFolders(t.Context(), nil)
+
+				return err
+			},
+			wantCode:    codes.PermissionDenied,
+			errContains: "failed to list folders",
+		},
+		"get folder not found": {
+			setupMock: func(m *mocks.MockFetcher) {
+				m.On("GetFolder", mock.Anything, "folders/123").
+					Return(nil, status.Error(codes.NotFound, "missing"))
+			},
+			call: func(s *folders.Service) error {
+				_, err := s.GetFolder(t.Context(), "folders/123")
+
+				return err
+			},
+			wantCode:    codes.NotFound,
+			errContains: "failed to get folder",
+		},
+		"non-gRPC error": {
+			setupMock: func(m *mocks.MockFetcher) {
+				m.On("WalkFolders", mock.Anything, mock.Anything, mock.Anything).
+					Return(walkFolders(nil, errServiceTestAPIError))
+			},
+			call: func(s *folders.Service) error {
+				_, err := s.ListFolders(t.Context(), nil)
+
+				return err
+			},
+			wantCode:    codes.Unknown,
+			errContains: "failed to list folders: API error",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockFetcher := mocks.NewMockFetcher(t)
+			service := folders.NewServiceWithLogger(mockFetcher, logger.NewNoOpLogger())
+			tt.setupMock(mockFetcher)
+
+			err := tt.call(service)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+
+			var folderErr *folders.Error
+			require.ErrorAs(t, err, &folderErr)
+			assert.Equal(t, tt.wantCode, folderErr.Code())
+		})
+	}
+}
+
+func TestService_ListFoldersRequestReason(t *testing.T) {
+	tests := map[string]struct {
+		reason string
+		want   []string
+	}{
+		"attaches request reason header": {
+			reason: "change ticket 42",
+			want:   []string{"change ticket 42"},
+		},
+		"omits header when reason is empty": {
+			reason: "",
+			want:   nil,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockFetcher := mocks.NewMockFetcher(t)
+			service := folders.NewServiceWithLogger(mockFetcher, logger.NewNoOpLogger())
+
+			mockFetcher.On("WalkFolders", mock.MatchedBy(func(ctx context.Context) bool {
+				md, _ := metadata.FromOutgoingContext(ctx)
+
+				return assert.ObjectsAreEqual(tt.want, md.Get("x-goog-request-reason"))
+			}), mock.Anything, mock.Anything).Return(walkFolders([]*folders.Folder{}, nil))
+
+			_, err := service.ListFolders(t.Context(), &folders.FetchOptions{RequestReason: tt.reason})
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestService_ListFoldersIter(t *testing.T) {
+	folderList := []*folders.Folder{{ID: "1"}, {ID: "2"}, {ID: "1"}, {ID: "3"}}
+
+	tests := map[string]struct {
+		breakAfter int
+		cancel     bool
+		want       []string
+		wantVisits int
+		wantErr    error
+	}{
+		"yields every unique folder": {
+			want:       []string{"1", "2", "3"},
+			wantVisits: 4,
+		},
+		"early break stops fetching": {
+			breakAfter: 2,
+			want:       []string{"1", "2"},
+			wantVisits: 2,
+		},
+		"cancelled context ends iteration with its error": {
+			cancel:     true,
+			want:       nil,
+			wantVisits: 1,
+			wantErr:    context.Canceled,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockFetcher := mocks.NewMockFetcher(t)
+			service := folders.NewServiceWithLogger(mockFetcher, logger.NewNoOpLogger())
+
+			visits := 0
+			mockFetcher.On("WalkFolders", mock.Anything, mock.Anything, mock.Anything).
+				Return(func(ctx context.Context, opts *folders.FetchOptions, fn func(*folders.Folder) error) error {
+					return walkFolders(folderList, nil)(ctx, opts, func(folder *folders.Folder) error {
+						visits++
+
+						return fn(folder)
+					})
+				})
+
+			ctx, cancel := context.WithCancel(t.Context())
+			defer cancel()
+			if tt.cancel {
+				cancel()
+			}
+
+			var got []string
+			var gotErr error
+			for folder, err := range service.ListFoldersIter(ctx, nil) {
+				if err != nil {
+					gotErr = err
+
+					break
+				}
+				got = append(got, folder.ID)
+				if len(got) == tt.breakAfter {
+					break
+				}
+			}
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantVisits, visits)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, gotErr, tt.wantErr)
+			} else {
+				require.NoError(t, gotErr)
+			}
+		})
+	}
+}
+
+func TestService_StreamFolders(t *testing.T) {
+	first := &folders.Folder{ID: "111", DisplayName: "Shared", Parent: "organizations/1"}
+	second := &folders.Folder{ID: "222", DisplayName: "Unique", Parent: "folders/111"}
+	duplicate := &folders.Folder{ID: "111", DisplayName: "Shared", Parent: "organizations/2"}
+
+	tests := map[string]struct {
+		fetched  []*folders.Folder
+		fetchErr error
+		want     []*folders.Folder
+		wantErr  bool
+	}{
+		"delivers folders in order without duplicates": {
+			fetched: []*folders.Folder{first, second, duplicate},
+			want:    []*folders.Folder{first, second},
+		},
+		"delivers folders fetched before an error": {
+			fetched:  []*folders.Folder{first},
+			fetchErr: errServiceTestAPIError,
+			want:     []*folders.Folder{first},
+			wantErr:  true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockFetcher := mocks.NewMockFetcher(t)
+			service := folders.NewServiceWithLogger(mockFetcher, logger.NewNoOpLogger())
+
+			mockFetcher.On("WalkFolders", mock.Anything, mock.Anything, mock.Anything).
+				Return(walkFolders(tt.fetched, tt.fetchErr))
+
+			folderCh, errCh := service.StreamFolders(t.Context(), nil)
+
+			var got []*folders.Folder
+			for folder := range folderCh {
+				got = append(got, folder)
+			}
+			err := <-errCh
+
+			assert.Equal(t, tt.want, got)
+			if tt.wantErr {
+				require.ErrorIs(t, err, tt.fetchErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestService_StreamFoldersCancel(t *testing.T) {
+	mockFetcher := mocks.NewMockFetcher(t)
+	service := folders.NewServiceWithLogger(mockFetcher, logger.NewNoOpLogger())
+
+	mockFetcher.On("WalkFolders", mock.Anything, mock.Anything, mock.Anything).
+		Return(func(_ context.Context, _ *folders.FetchOptions, fn func(*folders.Folder) error) error {
+			for i := 0; ; i++ {
+				if err := fn(&folders.Folder{ID: strconv.Itoa(i)}); err != nil {
+					return err
+				}
+			}
+		})
+
+	ctx, cancel := context.WithCancel(t.Context())
+	folderCh, errCh := service.StreamFolders(ctx, nil)
+
+	<-folderCh
+	cancel()
+	for range folderCh {
+	}
+
+	require.ErrorIs(t, <-errCh, context.Canceled)
+}
+
+func TestService_ListFoldersFromParent(t *testing.T) {
+	tests := map[string]struct {
+		parent     string
+		opts       *folders.FetchOptions
+		children   []*folders.Folder
+		fetchErr   error
+		wantIDs    []string
+		wantCode   codes.Code
+		wantReason string
+	}{
+		"direct children": {
+			parent:   "folders/1",
+			children: []*folders.Folder{{ID: "10", Parent: "folders/1"}, {ID: "11", Parent: "folders/1"}},
+			wantIDs:  []string{"10", "11"},
+		},
+		"no children": {
+			parent:  "organizations/9",
+			wantIDs: []string{},
+		},
+		"request reason is attached": {
+			parent:     "folders/1",
+			opts:       &folders.FetchOptions{RequestReason: "ticket-42"},
+			children:   []*folders.Folder{{ID: "10"}},
+			wantIDs:    []string{"10"},
+			wantReason: "ticket-42",
+		},
+		"permission denied keeps the code": {
+			parent:   "folders/2",
+			fetchErr: status.Error(codes.PermissionDenied, "denied"),
+			wantCode: codes.PermissionDenied,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockFetcher := mocks.NewMockFetcher(t)
+			service := folders.NewServiceWithLogger(mockFetcher, logger.NewNoOpLogger())
+
+			mockFetcher.EXPECT().ListFoldersFromParent(mock.Anything, tt.parent, mock.Anything).
+				RunAndReturn(func(ctx context.Context, _ string, _ *folders.FetchOptions) ([]*folders.Folder, error) {
+					md, _ := metadata.FromOutgoingContext(ctx)
+					if tt.wantReason != "" {
+						assert.Equal(t, []string{tt.wantReason}, md.Get("x-goog-request-reason"))
+					}
+
+					return tt.children, tt.fetchErr
+				})
+
+			got, err := service.ListFoldersFromParent(t.Context(), tt.parent, tt.opts)
+			if tt.fetchErr != nil {
+				require.Error(t, err)
+				assert.Nil(t, got)
+				assert.Equal(t, tt.wantCode, status.Code(err))
+
+				var folderErr *folders.Error
+				require.ErrorAs(t, err, &folderErr)
+				assert.Equal(t, "list folders", folderErr.Op)
+
+				return
+			}
+
+			require.NoError(t, err)
+			ids := make([]string, 0, len(got))
+			for _, folder := range got {
+				ids = append(ids, folder.ID)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
+
+func TestService_Progress(t *testing.T) {
+	byParent := map[string][]*folders.Folder{
+		"folders/1": {{ID: "10"}, {ID: "11"}},
+		"folders/2": {{ID: "20"}, {ID: "10"}},
+	}
+
+	tests := map[string]struct {
+		list func(*folders.Service, *folders.FetchOptions) error
+		want []int
+	}{
+		"search reports each unique folder": {
+			list: func(service *folders.Service, opts *folders.FetchOptions) error {
+				_, err := service.ListFolders(t.Context(), opts)
+
+				return err
+			},
+			want: []int{1, 2, 3},
+		},
+		"parent listings report after each parent": {
+			list: func(service *folders.Service, opts *folders.FetchOptions) error {
+				_, err := service.ListFoldersFromParents(t.Context(), []string{"folders/1", "folders/2"}, opts, false)
+
+				return err
+			},
+			want: []int{2, 3},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockFetcher := mocks.NewMockFetcher(t)
+			mockFetcher.On("WalkFolders", mock.Anything, mock.Anything, mock.Anything).
+				Return(walkFolders([]*folders.Folder{{ID: "1"}, {ID: "2"}, {ID: "1"}, {ID: "3"}}, nil)).Maybe()
+			mockFetcher.On("ListFoldersFromParent", mock.Anything, mock.Anything, mock.Anything).
+				Return(func(_ context.Context, parent string, _ *folders.FetchOptions) ([]*folders.Folder, error) {
+					return byParent[parent], nil
+				}).Maybe()
+			service := folders.NewServiceWithLogger(mockFetcher, logger.NewNoOpLogger())
+
+			var got []int
+			opts := &folders.FetchOptions{Progress: func(fetched int) { got = append(got, fetched) }}
+			require.NoError(t, tt.list(service, opts))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestService_ListFoldersFromParents(t *testing.T) {
+	byParent := map[string][]*folders.Folder{
+		"folders/1": {{ID: "10"}, {ID: "11"}},
+		"folders/3": {{ID: "30"}, {ID: "10"}},
+	}
+	denied := status.Error(codes.PermissionDenied, "denied")
+
+	tests := map[string]struct {
+		continueOnError bool
+		wantIDs         []string
+		wantFailed      []string
+	}{
+		"continue on error returns folders from healthy parents": {
+			continueOnError: true,
+			wantIDs:         []string{"10", "11", "30"},
+			wantFailed:      []string{"folders/2"},
+		},
+		"first failure aborts by default": {
+			continueOnError: false,
+			wantIDs:         nil,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockFetcher := mocks.NewMockFetcher(t)
+			service := folders.NewServiceWithLogger(mockFetcher, logger.NewNoOpLogger())
+
+			mockFetcher.On("ListFoldersFromParent", mock.Anything, mock.Anything, mock.Anything).
+				Return(func(_ context.Context, parent string, _ *folders.FetchOptions) ([]*folders.Folder, error) {
+					if parent == "folders/2" {
+						return nil, denied
+					}
+
+					return byParent[parent], nil
+				})
+
+			got, err := service.ListFoldersFromParents(
+				t.Context(), []string{"folders/1", "folders/2", "folders/3"}, nil, tt.continueOnError)
+
+			var ids []string
+			for _, folder := range got {
+				ids = append(ids, folder.ID)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+
+			require.Error(t, err)
+			assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+			var partialErr *folders.PartialError
+			if tt.wantFailed == nil {
+				assert.False(t, errors.As(err, &partialErr))
+
+				return
+			}
+			require.ErrorAs(t, err, &partialErr)
+			assert.Equal(t, 3, partialErr.Total)
+			failed := make([]string, 0, len(partialErr.Failures))
+			for _, failure := range partialErr.Failures {
+				failed = append(failed, failure.Parent)
+			}
+			assert.Equal(t, tt.wantFailed, failed)
+			assert.Equal(t, "failed to list folders from 1 of 3 parents", err.Error())
+		})
+	}
+}
+
+func TestService_ListFolderTree(t *testing.T) {
+	byParent := map[string][]*folders.Folder{
+		"organizations/9": {{ID: "1", Parent: "organizations/9"}, {ID: "2", Parent: "organizations/9"}},
+		"folders/1":       {{ID: "10", Parent: "folders/1"}},
+		"folders/10":      {{ID: "100", Parent: "folders/10"}},
+	}
+
+	tests := map[string]struct {
+		failParent string
+		wantIDs    []string
+		wantErr    bool
+	}{
+		"walks every level breadth-first": {
+			wantIDs: []string{"1", "2", "10", "100"},
+		},
+		"a failing level aborts the walk": {
+			failParent: "folders/10",
+			wantErr:    true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockFetcher := mocks.NewMockFetcher(t)
+			service := folders.NewServiceWithLogger(mockFetcher, logger.NewNoOpLogger())
+
+			mockFetcher.On("ListFoldersFromParent", mock.Anything, mock.Anything, mock.Anything).
+				Return(func(_ context.Context, parent string, _ *folders.FetchOptions) ([]*folders.Folder, error) {
+					if parent == tt.failParent {
+						return nil, errServiceTestAPIError
+					}
+
+					return byParent[parent], nil
+				})
+
+			got, err := service.ListFolderTree(t.Context(), "organizations/9", nil)
+			if tt.wantErr {
+				require.ErrorIs(t, err, errServiceTestAPIError)
+				assert.Nil(t, got)
+
+				return
+			}
+
+			require.NoError(t, err)
+			ids := make([]string, 0, len(got))
+			for _, folder := range got {
+				ids = append(ids, folder.ID)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
+
+// countingSpinner counts how often it is started and stopped.
+type countingSpinner struct {
+	starts atomic.Int32
+	stops  atomic.Int32
+}
+
+func (s *countingSpinner) Start() { s.starts.Add(1) }
+func (s *countingSpinner) Stop()  { s.stops.Add(1) }
+
+func TestService_SetSpinner(t *testing.T) {
+	tests := map[string]struct {
+		enabled    bool
+		wantStarts int32
+	}{
+		"spinner shown by default": {enabled: true, wantStarts: 1},
+		"no spinner":               {enabled: false, wantStarts: 0},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockFetcher := mocks.NewMockFetcher(t)
+			mockFetcher.On("WalkFolders", mock.Anything, mock.Anything, mock.Anything).
+				Return(walkFolders([]*folders.Folder{{ID: "1"}}, nil))
+			core, logs := observer.New(zapcore.DebugLevel)
+			service := folders.NewServiceWithLogger(mockFetcher, logger.NewZapLoggerForTesting(zap.New(core)))
+
+			spin := &countingSpinner{}
+			service.SetSpinnerFactory(func(string) progress.Spinner { return spin })
+			service.SetSpinner(tt.enabled)
+
+			_, err := service.ListFolders(t.Context(), nil)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantStarts, spin.starts.Load())
+			assert.Equal(t, tt.wantStarts, spin.stops.Load())
+			assert.Equal(t, 1, logs.FilterMessage("successfully fetched folders").Len(),
+				"debug logs should not depend on the spinner")
+		})
+	}
+}
+
+func TestService_WarnsAboutMalformedNames(t *testing.T) {
+	folderList := []*folders.Folder{
+		folders.FolderFromProto(&resourcemanagerpb.Folder{Name: "folders/1"}),
+		folders.FolderFromProto(&resourcemanagerpb.Folder{Name: "tagKeys/2"}),
+	}
+
+	mockFetcher := mocks.NewMockFetcher(t)
+	mockFetcher.On("WalkFolders", mock.Anything, mock.Anything, mock.Anything).
+		Return(walkFolders(folderList, nil))
+	core, logs := observer.New(zapcore.WarnLevel)
+	service := folders.NewServiceWithLogger(mockFetcher, logger.NewZapLoggerForTesting(zap.New(core)))
+
+	got, err := service.ListFolders(t.Context(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "tagKeys/2", got[1].ID, "the raw name is kept as the ID")
+
+	warnings := logs.FilterMessage("keeping the raw folder name as its ID").All()
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "tagKeys/2", warnings[0].ContextMap()["name"])
+}
+
+func TestService_GetFolders(t *testing.T) {
+	notFound := status.Error(codes.NotFound, "folder not found")
+
+	tests := map[string]struct {
+		names           []string
+		continueOnError bool
+		wantIDs         []string
+		wantFailures    []string
+		wantErrCode     codes.Code
+	}{
+		"all found in request order": {
+			names:   []string{"folders/3", "folders/1", "folders/2"},
+			wantIDs: []string{"3", "1", "2"},
+		},
+		"duplicates are fetched once": {
+			names:   []string{"folders/1", "folders/2", "folders/1"},
+			wantIDs: []string{"1", "2"},
+		},
+		"not found fails fast": {
+			names:       []string{"folders/1", "folders/404", "folders/2"},
+			wantErrCode: codes.NotFound,
+		},
+		"not found is collected with continue on error": {
+			names:           []string{"folders/1", "folders/404", "folders/2", "folders/405"},
+			continueOnError: true,
+			wantIDs:         []string{"1", "2"},
+			wantFailures:    []string{"folders/404", "folders/405"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockFetcher := mocks.NewMockFetcher(t)
+			mockFetcher.EXPECT().GetFolder(mock.Anything, mock.Anything).
+				RunAndReturn(func(_ context.Context, name string) (*folders.Folder, error) {
+					if strings.HasPrefix(name, "folders/40") {
+						return nil, notFound
+					}
+
+					return folders.FolderFromProto(&resourcemanagerpb.Folder{Name: name}), nil
+				}).Maybe()
+			service := folders.NewServiceWithLogger(mockFetcher, logger.NewNoOpLogger())
+
+			got, err := service.GetFolders(t.Context(), tt.names, 2, tt.continueOnError)
+			if tt.wantErrCode != codes.OK {
+				require.Error(t, err)
+				assert.Nil(t, got)
+				assert.Equal(t, tt.wantErrCode, status.Code(err))
+
+				return
+			}
+
+			ids := make([]string, 0, len(got))
+			for _, folder := range got {
+				ids = append(ids, folder.ID)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+
+			if tt.wantFailures == nil {
+				require.NoError(t, err)
+				mockFetcher.AssertNumberOfCalls(t, "GetFolder", len(tt.wantIDs))
+
+				return
+			}
+			var lookupErr *folders.LookupError
+			require.ErrorAs(t, err, &lookupErr)
+			assert.Equal(t, len(tt.names), lookupErr.Total)
+			failed := make([]string, 0, len(lookupErr.Failures))
+			for _, failure := range lookupErr.Failures {
+				failed = append(failed, failure.Name)
+				assert.Equal(t, codes.NotFound, status.Code(failure.Err))
+			}
+			assert.Equal(t, tt.wantFailures, failed)
+			assert.Equal(t, "failed to get 2 of 4 folders", err.Error())
+		})
+	}
+}
+
+func TestService_ResolveFolders(t *testing.T) {
+	mockFetcher := mocks.NewMockFetcher(t)
+	mockFetcher.EXPECT().GetFolder(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, name string) (*folders.Folder, error) {
+			switch name {
+			case "folders/404":
+				return nil, status.Error(codes.NotFound, "folder not found")
+			case "folders/403":
+				return nil, status.Error(codes.PermissionDenied, "caller lacks permission")
+			}
+
+			return folders.FolderFromProto(&resourcemanagerpb.Folder{Name: name, DisplayName: "Folder " + name}), nil
+		})
+	service := folders.NewServiceWithLogger(mockFetcher, logger.NewNoOpLogger())
+	service.SetSpinner(false)
+
+	got := service.ResolveFolders(t.Context(), []string{"folders/1", "folders/404", "folders/403", "folders/1"}, 2)
+
+	assert.Equal(t, []*folders.Resolution{
+		{ID: "1", Name: "folders/1", DisplayName: "Folder folders/1"},
+		{ID: "404", Name: "folders/404", Error: "NotFound: folder not found"},
+		{ID: "403", Name: "folders/403", Error: "PermissionDenied: caller lacks permission"},
+		{ID: "1", Name: "folders/1", DisplayName: "Folder folders/1"},
+	}, got)
+	// the repeated ID is served from the first lookup
+	mockFetcher.AssertNumberOfCalls(t, "GetFolder", 3)
+}
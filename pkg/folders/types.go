@@ -1,27 +1,43 @@
 package folders
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
 	resourcemanagerpb "cloud.google.com/go/resourcemanager/apiv3/resourcemanagerpb"
+	"github.com/andreygrechin/gcphelper/internal/resourcename"
 	"github.com/jedib0t/go-pretty/v6/table"
+	"google.golang.org/protobuf/proto"
 )
 
 // Folder represents a Google Cloud folder resource.
 type Folder struct {
-	ID          string    `json:"id"`           // ID is the folder's unique identifier ("123456789")
-	Name        string    `json:"name"`         // Name is the folder's resource name ("folders/123456789")
-	DisplayName string    `json:"display_name"` // DisplayName is the folder's human-readable name
-	Parent      string    `json:"parent"`       // Parent is the parent resource (organization or folder)
-	State       string    `json:"state"`        // State indicates the folder's lifecycle state
-	CreateTime  time.Time `json:"create_time"`  // CreateTime is when the folder was created
-	UpdateTime  time.Time `json:"update_time"`  // UpdateTime is when the folder was last updated
+	ID          string    `json:"id"`             // ID is the folder's unique identifier ("123456789")
+	Name        string    `json:"name"`           // Name is the folder's resource name ("folders/123456789")
+	DisplayName string    `json:"display_name"`   // DisplayName is the folder's human-readable name
+	Parent      string    `json:"parent"`         // Parent is the parent resource (organization or folder)
+	State       string    `json:"state"`          // State indicates the folder's lifecycle state
+	CreateTime  time.Time `json:"create_time"`    // CreateTime is when the folder was created
+	UpdateTime  time.Time `json:"update_time"`    // UpdateTime is when the folder was last updated
+	Etag        string    `json:"etag,omitempty"` // Etag changes whenever the folder is modified
+
+	// Raw is the API response the folder was converted from, kept only when FetchOptions.Raw is set.
+	Raw *resourcemanagerpb.Folder `json:"-"`
+
+	idErr error // idErr is why FolderFromProto could not extract the ID from the folder's name
 }
 
 // FetchOptions configures how folders are fetched.
 type FetchOptions struct {
-	Parent string // Parent specifies the parent resource to filter folders by (e.g., "folders/123", "organizations/456").
+	// Parent specifies the parent resource to filter folders by (e.g., "folders/123", "organizations/456").
+	Parent        string
+	RequestReason string // RequestReason is sent as the x-goog-request-reason header when set.
+	Query         string // Query holds raw SearchFolders query clauses AND-combined with the generated query.
+	Raw           bool   // Raw keeps each folder's API response in Folder.Raw.
+
+	// Progress, when set, is called with the number of folders fetched so far as results arrive.
+	Progress func(fetched int)
 }
 
 // NewFetchOptions creates a new FetchOptions with default values.
@@ -29,16 +45,55 @@ func NewFetchOptions() *FetchOptions {
 	return &FetchOptions{}
 }
 
-const folderPrefix = "folders/"
+// reportProgress passes the number of folders fetched so far to the Progress callback, if one is set.
+func (o *FetchOptions) reportProgress(fetched int) {
+	if o.Progress != nil {
+		o.Progress(fetched)
+	}
+}
+
+const (
+	folderPrefix       = "folders/"
+	organizationPrefix = "organizations/"
+)
+
+// Parent types returned by ParentType.
+const (
+	ParentTypeOrganization = "organization"
+	ParentTypeFolder       = "folder"
+	ParentTypeUnknown      = "unknown"
+)
 
-// FolderFromProto converts a protobuf Folder to our Folder type.
+// ParentType reports whether a parent resource name refers to an organization ("organizations/123")
+// or a folder ("folders/456"), returning ParentTypeUnknown for any other name.
+func ParentType(parent string) string {
+	switch {
+	case strings.HasPrefix(parent, organizationPrefix):
+		return ParentTypeOrganization
+	case strings.HasPrefix(parent, folderPrefix):
+		return ParentTypeFolder
+	default:
+		return ParentTypeUnknown
+	}
+}
+
+// FolderFromProto converts a protobuf Folder to our Folder type. A name that is not "folders/" followed
+// by a numeric ID is kept as the ID too, rather than guessing at it; IDError reports such names.
 func FolderFromProto(pb *resourcemanagerpb.Folder) *Folder {
+	id, err := resourcename.ExtractID(pb.GetName(), folderPrefix)
+	if err != nil {
+		id = pb.GetName()
+		err = fmt.Errorf("unexpected folder name: %w", err)
+	}
+
 	folder := &Folder{
-		ID:          strings.TrimPrefix(pb.GetName(), folderPrefix),
+		ID:          id,
 		Name:        pb.GetName(),
 		DisplayName: pb.GetDisplayName(),
 		Parent:      pb.GetParent(),
 		State:       pb.GetState().String(),
+		Etag:        pb.GetEtag(),
+		idErr:       err,
 	}
 
 	if pb.GetCreateTime() != nil {
@@ -57,11 +112,27 @@ func (f *Folder) GetID() string {
 	return f.ID
 }
 
+// GetName returns the folder's resource name ("folders/123456789").
+func (f *Folder) GetName() string {
+	return f.Name
+}
+
+// IDError reports why FolderFromProto kept the raw resource name as the folder's ID, because it was not
+// "folders/" followed by a numeric ID, or returns nil.
+func (f *Folder) IDError() error {
+	return f.idErr
+}
+
 // GetDisplayName returns the folder's display name.
 func (f *Folder) GetDisplayName() string {
 	return f.DisplayName
 }
 
+// GetParent returns the folder's parent resource name.
+func (f *Folder) GetParent() string {
+	return f.Parent
+}
+
 // GetState returns the folder's state.
 func (f *Folder) GetState() string {
 	return f.State
@@ -72,19 +143,34 @@ func (f *Folder) GetCreateTime() time.Time {
 	return f.CreateTime
 }
 
+// GetEtag returns the folder's etag.
+func (f *Folder) GetEtag() string {
+	return f.Etag
+}
+
+// Proto returns the API response the folder was converted from, or nil when it was not kept.
+func (f *Folder) Proto() proto.Message {
+	if f.Raw == nil {
+		return nil
+	}
+
+	return f.Raw
+}
+
 // GetUpdateTime returns the folder's last update time.
 func (f *Folder) GetUpdateTime() time.Time {
 	return f.UpdateTime
 }
 
-// TableRow returns the folder data as a table row.
+// TableRow returns the folder data as a table row. Timestamps are left as time.Time values
+// for the output formatter to render.
 func (f *Folder) TableRow() []interface{} {
 	return table.Row{
 		f.ID,
 		f.DisplayName,
 		f.Parent,
 		f.State,
-		f.CreateTime.Format("2006-01-02 15:04:05"),
-		f.UpdateTime.Format("2006-01-02 15:04:05"),
+		f.CreateTime,
+		f.UpdateTime,
 	}
 }
@@ -5,8 +5,10 @@ import (
 	"time"
 
 	resourcemanagerpb "cloud.google.com/go/resourcemanager/apiv3/resourcemanagerpb"
+	"github.com/andreygrechin/gcphelper/internal/resourcename"
 	"github.com/andreygrechin/gcphelper/pkg/folders"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -43,6 +45,7 @@ func TestFolderFromProto(t *testing.T) {
 				State:       resourcemanagerpb.Folder_ACTIVE,
 				CreateTime:  timestamppb.New(createTime),
 				UpdateTime:  timestamppb.New(updateTime),
+				Etag:        "W/\"etag-1\"",
 			},
 			want: &folders.Folder{
 				ID:          "123456789",
@@ -52,6 +55,7 @@ func TestFolderFromProto(t *testing.T) {
 				State:       "ACTIVE",
 				CreateTime:  createTime,
 				UpdateTime:  updateTime,
+				Etag:        "W/\"etag-1\"",
 			},
 		},
 		"converts folder with minimal fields": {
@@ -80,3 +84,48 @@ func TestFolderFromProto(t *testing.T) {
 		})
 	}
 }
+
+func TestFolderFromProtoIDExtraction(t *testing.T) {
+	tests := map[string]struct {
+		name    string
+		wantID  string
+		wantErr bool
+	}{
+		"well-formed name": {name: "folders/123456789", wantID: "123456789"},
+		"missing prefix":   {name: "123456789", wantID: "123456789", wantErr: true},
+		"empty id":         {name: "folders/", wantID: "folders/", wantErr: true},
+		"non-numeric id":   {name: "folders/prod", wantID: "folders/prod", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := folders.FolderFromProto(&resourcemanagerpb.Folder{Name: tt.name})
+			assert.Equal(t, tt.wantID, got.ID)
+			assert.Equal(t, tt.name, got.Name)
+			if tt.wantErr {
+				require.ErrorIs(t, got.IDError(), resourcename.ErrMalformedName)
+
+				return
+			}
+			require.NoError(t, got.IDError())
+		})
+	}
+}
+
+func TestParentType(t *testing.T) {
+	tests := map[string]struct {
+		parent string
+		want   string
+	}{
+		"organization parent": {parent: "organizations/987654321", want: folders.ParentTypeOrganization},
+		"folder parent":       {parent: "folders/123456789", want: folders.ParentTypeFolder},
+		"unknown prefix":      {parent: "projects/my-project", want: folders.ParentTypeUnknown},
+		"no parent":           {parent: "", want: folders.ParentTypeUnknown},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, folders.ParentType(tt.parent))
+		})
+	}
+}
@@ -0,0 +1,197 @@
+// Package iam checks which IAM permissions the caller holds on Resource Manager resources.
+package iam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/organizations"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"google.golang.org/api/option"
+)
+
+// ErrUnsupportedResource is returned when a resource name is not a folder or organization name.
+var ErrUnsupportedResource = errors.New("unsupported resource")
+
+// ErrNoPermissions is returned when no permissions are given to test.
+var ErrNoPermissions = errors.New("no permissions to test")
+
+// Permission states reported by PermissionResult.GetState.
+const (
+	StateGranted    = "GRANTED"
+	StateNotGranted = "NOT_GRANTED"
+)
+
+// Resource name prefixes of the supported resource kinds.
+const (
+	folderPrefix       = "folders/"
+	organizationPrefix = "organizations/"
+)
+
+// ResourceKind identifies the Resource Manager client that owns a resource.
+type ResourceKind string
+
+// Supported resource kinds.
+const (
+	KindFolder       ResourceKind = "folder"
+	KindOrganization ResourceKind = "organization"
+)
+
+// PermissionTester reports which of the given permissions the caller holds on a resource.
+type PermissionTester interface {
+	// TestIamPermissions returns the subset of permissions granted on the resource.
+	TestIamPermissions(ctx context.Context, resource string, permissions []string) ([]string, error)
+
+	// Close releases any resources held by the tester.
+	Close() error
+}
+
+// PermissionResult is the outcome of testing one permission on a resource.
+type PermissionResult struct {
+	Resource   string `json:"resource"`   // Resource is the tested resource name, e.g. "folders/123"
+	Permission string `json:"permission"` // Permission is the tested permission
+	Granted    bool   `json:"granted"`    // Granted reports whether the caller holds the permission
+}
+
+// GetID returns the permission name.
+func (r *PermissionResult) GetID() string {
+	return r.Permission
+}
+
+// GetName returns the permission name.
+func (r *PermissionResult) GetName() string {
+	return r.Permission
+}
+
+// GetDisplayName returns the permission name.
+func (r *PermissionResult) GetDisplayName() string {
+	return r.Permission
+}
+
+// GetState returns GRANTED or NOT_GRANTED, so results can be filtered and grouped by state.
+func (r *PermissionResult) GetState() string {
+	if r.Granted {
+		return StateGranted
+	}
+
+	return StateNotGranted
+}
+
+// GetCreateTime returns the zero time since permission results have no timestamps.
+func (r *PermissionResult) GetCreateTime() time.Time {
+	return time.Time{}
+}
+
+// GetUpdateTime returns the zero time since permission results have no timestamps.
+func (r *PermissionResult) GetUpdateTime() time.Time {
+	return time.Time{}
+}
+
+// TableRow returns the result row for table output.
+func (r *PermissionResult) TableRow() []interface{} {
+	return table.Row{r.Resource, r.Permission, r.Granted}
+}
+
+// PermissionHeaders returns the table headers for permission results.
+func PermissionHeaders() []string {
+	return []string{"Resource", "Permission", "Granted"}
+}
+
+// ParseResource returns the kind of a resource name such as "folders/123" or "organizations/456".
+func ParseResource(resource string) (ResourceKind, error) {
+	var kind ResourceKind
+	var id string
+	switch {
+	case strings.HasPrefix(resource, folderPrefix):
+		kind, id = KindFolder, strings.TrimPrefix(resource, folderPrefix)
+	case strings.HasPrefix(resource, organizationPrefix):
+		kind, id = KindOrganization, strings.TrimPrefix(resource, organizationPrefix)
+	default:
+		return "", fmt.Errorf("%w: %q (expected folders/ID or organizations/ID)", ErrUnsupportedResource, resource)
+	}
+
+	if id == "" || strings.Trim(id, "0123456789") != "" {
+		return "", fmt.Errorf("%w: %q has an invalid numeric ID", ErrUnsupportedResource, resource)
+	}
+
+	return kind, nil
+}
+
+// ParsePermissions trims the permission names, dropping blanks and duplicates while keeping their order.
+func ParsePermissions(permissions []string) ([]string, error) {
+	seen := make(map[string]bool, len(permissions))
+	parsed := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		permission = strings.TrimSpace(permission)
+		if permission == "" || seen[permission] {
+			continue
+		}
+		seen[permission] = true
+		parsed = append(parsed, permission)
+	}
+
+	if len(parsed) == 0 {
+		return nil, ErrNoPermissions
+	}
+
+	return parsed, nil
+}
+
+// NewPermissionTester creates a tester backed by the folders or organizations client, depending on
+// the resource name. Client options such as option.WithEndpoint are passed to the underlying client.
+func NewPermissionTester(
+	ctx context.Context,
+	resource string,
+	clientOpts ...option.ClientOption,
+) (PermissionTester, error) {
+	kind, err := ParseResource(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	if kind == KindFolder {
+		client, err := folders.NewClientFromContext(ctx, clientOpts...)
+		if err != nil {
+			return nil, err
+		}
+
+		return client, nil
+	}
+
+	client, err := organizations.NewClientFromContext(ctx, clientOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// TestPermissions tests the permissions on the resource and returns one result per permission, in
+// the order they were given.
+func TestPermissions(
+	ctx context.Context,
+	tester PermissionTester,
+	resource string,
+	permissions []string,
+) ([]*PermissionResult, error) {
+	granted, err := tester.TestIamPermissions(ctx, resource, permissions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to test permissions on %s: %w", resource, err)
+	}
+
+	grantedSet := make(map[string]bool, len(granted))
+	for _, permission := range granted {
+		grantedSet[permission] = true
+	}
+
+	results := make([]*PermissionResult, len(permissions))
+	for i, permission := range permissions {
+		results[i] = &PermissionResult{Resource: resource, Permission: permission, Granted: grantedSet[permission]}
+	}
+
+	return results, nil
+}
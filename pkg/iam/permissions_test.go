@@ -0,0 +1,129 @@
+package iam_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/pkg/iam"
+	"github.com/andreygrechin/gcphelper/pkg/iam/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Test error variables for err113 compliance.
+var errIAMTestAPIError = errors.New("API error")
+
+func TestParseResource(t *testing.T) {
+	tests := map[string]struct {
+		resource string
+		want     iam.ResourceKind
+		wantErr  bool
+	}{
+		"folder":              {resource: "folders/123", want: iam.KindFolder},
+		"organization":        {resource: "organizations/456", want: iam.KindOrganization},
+		"project":             {resource: "projects/my-project", wantErr: true},
+		"bare ID":             {resource: "123", wantErr: true},
+		"empty":               {resource: "", wantErr: true},
+		"missing folder ID":   {resource: "folders/", wantErr: true},
+		"non-numeric org ID":  {resource: "organizations/acme", wantErr: true},
+		"nested folder names": {resource: "folders/123/folders/456", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := iam.ParseResource(tt.resource)
+			if tt.wantErr {
+				require.ErrorIs(t, err, iam.ErrUnsupportedResource)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParsePermissions(t *testing.T) {
+	tests := map[string]struct {
+		permissions []string
+		want        []string
+		wantErr     error
+	}{
+		"trims and keeps order": {
+			permissions: []string{" resourcemanager.folders.list", "resourcemanager.folders.get "},
+			want:        []string{"resourcemanager.folders.list", "resourcemanager.folders.get"},
+		},
+		"drops blanks and duplicates": {
+			permissions: []string{"a.b.c", "", "a.b.c", "d.e.f"},
+			want:        []string{"a.b.c", "d.e.f"},
+		},
+		"none":       {permissions: nil, wantErr: iam.ErrNoPermissions},
+		"only blank": {permissions: []string{" ", ""}, wantErr: iam.ErrNoPermissions},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := iam.ParsePermissions(tt.permissions)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTestPermissions(t *testing.T) {
+	permissions := []string{"resourcemanager.folders.list", "resourcemanager.folders.get"}
+
+	tests := map[string]struct {
+		granted []string
+		apiErr  error
+		want    []*iam.PermissionResult
+	}{
+		"partially granted": {
+			granted: []string{"resourcemanager.folders.get"},
+			want: []*iam.PermissionResult{
+				{Resource: "folders/123", Permission: "resourcemanager.folders.list", Granted: false},
+				{Resource: "folders/123", Permission: "resourcemanager.folders.get", Granted: true},
+			},
+		},
+		"none granted": {
+			granted: nil,
+			want: []*iam.PermissionResult{
+				{Resource: "folders/123", Permission: "resourcemanager.folders.list", Granted: false},
+				{Resource: "folders/123", Permission: "resourcemanager.folders.get", Granted: false},
+			},
+		},
+		"API error": {
+			apiErr: errIAMTestAPIError,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tester := mocks.NewMockPermissionTester(t)
+			tester.EXPECT().
+				TestIamPermissions(mock.Anything, "folders/123", permissions).
+				Return(tt.granted, tt.apiErr)
+
+			got, err := iam.TestPermissions(context.Background(), tester, "folders/123", permissions)
+			if tt.apiErr != nil {
+				require.ErrorIs(t, err, tt.apiErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPermissionResultState(t *testing.T) {
+	assert.Equal(t, iam.StateGranted, (&iam.PermissionResult{Granted: true}).GetState())
+	assert.Equal(t, iam.StateNotGranted, (&iam.PermissionResult{}).GetState())
+}
@@ -0,0 +1,226 @@
+// Package identity reports which principal Application Default Credentials authenticate as.
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/auth"
+	"cloud.google.com/go/auth/credentials"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// ErrNoCredentials is returned when Application Default Credentials cannot be found.
+var ErrNoCredentials = errors.New("no Application Default Credentials found; run " +
+	"'gcloud auth application-default login' or set GOOGLE_APPLICATION_CREDENTIALS to a service account key file")
+
+// ErrTokenIntrospection is returned when the token info endpoint rejects an access token.
+var ErrTokenIntrospection = errors.New("token introspection failed")
+
+// TokenInfoURL is Google's OAuth 2.0 token introspection endpoint.
+const TokenInfoURL = "https://oauth2.googleapis.com/tokeninfo"
+
+// Scopes requested when resolving Application Default Credentials. The email scope lets token
+// introspection report the account of credentials that are not tied to a key file.
+var defaultScopes = []string{
+	"https://www.googleapis.com/auth/cloud-platform",
+	"https://www.googleapis.com/auth/userinfo.email",
+}
+
+// Credential types reported in Identity.CredentialType besides the "type" of a credentials file.
+const (
+	TypeMetadata = "metadata_server" // TypeMetadata marks credentials from the compute metadata server
+	TypeUnknown  = "unknown"         // TypeUnknown marks a credentials file without a type
+)
+
+// Sources reported in Identity.AccountSource.
+const (
+	SourceCredentialsFile    = "credentials file"
+	SourceImpersonation      = "impersonation URL"
+	SourceTokenIntrospection = "token introspection"
+)
+
+// tokenInfoTimeout bounds the token introspection request.
+const tokenInfoTimeout = 30 * time.Second
+
+// Identity describes the principal of the active Application Default Credentials.
+type Identity struct {
+	Account        string `json:"account"`         // Account is the principal's email, empty when unknown
+	CredentialType string `json:"credential_type"` // CredentialType is e.g. "service_account" or "authorized_user"
+	QuotaProject   string `json:"quota_project"`   // QuotaProject is the project billed for API quota, if set
+	AccountSource  string `json:"account_source"`  // AccountSource tells how the account was determined
+}
+
+// GetID returns the account email.
+func (i *Identity) GetID() string {
+	return i.Account
+}
+
+// GetName returns the account email.
+func (i *Identity) GetName() string {
+	return i.Account
+}
+
+// GetDisplayName returns the account email.
+func (i *Identity) GetDisplayName() string {
+	return i.Account
+}
+
+// GetState returns an empty string since identities have no lifecycle state.
+func (i *Identity) GetState() string {
+	return ""
+}
+
+// GetCreateTime returns the zero time since identities have no timestamps.
+func (i *Identity) GetCreateTime() time.Time {
+	return time.Time{}
+}
+
+// GetUpdateTime returns the zero time since identities have no timestamps.
+func (i *Identity) GetUpdateTime() time.Time {
+	return time.Time{}
+}
+
+// TableRow returns the identity as a table row.
+func (i *Identity) TableRow() []interface{} {
+	return table.Row{i.Account, i.CredentialType, i.QuotaProject, i.AccountSource}
+}
+
+// Headers returns the table headers for identity output.
+func Headers() []string {
+	return []string{"Account", "Credential Type", "Quota Project", "Account Source"}
+}
+
+// CredentialsResolver returns the Application Default Credentials.
+type CredentialsResolver func(ctx context.Context) (*auth.Credentials, error)
+
+// TokenIntrospector returns the email of the account an access token was issued to, or an empty
+// string when the token does not reveal it.
+type TokenIntrospector func(ctx context.Context, accessToken string) (string, error)
+
+// DetectDefault resolves Application Default Credentials the same way the API clients do.
+func DetectDefault(_ context.Context) (*auth.Credentials, error) {
+	creds, err := credentials.DetectDefault(&credentials.DetectOptions{Scopes: defaultScopes})
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect default credentials: %w", err)
+	}
+
+	return creds, nil
+}
+
+// NewTokenIntrospector returns a TokenIntrospector that asks the token info endpoint at endpoint,
+// normally TokenInfoURL, using client.
+func NewTokenIntrospector(client *http.Client, endpoint string) TokenIntrospector {
+	return func(ctx context.Context, accessToken string) (string, error) {
+		ctx, cancel := context.WithTimeout(ctx, tokenInfoTimeout)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint,
+			strings.NewReader(url.Values{"access_token": {accessToken}}.Encode()))
+		if err != nil {
+			return "", fmt.Errorf("failed to build token info request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("failed to call token info endpoint: %w", err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", fmt.Errorf("failed to read token info response: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("%w: %s: %s", ErrTokenIntrospection, resp.Status, strings.TrimSpace(string(body)))
+		}
+
+		var info struct {
+			Email string `json:"email"`
+		}
+		if err := json.Unmarshal(body, &info); err != nil {
+			return "", fmt.Errorf("failed to decode token info response: %w", err)
+		}
+
+		return info.Email, nil
+	}
+}
+
+// credentialsFile holds the fields of a credentials file that identify its principal.
+type credentialsFile struct {
+	Type                           string `json:"type"`
+	ClientEmail                    string `json:"client_email"`
+	ServiceAccountImpersonationURL string `json:"service_account_impersonation_url"`
+}
+
+// Whoami resolves the Application Default Credentials with resolve and reports their principal. The
+// account of a service account key, or of a service account impersonated by the credentials, is read
+// from the credentials file; for other credentials, such as user credentials or the metadata server,
+// an access token is requested and introspected.
+func Whoami(ctx context.Context, resolve CredentialsResolver, introspect TokenIntrospector) (*Identity, error) {
+	creds, err := resolve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNoCredentials, err)
+	}
+
+	identity := &Identity{CredentialType: TypeMetadata}
+	if data := creds.JSON(); len(data) > 0 {
+		var file credentialsFile
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("failed to decode credentials file: %w", err)
+		}
+		identity.CredentialType = file.Type
+		if identity.CredentialType == "" {
+			identity.CredentialType = TypeUnknown
+		}
+		switch {
+		case file.ClientEmail != "":
+			identity.Account = file.ClientEmail
+			identity.AccountSource = SourceCredentialsFile
+		case file.ServiceAccountImpersonationURL != "":
+			identity.Account = impersonatedAccount(file.ServiceAccountImpersonationURL)
+			identity.AccountSource = SourceImpersonation
+		}
+	}
+
+	identity.QuotaProject, err = creds.QuotaProjectID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve quota project: %w", err)
+	}
+
+	if identity.Account != "" {
+		return identity, nil
+	}
+
+	token, err := creds.Token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain a token: %w", err)
+	}
+	identity.Account, err = introspect(ctx, token.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to introspect token: %w", err)
+	}
+	identity.AccountSource = SourceTokenIntrospection
+
+	return identity, nil
+}
+
+// impersonatedAccount extracts the service account email from an impersonation URL such as
+// ".../serviceAccounts/sa@project.iam.gserviceaccount.com:generateAccessToken".
+func impersonatedAccount(impersonationURL string) string {
+	_, account, ok := strings.Cut(impersonationURL, "/serviceAccounts/")
+	if !ok {
+		return ""
+	}
+	account, _, _ = strings.Cut(account, ":")
+
+	return account
+}
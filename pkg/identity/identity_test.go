@@ -0,0 +1,169 @@
+package identity_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cloud.google.com/go/auth"
+	"github.com/andreygrechin/gcphelper/pkg/identity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test error variables for err113 compliance.
+var (
+	errTestNoCredentials = errors.New("could not find default credentials")
+	errTestTokenInfo     = errors.New("token info unavailable")
+)
+
+// staticToken is a token provider returning a fixed access token.
+type staticToken string
+
+func (s staticToken) Token(context.Context) (*auth.Token, error) {
+	return &auth.Token{Value: string(s)}, nil
+}
+
+// syntheticCredentials returns a resolver for credentials with the given file contents and quota project.
+func syntheticCredentials(json, quotaProject string) identity.CredentialsResolver {
+	return func(context.Context) (*auth.Credentials, error) {
+		return auth.NewCredentials(&auth.CredentialsOptions{
+			TokenProvider: staticToken("access-token"),
+			JSON:          []byte(json),
+			QuotaProjectIDProvider: auth.CredentialsPropertyFunc(func(context.Context) (string, error) {
+				return quotaProject, nil
+			}),
+		}), nil
+	}
+}
+
+func TestWhoami(t *testing.T) {
+	tests := map[string]struct {
+		resolve       identity.CredentialsResolver
+		introspectErr error
+		want          *identity.Identity
+		wantTokens    []string
+		wantErr       error
+	}{
+		"service account key": {
+			resolve: syntheticCredentials(
+				`{"type": "service_account", "client_email": "sa@demo.iam.gserviceaccount.com"}`, "billing-proj"),
+			want: &identity.Identity{
+				Account:        "sa@demo.iam.gserviceaccount.com",
+				CredentialType: "service_account",
+				QuotaProject:   "billing-proj",
+				AccountSource:  identity.SourceCredentialsFile,
+			},
+		},
+		"impersonated service account": {
+			resolve: syntheticCredentials(`{"type": "impersonated_service_account", `+
+				`"service_account_impersonation_url": "https://iamcredentials.googleapis.com/v1/projects/-/`+
+				`serviceAccounts/deployer@demo.iam.gserviceaccount.com:generateAccessToken"}`, ""),
+			want: &identity.Identity{
+				Account:        "deployer@demo.iam.gserviceaccount.com",
+				CredentialType: "impersonated_service_account",
+				AccountSource:  identity.SourceImpersonation,
+			},
+		},
+		"user credentials are introspected": {
+			resolve: syntheticCredentials(`{"type": "authorized_user", "client_id": "123"}`, "user-proj"),
+			want: &identity.Identity{
+				Account:        "user@example.com",
+				CredentialType: "authorized_user",
+				QuotaProject:   "user-proj",
+				AccountSource:  identity.SourceTokenIntrospection,
+			},
+			wantTokens: []string{"access-token"},
+		},
+		"metadata server credentials are introspected": {
+			resolve: syntheticCredentials("", ""),
+			want: &identity.Identity{
+				Account:        "user@example.com",
+				CredentialType: identity.TypeMetadata,
+				AccountSource:  identity.SourceTokenIntrospection,
+			},
+			wantTokens: []string{"access-token"},
+		},
+		"no credentials": {
+			resolve: func(context.Context) (*auth.Credentials, error) {
+				return nil, errTestNoCredentials
+			},
+			wantErr: identity.ErrNoCredentials,
+		},
+		"introspection fails": {
+			resolve:       syntheticCredentials(`{"type": "authorized_user"}`, ""),
+			introspectErr: errTestTokenInfo,
+			wantTokens:    []string{"access-token"},
+			wantErr:       errTestTokenInfo,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var tokens []string
+			introspect := func(_ context.Context, accessToken string) (string, error) {
+				tokens = append(tokens, accessToken)
+
+				return "user@example.com", tt.introspectErr
+			}
+
+			got, err := identity.Whoami(t.Context(), tt.resolve, introspect)
+			assert.Equal(t, tt.wantTokens, tokens)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewTokenIntrospector(t *testing.T) {
+	tests := map[string]struct {
+		status  int
+		body    string
+		want    string
+		wantErr error
+	}{
+		"email in token info": {
+			status: http.StatusOK,
+			body:   `{"email": "user@example.com", "email_verified": "true"}`,
+			want:   "user@example.com",
+		},
+		"token without email scope": {
+			status: http.StatusOK,
+			body:   `{"scope": "https://www.googleapis.com/auth/cloud-platform"}`,
+			want:   "",
+		},
+		"invalid token": {
+			status:  http.StatusBadRequest,
+			body:    `{"error_description": "Invalid Value"}`,
+			wantErr: identity.ErrTokenIntrospection,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "access-token", r.PostFormValue("access_token"))
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			t.Cleanup(server.Close)
+
+			got, err := identity.NewTokenIntrospector(server.Client(), server.URL)(t.Context(), "access-token")
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
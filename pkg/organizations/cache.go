@@ -0,0 +1,94 @@
+package organizations
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Getter retrieves a single organization by its resource name.
+type Getter interface {
+	GetOrganization(ctx context.Context, name string) (*Organization, error)
+}
+
+// NameCache memoizes organization lookups for the lifetime of a command. It is safe for concurrent
+// use, and concurrent lookups of the same organization share a single underlying call.
+type NameCache struct {
+	getter Getter
+	group  singleflight.Group
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// cacheEntry is the memoized result of one organization lookup.
+type cacheEntry struct {
+	org *Organization
+	err error
+}
+
+// NewNameCache creates an empty cache backed by getter.
+func NewNameCache(getter Getter) *NameCache {
+	return &NameCache{
+		getter:  getter,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+// Get returns the display name of the organization with the given ID ("123") or resource name
+// ("organizations/123"), fetching it on first use.
+func (c *NameCache) Get(ctx context.Context, id string) (string, error) {
+	org, err := c.GetOrganization(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	return org.DisplayName, nil
+}
+
+// GetOrganization returns the organization with the given ID or resource name, fetching it on first
+// use. Failed lookups are memoized too, except for context cancellation and deadline errors.
+func (c *NameCache) GetOrganization(ctx context.Context, id string) (*Organization, error) {
+	name := id
+	if !strings.HasPrefix(name, orgPrefix) {
+		name = orgPrefix + name
+	}
+
+	if entry, ok := c.lookup(name); ok {
+		return entry.org, entry.err
+	}
+
+	result, _, _ := c.group.Do(name, func() (any, error) {
+		// a concurrent call may have filled the entry between the lookup and Do
+		if entry, ok := c.lookup(name); ok {
+			return entry, nil
+		}
+
+		org, err := c.getter.GetOrganization(ctx, name)
+		entry := cacheEntry{org: org, err: err}
+		if !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+			c.mu.Lock()
+			c.entries[name] = entry
+			c.mu.Unlock()
+		}
+
+		return entry, nil
+	})
+
+	entry, _ := result.(cacheEntry)
+
+	return entry.org, entry.err
+}
+
+// lookup returns the memoized entry for name, if any.
+func (c *NameCache) lookup(name string) (cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[name]
+
+	return entry, ok
+}
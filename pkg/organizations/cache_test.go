@@ -0,0 +1,106 @@
+package organizations_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andreygrechin/gcphelper/pkg/organizations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// countingGetter returns a fixed organization, counting calls and holding each one until released.
+type countingGetter struct {
+	calls   atomic.Int32
+	release chan struct{}
+	err     error
+}
+
+func (g *countingGetter) GetOrganization(ctx context.Context, name string) (*organizations.Organization, error) {
+	g.calls.Add(1)
+	select {
+	case <-g.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if g.err != nil {
+		return nil, g.err
+	}
+
+	return &organizations.Organization{Name: name, DisplayName: "Acme " + name}, nil
+}
+
+func TestNameCacheConcurrentGet(t *testing.T) {
+	getter := &countingGetter{release: make(chan struct{})}
+	cache := organizations.NewNameCache(getter)
+
+	const callers = 20
+	names := make([]string, callers)
+	errs := make([]error, callers)
+
+	var started, done sync.WaitGroup
+	started.Add(callers)
+	done.Add(callers)
+	for i := range callers {
+		go func() {
+			defer done.Done()
+			started.Done()
+			names[i], errs[i] = cache.Get(t.Context(), "123")
+		}()
+	}
+
+	// give the callers time to reach the cache before the first fetch completes
+	started.Wait()
+	time.Sleep(10 * time.Millisecond)
+	close(getter.release)
+	done.Wait()
+
+	assert.Equal(t, int32(1), getter.calls.Load(), "concurrent gets should share one fetch")
+	for i := range callers {
+		require.NoError(t, errs[i])
+		assert.Equal(t, "Acme organizations/123", names[i])
+	}
+
+	// later lookups by ID or resource name are served from the cache
+	name, err := cache.Get(t.Context(), "organizations/123")
+	require.NoError(t, err)
+	assert.Equal(t, "Acme organizations/123", name)
+	assert.Equal(t, int32(1), getter.calls.Load())
+}
+
+func TestNameCacheErrors(t *testing.T) {
+	testCases := map[string]struct {
+		err       error
+		wantCalls int32
+	}{
+		"permission denied is memoized": {
+			err:       status.Error(codes.PermissionDenied, "denied"),
+			wantCalls: 1,
+		},
+		"cancellation is not memoized": {
+			err:       context.Canceled,
+			wantCalls: 2,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			getter := &countingGetter{release: make(chan struct{}), err: tc.err}
+			close(getter.release)
+			cache := organizations.NewNameCache(getter)
+
+			for range 2 {
+				_, err := cache.Get(t.Context(), "7")
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tc.err) || status.Code(err) == status.Code(tc.err))
+			}
+			assert.Equal(t, tc.wantCalls, getter.calls.Load())
+		})
+	}
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 
+	iampb "cloud.google.com/go/iam/apiv1/iampb"
 	resourcemanager "cloud.google.com/go/resourcemanager/apiv3"
 	resourcemanagerpb "cloud.google.com/go/resourcemanager/apiv3/resourcemanagerpb"
 	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
 )
 
 // Fetcher defines the interface for fetching organizations from Google Cloud.
@@ -15,6 +18,13 @@ type Fetcher interface {
 	// SearchOrganizations searches for organizations accessible to the caller.
 	SearchOrganizations(ctx context.Context) ([]*Organization, error)
 
+	// SearchOrganizationsByDomain searches for the organizations accessible to the caller whose primary
+	// domain is domain.
+	SearchOrganizationsByDomain(ctx context.Context, domain string) ([]*Organization, error)
+
+	// GetOrganization retrieves a single organization by its resource name (e.g., "organizations/123").
+	GetOrganization(ctx context.Context, name string) (*Organization, error)
+
 	// Close releases any resources held by the fetcher.
 	Close() error
 }
@@ -25,8 +35,9 @@ type Client struct {
 }
 
 // NewClientFromContext creates a new organizations client using application default credentials.
-func NewClientFromContext(ctx context.Context) (*Client, error) {
-	c, err := resourcemanager.NewOrganizationsClient(ctx)
+// Client options such as option.WithEndpoint are passed to the underlying API client.
+func NewClientFromContext(ctx context.Context, clientOpts ...option.ClientOption) (*Client, error) {
+	c, err := resourcemanager.NewOrganizationsClient(ctx, clientOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create organizations client: %w", err)
 	}
@@ -38,12 +49,37 @@ func NewClientFromContext(ctx context.Context) (*Client, error) {
 
 // SearchOrganizations searches for organizations accessible to the caller.
 func (c *Client) SearchOrganizations(ctx context.Context) ([]*Organization, error) {
+	return c.SearchOrganizationsLimit(ctx, 0)
+}
+
+// SearchOrganizationsLimit searches for organizations accessible to the caller, returning at most
+// limit organizations. A limit of zero or less returns all of them.
+func (c *Client) SearchOrganizationsLimit(ctx context.Context, limit int) ([]*Organization, error) {
 	req := &resourcemanagerpb.SearchOrganizationsRequest{}
+	if limit > 0 && limit <= math.MaxInt32 {
+		req.PageSize = int32(limit)
+	}
 
+	return c.search(ctx, req, limit)
+}
+
+// SearchOrganizationsByDomain searches for the organizations accessible to the caller whose primary
+// domain is domain, with a "domain:" query.
+func (c *Client) SearchOrganizationsByDomain(ctx context.Context, domain string) ([]*Organization, error) {
+	return c.search(ctx, &resourcemanagerpb.SearchOrganizationsRequest{Query: "domain:" + domain}, 0)
+}
+
+// search runs the search request, returning at most limit organizations. A limit of zero or less returns
+// all of them.
+func (c *Client) search(
+	ctx context.Context,
+	req *resourcemanagerpb.SearchOrganizationsRequest,
+	limit int,
+) ([]*Organization, error) {
 	it := c.client.SearchOrganizations(ctx, req)
 
 	var organizations []*Organization
-	for {
+	for limit <= 0 || len(organizations) < limit {
 		org, err := it.Next()
 		if errors.Is(err, iterator.Done) {
 			break
@@ -52,12 +88,36 @@ func (c *Client) SearchOrganizations(ctx context.Context) ([]*Organization, erro
 			return nil, fmt.Errorf("failed to iterate organizations: %w", err)
 		}
 
-		organizations = append(organizations, OrganizationFromProto(org))
+		organizations = append(organizations, organizationFromResponse(org))
 	}
 
 	return organizations, nil
 }
 
+// GetOrganization retrieves a single organization by its resource name (e.g., "organizations/123").
+func (c *Client) GetOrganization(ctx context.Context, name string) (*Organization, error) {
+	org, err := c.client.GetOrganization(ctx, &resourcemanagerpb.GetOrganizationRequest{Name: name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization %s: %w", name, err)
+	}
+
+	return organizationFromResponse(org), nil
+}
+
+// TestIamPermissions returns the subset of permissions the caller holds on the organization with the given
+// resource name.
+func (c *Client) TestIamPermissions(ctx context.Context, resource string, permissions []string) ([]string, error) {
+	resp, err := c.client.TestIamPermissions(ctx, &iampb.TestIamPermissionsRequest{
+		Resource:    resource,
+		Permissions: permissions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to test IAM permissions on %s: %w", resource, err)
+	}
+
+	return resp.GetPermissions(), nil
+}
+
 // Close releases any resources held by the fetcher.
 func (c *Client) Close() error {
 	if err := c.client.Close(); err != nil {
@@ -66,3 +126,12 @@ func (c *Client) Close() error {
 
 	return nil
 }
+
+// organizationFromResponse converts an API response, keeping the response itself for raw output.
+// Organization listings are small, so the response is always kept.
+func organizationFromResponse(pb *resourcemanagerpb.Organization) *Organization {
+	org := OrganizationFromProto(pb)
+	org.Raw = pb
+
+	return org
+}
@@ -81,6 +81,74 @@ func (_c *MockFetcher_Close_Call) RunAndReturn(run func() error) *MockFetcher_Cl
 	return _c
 }
 
+// GetOrganization provides a mock function for the type MockFetcher
+func (_mock *MockFetcher) GetOrganization(ctx context.Context, name string) (*organizations.Organization, error) {
+	ret := _mock.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrganization")
+	}
+
+	var r0 *organizations.Organization
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*organizations.Organization, error)); ok {
+		return returnFunc(ctx, name)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *organizations.Organization); ok {
+		r0 = returnFunc(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*organizations.Organization)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockFetcher_GetOrganization_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrganization'
+type MockFetcher_GetOrganization_Call struct {
+	*mock.Call
+}
+
+// GetOrganization is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+func (_e *MockFetcher_Expecter) GetOrganization(ctx interface{}, name interface{}) *MockFetcher_GetOrganization_Call {
+	return &MockFetcher_GetOrganization_Call{Call: _e.mock.On("GetOrganization", ctx, name)}
+}
+
+func (_c *MockFetcher_GetOrganization_Call) Run(run func(ctx context.Context, name string)) *MockFetcher_GetOrganization_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockFetcher_GetOrganization_Call) Return(organization *organizations.Organization, err error) *MockFetcher_GetOrganization_Call {
+	_c.Call.Return(organization, err)
+	return _c
+}
+
+func (_c *MockFetcher_GetOrganization_Call) RunAndReturn(run func(ctx context.Context, name string) (*organizations.Organization, error)) *MockFetcher_GetOrganization_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SearchOrganizations provides a mock function for the type MockFetcher
 func (_mock *MockFetcher) SearchOrganizations(ctx context.Context) ([]*organizations.Organization, error) {
 	ret := _mock.Called(ctx)
@@ -142,3 +210,166 @@ func (_c *MockFetcher_SearchOrganizations_Call) RunAndReturn(run func(ctx contex
 	_c.Call.Return(run)
 	return _c
 }
+
+// SearchOrganizationsByDomain provides a mock function for the type MockFetcher
+func (_mock *MockFetcher) SearchOrganizationsByDomain(ctx context.Context, domain string) ([]*organizations.Organization, error) {
+	ret := _mock.Called(ctx, domain)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchOrganizationsByDomain")
+	}
+
+	var r0 []*organizations.Organization
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]*organizations.Organization, error)); ok {
+		return returnFunc(ctx, domain)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []*organizations.Organization); ok {
+		r0 = returnFunc(ctx, domain)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*organizations.Organization)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, domain)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockFetcher_SearchOrganizationsByDomain_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SearchOrganizationsByDomain'
+type MockFetcher_SearchOrganizationsByDomain_Call struct {
+	*mock.Call
+}
+
+// SearchOrganizationsByDomain is a helper method to define mock.On call
+//   - ctx context.Context
+//   - domain string
+func (_e *MockFetcher_Expecter) SearchOrganizationsByDomain(ctx interface{}, domain interface{}) *MockFetcher_SearchOrganizationsByDomain_Call {
+	return &MockFetcher_SearchOrganizationsByDomain_Call{Call: _e.mock.On("SearchOrganizationsByDomain", ctx, domain)}
+}
+
+func (_c *MockFetcher_SearchOrganizationsByDomain_Call) Run(run func(ctx context.Context, domain string)) *MockFetcher_SearchOrganizationsByDomain_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockFetcher_SearchOrganizationsByDomain_Call) Return(organizations1 []*organizations.Organization, err error) *MockFetcher_SearchOrganizationsByDomain_Call {
+	_c.Call.Return(organizations1, err)
+	return _c
+}
+
+func (_c *MockFetcher_SearchOrganizationsByDomain_Call) RunAndReturn(run func(ctx context.Context, domain string) ([]*organizations.Organization, error)) *MockFetcher_SearchOrganizationsByDomain_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockGetter creates a new instance of MockGetter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockGetter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockGetter {
+	mock := &MockGetter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockGetter is an autogenerated mock type for the Getter type
+type MockGetter struct {
+	mock.Mock
+}
+
+type MockGetter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockGetter) EXPECT() *MockGetter_Expecter {
+	return &MockGetter_Expecter{mock: &_m.Mock}
+}
+
+// GetOrganization provides a mock function for the type MockGetter
+func (_mock *MockGetter) GetOrganization(ctx context.Context, name string) (*organizations.Organization, error) {
+	ret := _mock.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrganization")
+	}
+
+	var r0 *organizations.Organization
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*organizations.Organization, error)); ok {
+		return returnFunc(ctx, name)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *organizations.Organization); ok {
+		r0 = returnFunc(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*organizations.Organization)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockGetter_GetOrganization_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrganization'
+type MockGetter_GetOrganization_Call struct {
+	*mock.Call
+}
+
+// GetOrganization is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+func (_e *MockGetter_Expecter) GetOrganization(ctx interface{}, name interface{}) *MockGetter_GetOrganization_Call {
+	return &MockGetter_GetOrganization_Call{Call: _e.mock.On("GetOrganization", ctx, name)}
+}
+
+func (_c *MockGetter_GetOrganization_Call) Run(run func(ctx context.Context, name string)) *MockGetter_GetOrganization_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockGetter_GetOrganization_Call) Return(organization *organizations.Organization, err error) *MockGetter_GetOrganization_Call {
+	_c.Call.Return(organization, err)
+	return _c
+}
+
+func (_c *MockGetter_GetOrganization_Call) RunAndReturn(run func(ctx context.Context, name string) (*organizations.Organization, error)) *MockGetter_GetOrganization_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -6,8 +6,10 @@ import (
 	"time"
 
 	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/andreygrechin/gcphelper/internal/progress"
 	"github.com/briandowns/spinner"
 	"go.uber.org/zap"
+	"google.golang.org/api/option"
 )
 
 const (
@@ -19,6 +21,9 @@ const (
 type Service struct {
 	fetcher Fetcher
 	logger  logger.Logger
+
+	noSpinner  bool
+	newSpinner func(suffix string) progress.Spinner
 }
 
 // NewServiceWithLogger creates a new organizations service with the provided fetcher and logger.
@@ -31,8 +36,12 @@ func NewServiceWithLogger(fetcher Fetcher, log logger.Logger) *Service {
 
 // NewServiceFromContextWithLogger creates a new organizations service using
 // application default credentials with logger.
-func NewServiceFromContextWithLogger(ctx context.Context, log logger.Logger) (*Service, error) {
-	client, err := NewClientFromContext(ctx)
+func NewServiceFromContextWithLogger(
+	ctx context.Context,
+	log logger.Logger,
+	clientOpts ...option.ClientOption,
+) (*Service, error) {
+	client, err := NewClientFromContext(ctx, clientOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -43,19 +52,59 @@ func NewServiceFromContextWithLogger(ctx context.Context, log logger.Logger) (*S
 	return NewServiceWithLogger(client, log), nil
 }
 
-// SearchOrganizations searches for organizations accessible to the caller.
-func (s *Service) SearchOrganizations(ctx context.Context) ([]*Organization, error) {
+// SetSpinner enables or disables the progress spinner shown while fetching. The spinner is enabled by
+// default and is only drawn when stdout is a terminal.
+func (s *Service) SetSpinner(enabled bool) {
+	s.noSpinner = !enabled
+}
+
+// SetSpinnerFactory replaces how progress spinners are created, for example to observe them in tests.
+func (s *Service) SetSpinnerFactory(newSpinner func(suffix string) progress.Spinner) {
+	s.newSpinner = newSpinner
+}
+
+// startSpinner shows a spinner with the given suffix, unless spinners are disabled, and returns a
+// function that stops it.
+func (s *Service) startSpinner(ctx context.Context, suffix string) func() {
+	if s.noSpinner {
+		return func() {}
+	}
+
+	newSpinner := s.newSpinner
+	if newSpinner == nil {
+		newSpinner = terminalSpinner
+	}
+
+	return progress.Start(ctx, newSpinner(suffix))
+}
+
+// terminalSpinner creates the animated terminal spinner.
+func terminalSpinner(suffix string) progress.Spinner {
+	spin := spinner.New(spinner.CharSets[spinnerStyle], spinnerSpeed)
+	spin.Suffix = suffix
+
+	return spin
+}
+
+// SearchOrganizations searches for organizations accessible to the caller. The API has no state
+// filter, so organizations that are not ACTIVE are removed client-side unless opts.IncludeDeleted is
+// set, matching the ACTIVE-only default of folder searches. With opts.Domain only the organization with
+// that primary domain is searched for. A nil opts uses the defaults.
+func (s *Service) SearchOrganizations(ctx context.Context, opts *OrgFetchOptions) ([]*Organization, error) {
 	if s.logger != nil {
 		s.logger.Info("searching for accessible organizations")
 	}
 
 	// show progress indicator for potentially long-running operations
-	spin := spinner.New(spinner.CharSets[spinnerStyle], spinnerSpeed)
-	spin.Suffix = " Searching for accessible organizations..."
-	spin.Start()
-	defer spin.Stop()
+	defer s.startSpinner(ctx, " Searching for accessible organizations...")()
 
-	organizations, err := s.fetcher.SearchOrganizations(ctx)
+	var organizations []*Organization
+	var err error
+	if opts != nil && opts.Domain != "" {
+		organizations, err = s.fetcher.SearchOrganizationsByDomain(ctx, opts.Domain)
+	} else {
+		organizations, err = s.fetcher.SearchOrganizations(ctx)
+	}
 	if err != nil {
 		if s.logger != nil {
 			s.logger.Debug("failed to search organizations", zap.Error(err))
@@ -64,6 +113,14 @@ func (s *Service) SearchOrganizations(ctx context.Context) ([]*Organization, err
 		return nil, fmt.Errorf("failed to search organizations: %w", err)
 	}
 
+	for _, org := range organizations {
+		s.warnMalformedID(org)
+	}
+
+	if opts == nil || !opts.IncludeDeleted {
+		organizations = activeOrganizations(organizations)
+	}
+
 	if s.logger != nil {
 		s.logger.Debug("successfully found organizations", zap.Int("count", len(organizations)))
 	}
@@ -71,6 +128,44 @@ func (s *Service) SearchOrganizations(ctx context.Context) ([]*Organization, err
 	return organizations, nil
 }
 
+// warnMalformedID logs a warning when a fetched organization's name is not "organizations/" followed by
+// a numeric ID, in which case the raw name is kept as its ID.
+func (s *Service) warnMalformedID(org *Organization) {
+	if s.logger == nil || org == nil {
+		return
+	}
+	if err := org.IDError(); err != nil {
+		s.logger.Warn("keeping the raw organization name as its ID", zap.String("name", org.Name), zap.Error(err))
+	}
+}
+
+// activeOrganizations returns the organizations in the ACTIVE state, preserving order.
+func activeOrganizations(organizations []*Organization) []*Organization {
+	active := make([]*Organization, 0, len(organizations))
+	for _, org := range organizations {
+		if org.State == stateActive {
+			active = append(active, org)
+		}
+	}
+
+	return active
+}
+
+// GetOrganization retrieves a single organization by its resource name (e.g., "organizations/123").
+func (s *Service) GetOrganization(ctx context.Context, name string) (*Organization, error) {
+	if s.logger != nil {
+		s.logger.Debug("fetching organization", zap.String("name", name))
+	}
+
+	org, err := s.fetcher.GetOrganization(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+	s.warnMalformedID(org)
+
+	return org, nil
+}
+
 // Close releases any resources held by the service.
 func (s *Service) Close() error {
 	if s.fetcher != nil {
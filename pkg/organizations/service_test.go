@@ -0,0 +1,82 @@
+package organizations_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/andreygrechin/gcphelper/pkg/organizations"
+	"github.com/andreygrechin/gcphelper/pkg/organizations/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Test error variables for err113 compliance.
+var errServiceTestAPIError = errors.New("API error")
+
+func TestService_SearchOrganizations(t *testing.T) {
+	mixed := []*organizations.Organization{
+		{ID: "1", Name: "organizations/1", DisplayName: "Acme", State: "ACTIVE"},
+		{ID: "2", Name: "organizations/2", DisplayName: "Gone Corp", State: "DELETE_REQUESTED"},
+		{ID: "3", Name: "organizations/3", DisplayName: "Unknown Inc", State: "STATE_UNSPECIFIED"},
+		{ID: "4", Name: "organizations/4", DisplayName: "Example", State: "ACTIVE"},
+	}
+
+	tests := map[string]struct {
+		opts    *organizations.OrgFetchOptions
+		wantIDs []string
+	}{
+		"nil options hide non-active organizations": {
+			opts:    nil,
+			wantIDs: []string{"1", "4"},
+		},
+		"default options hide non-active organizations": {
+			opts:    &organizations.OrgFetchOptions{},
+			wantIDs: []string{"1", "4"},
+		},
+		"include deleted keeps every organization": {
+			opts:    &organizations.OrgFetchOptions{IncludeDeleted: true},
+			wantIDs: []string{"1", "2", "3", "4"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			fetcher := mocks.NewMockFetcher(t)
+			fetcher.On("SearchOrganizations", mock.Anything).Return(mixed, nil)
+			service := organizations.NewServiceWithLogger(fetcher, logger.NewNoOpLogger())
+
+			got, err := service.SearchOrganizations(t.Context(), tt.opts)
+			require.NoError(t, err)
+
+			ids := make([]string, len(got))
+			for i, org := range got {
+				ids[i] = org.ID
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
+
+func TestService_SearchOrganizationsError(t *testing.T) {
+	fetcher := mocks.NewMockFetcher(t)
+	fetcher.On("SearchOrganizations", mock.Anything).Return(nil, errServiceTestAPIError)
+	service := organizations.NewServiceWithLogger(fetcher, logger.NewNoOpLogger())
+
+	_, err := service.SearchOrganizations(t.Context(), nil)
+	require.ErrorIs(t, err, errServiceTestAPIError)
+}
+
+func TestService_SearchOrganizationsByDomain(t *testing.T) {
+	fetcher := mocks.NewMockFetcher(t)
+	fetcher.On("SearchOrganizationsByDomain", mock.Anything, "example.com").Return([]*organizations.Organization{
+		{ID: "4", Name: "organizations/4", DisplayName: "example.com", State: "ACTIVE"},
+	}, nil)
+	service := organizations.NewServiceWithLogger(fetcher, logger.NewNoOpLogger())
+
+	got, err := service.SearchOrganizations(t.Context(), &organizations.OrgFetchOptions{Domain: "example.com"})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "organizations/4", got[0].Name)
+}
@@ -1,36 +1,62 @@
 package organizations
 
 import (
-	"strings"
+	"fmt"
 	"time"
 
 	resourcemanagerpb "cloud.google.com/go/resourcemanager/apiv3/resourcemanagerpb"
+	"github.com/andreygrechin/gcphelper/internal/resourcename"
 	"github.com/jedib0t/go-pretty/v6/table"
+	"google.golang.org/protobuf/proto"
 )
 
 const orgPrefix = "organizations/"
 
+// stateActive is the lifecycle state of organizations that have not been marked for deletion.
+const stateActive = "ACTIVE"
+
+// OrgFetchOptions configures organization searches.
+type OrgFetchOptions struct {
+	IncludeDeleted bool   // IncludeDeleted keeps organizations that are not ACTIVE, which are hidden by default.
+	Domain         string // Domain limits the search to the organization with this primary domain, e.g. "example.com"
+}
+
 // Organization represents a Google Cloud organization.
 type Organization struct {
-	ID          string    `json:"id"`           // ID is the organization's numeric ID ("123456789")
-	Name        string    `json:"name"`         // Name is the organization's resource name ("organizations/123456789")
-	DisplayName string    `json:"display_name"` // DisplayName is the organization's human-readable name
-	State       string    `json:"state"`        // State indicates the organization's lifecycle state
-	CreateTime  time.Time `json:"create_time"`  // CreateTime is when the organization was created
-	UpdateTime  time.Time `json:"update_time"`  // UpdateTime is when the organization was last updated
+	ID          string    `json:"id"`             // ID is the organization's numeric ID ("123456789")
+	Name        string    `json:"name"`           // Name is the organization's resource name ("organizations/123456789")
+	DisplayName string    `json:"display_name"`   // DisplayName is the organization's human-readable name
+	State       string    `json:"state"`          // State indicates the organization's lifecycle state
+	CreateTime  time.Time `json:"create_time"`    // CreateTime is when the organization was created
+	UpdateTime  time.Time `json:"update_time"`    // UpdateTime is when the organization was last updated
+	Etag        string    `json:"etag,omitempty"` // Etag changes whenever the organization is modified
+
+	// Raw is the API response the organization was converted from, kept by Client lookups.
+	Raw *resourcemanagerpb.Organization `json:"-"`
+
+	idErr error // idErr is why OrganizationFromProto could not extract the ID from the organization's name
 }
 
-// OrganizationFromProto converts a protobuf organization to our internal type.
+// OrganizationFromProto converts a protobuf organization to our internal type. A name that is not
+// "organizations/" followed by a numeric ID is kept as the ID too; IDError reports such names.
 func OrganizationFromProto(pb *resourcemanagerpb.Organization) *Organization {
 	if pb == nil {
 		return nil
 	}
 
+	id, err := resourcename.ExtractID(pb.GetName(), orgPrefix)
+	if err != nil {
+		id = pb.GetName()
+		err = fmt.Errorf("unexpected organization name: %w", err)
+	}
+
 	org := &Organization{
-		ID:          strings.TrimPrefix(pb.GetName(), orgPrefix),
+		ID:          id,
 		Name:        pb.GetName(),
 		DisplayName: pb.GetDisplayName(),
 		State:       pb.GetState().String(),
+		Etag:        pb.GetEtag(),
+		idErr:       err,
 	}
 
 	if pb.GetCreateTime() != nil {
@@ -49,6 +75,17 @@ func (o *Organization) GetID() string {
 	return o.ID
 }
 
+// GetName returns the organization's resource name ("organizations/123456789").
+func (o *Organization) GetName() string {
+	return o.Name
+}
+
+// IDError reports why OrganizationFromProto kept the raw resource name as the organization's ID, because
+// it was not "organizations/" followed by a numeric ID, or returns nil.
+func (o *Organization) IDError() error {
+	return o.idErr
+}
+
 // GetDisplayName returns the organization's display name.
 func (o *Organization) GetDisplayName() string {
 	return o.DisplayName
@@ -64,18 +101,33 @@ func (o *Organization) GetCreateTime() time.Time {
 	return o.CreateTime
 }
 
+// GetEtag returns the organization's etag.
+func (o *Organization) GetEtag() string {
+	return o.Etag
+}
+
+// Proto returns the API response the organization was converted from, or nil when it was not kept.
+func (o *Organization) Proto() proto.Message {
+	if o.Raw == nil {
+		return nil
+	}
+
+	return o.Raw
+}
+
 // GetUpdateTime returns the organization's last update time.
 func (o *Organization) GetUpdateTime() time.Time {
 	return o.UpdateTime
 }
 
-// TableRow returns the organization data as a table row.
+// TableRow returns the organization data as a table row. Timestamps are left as time.Time values
+// for the output formatter to render.
 func (o *Organization) TableRow() []interface{} {
 	return table.Row{
 		o.ID,
 		o.DisplayName,
 		o.State,
-		o.CreateTime.Format("2006-01-02 15:04:05"),
-		o.UpdateTime.Format("2006-01-02 15:04:05"),
+		o.CreateTime,
+		o.UpdateTime,
 	}
 }
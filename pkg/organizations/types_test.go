@@ -5,8 +5,10 @@ import (
 	"time"
 
 	resourcemanagerpb "cloud.google.com/go/resourcemanager/apiv3/resourcemanagerpb"
+	"github.com/andreygrechin/gcphelper/internal/resourcename"
 	"github.com/andreygrechin/gcphelper/pkg/organizations"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -22,6 +24,7 @@ func TestOrgFromProto(t *testing.T) {
 				State:       resourcemanagerpb.Organization_ACTIVE,
 				CreateTime:  timestamppb.New(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)),
 				UpdateTime:  timestamppb.New(time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)),
+				Etag:        "BwXhqY1jIIw=",
 			},
 			want: &organizations.Organization{
 				ID:          "123456789",
@@ -30,6 +33,7 @@ func TestOrgFromProto(t *testing.T) {
 				State:       "ACTIVE",
 				CreateTime:  time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
 				UpdateTime:  time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+				Etag:        "BwXhqY1jIIw=",
 			},
 		},
 		"organization without timestamps": {
@@ -60,3 +64,29 @@ func TestOrgFromProto(t *testing.T) {
 		})
 	}
 }
+
+func TestOrgFromProtoIDExtraction(t *testing.T) {
+	testCases := map[string]struct {
+		name    string
+		wantID  string
+		wantErr bool
+	}{
+		"well-formed name": {name: "organizations/123456789", wantID: "123456789"},
+		"missing prefix":   {name: "123456789", wantID: "123456789", wantErr: true},
+		"empty id":         {name: "organizations/", wantID: "organizations/", wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got := organizations.OrganizationFromProto(&resourcemanagerpb.Organization{Name: tc.name})
+			assert.Equal(t, tc.wantID, got.ID)
+			assert.Equal(t, tc.name, got.Name)
+			if tc.wantErr {
+				require.ErrorIs(t, got.IDError(), resourcename.ErrMalformedName)
+
+				return
+			}
+			require.NoError(t, got.IDError())
+		})
+	}
+}
@@ -5,6 +5,17 @@ import (
 	"github.com/andreygrechin/gcphelper/pkg/organizations"
 )
 
+// Resource type names, under which the commands register the descriptors of the types they render.
+const (
+	ResourceTypeFolders       = "folders"
+	ResourceTypeOrganizations = "organizations"
+	ResourceTypeFolderCounts  = "folder-counts"
+	ResourceTypePermissions   = "permissions"
+	ResourceTypeIdentity      = "identity"
+	ResourceTypeSettings      = "settings"
+	ResourceTypeFolderNames   = "folder-names"
+)
+
 // FoldersToResources converts a slice of folders to a slice of resources.
 func FoldersToResources(folderList []*folders.Folder) []Resource {
 	resources := make([]Resource, len(folderList))
@@ -30,6 +41,30 @@ func FolderHeaders() []string {
 	return []string{"ID", "Display Name", "Parent", "State", "Create Time", "Update Time"}
 }
 
+// ParentTypeColumn returns the "Parent Type" computed column, which tells whether a folder's parent
+// is an organization or another folder.
+func ParentTypeColumn() Column {
+	return Column{
+		Header: "Parent Type",
+		Field:  "parent_type",
+		Value: func(r Resource) interface{} {
+			return folders.ParentType(parentOf(r))
+		},
+	}
+}
+
+// EtagColumn returns the "Etag" computed column with the resource's etag, for change detection and
+// optimistic concurrency. It is not part of the default headers.
+func EtagColumn() Column {
+	return Column{
+		Header: "Etag",
+		Field:  "etag",
+		Value: func(r Resource) interface{} {
+			return etagOf(r)
+		},
+	}
+}
+
 // OrganizationHeaders returns the table headers for organization output.
 func OrganizationHeaders() []string {
 	return []string{"ID", "Display Name", "State", "Create Time", "Update Time"}
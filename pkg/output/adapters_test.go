@@ -1,6 +1,7 @@
 package output_test
 
 import (
+	"bytes"
 	"testing"
 	"time"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/andreygrechin/gcphelper/pkg/organizations"
 	"github.com/andreygrechin/gcphelper/pkg/output"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFoldersToResources(t *testing.T) {
@@ -77,3 +79,95 @@ func TestOrganizationHeaders(t *testing.T) {
 	expected := []string{"ID", "Display Name", "State", "Create Time", "Update Time"}
 	assert.Equal(t, expected, headers)
 }
+
+func TestParentTypeColumn(t *testing.T) {
+	resources := output.FoldersToResources([]*folders.Folder{
+		{ID: "1", DisplayName: "prod", Parent: "organizations/9"},
+		{ID: "2", DisplayName: "team", Parent: "folders/1"},
+		{ID: "3", DisplayName: "odd", Parent: "projects/p"},
+	})
+
+	tests := map[string]struct {
+		resources func() ([]output.Resource, []string)
+		format    output.Format
+		want      string
+	}{
+		"opt-in column in csv": {
+			resources: func() ([]output.Resource, []string) {
+				selected, headers, err := output.SelectFields(resources, output.FolderHeaders(), []string{"id"})
+				require.NoError(t, err)
+
+				return output.WithColumns(selected, headers, output.ParentTypeColumn())
+			},
+			format: output.FormatCSV,
+			want:   "ID,Parent Type\n1,organization\n2,folder\n3,unknown\n",
+		},
+		"selected field in jsonl": {
+			resources: func() ([]output.Resource, []string) {
+				selected, headers, err := output.SelectFields(
+					resources, output.FolderHeaders(), []string{"id", "parent_type"}, output.ParentTypeColumn(),
+				)
+				require.NoError(t, err)
+
+				return selected, headers
+			},
+			format: output.FormatJSONL,
+			want: `{"id":"1","parent_type":"organization"}` + "\n" +
+				`{"id":"2","parent_type":"folder"}` + "\n" +
+				`{"id":"3","parent_type":"unknown"}` + "\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, headers := tt.resources()
+			var stdout bytes.Buffer
+			formatter := output.NewFormatter(&stdout, &bytes.Buffer{}, false, output.ResourceTypeFolders)
+			require.NoError(t, formatter.Format(got, tt.format, headers))
+			assert.Equal(t, tt.want, stdout.String())
+		})
+	}
+}
+
+func TestEtagField(t *testing.T) {
+	tests := map[string]struct {
+		resourceType string
+		headers      []string
+		resources    []output.Resource
+		want         string
+	}{
+		"folders": {
+			resourceType: output.ResourceTypeFolders,
+			headers:      output.FolderHeaders(),
+			resources: output.FoldersToResources([]*folders.Folder{
+				{ID: "1", Etag: "etag-1"},
+				{ID: "2"},
+			}),
+			want: `{"id":"1","etag":"etag-1"}` + "\n" + `{"id":"2","etag":""}` + "\n",
+		},
+		"organizations": {
+			resourceType: output.ResourceTypeOrganizations,
+			headers:      output.OrganizationHeaders(),
+			resources: output.OrganizationsToResources([]*organizations.Organization{
+				{ID: "9", Etag: "etag-9"},
+			}),
+			want: `{"id":"9","etag":"etag-9"}` + "\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.NotContains(t, tt.headers, "Etag", "etag should stay out of the default headers")
+
+			selected, headers, err := output.SelectFields(
+				tt.resources, tt.headers, []string{"id", "etag"}, output.EtagColumn())
+			require.NoError(t, err)
+			assert.Equal(t, []string{"ID", "Etag"}, headers)
+
+			var stdout bytes.Buffer
+			formatter := output.NewFormatter(&stdout, &bytes.Buffer{}, false, tt.resourceType)
+			require.NoError(t, formatter.Format(selected, output.FormatJSONL, headers))
+			assert.Equal(t, tt.want, stdout.String())
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidAgeUnit is returned when an unknown age unit is requested.
+var ErrInvalidAgeUnit = errors.New("invalid age unit")
+
+// AgeField is the field name of the age computed column.
+const AgeField = "age"
+
+// day is the length of a day in age output; ages ignore daylight saving changes.
+const day = 24 * time.Hour
+
+// AgeUnit selects how ages are encoded in JSON output.
+type AgeUnit string
+
+// Age unit constants.
+const (
+	AgeUnitSeconds AgeUnit = "seconds" // AgeUnitSeconds encodes ages as whole seconds
+	AgeUnitDays    AgeUnit = "days"    // AgeUnitDays encodes ages as whole days
+)
+
+// ParseAgeUnit validates an age unit name. An empty name selects AgeUnitSeconds.
+func ParseAgeUnit(name string) (AgeUnit, error) {
+	switch unit := AgeUnit(name); unit {
+	case "", AgeUnitSeconds:
+		return AgeUnitSeconds, nil
+	case AgeUnitDays:
+		return unit, nil
+	default:
+		return "", fmt.Errorf("%w: %s (supported: %s, %s)", ErrInvalidAgeUnit, name, AgeUnitSeconds, AgeUnitDays)
+	}
+}
+
+// Age is how long ago a resource was created. It is written as a humanized duration such as "3d" in
+// table, CSV and value output, and as a number in the configured unit in JSON output.
+type Age struct {
+	Duration time.Duration // Duration is the time elapsed since creation
+	Unit     AgeUnit       // Unit is the JSON encoding unit
+}
+
+// String returns the age as a humanized duration: seconds, minutes or hours below a day, days below
+// a year, and years followed by the remaining days beyond that.
+func (a Age) String() string {
+	d := max(a.Duration, 0)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int64(d/time.Second))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int64(d/time.Minute))
+	case d < day:
+		return fmt.Sprintf("%dh", int64(d/time.Hour))
+	}
+
+	days := int64(d / day)
+	if days < 365 {
+		return fmt.Sprintf("%dd", days)
+	}
+	if days%365 == 0 {
+		return fmt.Sprintf("%dy", days/365)
+	}
+
+	return fmt.Sprintf("%dy%dd", days/365, days%365)
+}
+
+// MarshalJSON encodes the age as whole days with AgeUnitDays and as whole seconds otherwise.
+func (a Age) MarshalJSON() ([]byte, error) {
+	d := max(a.Duration, 0)
+	if a.Unit == AgeUnitDays {
+		return []byte(strconv.FormatInt(int64(d/day), 10)), nil
+	}
+
+	return []byte(strconv.FormatInt(int64(d/time.Second), 10)), nil
+}
+
+// AgeColumn returns the "Age" computed column with the time elapsed between each resource's creation
+// and now(), encoded in unit in JSON output. Resources without a creation time have no age. It is not
+// part of the default headers.
+func AgeColumn(now func() time.Time, unit AgeUnit) Column {
+	return Column{
+		Header: "Age",
+		Field:  AgeField,
+		Value: func(r Resource) interface{} {
+			created := r.GetCreateTime()
+			if created.IsZero() {
+				return nil
+			}
+
+			return Age{Duration: now().Sub(created), Unit: unit}
+		},
+	}
+}
+
+// WithAgeUnit returns a copy of columns in which the age column encodes ages in unit.
+func WithAgeUnit(columns []Column, unit AgeUnit) []Column {
+	updated := make([]Column, len(columns))
+	for i, column := range columns {
+		if column.Field == AgeField {
+			column = AgeColumn(time.Now, unit)
+		}
+		updated[i] = column
+	}
+
+	return updated
+}
@@ -0,0 +1,115 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAgeUnit(t *testing.T) {
+	tests := map[string]struct {
+		name    string
+		want    output.AgeUnit
+		wantErr error
+	}{
+		"empty defaults to seconds": {name: "", want: output.AgeUnitSeconds},
+		"seconds":                   {name: "seconds", want: output.AgeUnitSeconds},
+		"days":                      {name: "days", want: output.AgeUnitDays},
+		"unknown":                   {name: "weeks", wantErr: output.ErrInvalidAgeUnit},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := output.ParseAgeUnit(tt.name)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAgeString(t *testing.T) {
+	tests := map[string]struct {
+		duration time.Duration
+		want     string
+	}{
+		"seconds":          {duration: 42 * time.Second, want: "42s"},
+		"minutes":          {duration: 5*time.Minute + 30*time.Second, want: "5m"},
+		"hours":            {duration: 23*time.Hour + 59*time.Minute, want: "23h"},
+		"days":             {duration: 30*24*time.Hour + 5*time.Hour, want: "30d"},
+		"whole years":      {duration: 2 * 365 * 24 * time.Hour, want: "2y"},
+		"years and days":   {duration: 400 * 24 * time.Hour, want: "1y35d"},
+		"future is zero":   {duration: -time.Hour, want: "0s"},
+		"zero is 0 second": {duration: 0, want: "0s"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, output.Age{Duration: tt.duration}.String())
+		})
+	}
+}
+
+func TestAgeColumn(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	resources := output.FoldersToResources([]*folders.Folder{
+		{ID: "1", CreateTime: now.Add(-(10*24*time.Hour + 3*time.Hour))},
+		{ID: "2", CreateTime: now.Add(-90 * time.Minute)},
+		{ID: "3"},
+	})
+
+	tests := map[string]struct {
+		unit   output.AgeUnit
+		format output.Format
+		want   string
+	}{
+		"table shows humanized ages": {
+			unit:   output.AgeUnitSeconds,
+			format: output.FormatCSV,
+			want:   "ID,Age\n1,10d\n2,1h\n3,\n",
+		},
+		"json in seconds": {
+			unit:   output.AgeUnitSeconds,
+			format: output.FormatJSONL,
+			want:   `{"id":"1","age":874800}` + "\n" + `{"id":"2","age":5400}` + "\n" + `{"id":"3","age":null}` + "\n",
+		},
+		"json in days": {
+			unit:   output.AgeUnitDays,
+			format: output.FormatJSONL,
+			want:   `{"id":"1","age":10}` + "\n" + `{"id":"2","age":0}` + "\n" + `{"id":"3","age":null}` + "\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			selected, headers, err := output.SelectFields(
+				resources, output.FolderHeaders(), []string{"id", "age"}, output.AgeColumn(clock, tt.unit))
+			require.NoError(t, err)
+			assert.Equal(t, []string{"ID", "Age"}, headers)
+
+			var stdout bytes.Buffer
+			formatter := output.NewFormatter(&stdout, &bytes.Buffer{}, false, output.ResourceTypeFolders)
+			require.NoError(t, formatter.Format(selected, tt.format, headers))
+			assert.Equal(t, tt.want, stdout.String())
+		})
+	}
+}
+
+func TestWithAgeUnit(t *testing.T) {
+	columns := []output.Column{output.EtagColumn(), output.AgeColumn(time.Now, output.AgeUnitSeconds)}
+
+	fields := output.WithAgeUnit(columns, output.AgeUnitDays)
+	require.Len(t, fields, len(columns))
+	assert.Equal(t, "etag", fields[0].Field)
+	assert.Equal(t, output.AgeField, fields[1].Field)
+}
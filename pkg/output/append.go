@@ -0,0 +1,99 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrAppendMismatch is returned when new output cannot be appended to an existing output file, because
+// the file is not a JSON array or its CSV header differs.
+var ErrAppendMismatch = errors.New("cannot append to the existing output")
+
+// AppendOutput returns the contents of an output file after appending the output of another run in
+// format to its existing contents. JSON arrays, and the JSON objects of describe commands, are merged into
+// one array, indented unless compact is set, and CSV output keeps a single header; other formats are
+// concatenated.
+func AppendOutput(existing, added []byte, format Format, compact bool) ([]byte, error) {
+	if len(bytes.TrimSpace(existing)) == 0 {
+		if isJSONFormat(format) && bytes.HasPrefix(bytes.TrimSpace(added), []byte("{")) {
+			// start an array, so that later runs can append to the file
+			return appendJSON([]byte("[]"), added, compact)
+		}
+
+		return added, nil
+	}
+
+	switch {
+	case isJSONFormat(format):
+		return appendJSON(existing, added, compact)
+	case format == FormatCSV:
+		return appendCSV(existing, added)
+	default:
+		return append(withNewline(existing), added...), nil
+	}
+}
+
+// isJSONFormat reports whether output in format is a JSON array, or a JSON object for a lone resource.
+func isJSONFormat(format Format) bool {
+	return format == FormatJSON || format == FormatRawJSON || format == FormatJSONObject
+}
+
+// appendJSON merges the elements of two JSON arrays into one array, encoded like json output. Added output
+// that is a single JSON object is appended as one element.
+func appendJSON(existing, added []byte, compact bool) ([]byte, error) {
+	var elements []json.RawMessage
+	if err := json.Unmarshal(existing, &elements); err != nil {
+		return nil, fmt.Errorf("%w: the file is not a JSON array: %w", ErrAppendMismatch, err)
+	}
+	switch added = bytes.TrimSpace(added); {
+	case bytes.HasPrefix(added, []byte("{")):
+		// a lone resource written as an object, e.g. by a describe command, is appended as one element
+		elements = append(elements, json.RawMessage(added))
+	case len(added) > 0:
+		var addedElements []json.RawMessage
+		if err := json.Unmarshal(added, &addedElements); err != nil {
+			return nil, fmt.Errorf("%w: the output is not a JSON array: %w", ErrAppendMismatch, err)
+		}
+		elements = append(elements, addedElements...)
+	}
+	// an empty array is written as [] rather than null
+	if elements == nil {
+		elements = []json.RawMessage{}
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	if !compact {
+		encoder.SetIndent("", "  ")
+	}
+	if err := encoder.Encode(elements); err != nil {
+		return nil, fmt.Errorf("failed to encode JSON: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// appendCSV appends the records of added to existing, dropping the header of added, which must match
+// the header of existing.
+func appendCSV(existing, added []byte) ([]byte, error) {
+	header, records, _ := bytes.Cut(added, []byte("\n"))
+	existingHeader, _, _ := bytes.Cut(existing, []byte("\n"))
+	header, existingHeader = bytes.TrimSuffix(header, []byte("\r")), bytes.TrimSuffix(existingHeader, []byte("\r"))
+	if len(added) > 0 && !bytes.Equal(header, existingHeader) {
+		return nil, fmt.Errorf("%w: the CSV header %q differs from the file's header %q",
+			ErrAppendMismatch, header, existingHeader)
+	}
+
+	return append(withNewline(existing), records...), nil
+}
+
+// withNewline returns data ending with a newline, so that appended output starts on a line of its own.
+func withNewline(data []byte) []byte {
+	if len(data) > 0 && !bytes.HasSuffix(data, []byte("\n")) {
+		return append(data, '\n')
+	}
+
+	return data
+}
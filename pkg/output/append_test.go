@@ -0,0 +1,125 @@
+package output_test
+
+import (
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendOutput(t *testing.T) {
+	tests := map[string]struct {
+		existing string
+		added    string
+		format   output.Format
+		compact  bool
+		want     string
+		wantErr  error
+	}{
+		"json into empty file": {
+			added:  "[\n  {\"id\": \"1\"}\n]\n",
+			format: output.FormatJSON,
+			want:   "[\n  {\"id\": \"1\"}\n]\n",
+		},
+		"json arrays merged": {
+			existing: "[\n  {\n    \"id\": \"1\"\n  }\n]\n",
+			added:    "[\n  {\n    \"id\": \"2\"\n  }\n]\n",
+			format:   output.FormatJSON,
+			want:     "[\n  {\n    \"id\": \"1\"\n  },\n  {\n    \"id\": \"2\"\n  }\n]\n",
+		},
+		"compact json": {
+			existing: "[{\"id\":\"1\"}]\n",
+			added:    "[{\"id\":\"2\"}]\n",
+			format:   output.FormatJSON,
+			compact:  true,
+			want:     "[{\"id\":\"1\"},{\"id\":\"2\"}]\n",
+		},
+		"empty json arrays": {
+			existing: "[]\n",
+			added:    "[]\n",
+			format:   output.FormatJSON,
+			compact:  true,
+			want:     "[]\n",
+		},
+		"rawjson arrays merged": {
+			existing: "[{\"name\":\"folders/1\"}]",
+			added:    "[{\"name\":\"folders/2\"}]",
+			format:   output.FormatRawJSON,
+			compact:  true,
+			want:     "[{\"name\":\"folders/1\"},{\"name\":\"folders/2\"}]\n",
+		},
+		"json object appended as an element": {
+			existing: "[{\"id\":\"1\"}]\n",
+			added:    "{\"id\":\"2\"}\n",
+			format:   output.FormatJSON,
+			compact:  true,
+			want:     "[{\"id\":\"1\"},{\"id\":\"2\"}]\n",
+		},
+		"json object into empty file starts an array": {
+			added:   "{\"id\":\"1\"}\n",
+			format:  output.FormatJSON,
+			compact: true,
+			want:    "[{\"id\":\"1\"}]\n",
+		},
+		"json file not an array": {
+			existing: "{\"id\": \"1\"}\n",
+			added:    "[]\n",
+			format:   output.FormatJSON,
+			wantErr:  output.ErrAppendMismatch,
+		},
+		"jsonl lines appended": {
+			existing: "{\"id\":\"1\"}\n",
+			added:    "{\"id\":\"2\"}\n",
+			format:   output.FormatJSONL,
+			want:     "{\"id\":\"1\"}\n{\"id\":\"2\"}\n",
+		},
+		"jsonl without trailing newline": {
+			existing: "{\"id\":\"1\"}",
+			added:    "{\"id\":\"2\"}\n",
+			format:   output.FormatJSONL,
+			want:     "{\"id\":\"1\"}\n{\"id\":\"2\"}\n",
+		},
+		"csv header written once": {
+			existing: "ID,Name\n1,folders/1\n",
+			added:    "ID,Name\n2,folders/2\n",
+			format:   output.FormatCSV,
+			want:     "ID,Name\n1,folders/1\n2,folders/2\n",
+		},
+		"csv into empty file keeps header": {
+			added:  "ID,Name\n2,folders/2\n",
+			format: output.FormatCSV,
+			want:   "ID,Name\n2,folders/2\n",
+		},
+		"csv with no new output": {
+			existing: "ID,Name\n1,folders/1\n",
+			format:   output.FormatCSV,
+			want:     "ID,Name\n1,folders/1\n",
+		},
+		"csv header differs": {
+			existing: "ID,Name\n1,folders/1\n",
+			added:    "ID,State\n2,ACTIVE\n",
+			format:   output.FormatCSV,
+			wantErr:  output.ErrAppendMismatch,
+		},
+		"id lines appended": {
+			existing: "1\n",
+			added:    "2\n",
+			format:   output.FormatID,
+			want:     "1\n2\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := output.AppendOutput([]byte(tt.existing), []byte(tt.added), tt.format, tt.compact)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
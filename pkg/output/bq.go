@@ -0,0 +1,117 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// formatBigQuery writes one JSON object per line for loading into BigQuery: timestamps are RFC3339 strings,
+// a TIMESTAMP-compatible encoding, and unset timestamps are left out instead of written as the zero time.
+func (f *Formatter) formatBigQuery(resources []Resource) error {
+	for _, resource := range resources {
+		if err := f.writeBigQueryRow(resource); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// streamBigQuery writes BigQuery rows as resources arrive, producing the same bytes as formatBigQuery.
+func (f *Formatter) streamBigQuery(resources <-chan Resource) error {
+	for resource := range resources {
+		if err := f.writeBigQueryRow(resource); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeBigQueryRow writes the BigQuery row of a resource followed by a newline.
+func (f *Formatter) writeBigQueryRow(resource Resource) error {
+	row, err := f.bigQueryRow(resource)
+	if err != nil {
+		return err
+	}
+	if _, err := f.writer.Write(append(row, '\n')); err != nil {
+		return fmt.Errorf("failed to write BigQuery row: %w", err)
+	}
+
+	return nil
+}
+
+// bigQueryRow encodes the JSON object of a resource with its timestamp fields rewritten as RFC3339
+// strings and unset timestamps removed, keeping the key order of the JSON output.
+func (f *Formatter) bigQueryRow(resource Resource) ([]byte, error) {
+	base, err := json.Marshal(f.jsonResource(resource))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode resource: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(base))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, ErrNonObjectResource
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for written := 0; dec.More(); {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode resource: %w", err)
+		}
+		key, _ := tok.(string)
+
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, fmt.Errorf("failed to decode resource field %s: %w", key, err)
+		}
+		if timeFields[key] {
+			var ok bool
+			if value, ok = bigQueryTimestamp(value); !ok {
+				continue
+			}
+		}
+
+		encodedKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode field name %s: %w", key, err)
+		}
+		if written > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(encodedKey)
+		buf.WriteByte(':')
+		buf.Write(value)
+		written++
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// bigQueryTimestamp re-encodes a timestamp value as an RFC3339 string. It reports false for unset
+// timestamps, which are null or the zero time, and keeps values that are not timestamps unchanged.
+func bigQueryTimestamp(value json.RawMessage) (json.RawMessage, bool) {
+	if string(value) == "null" {
+		return nil, false
+	}
+
+	var t time.Time
+	if err := json.Unmarshal(value, &t); err != nil {
+		return value, true
+	}
+	if t.IsZero() {
+		return nil, false
+	}
+
+	encoded, err := json.Marshal(t.Format(time.RFC3339Nano))
+	if err != nil {
+		return value, true
+	}
+
+	return encoded, true
+}
@@ -0,0 +1,84 @@
+package output_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatBigQuery(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	updated := time.Date(2024, 6, 7, 8, 9, 10, 500000000, time.UTC)
+	resources := output.FoldersToResources([]*folders.Folder{
+		{ID: "1", Name: "folders/1", DisplayName: "Never updated", Parent: "organizations/9", State: "ACTIVE",
+			CreateTime: created},
+		{ID: "2", Name: "folders/2", DisplayName: "Updated", Parent: "folders/1", State: "ACTIVE",
+			CreateTime: created, UpdateTime: updated, Etag: "abc"},
+		{ID: "3", Name: "folders/3", DisplayName: "No times", Parent: "folders/1", State: "ACTIVE"},
+	})
+
+	tests := map[string]struct {
+		location *time.Location
+		want     string
+	}{
+		"utc": {
+			want: `{"id":"1","name":"folders/1","display_name":"Never updated","parent":"organizations/9",` +
+				`"state":"ACTIVE","create_time":"2024-01-02T03:04:05Z"}` + "\n" +
+				`{"id":"2","name":"folders/2","display_name":"Updated","parent":"folders/1","state":"ACTIVE",` +
+				`"create_time":"2024-01-02T03:04:05Z","update_time":"2024-06-07T08:09:10.5Z","etag":"abc"}` + "\n" +
+				`{"id":"3","name":"folders/3","display_name":"No times","parent":"folders/1","state":"ACTIVE"}` + "\n",
+		},
+		"timezone": {
+			location: time.FixedZone("UTC+2", 2*60*60),
+			want: `{"id":"1","name":"folders/1","display_name":"Never updated","parent":"organizations/9",` +
+				`"state":"ACTIVE","create_time":"2024-01-02T05:04:05+02:00"}` + "\n" +
+				`{"id":"2","name":"folders/2","display_name":"Updated","parent":"folders/1","state":"ACTIVE",` +
+				`"create_time":"2024-01-02T05:04:05+02:00","update_time":"2024-06-07T10:09:10.5+02:00","etag":"abc"}` +
+				"\n" +
+				`{"id":"3","name":"folders/3","display_name":"No times","parent":"folders/1","state":"ACTIVE"}` + "\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			formatter := output.NewFormatter(&stdout, &bytes.Buffer{}, false, output.ResourceTypeFolders)
+			formatter.SetLocation(tt.location)
+			require.NoError(t, formatter.Format(resources, output.FormatBigQuery, output.FolderHeaders()))
+			assert.Equal(t, tt.want, stdout.String())
+
+			// streamed output matches
+			ch := make(chan output.Resource, len(resources))
+			for _, resource := range resources {
+				ch <- resource
+			}
+			close(ch)
+			var streamed bytes.Buffer
+			formatter = output.NewFormatter(&streamed, &bytes.Buffer{}, false, output.ResourceTypeFolders)
+			formatter.SetLocation(tt.location)
+			require.NoError(t, formatter.FormatStream(ch, output.FormatBigQuery, output.FolderHeaders()))
+			assert.Equal(t, tt.want, streamed.String())
+		})
+	}
+}
+
+func TestFormatBigQuerySelectedFields(t *testing.T) {
+	resources := output.FoldersToResources([]*folders.Folder{{ID: "1", DisplayName: "Engineering"}})
+	selected, headers, err := output.SelectFields(resources, output.FolderHeaders(),
+		[]string{"id", "update_time", "display_name"})
+	require.NoError(t, err)
+
+	var stdout bytes.Buffer
+	formatter := output.NewFormatter(&stdout, &bytes.Buffer{}, false, output.ResourceTypeFolders)
+	require.NoError(t, formatter.Format(selected, output.FormatBigQuery, headers))
+
+	var row map[string]any
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &row))
+	assert.Equal(t, map[string]any{"id": "1", "display_name": "Engineering"}, row)
+}
@@ -0,0 +1,153 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrNonObjectResource is returned when a resource cannot be extended with computed columns
+// because it does not encode as a JSON object.
+var ErrNonObjectResource = errors.New("resource does not encode as a JSON object")
+
+// annotatedResource wraps a resource with computed columns appended to its output.
+type annotatedResource struct {
+	Resource
+
+	columns []Column
+}
+
+// Unwrap returns the wrapped resource.
+func (a *annotatedResource) Unwrap() Resource {
+	return a.Resource
+}
+
+// TableRow returns the wrapped resource's row followed by the computed column values.
+func (a *annotatedResource) TableRow() []interface{} {
+	row := a.Resource.TableRow()
+	for _, column := range a.columns {
+		row = append(row, column.Value(a.Resource))
+	}
+
+	return row
+}
+
+// MarshalJSON encodes the wrapped resource with the computed columns added as extra fields.
+func (a *annotatedResource) MarshalJSON() ([]byte, error) {
+	base, err := json.Marshal(a.Resource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode resource: %w", err)
+	}
+	if len(base) < 2 || base[0] != '{' || base[len(base)-1] != '}' {
+		return nil, ErrNonObjectResource
+	}
+
+	var buf bytes.Buffer
+	buf.Write(base[:len(base)-1])
+	for i, column := range a.columns {
+		value, err := json.Marshal(column.Value(a.Resource))
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode column %s: %w", column.Field, err)
+		}
+		key, err := json.Marshal(column.Field)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode column name %s: %w", column.Field, err)
+		}
+
+		// the base object is "{}" when it has no fields of its own
+		if i > 0 || len(base) > 2 {
+			buf.WriteByte(',')
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// parentOf returns the parent of a resource that has one, looking through the wrappers added for
+// field selection, computed columns, and time zones, or "" for resources without a parent.
+func parentOf(resource Resource) string {
+	if p, ok := unwrapAs[interface{ GetParent() string }](resource); ok {
+		return p.GetParent()
+	}
+
+	return ""
+}
+
+// etagOf returns the etag of a resource that has one, looking through resource wrappers, or "" for
+// resources without an etag.
+func etagOf(resource Resource) string {
+	if e, ok := unwrapAs[interface{ GetEtag() string }](resource); ok {
+		return e.GetEtag()
+	}
+
+	return ""
+}
+
+// unwrapAs returns the first resource in the chain of wrappers around resource, starting with
+// resource itself, that implements T.
+func unwrapAs[T any](resource Resource) (T, bool) {
+	for {
+		if target, ok := resource.(T); ok {
+			return target, true
+		}
+		wrapper, ok := resource.(interface{ Unwrap() Resource })
+		if !ok {
+			var zero T
+
+			return zero, false
+		}
+		resource = wrapper.Unwrap()
+	}
+}
+
+// Column describes a computed column appended to resource output.
+type Column struct {
+	Header string                     // Header is the column title in table and CSV output
+	Field  string                     // Field is the key of the value in JSON output
+	Value  func(Resource) interface{} // Value computes the column value for a resource
+}
+
+// WithColumns wraps resources so that the given computed columns are appended to their
+// table rows and JSON objects. It returns the wrapped resources and the extended headers.
+func WithColumns(resources []Resource, headers []string, columns ...Column) ([]Resource, []string) {
+	if len(columns) == 0 {
+		return resources, headers
+	}
+
+	wrapped := make([]Resource, len(resources))
+	for i, resource := range resources {
+		wrapped[i] = Annotate(resource, columns...)
+	}
+
+	return wrapped, ColumnHeaders(headers, columns...)
+}
+
+// Annotate wraps a single resource so that the computed columns are appended to its table row and
+// JSON object, for output that handles resources one at a time. No columns returns it unchanged.
+func Annotate(resource Resource, columns ...Column) Resource {
+	if len(columns) == 0 {
+		return resource
+	}
+
+	return &annotatedResource{Resource: resource, columns: columns}
+}
+
+// ColumnHeaders returns headers extended with the headers of the computed columns.
+func ColumnHeaders(headers []string, columns ...Column) []string {
+	if len(columns) == 0 {
+		return headers
+	}
+
+	extended := make([]string, 0, len(headers)+len(columns))
+	extended = append(extended, headers...)
+	for _, column := range columns {
+		extended = append(extended, column.Header)
+	}
+
+	return extended
+}
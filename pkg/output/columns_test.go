@@ -0,0 +1,96 @@
+package output_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithColumns(t *testing.T) {
+	flagged := map[string]bool{"123": true}
+	column := output.Column{
+		Header: "Flagged",
+		Field:  "flagged",
+		Value: func(r output.Resource) interface{} {
+			return flagged[r.GetID()]
+		},
+	}
+
+	tests := map[string]struct {
+		columns     []output.Column
+		wantHeaders []string
+		wantRowLen  int
+	}{
+		"appends computed column": {
+			columns:     []output.Column{column},
+			wantHeaders: []string{"ID", "Name", "Flagged"},
+			wantRowLen:  6,
+		},
+		"returns inputs unchanged without columns": {
+			columns:     nil,
+			wantHeaders: []string{"ID", "Name"},
+			wantRowLen:  5,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resources, headers := output.WithColumns(createTestResources(), []string{"ID", "Name"}, tt.columns...)
+
+			assert.Equal(t, tt.wantHeaders, headers)
+			require.Len(t, resources, 2)
+			assert.Equal(t, "123", resources[0].GetID())
+
+			row := resources[0].TableRow()
+			require.Len(t, row, tt.wantRowLen)
+			if len(tt.columns) > 0 {
+				assert.Equal(t, true, row[len(row)-1])
+				assert.Equal(t, false, resources[1].TableRow()[len(row)-1])
+			}
+		})
+	}
+}
+
+func TestWithColumns_JSON(t *testing.T) {
+	depth := output.Column{
+		Header: "Depth",
+		Field:  "depth",
+		Value:  func(output.Resource) interface{} { return 2 },
+	}
+
+	tests := map[string]struct {
+		resources []output.Resource
+		want      map[string]interface{}
+	}{
+		"adds field to folder object": {
+			resources: output.FoldersToResources([]*folders.Folder{{ID: "123", DisplayName: "Test"}}),
+			want:      map[string]interface{}{"id": "123", "display_name": "Test", "depth": float64(2)},
+		},
+		"adds field to resource without exported fields": {
+			resources: createTestResources()[:1],
+			want:      map[string]interface{}{"depth": float64(2)},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resources, headers := output.WithColumns(tt.resources, []string{"ID"}, depth)
+
+			var buf bytes.Buffer
+			formatter := output.NewFormatterWithType(&buf, false, "")
+			require.NoError(t, formatter.Format(resources, output.FormatJSON, headers))
+
+			var result []map[string]interface{}
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+			require.Len(t, result, 1)
+			for key, value := range tt.want {
+				assert.Equal(t, value, result[0][key], key)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// ErrInvalidCountBy is returned when resources are counted by an unsupported field.
+var ErrInvalidCountBy = errors.New("invalid count-by field")
+
+// countField is a field that resources can be counted by.
+type countField struct {
+	header   string
+	accessor func(Resource) string
+}
+
+// countFields maps the fields accepted by --count-by to their column header and accessor.
+var countFields = map[string]countField{
+	"state":       {header: "State", accessor: func(r Resource) string { return r.GetState() }},
+	"parent":      {header: "Parent", accessor: parentOf},
+	"parent_type": {header: "Parent Type", accessor: func(r Resource) string { return folders.ParentType(parentOf(r)) }},
+}
+
+// ParseCountBy validates a count-by field name, matched case-insensitively, and returns its canonical
+// lowercase form. An empty name disables counting.
+func ParseCountBy(name string) (string, error) {
+	field := strings.ToLower(name)
+	if _, ok := countFields[field]; field != "" && !ok {
+		return "", fmt.Errorf("%w: %s (supported: state, parent, parent_type)", ErrInvalidCountBy, name)
+	}
+
+	return field, nil
+}
+
+// CountBy returns the number of resources with each value of field, which must be accepted by
+// ParseCountBy. Resources without a value are counted under the empty string.
+func CountBy(resources []Resource, field string) map[string]int {
+	counts := make(map[string]int)
+	count, ok := countFields[field]
+	if !ok {
+		return counts
+	}
+
+	for _, resource := range resources {
+		counts[count.accessor(resource)]++
+	}
+
+	return counts
+}
+
+// FieldCount is the number of resources sharing one value of a count-by field.
+type FieldCount struct {
+	Field string // Field is the count-by field, e.g. "state"
+	Value string // Value is the field's value, empty for resources without one
+	Count int    // Count is the number of resources with the value
+}
+
+// GetID returns the counted value.
+func (c *FieldCount) GetID() string {
+	return c.Value
+}
+
+// GetName returns the counted value.
+func (c *FieldCount) GetName() string {
+	return c.Value
+}
+
+// GetDisplayName returns the counted value.
+func (c *FieldCount) GetDisplayName() string {
+	return c.Value
+}
+
+// GetState returns an empty string since counts have no lifecycle state.
+func (c *FieldCount) GetState() string {
+	return ""
+}
+
+// GetCreateTime returns the zero time since counts have no timestamps.
+func (c *FieldCount) GetCreateTime() time.Time {
+	return time.Time{}
+}
+
+// GetUpdateTime returns the zero time since counts have no timestamps.
+func (c *FieldCount) GetUpdateTime() time.Time {
+	return time.Time{}
+}
+
+// TableRow returns the value and its count, labelling the missing value like grouped tables do.
+func (c *FieldCount) TableRow() []interface{} {
+	value := c.Value
+	if value == "" {
+		value = noGroupKey
+	}
+
+	return table.Row{value, c.Count}
+}
+
+// MarshalJSON encodes the count as an object keyed by the field name, e.g. {"state":"ACTIVE","count":42}.
+func (c *FieldCount) MarshalJSON() ([]byte, error) {
+	value, err := json.Marshal(c.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s count: %w", c.Field, err)
+	}
+
+	return []byte(`{` + strconv.Quote(c.Field) + `:` + string(value) + `,"count":` + strconv.Itoa(c.Count) + `}`), nil
+}
+
+// CountResources counts resources by field, which must be accepted by ParseCountBy, and returns one
+// FieldCount per value, sorted by value, along with the headers of the count table.
+func CountResources(resources []Resource, field string) ([]Resource, []string) {
+	counts := CountBy(resources, field)
+
+	values := make([]string, 0, len(counts))
+	for value := range counts {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+
+	rows := make([]Resource, 0, len(values))
+	for _, value := range values {
+		rows = append(rows, &FieldCount{Field: field, Value: value, Count: counts[value]})
+	}
+
+	return rows, []string{countFields[field].header, "Count"}
+}
@@ -0,0 +1,115 @@
+package output_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCountBy(t *testing.T) {
+	tests := map[string]struct {
+		name    string
+		want    string
+		wantErr error
+	}{
+		"empty disables counting":      {name: "", want: ""},
+		"state":                        {name: "state", want: "state"},
+		"parent type case-insensitive": {name: "Parent_Type", want: "parent_type"},
+		"unknown field":                {name: "display_name", wantErr: output.ErrInvalidCountBy},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := output.ParseCountBy(tt.name)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCountBy(t *testing.T) {
+	resources := output.FoldersToResources([]*folders.Folder{
+		{ID: "1", Parent: "organizations/9", State: "ACTIVE"},
+		{ID: "2", Parent: "folders/5", State: "DELETE_REQUESTED"},
+		{ID: "3", Parent: "organizations/9", State: "ACTIVE"},
+		{ID: "4", State: "ACTIVE"},
+	})
+
+	tests := map[string]struct {
+		field string
+		want  map[string]int
+	}{
+		"state": {
+			field: "state",
+			want:  map[string]int{"ACTIVE": 3, "DELETE_REQUESTED": 1},
+		},
+		"parent": {
+			field: "parent",
+			want:  map[string]int{"organizations/9": 2, "folders/5": 1, "": 1},
+		},
+		"parent type": {
+			field: "parent_type",
+			want: map[string]int{
+				folders.ParentTypeOrganization: 2,
+				folders.ParentTypeFolder:       1,
+				folders.ParentTypeUnknown:      1,
+			},
+		},
+		"unknown field counts nothing": {
+			field: "display_name",
+			want:  map[string]int{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, output.CountBy(resources, tt.field))
+		})
+	}
+}
+
+func TestCountResourcesFormats(t *testing.T) {
+	resources := output.FoldersToResources([]*folders.Folder{
+		{ID: "1", Parent: "organizations/9", State: "ACTIVE"},
+		{ID: "2", State: "DELETE_REQUESTED"},
+		{ID: "3", Parent: "organizations/9", State: "ACTIVE"},
+	})
+
+	tests := map[string]struct {
+		field  string
+		format output.Format
+		want   string
+	}{
+		"state as compact json": {
+			field:  "state",
+			format: output.FormatJSONL,
+			want:   "{\"state\":\"ACTIVE\",\"count\":2}\n{\"state\":\"DELETE_REQUESTED\",\"count\":1}\n",
+		},
+		"parent as csv": {
+			field:  "parent",
+			format: output.FormatCSV,
+			want:   "Parent,Count\n(none),1\norganizations/9,2\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			counts, headers := output.CountResources(resources, tt.field)
+
+			var buf bytes.Buffer
+			err := output.NewFormatter(&buf, io.Discard, false, "folders").Format(counts, tt.format, headers)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package output
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SetEnvNames names the variables of env output after the display names of the resources instead of
+// numbering them.
+func (f *Formatter) SetEnvNames(byName bool) {
+	f.envNames = byName
+}
+
+// formatEnv writes one export statement per resource assigning its ID, such as "export FOLDER_1=123", so
+// that the output can be sourced by a shell script. Variables are numbered in output order, or named after
+// the display names with SetEnvNames.
+func (f *Formatter) formatEnv(resources []Resource) error {
+	names := EnvVarNames(EnvPrefix(f.resourceLabel()), resources, f.envNames)
+	for i, resource := range resources {
+		if _, err := fmt.Fprintf(f.writer, "export %s=%s\n", names[i], shellQuote(f.resourceID(resource))); err != nil {
+			return fmt.Errorf("failed to write environment variable: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// EnvPrefix returns the variable name prefix for a plural resource type, e.g. "FOLDER" for "folders".
+func EnvPrefix(resourceType string) string {
+	prefix := SanitizeEnvName(strings.TrimSuffix(resourceType, "s"))
+	if prefix == "" {
+		return "RESOURCE"
+	}
+
+	return prefix
+}
+
+// SanitizeEnvName turns s into the body of a valid shell variable name: letters are upper-cased, every
+// run of other characters than ASCII letters and digits becomes a single underscore, and leading and
+// trailing underscores are removed. The result may be empty, and may start with a digit, so it is meant
+// to follow a prefix.
+func SanitizeEnvName(s string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToUpper(s) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			underscore = false
+
+			continue
+		}
+		if !underscore {
+			b.WriteByte('_')
+			underscore = true
+		}
+	}
+
+	return strings.Trim(b.String(), "_")
+}
+
+// EnvVarNames returns a unique variable name for each resource, starting with prefix: numbered from 1 in
+// order, as PREFIX_1, or, when byName is set, named after the sanitized display name, as PREFIX_FINANCE.
+// Resources whose display name sanitizes to nothing keep their number. A name already taken gets the
+// first free suffix from _2 on, so that no assignment overwrites another.
+func EnvVarNames(prefix string, resources []Resource, byName bool) []string {
+	names := make([]string, len(resources))
+	taken := make(map[string]bool, len(resources))
+	for i, resource := range resources {
+		body := strconv.Itoa(i + 1)
+		if byName {
+			if sanitized := SanitizeEnvName(resource.GetDisplayName()); sanitized != "" {
+				body = sanitized
+			}
+		}
+
+		name := prefix + "_" + body
+		for n := 2; taken[name]; n++ {
+			name = prefix + "_" + body + "_" + strconv.Itoa(n)
+		}
+		taken[name] = true
+		names[i] = name
+	}
+
+	return names
+}
+
+// shellQuote returns value as a shell word: unchanged when it only holds characters that need no
+// quoting, such as numeric IDs and resource names, and single-quoted otherwise.
+func shellQuote(value string) string {
+	safe := value != "" && strings.IndexFunc(value, func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') && !strings.ContainsRune("_-./:@", r)
+	}) < 0
+	if safe {
+		return value
+	}
+
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,180 @@
+package output_test
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// validEnvName matches the names POSIX shells accept as variable names.
+var validEnvName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func TestSanitizeEnvName(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		want  string
+	}{
+		"letters are upper-cased":           {input: "finance", want: "FINANCE"},
+		"spaces become underscores":         {input: "Data Platform", want: "DATA_PLATFORM"},
+		"runs of symbols collapse":          {input: "prod -- eu/west", want: "PROD_EU_WEST"},
+		"edges are trimmed":                 {input: "  (shared)  ", want: "SHARED"},
+		"digits are kept":                   {input: "team-42", want: "TEAM_42"},
+		"non-ASCII letters are replaced":    {input: "Café Ops", want: "CAF_OPS"},
+		"only symbols sanitize to nothing":  {input: "---", want: ""},
+		"empty input sanitizes to nothing":  {input: "", want: ""},
+		"existing underscores are squeezed": {input: "a__b", want: "A_B"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, output.SanitizeEnvName(tt.input))
+		})
+	}
+}
+
+func TestEnvPrefix(t *testing.T) {
+	tests := map[string]struct {
+		resourceType string
+		want         string
+	}{
+		"folders":       {resourceType: "folders", want: "FOLDER"},
+		"organizations": {resourceType: "organizations", want: "ORGANIZATION"},
+		"spaces":        {resourceType: "IAM bindings", want: "IAM_BINDING"},
+		"empty":         {resourceType: "", want: "RESOURCE"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, output.EnvPrefix(tt.resourceType))
+		})
+	}
+}
+
+func TestEnvVarNames(t *testing.T) {
+	tests := map[string]struct {
+		displayNames []string
+		byName       bool
+		want         []string
+	}{
+		"numbered in order": {
+			displayNames: []string{"Finance", "Finance"},
+			want:         []string{"FOLDER_1", "FOLDER_2"},
+		},
+		"named after display names": {
+			displayNames: []string{"Finance", "Data Platform"},
+			byName:       true,
+			want:         []string{"FOLDER_FINANCE", "FOLDER_DATA_PLATFORM"},
+		},
+		"collisions get suffixes": {
+			displayNames: []string{"Finance", "finance", "FINANCE!"},
+			byName:       true,
+			want:         []string{"FOLDER_FINANCE", "FOLDER_FINANCE_2", "FOLDER_FINANCE_3"},
+		},
+		"suffix skips names already taken": {
+			displayNames: []string{"Finance 2", "Finance", "Finance"},
+			byName:       true,
+			want:         []string{"FOLDER_FINANCE_2", "FOLDER_FINANCE", "FOLDER_FINANCE_3"},
+		},
+		"empty names fall back to numbers": {
+			displayNames: []string{"***", "", "Ops"},
+			byName:       true,
+			want:         []string{"FOLDER_1", "FOLDER_2", "FOLDER_OPS"},
+		},
+		"number fallback avoids display names": {
+			displayNames: []string{"1", "***"},
+			byName:       true,
+			want:         []string{"FOLDER_1", "FOLDER_2"},
+		},
+		"no resources": {
+			want: []string{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			folderList := make([]*folders.Folder, len(tt.displayNames))
+			for i, displayName := range tt.displayNames {
+				folderList[i] = &folders.Folder{ID: "1", DisplayName: displayName}
+			}
+
+			names := output.EnvVarNames("FOLDER", output.FoldersToResources(folderList), tt.byName)
+
+			assert.Equal(t, tt.want, names)
+			for _, n := range names {
+				assert.Regexp(t, validEnvName, n)
+			}
+		})
+	}
+}
+
+func TestFormatterEnv(t *testing.T) {
+	folderList := []*folders.Folder{
+		{ID: "111", Name: "folders/111", DisplayName: "Finance"},
+		{ID: "222", Name: "folders/222", DisplayName: "finance"},
+		{ID: "it's", Name: "folders/it's", DisplayName: "9 lives"},
+	}
+
+	tests := map[string]struct {
+		byName bool
+		want   string
+	}{
+		"numbered": {
+			want: "export FOLDER_1=111\nexport FOLDER_2=222\nexport FOLDER_3='it'\\''s'\n",
+		},
+		"by name": {
+			byName: true,
+			want:   "export FOLDER_FINANCE=111\nexport FOLDER_FINANCE_2=222\nexport FOLDER_9_LIVES='it'\\''s'\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			formatter := output.NewFormatter(&buf, &bytes.Buffer{}, false, "folders")
+			formatter.SetEnvNames(tt.byName)
+
+			require.NoError(t, formatter.Format(output.FoldersToResources(folderList), output.FormatEnv, nil))
+
+			assert.Equal(t, tt.want, buf.String())
+			for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+				assignment, ok := strings.CutPrefix(line, "export ")
+				require.True(t, ok, line)
+				varName, _, ok := strings.Cut(assignment, "=")
+				require.True(t, ok, line)
+				assert.Regexp(t, validEnvName, varName)
+			}
+		})
+	}
+}
+
+func TestFormatterEnvStream(t *testing.T) {
+	resources := output.FoldersToResources([]*folders.Folder{
+		{ID: "111", DisplayName: "Ops"},
+		{ID: "222", DisplayName: "Ops"},
+	})
+
+	var want bytes.Buffer
+	formatter := output.NewFormatter(&want, &bytes.Buffer{}, false, "folders")
+	formatter.SetEnvNames(true)
+	require.NoError(t, formatter.Format(resources, output.FormatEnv, nil))
+
+	ch := make(chan output.Resource, len(resources))
+	for _, resource := range resources {
+		ch <- resource
+	}
+	close(ch)
+
+	var got bytes.Buffer
+	formatter = output.NewFormatter(&got, &bytes.Buffer{}, false, "folders")
+	formatter.SetEnvNames(true)
+	require.NoError(t, formatter.FormatStream(ch, output.FormatEnv, nil))
+
+	assert.Equal(t, want.String(), got.String())
+	assert.Equal(t, "export FOLDER_OPS=111\nexport FOLDER_OPS_2=222\n", got.String())
+}
@@ -0,0 +1,27 @@
+package output
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// extensionFormats maps output file extensions to the formats they select.
+var extensionFormats = map[string]Format{
+	".json":  FormatJSON,
+	".jsonl": FormatJSONL,
+	".csv":   FormatCSV,
+	".yaml":  "yaml",
+	".yml":   "yaml",
+	".tsv":   "tsv",
+	".dot":   FormatDOT,
+	".gv":    FormatDOT,
+	".mmd":   FormatMermaid,
+}
+
+// FormatFromExtension returns the format selected by the extension of an output file path, matched
+// case-insensitively. The format is not validated, so callers should pass it to ParseFormat.
+func FormatFromExtension(path string) (Format, bool) {
+	format, ok := extensionFormats[strings.ToLower(filepath.Ext(path))]
+
+	return format, ok
+}
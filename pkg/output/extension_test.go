@@ -0,0 +1,37 @@
+package output_test
+
+import (
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatFromExtension(t *testing.T) {
+	tests := map[string]struct {
+		path   string
+		want   output.Format
+		wantOK bool
+	}{
+		"json":           {path: "folders.json", want: output.FormatJSON, wantOK: true},
+		"jsonl":          {path: "out/folders.jsonl", want: output.FormatJSONL, wantOK: true},
+		"csv":            {path: "folders.csv", want: output.FormatCSV, wantOK: true},
+		"yaml":           {path: "folders.yaml", want: "yaml", wantOK: true},
+		"yml":            {path: "folders.yml", want: "yaml", wantOK: true},
+		"tsv":            {path: "folders.tsv", want: "tsv", wantOK: true},
+		"dot":            {path: "graph.dot", want: output.FormatDOT, wantOK: true},
+		"mermaid":        {path: "graph.mmd", want: output.FormatMermaid, wantOK: true},
+		"upper case":     {path: "FOLDERS.CSV", want: output.FormatCSV, wantOK: true},
+		"unknown":        {path: "folders.txt"},
+		"no extension":   {path: "folders"},
+		"dotted dirname": {path: "out.json/folders"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := output.FormatFromExtension(tt.path)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
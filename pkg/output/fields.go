@@ -0,0 +1,222 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrUnknownField is returned when a selected field does not exist for the resource type.
+var ErrUnknownField = errors.New("unknown field")
+
+// FieldName returns the field name of a table header, its snake_case form, which is also the
+// resource's JSON key (e.g. "Display Name" becomes "display_name").
+func FieldName(header string) string {
+	return strings.ToLower(strings.Join(strings.Fields(header), "_"))
+}
+
+// ParseFieldList splits a comma- or newline-separated list of field names, trimming whitespace
+// and skipping blank entries.
+func ParseFieldList(list string) []string {
+	var fields []string
+	for _, line := range strings.Split(list, "\n") {
+		for _, field := range strings.Split(line, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				fields = append(fields, field)
+			}
+		}
+	}
+
+	return fields
+}
+
+// AllFields is the field name that selects every available field of a resource type, in order.
+const AllFields = "all"
+
+// extraFields are selectable fields that are not part of any default header set.
+var extraFields = []Column{
+	{Header: "Name", Field: "name", Value: func(r Resource) interface{} { return r.GetName() }},
+}
+
+// selectedField is one field picked by a FieldSelector.
+type selectedField struct {
+	key   string                     // key is the field name and JSON key
+	index int                        // index is the position in the default table row, or -1
+	value func(Resource) interface{} // value computes fields outside the default row
+}
+
+// FieldSelector restricts resource output to a subset of fields, in the order they were selected.
+type FieldSelector struct {
+	fields  []selectedField
+	headers []string
+}
+
+// NewFieldSelector builds a selector for the given fields of a resource type with the given headers.
+// Besides the fields of the headers, the full resource name can be selected as "name", along with
+// the computed fields in extra, which are not part of the default output, and AllFields selects the
+// fields of the headers followed by every other one. Field names are matched case-insensitively and
+// without underscores, so "displayName" selects "display_name". Unknown field names are reported
+// together with the available ones.
+func NewFieldSelector(headers, fields []string, extra ...Column) (*FieldSelector, error) {
+	computed := append(slices.Clone(extraFields), extra...)
+	available := make([]string, 0, len(headers)+len(computed))
+	lookup := make(map[string]selectedField, len(headers)+len(computed))
+	titles := make(map[string]string, len(headers)+len(computed))
+	for i, header := range headers {
+		key := FieldName(header)
+		available = append(available, key)
+		lookup[normalizeField(key)] = selectedField{key: key, index: i}
+		titles[key] = header
+	}
+	for _, column := range computed {
+		if _, ok := lookup[normalizeField(column.Field)]; ok {
+			continue
+		}
+		available = append(available, column.Field)
+		lookup[normalizeField(column.Field)] = selectedField{key: column.Field, index: -1, value: column.Value}
+		titles[column.Field] = column.Header
+	}
+
+	selector := &FieldSelector{}
+	for _, name := range expandAllFields(fields, available) {
+		field, ok := lookup[normalizeField(name)]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q (available: %s)", ErrUnknownField, name, strings.Join(available, ", "))
+		}
+		selector.fields = append(selector.fields, field)
+		selector.headers = append(selector.headers, titles[field.key])
+	}
+
+	return selector, nil
+}
+
+// Headers returns the headers of the selected fields.
+func (s *FieldSelector) Headers() []string {
+	return append([]string(nil), s.headers...)
+}
+
+// Select wraps a resource so that its table row and JSON object contain only the selected fields.
+func (s *FieldSelector) Select(resource Resource) Resource {
+	return &selectedResource{Resource: resource, selector: s}
+}
+
+// SelectFields restricts resources to the given fields, which may include the computed fields in extra,
+// returning the wrapped resources and the headers of the selected fields. An empty field list returns
+// the resources unchanged.
+func SelectFields(resources []Resource, headers, fields []string, extra ...Column) ([]Resource, []string, error) {
+	if len(fields) == 0 {
+		return resources, headers, nil
+	}
+
+	selector, err := NewFieldSelector(headers, fields, extra...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	selected := make([]Resource, len(resources))
+	for i, resource := range resources {
+		selected[i] = selector.Select(resource)
+	}
+
+	return selected, selector.Headers(), nil
+}
+
+// selectedResource wraps a resource and exposes only the fields picked by its selector.
+type selectedResource struct {
+	Resource
+
+	selector *FieldSelector
+}
+
+// Unwrap returns the wrapped resource.
+func (s *selectedResource) Unwrap() Resource {
+	return s.Resource
+}
+
+// TableRow returns the selected values of the wrapped resource's row.
+func (s *selectedResource) TableRow() []interface{} {
+	full := s.Resource.TableRow()
+	row := make([]interface{}, len(s.selector.fields))
+	for i, field := range s.selector.fields {
+		switch {
+		case field.value != nil:
+			row[i] = field.value(s.Resource)
+		case field.index < len(full):
+			row[i] = full[field.index]
+		}
+	}
+
+	return row
+}
+
+// MarshalJSON encodes the selected keys of the wrapped resource's JSON object, in selection order.
+// Keys the resource omits from its own encoding are omitted here too, except for fields outside the
+// default row, which are computed when the resource does not encode them.
+func (s *selectedResource) MarshalJSON() ([]byte, error) {
+	base, err := json.Marshal(s.Resource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode resource: %w", err)
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(base, &object); err != nil {
+		return nil, ErrNonObjectResource
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	written := 0
+	for _, field := range s.selector.fields {
+		value, ok := object[field.key]
+		if !ok && field.value != nil {
+			if value, err = json.Marshal(field.value(s.Resource)); err != nil {
+				return nil, fmt.Errorf("failed to encode field %s: %w", field.key, err)
+			}
+			ok = true
+		}
+		if !ok {
+			continue
+		}
+		encodedKey, err := json.Marshal(field.key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode field name %s: %w", field.key, err)
+		}
+
+		if written > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(encodedKey)
+		buf.WriteByte(':')
+		buf.Write(value)
+		written++
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// expandAllFields replaces AllFields in fields with the available field names.
+func expandAllFields(fields, available []string) []string {
+	if !slices.ContainsFunc(fields, func(name string) bool { return strings.EqualFold(name, AllFields) }) {
+		return fields
+	}
+
+	expanded := make([]string, 0, len(fields)+len(available))
+	for _, name := range fields {
+		if strings.EqualFold(name, AllFields) {
+			expanded = append(expanded, available...)
+		} else {
+			expanded = append(expanded, name)
+		}
+	}
+
+	return expanded
+}
+
+// normalizeField folds a field name for matching.
+func normalizeField(field string) string {
+	return strings.ReplaceAll(strings.ToLower(field), "_", "")
+}
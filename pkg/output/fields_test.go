@@ -0,0 +1,167 @@
+package output_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/organizations"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/andreygrechin/gcphelper/pkg/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFieldList(t *testing.T) {
+	tests := map[string]struct {
+		list string
+		want []string
+	}{
+		"comma-separated":           {list: "id,display_name", want: []string{"id", "display_name"}},
+		"newline-separated":         {list: "id\ndisplay_name\n", want: []string{"id", "display_name"}},
+		"mixed with blanks":         {list: "  id , \n\n display_name,state\r\n\t\n", want: []string{"id", "display_name", "state"}},
+		"empty":                     {list: " \n , \n", want: nil},
+		"trailing comma on line":    {list: "id,\nstate", want: []string{"id", "state"}},
+		"header name with spaces":   {list: "Display Name", want: []string{"Display Name"}},
+		"single field without eol":  {list: "parent", want: []string{"parent"}},
+		"windows line endings only": {list: "id\r\nparent\r\n", want: []string{"id", "parent"}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, output.ParseFieldList(tt.list))
+		})
+	}
+}
+
+func TestSelectFields(t *testing.T) {
+	resources := output.FoldersToResources([]*folders.Folder{
+		{ID: "1", Name: "folders/1", DisplayName: "prod", Parent: "organizations/9", State: "ACTIVE"},
+	})
+
+	tests := map[string]struct {
+		fields      []string
+		wantHeaders []string
+		wantRow     []interface{}
+		wantJSON    string
+		wantErr     error
+	}{
+		"no fields keeps everything": {
+			wantHeaders: output.FolderHeaders(),
+			wantRow:     resources[0].TableRow(),
+		},
+		"subset in selection order": {
+			fields:      []string{"state", "id"},
+			wantHeaders: []string{"State", "ID"},
+			wantRow:     []interface{}{"ACTIVE", "1"},
+			wantJSON:    `{"state":"ACTIVE","id":"1"}`,
+		},
+		"case and underscore insensitive": {
+			fields:      []string{"displayName", "PARENT"},
+			wantHeaders: []string{"Display Name", "Parent"},
+			wantRow:     []interface{}{"prod", "organizations/9"},
+			wantJSON:    `{"display_name":"prod","parent":"organizations/9"}`,
+		},
+		"all selects the default fields then the name": {
+			fields:      []string{"ALL"},
+			wantHeaders: append(output.FolderHeaders(), "Name"),
+			wantRow:     append(resources[0].TableRow(), "folders/1"),
+		},
+		"unknown field": {
+			fields:  []string{"id", "labels"},
+			wantErr: output.ErrUnknownField,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			selected, headers, err := output.SelectFields(resources, output.FolderHeaders(), tt.fields)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Contains(t, err.Error(), "available: id, display_name, parent, state, create_time, update_time")
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantHeaders, headers)
+			require.Len(t, selected, 1)
+			assert.Equal(t, tt.wantRow, selected[0].TableRow())
+
+			if tt.wantJSON != "" {
+				data, err := json.Marshal(selected[0])
+				require.NoError(t, err)
+				assert.JSONEq(t, tt.wantJSON, string(data))
+				assert.Equal(t, tt.wantJSON, string(data), "keys should follow the selection order")
+			}
+		})
+	}
+}
+
+func TestSelectFieldsOmittedKey(t *testing.T) {
+	count := 3
+	resources := []output.Resource{&report.FolderCount{OrgID: "1", Count: &count}}
+
+	selected, headers, err := output.SelectFields(resources, report.FolderCountHeaders(), []string{"note", "folder_count"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Note", "Folder Count"}, headers)
+
+	var buf bytes.Buffer
+	formatter := output.NewFormatter(&buf, nil, false, output.ResourceTypeFolderCounts)
+	require.NoError(t, formatter.Format(selected, output.FormatJSONL, headers))
+	assert.Equal(t, `{"folder_count":3}`+"\n", buf.String())
+}
+
+func TestSelectFieldsName(t *testing.T) {
+	count := 2
+	tests := map[string]struct {
+		resources   []output.Resource
+		headers     []string
+		fields      []string
+		wantHeaders []string
+		wantCSV     string
+		wantJSONL   string
+	}{
+		"folder": {
+			resources:   output.FoldersToResources([]*folders.Folder{{ID: "123", Name: "folders/123"}}),
+			headers:     output.FolderHeaders(),
+			fields:      []string{"id", "name"},
+			wantHeaders: []string{"ID", "Name"},
+			wantCSV:     "ID,Name\n123,folders/123\n",
+			wantJSONL:   `{"id":"123","name":"folders/123"}` + "\n",
+		},
+		"organization": {
+			resources: output.OrganizationsToResources([]*organizations.Organization{
+				{ID: "9", Name: "organizations/9", DisplayName: "Acme"},
+			}),
+			headers:     output.OrganizationHeaders(),
+			fields:      []string{"name", "display_name"},
+			wantHeaders: []string{"Name", "Display Name"},
+			wantCSV:     "Name,Display Name\norganizations/9,Acme\n",
+			wantJSONL:   `{"name":"organizations/9","display_name":"Acme"}` + "\n",
+		},
+		"folder count computes the name": {
+			resources:   []output.Resource{&report.FolderCount{OrgID: "9", Count: &count}},
+			headers:     report.FolderCountHeaders(),
+			fields:      []string{"Name", "folder_count"},
+			wantHeaders: []string{"Name", "Folder Count"},
+			wantCSV:     "Name,Folder Count\norganizations/9,2\n",
+			wantJSONL:   `{"name":"organizations/9","folder_count":2}` + "\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			selected, headers, err := output.SelectFields(tt.resources, tt.headers, tt.fields)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantHeaders, headers)
+
+			var csvOut, jsonlOut bytes.Buffer
+			require.NoError(t, output.NewFormatter(&csvOut, nil, false, "").Format(selected, output.FormatCSV, headers))
+			assert.Equal(t, tt.wantCSV, csvOut.String())
+			require.NoError(t, output.NewFormatter(&jsonlOut, nil, false, "").Format(selected, output.FormatJSONL, headers))
+			assert.Equal(t, tt.wantJSONL, jsonlOut.String())
+		})
+	}
+}
@@ -0,0 +1,347 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidFilter is returned when a filter expression cannot be parsed.
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// filterParser is a recursive-descent parser for filter expressions.
+//
+// Grammar (AND binds tighter than OR):
+//
+//	expr       = andExpr { "OR" andExpr }
+//	andExpr    = primary { "AND" primary }
+//	primary    = "(" expr ")" | comparison
+//	comparison = field ( "=" | "!=" | "~" ) value
+//
+// AND and OR are keywords only between expressions; after an operator they are ordinary values, so that
+// `displayName=OR` compares with the text "OR".
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+// parseExpr parses a sequence of AND expressions joined by OR.
+func (p *filterParser) parseExpr() (filterNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenOr {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+// parseAnd parses a sequence of primaries joined by AND.
+func (p *filterParser) parseAnd() (filterNode, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenAnd {
+		p.pos++
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+// parsePrimary parses a parenthesized expression or a single comparison.
+func (p *filterParser) parsePrimary() (filterNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenLParen:
+		node, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, filterSyntaxError(closing, "expected ')'")
+		}
+
+		return node, nil
+	case tokenWord:
+		return p.parseComparison(tok)
+	default:
+		return nil, filterSyntaxError(tok, "expected field name or '('")
+	}
+}
+
+// parseComparison parses the operator and value following a field name.
+func (p *filterParser) parseComparison(field filterToken) (filterNode, error) {
+	accessor, ok := filterFields[strings.ToLower(field.text)]
+	if !ok {
+		return nil, filterSyntaxError(field, fmt.Sprintf("unknown field %q", field.text))
+	}
+
+	op := p.next()
+	if op.kind != tokenOperator {
+		return nil, filterSyntaxError(op, "expected operator '=', '!=' or '~'")
+	}
+
+	// a keyword in value position is literal text, e.g. displayName=OR
+	value := p.next()
+	switch value.kind {
+	case tokenWord, tokenString, tokenAnd, tokenOr:
+	default:
+		return nil, filterSyntaxError(value, "expected value")
+	}
+
+	return comparisonNode{accessor: accessor, op: op.text, value: value.text}, nil
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() filterToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+
+	return tok
+}
+
+// andNode matches when both operands match.
+type andNode struct {
+	left, right filterNode
+}
+
+func (n andNode) match(r Resource) bool {
+	return n.left.match(r) && n.right.match(r)
+}
+
+// orNode matches when either operand matches.
+type orNode struct {
+	left, right filterNode
+}
+
+func (n orNode) match(r Resource) bool {
+	return n.left.match(r) || n.right.match(r)
+}
+
+// comparisonNode compares a single resource field against a value.
+type comparisonNode struct {
+	accessor func(Resource) string
+	op       string
+	value    string
+}
+
+func (n comparisonNode) match(r Resource) bool {
+	actual := n.accessor(r)
+	switch n.op {
+	case "=":
+		return strings.EqualFold(actual, n.value)
+	case "!=":
+		return !strings.EqualFold(actual, n.value)
+	default:
+		return strings.Contains(strings.ToLower(actual), strings.ToLower(n.value))
+	}
+}
+
+// Filter is a compiled client-side filter expression.
+type Filter struct {
+	root filterNode
+}
+
+// Match reports whether the resource satisfies the filter.
+func (f *Filter) Match(r Resource) bool {
+	return f.root.match(r)
+}
+
+// filterNode is a node of a parsed filter expression.
+type filterNode interface {
+	match(r Resource) bool
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenAnd
+	tokenOr
+)
+
+// filterToken is a lexical token with its 1-based position in the expression.
+type filterToken struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// filterFields maps lowercase field names to resource accessors.
+var filterFields = map[string]func(Resource) string{
+	"id":           func(r Resource) string { return r.GetID() },
+	"displayname":  func(r Resource) string { return r.GetDisplayName() },
+	"display_name": func(r Resource) string { return r.GetDisplayName() },
+	"state":        func(r Resource) string { return r.GetState() },
+	"name":         func(r Resource) string { return r.GetName() },
+	"parent":       parentOf,
+}
+
+// ParseFilter compiles a filter expression such as `state=ACTIVE AND (displayName~prod OR id=123)`.
+// Comparisons support "=" and "!=" (case-insensitive equality) and "~" (case-insensitive substring).
+// AND binds tighter than OR, and parentheses group sub-expressions. AND and OR directly after an
+// operator are values, not keywords.
+func ParseFilter(expr string) (*Filter, error) {
+	tokens, err := tokenizeFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &filterParser{tokens: tokens}
+	root, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, filterSyntaxError(tok, "unexpected "+describeToken(tok))
+	}
+
+	return &Filter{root: root}, nil
+}
+
+// FilterResources returns the resources matching the filter expression, preserving order.
+// An empty expression returns the resources unchanged.
+func FilterResources(resources []Resource, expr string) ([]Resource, error) {
+	if strings.TrimSpace(expr) == "" {
+		return resources, nil
+	}
+
+	filter, err := ParseFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make([]Resource, 0, len(resources))
+	for _, resource := range resources {
+		if filter.Match(resource) {
+			matched = append(matched, resource)
+		}
+	}
+
+	return matched, nil
+}
+
+// FilterByIDPrefix returns the resources whose IDs start with prefix, preserving order.
+// An empty prefix returns the resources unchanged.
+func FilterByIDPrefix(resources []Resource, prefix string) []Resource {
+	if prefix == "" {
+		return resources
+	}
+
+	matched := make([]Resource, 0, len(resources))
+	for _, resource := range resources {
+		if strings.HasPrefix(resource.GetID(), prefix) {
+			matched = append(matched, resource)
+		}
+	}
+
+	return matched
+}
+
+// tokenizeFilter splits a filter expression into tokens, always ending with an EOF token.
+func tokenizeFilter(expr string) ([]filterToken, error) {
+	var tokens []filterToken
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, filterToken{kind: tokenLParen, text: "(", pos: pos})
+			i++
+		case r == ')':
+			tokens = append(tokens, filterToken{kind: tokenRParen, text: ")", pos: pos})
+			i++
+		case r == '=' || r == '~':
+			tokens = append(tokens, filterToken{kind: tokenOperator, text: string(r), pos: pos})
+			i++
+		case r == '!':
+			if i+1 >= len(runes) || runes[i+1] != '=' {
+				return nil, fmt.Errorf("%w: at position %d: expected '!='", ErrInvalidFilter, pos)
+			}
+			tokens = append(tokens, filterToken{kind: tokenOperator, text: "!=", pos: pos})
+			i += 2
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("%w: at position %d: unterminated string", ErrInvalidFilter, pos)
+			}
+			tokens = append(tokens, filterToken{kind: tokenString, text: string(runes[i+1 : end]), pos: pos})
+			i = end + 1
+		default:
+			end := i
+			for end < len(runes) && !isFilterDelimiter(runes[end]) {
+				end++
+			}
+			tokens = append(tokens, wordToken(string(runes[i:end]), pos))
+			i = end
+		}
+	}
+
+	return append(tokens, filterToken{kind: tokenEOF, pos: len(runes) + 1}), nil
+}
+
+// wordToken classifies a bare word as a keyword or a plain word.
+func wordToken(text string, pos int) filterToken {
+	switch text {
+	case "AND":
+		return filterToken{kind: tokenAnd, text: text, pos: pos}
+	case "OR":
+		return filterToken{kind: tokenOr, text: text, pos: pos}
+	default:
+		return filterToken{kind: tokenWord, text: text, pos: pos}
+	}
+}
+
+func isFilterDelimiter(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune(`()=!~"`, r)
+}
+
+func describeToken(tok filterToken) string {
+	if tok.kind == tokenEOF {
+		return "end of expression"
+	}
+
+	return fmt.Sprintf("%q", tok.text)
+}
+
+func filterSyntaxError(tok filterToken, msg string) error {
+	if tok.kind == tokenEOF && !strings.HasPrefix(msg, "unexpected") {
+		msg += ", got end of expression"
+	}
+
+	return fmt.Errorf("%w: at position %d: %s", ErrInvalidFilter, tok.pos, msg)
+}
@@ -0,0 +1,180 @@
+package output_test
+
+import (
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createFilterResources() []output.Resource {
+	return output.FoldersToResources([]*folders.Folder{
+		{ID: "1", DisplayName: "prod-web", State: "ACTIVE", Parent: "organizations/100"},
+		{ID: "2", DisplayName: "prod-db", State: "DELETE_REQUESTED", Parent: "organizations/100"},
+		{ID: "3", DisplayName: "staging", State: "ACTIVE", Parent: "folders/1"},
+		{ID: "4", DisplayName: "Sandbox", State: "ACTIVE", Parent: "folders/3"},
+	})
+}
+
+func TestFilterResources(t *testing.T) {
+	tests := map[string]struct {
+		expr    string
+		wantIDs []string
+	}{
+		"empty expression keeps everything": {
+			expr:    "",
+			wantIDs: []string{"1", "2", "3", "4"},
+		},
+		"single equality is case-insensitive": {
+			expr:    "state=active",
+			wantIDs: []string{"1", "3", "4"},
+		},
+		"inequality": {
+			expr:    "state!=ACTIVE",
+			wantIDs: []string{"2"},
+		},
+		"substring match": {
+			expr:    "displayName~PROD",
+			wantIDs: []string{"1", "2"},
+		},
+		"snake case field name and parent field": {
+			expr:    "display_name~box OR parent=folders/1",
+			wantIDs: []string{"3", "4"},
+		},
+		"AND combination": {
+			expr:    "state=ACTIVE AND displayName~prod",
+			wantIDs: []string{"1"},
+		},
+		"AND binds tighter than OR": {
+			expr:    "id=2 OR state=ACTIVE AND displayName~staging",
+			wantIDs: []string{"2", "3"},
+		},
+		"parentheses override precedence": {
+			expr:    "(id=2 OR state=ACTIVE) AND displayName~prod",
+			wantIDs: []string{"1", "2"},
+		},
+		"nested parentheses": {
+			expr:    "((id=1 OR id=4) AND (displayName~web OR displayName~sand)) OR id=\"3\"",
+			wantIDs: []string{"1", "3", "4"},
+		},
+		"quoted value with spaces": {
+			expr:    `displayName="prod db" OR displayName="prod-db"`,
+			wantIDs: []string{"2"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := output.FilterResources(createFilterResources(), tt.expr)
+			require.NoError(t, err)
+
+			ids := make([]string, 0, len(got))
+			for _, r := range got {
+				ids = append(ids, r.GetID())
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
+
+func TestFilterResources_KeywordValues(t *testing.T) {
+	resources := output.FoldersToResources([]*folders.Folder{
+		{ID: "1", DisplayName: "OR", State: "DELETE_REQUESTED"},
+		{ID: "2", DisplayName: "and", State: "ACTIVE"},
+		{ID: "3", DisplayName: "Android", State: "ACTIVE"},
+		{ID: "4", DisplayName: "prod", State: "ACTIVE"},
+	})
+
+	tests := map[string]struct {
+		expr    string
+		wantIDs []string
+	}{
+		"keywords as unquoted values": {
+			expr:    "displayName=OR OR displayName=and",
+			wantIDs: []string{"1", "2"},
+		},
+		"keyword value followed by a keyword": {
+			expr:    "displayName~AND AND state=ACTIVE",
+			wantIDs: []string{"2", "3"},
+		},
+		"keyword value inside parentheses": {
+			expr:    "(displayName!=OR) AND state!=ACTIVE",
+			wantIDs: []string{},
+		},
+		"quoted keyword value": {
+			expr:    `displayName="OR"`,
+			wantIDs: []string{"1"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := output.FilterResources(resources, tt.expr)
+			require.NoError(t, err)
+
+			ids := make([]string, 0, len(got))
+			for _, r := range got {
+				ids = append(ids, r.GetID())
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
+
+func TestParseFilter_Errors(t *testing.T) {
+	tests := map[string]struct {
+		expr    string
+		wantErr string
+	}{
+		"unknown field": {
+			expr:    "color=red",
+			wantErr: `at position 1: unknown field "color"`,
+		},
+		"missing operator": {
+			expr:    "state ACTIVE",
+			wantErr: "at position 7: expected operator",
+		},
+		"missing value": {
+			expr:    "state=",
+			wantErr: "at position 7: expected value, got end of expression",
+		},
+		"unclosed parenthesis": {
+			expr:    "(state=ACTIVE",
+			wantErr: "at position 14: expected ')'",
+		},
+		"dangling operator keyword": {
+			expr:    "state=ACTIVE AND",
+			wantErr: "at position 17: expected field name or '('",
+		},
+		"trailing token": {
+			expr:    "state=ACTIVE id=1",
+			wantErr: `at position 14: unexpected "id"`,
+		},
+		"lone exclamation mark": {
+			expr:    "state!ACTIVE",
+			wantErr: "at position 6: expected '!='",
+		},
+		"keyword value without operator": {
+			expr:    "displayName OR",
+			wantErr: `at position 13: expected operator '=', '!=' or '~'`,
+		},
+		"keyword value followed by dangling keyword": {
+			expr:    "displayName=AND AND",
+			wantErr: "at position 20: expected field name or '('",
+		},
+		"unterminated string": {
+			expr:    `displayName="prod`,
+			wantErr: "at position 13: unterminated string",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := output.ParseFilter(tt.expr)
+			require.ErrorIs(t, err, output.ErrInvalidFilter)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// FormatFunc writes resources in a registered format to w. Headers are the column titles of the
+// resources' table rows, and verbose is set when status messages and counts were requested.
+type FormatFunc func(w io.Writer, resources []Resource, headers []string, verbose bool) error
+
+// formatHandler renders resources with the settings of a formatter.
+type formatHandler func(f *Formatter, resources []Resource, headers []string) error
+
+var (
+	formatsMu sync.RWMutex
+	formats   = map[Format]formatHandler{
+		FormatTable: (*Formatter).formatTable,
+		FormatJSON: func(f *Formatter, resources []Resource, _ []string) error {
+			return f.formatJSON(resources)
+		},
+		FormatJSONL: func(f *Formatter, resources []Resource, _ []string) error {
+			return f.formatJSONL(resources)
+		},
+		FormatCSV: (*Formatter).formatCSV,
+		FormatID: func(f *Formatter, resources []Resource, _ []string) error {
+			return f.formatID(resources)
+		},
+		FormatTemplate: func(f *Formatter, resources []Resource, _ []string) error {
+			return f.formatTemplate(resources)
+		},
+		FormatValue: (*Formatter).formatValue,
+		FormatRawJSON: func(f *Formatter, resources []Resource, _ []string) error {
+			return f.formatRawJSON(resources)
+		},
+		FormatBigQuery: func(f *Formatter, resources []Resource, _ []string) error {
+			return f.formatBigQuery(resources)
+		},
+		FormatEnv: func(f *Formatter, resources []Resource, _ []string) error {
+			return f.formatEnv(resources)
+		},
+	}
+)
+
+// RegisterFormat makes a custom output format available to ParseFormat, Formatter.Format and
+// Formatter.FormatStream by name. Format calls fn with the formatter's writer; formatter settings such
+// as the time zone or ID style are not applied to the resources. It panics if the name is empty, fn is
+// nil, or the name is already registered, including the built-in format names, the graph formats and
+// FormatJSONObject.
+func RegisterFormat(name Format, fn FormatFunc) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+
+	if name == "" || fn == nil {
+		panic("output: RegisterFormat requires a name and a format function")
+	}
+	if _, dup := formats[name]; dup || IsGraphFormat(name) || name == FormatJSONObject {
+		panic("output: RegisterFormat called twice for format " + string(name))
+	}
+
+	formats[name] = func(f *Formatter, resources []Resource, headers []string) error {
+		return fn(f.writer, resources, headers, f.verbose)
+	}
+}
+
+// RegisteredFormats returns the names of all built-in and registered formats in sorted order.
+func RegisteredFormats() []Format {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+
+	names := make([]Format, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+
+	return names
+}
+
+// lookupFormat returns the handler registered for the format.
+func lookupFormat(format Format) (formatHandler, error) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+
+	handler, ok := formats[format]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedOutputFormat, format)
+	}
+
+	return handler, nil
+}
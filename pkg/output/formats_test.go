@@ -0,0 +1,118 @@
+package output_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// formatNames is a custom format writing the display names of the resources on one line.
+const formatNames output.Format = "names"
+
+// registerNames registers formatNames once, so that the tests can run repeatedly in one process.
+var registerNames = sync.OnceFunc(func() {
+	output.RegisterFormat(formatNames, func(w io.Writer, resources []output.Resource, headers []string,
+		verbose bool,
+	) error {
+		names := make([]string, len(resources))
+		for i, resource := range resources {
+			names[i] = resource.GetDisplayName()
+		}
+		_, err := fmt.Fprintf(w, "%d columns, verbose=%t: %s\n", len(headers), verbose, strings.Join(names, ";"))
+
+		return err
+	})
+})
+
+func TestRegisterFormat(t *testing.T) {
+	registerNames()
+	resources := output.FoldersToResources([]*folders.Folder{
+		{ID: "1", DisplayName: "Engineering"},
+		{ID: "2", DisplayName: "Finance"},
+	})
+	headers := output.FolderHeaders()
+	want := "6 columns, verbose=true: Engineering;Finance\n"
+
+	format, err := output.ParseFormat("names")
+	require.NoError(t, err)
+	assert.Equal(t, formatNames, format)
+	assert.Contains(t, output.RegisteredFormats(), formatNames)
+
+	t.Run("format", func(t *testing.T) {
+		var stdout bytes.Buffer
+		formatter := output.NewFormatter(&stdout, &bytes.Buffer{}, true, output.ResourceTypeFolders)
+		require.NoError(t, formatter.Format(resources, formatNames, headers))
+		assert.Equal(t, want, stdout.String())
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		ch := make(chan output.Resource, len(resources))
+		for _, resource := range resources {
+			ch <- resource
+		}
+		close(ch)
+
+		var stdout bytes.Buffer
+		formatter := output.NewFormatter(&stdout, &bytes.Buffer{}, true, output.ResourceTypeFolders)
+		require.NoError(t, formatter.FormatStream(ch, formatNames, headers))
+		assert.Equal(t, want, stdout.String())
+	})
+}
+
+func TestRegisterFormatPanics(t *testing.T) {
+	noop := func(io.Writer, []output.Resource, []string, bool) error { return nil }
+
+	tests := map[string]struct {
+		name output.Format
+		fn   output.FormatFunc
+	}{
+		"empty name":     {name: "", fn: noop},
+		"nil function":   {name: "custom", fn: nil},
+		"built-in name":  {name: output.FormatJSON, fn: noop},
+		"built-in graph": {name: output.FormatDOT, fn: noop},
+		"json object":    {name: output.FormatJSONObject, fn: noop},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Panics(t, func() { output.RegisterFormat(tt.name, tt.fn) })
+		})
+	}
+}
+
+func TestGraphFormatsNotRegistered(t *testing.T) {
+	for _, format := range []output.Format{output.FormatDOT, output.FormatMermaid} {
+		t.Run(string(format), func(t *testing.T) {
+			assert.True(t, output.IsGraphFormat(format))
+			assert.NotContains(t, output.RegisteredFormats(), format)
+
+			_, err := output.ParseFormat(string(format))
+			require.ErrorIs(t, err, output.ErrUnsupportedOutputFormat)
+		})
+	}
+	assert.False(t, output.IsGraphFormat(output.FormatJSON))
+}
+
+func TestJSONObjectFormatNotRegistered(t *testing.T) {
+	assert.NotContains(t, output.RegisteredFormats(), output.FormatJSONObject)
+
+	_, err := output.ParseFormat(string(output.FormatJSONObject))
+	require.ErrorIs(t, err, output.ErrUnsupportedOutputFormat)
+}
+
+func TestFormatUnregistered(t *testing.T) {
+	_, err := output.ParseFormat("yaml")
+	require.ErrorIs(t, err, output.ErrUnsupportedOutputFormat)
+
+	formatter := output.NewFormatter(&bytes.Buffer{}, &bytes.Buffer{}, false, output.ResourceTypeFolders)
+	err = formatter.Format(nil, "yaml", nil)
+	require.ErrorIs(t, err, output.ErrUnsupportedOutputFormat)
+}
@@ -24,13 +24,40 @@ type Format string
 const (
 	FormatTable Format = "table"
 	FormatJSON  Format = "json"
-	FormatCSV   Format = "csv"
-	FormatID    Format = "id"
+	// FormatJSONObject writes a lone resource as a JSON object rather than a one-element array, and any
+	// other number of resources as a JSON array like FormatJSON. The describe commands choose it for a
+	// single requested resource; it is not registered, so ParseFormat rejects it as a --format value.
+	FormatJSONObject Format = "json-object"
+	FormatJSONL      Format = "jsonl"
+	FormatCSV        Format = "csv"
+	FormatID         Format = "id"
+	FormatTemplate   Format = "template"
+	FormatValue      Format = "value"
+	FormatRawJSON    Format = "rawjson"
+	FormatBigQuery   Format = "bq"
+	// FormatEnv writes shell export statements assigning resource IDs to variables.
+	FormatEnv Format = "env"
+
+	// FormatDOT and FormatMermaid draw folder hierarchies and are only supported by RenderGraph, so
+	// ParseFormat rejects them.
+	FormatDOT     Format = "dot"
+	FormatMermaid Format = "mermaid"
 )
 
+// ParseFormat validates an output format name against the built-in and registered formats.
+func ParseFormat(name string) (Format, error) {
+	format := Format(name)
+	if _, err := lookupFormat(format); err != nil {
+		return "", err
+	}
+
+	return format, nil
+}
+
 // Resource represents a generic cloud resource with common fields.
 type Resource interface {
 	GetID() string
+	GetName() string
 	GetDisplayName() string
 	GetState() string
 	GetCreateTime() time.Time
@@ -41,66 +68,150 @@ type Resource interface {
 // Formatter handles output formatting for resources.
 type Formatter struct {
 	writer       io.Writer
+	errWriter    io.Writer
 	verbose      bool
 	resourceType string
+	parent       string
+	compact      bool
+	location     *time.Location
+	template     *Template
+	idStyle      IDStyle
+	groupBy      GroupBy
+	truncate     int
+	nullValue    string
+	human        bool
+	envNames     bool
 }
 
-// NewFormatterWithType creates a new formatter with a specific resource type for messages.
-func NewFormatterWithType(writer io.Writer, verbose bool, resourceType string) *Formatter {
+// NewFormatter creates a new formatter that writes resources to writer and status messages to errWriter.
+// A nil writer defaults to os.Stdout and a nil errWriter defaults to os.Stderr.
+func NewFormatter(writer, errWriter io.Writer, verbose bool, resourceType string) *Formatter {
 	if writer == nil {
 		writer = os.Stdout
 	}
+	if errWriter == nil {
+		errWriter = os.Stderr
+	}
 
 	return &Formatter{
 		writer:       writer,
+		errWriter:    errWriter,
 		verbose:      verbose,
 		resourceType: resourceType,
 	}
 }
 
-// Format outputs the resources in the specified format.
+// NewFormatterWithType creates a new formatter with a specific resource type for messages.
+// Status messages are written to os.Stderr.
+func NewFormatterWithType(writer io.Writer, verbose bool, resourceType string) *Formatter {
+	return NewFormatter(writer, nil, verbose, resourceType)
+}
+
+// SetParent records the parent filter shown in the verbose table summary panel.
+func (f *Formatter) SetParent(parent string) {
+	f.parent = parent
+}
+
+// SetCompact disables indentation in JSON output. JSONL output is always compact.
+func (f *Formatter) SetCompact(compact bool) {
+	f.compact = compact
+}
+
+// Format outputs the resources in the specified format, one of the built-in formats, FormatJSONObject
+// or a format added with RegisterFormat.
 func (f *Formatter) Format(resources []Resource, format Format, headers []string) error {
-	switch format {
-	case FormatJSON:
-		return f.formatJSON(resources)
-	case FormatCSV:
-		return f.formatCSV(resources, headers)
-	case FormatTable:
-		return f.formatTable(resources, headers)
-	case FormatID:
-		return f.formatID(resources)
-	default:
-		return fmt.Errorf("%w: %s", ErrUnsupportedOutputFormat, format)
+	if format == FormatJSONObject {
+		return f.formatJSONObject(resources)
 	}
+
+	handler, err := lookupFormat(format)
+	if err != nil {
+		return err
+	}
+
+	return handler(f, resources, headers)
 }
 
 func (f *Formatter) formatJSON(resources []Resource) error {
 	encoder := json.NewEncoder(f.writer)
-	encoder.SetIndent("", "  ")
+	if !f.compact {
+		encoder.SetIndent("", "  ")
+	}
+
+	if err := encoder.Encode(f.jsonResources(resources)); err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+
+	return nil
+}
+
+// formatJSONObject writes a lone resource as a JSON object and falls back to a JSON array otherwise.
+func (f *Formatter) formatJSONObject(resources []Resource) error {
+	if len(resources) != 1 {
+		return f.formatJSON(resources)
+	}
 
-	if err := encoder.Encode(resources); err != nil {
+	encoder := json.NewEncoder(f.writer)
+	if !f.compact {
+		encoder.SetIndent("", "  ")
+	}
+	if err := encoder.Encode(f.jsonResource(resources[0])); err != nil {
 		return fmt.Errorf("failed to encode JSON: %w", err)
 	}
 
 	return nil
 }
 
+func (f *Formatter) formatJSONL(resources []Resource) error {
+	encoder := json.NewEncoder(f.writer)
+	for _, resource := range resources {
+		if err := encoder.Encode(f.jsonResource(resource)); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+	}
+
+	return nil
+}
+
 func (f *Formatter) formatTable(resources []Resource, headers []string) error {
 	if len(resources) == 0 {
 		if f.verbose {
-			resourceType := f.resourceType
-			if resourceType == "" {
-				resourceType = "resources"
-			}
-			fmt.Fprintf(os.Stderr, "No %s found.\n", resourceType)
+			f.printStatus("No %s found.\n", f.resourceLabel())
 		}
 
 		return nil
 	}
 
+	if f.groupBy == "" {
+		f.renderTable(resources, headers, "")
+	} else {
+		for i, group := range groupResources(resources, f.groupBy) {
+			if i > 0 {
+				fmt.Fprintln(f.writer)
+			}
+			f.renderTable(group.resources, headers, groupTitle(f.groupBy, group, f.human))
+		}
+	}
+
+	if f.verbose {
+		RenderSummaryPanel(f.errWriter, resources, SummaryOptions{
+			ResourceType: f.resourceLabel(),
+			Parent:       f.parent,
+			Human:        f.human,
+		})
+	}
+
+	return nil
+}
+
+// renderTable writes a single table of resources, with a title above the header when title is set.
+func (f *Formatter) renderTable(resources []Resource, headers []string, title string) {
 	t := table.NewWriter()
 	t.SetOutputMirror(f.writer)
 	t.SetStyle(table.StyleDefault)
+	if title != "" {
+		t.SetTitle(title)
+	}
 
 	headerRow := make(table.Row, len(headers))
 	for i, h := range headers {
@@ -108,20 +219,11 @@ func (f *Formatter) formatTable(resources []Resource, headers []string) error {
 	}
 	t.AppendHeader(headerRow)
 
+	idColumn := f.idColumn(headers)
 	for _, resource := range resources {
-		t.AppendRow(resource.TableRow())
-	}
-
-	if f.verbose {
-		resourceType := f.resourceType
-		if resourceType == "" {
-			resourceType = defaultResourceType
-		}
-		t.SetCaption("Total %s: %d", resourceType, len(resources))
+		t.AppendRow(f.tableRow(resource, idColumn))
 	}
 	t.Render()
-
-	return nil
 }
 
 func (f *Formatter) formatCSV(resources []Resource, headers []string) error {
@@ -135,8 +237,9 @@ func (f *Formatter) formatCSV(resources []Resource, headers []string) error {
 	}
 	t.AppendHeader(headerRow)
 
+	idColumn := f.idColumn(headers)
 	for _, resource := range resources {
-		t.AppendRow(resource.TableRow())
+		t.AppendRow(f.row(resource, idColumn))
 	}
 
 	t.RenderCSV()
@@ -146,20 +249,30 @@ func (f *Formatter) formatCSV(resources []Resource, headers []string) error {
 
 func (f *Formatter) formatID(resources []Resource) error {
 	if len(resources) == 0 && f.verbose {
-		resourceType := f.resourceType
-		if resourceType == "" {
-			resourceType = defaultResourceType
-		}
-		fmt.Fprintf(os.Stderr, "No %s found.\n", resourceType)
+		f.printStatus("No %s found.\n", f.resourceLabel())
 
 		return nil
 	}
 
 	for _, resource := range resources {
-		if _, err := fmt.Fprintln(f.writer, resource.GetID()); err != nil {
+		if _, err := fmt.Fprintln(f.writer, f.resourceID(resource)); err != nil {
 			return fmt.Errorf("failed to write resource ID: %w", err)
 		}
 	}
 
 	return nil
 }
+
+// printStatus writes a human-oriented status message to the error writer, keeping the data stream clean.
+func (f *Formatter) printStatus(format string, args ...interface{}) {
+	fmt.Fprintf(f.errWriter, format, args...)
+}
+
+// resourceLabel returns the plural resource name used in status messages.
+func (f *Formatter) resourceLabel() string {
+	if f.resourceType == "" {
+		return defaultResourceType
+	}
+
+	return f.resourceType
+}
@@ -3,10 +3,12 @@ package output_test
 import (
 	"bytes"
 	"encoding/json"
+	"io"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/andreygrechin/gcphelper/pkg/folders"
 	"github.com/andreygrechin/gcphelper/pkg/output"
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/stretchr/testify/assert"
@@ -23,6 +25,7 @@ type mockResource struct {
 }
 
 func (m *mockResource) GetID() string            { return m.id }
+func (m *mockResource) GetName() string          { return "mocks/" + m.id }
 func (m *mockResource) GetDisplayName() string   { return m.displayName }
 func (m *mockResource) GetState() string         { return m.state }
 func (m *mockResource) GetCreateTime() time.Time { return m.createTime }
@@ -79,7 +82,7 @@ func TestFormatter_FormatJSON(t *testing.T) {
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
 			var buf bytes.Buffer
-			formatter := output.NewFormatterWithType(&buf, tt.verbose, "")
+			formatter := output.NewFormatter(&buf, io.Discard, tt.verbose, "")
 			headers := []string{"ID", "Name", "State", "Created", "Updated"}
 
 			err := formatter.Format(tt.resources, output.FormatJSON, headers)
@@ -95,6 +98,41 @@ func TestFormatter_FormatJSON(t *testing.T) {
 	}
 }
 
+func TestFormatter_FormatJSONObject(t *testing.T) {
+	lone := output.FoldersToResources([]*folders.Folder{{ID: "1", Name: "folders/1", DisplayName: "Finance"}})
+	pair := output.FoldersToResources([]*folders.Folder{{ID: "1"}, {ID: "2"}})
+
+	tests := map[string]struct {
+		resources  []output.Resource
+		wantObject bool
+	}{
+		"lone resource as an object":    {resources: lone, wantObject: true},
+		"several resources as an array": {resources: pair},
+		"no resources as an array":      {resources: []output.Resource{}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			formatter := output.NewFormatter(&buf, io.Discard, false, output.ResourceTypeFolders)
+			formatter.SetCompact(true)
+			require.NoError(t, formatter.Format(tt.resources, output.FormatJSONObject, output.FolderHeaders()))
+
+			if tt.wantObject {
+				var folder folders.Folder
+				require.NoError(t, json.Unmarshal(buf.Bytes(), &folder))
+				assert.Equal(t, "folders/1", folder.Name)
+				assert.Equal(t, "Finance", folder.DisplayName)
+
+				return
+			}
+			var list []folders.Folder
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &list))
+			assert.Len(t, list, len(tt.resources))
+		})
+	}
+}
+
 func TestFormatter_FormatTable(t *testing.T) {
 	tests := map[string]struct {
 		resources []output.Resource
@@ -116,7 +154,7 @@ func TestFormatter_FormatTable(t *testing.T) {
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
 			var buf bytes.Buffer
-			formatter := output.NewFormatterWithType(&buf, tt.verbose, "")
+			formatter := output.NewFormatter(&buf, io.Discard, tt.verbose, "")
 
 			err := formatter.Format(tt.resources, output.FormatTable, tt.headers)
 			require.NoError(t, err)
@@ -189,7 +227,7 @@ func TestFormatter_FormatID(t *testing.T) {
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
 			var buf bytes.Buffer
-			formatter := output.NewFormatterWithType(&buf, tt.verbose, "")
+			formatter := output.NewFormatter(&buf, io.Discard, tt.verbose, "")
 
 			err := formatter.Format(tt.resources, output.FormatID, nil)
 			require.NoError(t, err)
@@ -214,3 +252,92 @@ func TestFormatter_UnsupportedFormat(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "unsupported output format")
 }
+
+func TestFormatter_StatusMessages(t *testing.T) {
+	tests := map[string]struct {
+		resources  []output.Resource
+		format     output.Format
+		verbose    bool
+		wantStderr string
+	}{
+		"table empty verbose": {
+			resources:  []output.Resource{},
+			format:     output.FormatTable,
+			verbose:    true,
+			wantStderr: "No folders found.\n",
+		},
+		"table summary verbose": {
+			resources: createTestResources(),
+			format:    output.FormatTable,
+			verbose:   true,
+			wantStderr: `+----------------------------------------+
+| Summary                                |
++---------------+------------------------+
+| Total folders | 2                      |
+| By state      | ACTIVE: 1, INACTIVE: 1 |
+| Parent filter | none                   |
++---------------+------------------------+
+`,
+		},
+		"table non-verbose": {
+			resources:  createTestResources(),
+			format:     output.FormatTable,
+			verbose:    false,
+			wantStderr: "",
+		},
+		"id empty verbose": {
+			resources:  []output.Resource{},
+			format:     output.FormatID,
+			verbose:    true,
+			wantStderr: "No folders found.\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			formatter := output.NewFormatter(&stdout, &stderr, tt.verbose, "folders")
+
+			err := formatter.Format(tt.resources, tt.format, []string{"ID", "Name", "State", "Created", "Updated"})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantStderr, stderr.String())
+			assert.NotContains(t, stdout.String(), "Total")
+			assert.NotContains(t, stdout.String(), "No folders found")
+		})
+	}
+}
+
+func TestFormatter_FormatJSONCompact(t *testing.T) {
+	tests := map[string]struct {
+		resources []output.Resource
+		want      string
+	}{
+		"multiple resources": {
+			resources: output.FoldersToResources([]*folders.Folder{{ID: "1", State: "ACTIVE"}, {ID: "2"}}),
+		},
+		"empty resources": {
+			resources: []output.Resource{},
+			want:      "[]\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			formatter := output.NewFormatter(&buf, io.Discard, false, "folders")
+			formatter.SetCompact(true)
+
+			err := formatter.Format(tt.resources, output.FormatJSON, output.FolderHeaders())
+			require.NoError(t, err)
+
+			out := buf.String()
+			assert.True(t, json.Valid(buf.Bytes()))
+			assert.Equal(t, 1, strings.Count(out, "\n"), "compact JSON should be a single line")
+			assert.NotContains(t, out, "  ")
+			if tt.want != "" {
+				assert.Equal(t, tt.want, out)
+			}
+		})
+	}
+}
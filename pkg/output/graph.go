@@ -0,0 +1,203 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+)
+
+// Kinds of graph nodes.
+const (
+	NodeFolder       = "folder"       // NodeFolder is a listed folder
+	NodeOrganization = "organization" // NodeOrganization is an organization parenting listed folders
+	NodeOrphan       = "orphan"       // NodeOrphan is an unlisted folder or unknown parent of listed folders
+)
+
+// GraphNode is a resource in a folder hierarchy graph.
+type GraphNode struct {
+	Name  string // Name is the resource name, e.g. "folders/123" or "organizations/456"
+	Label string // Label is the display name and ID for folders, and the resource name otherwise
+	Kind  string // Kind is NodeFolder, NodeOrganization or NodeOrphan
+}
+
+// GraphEdge links a parent resource to a child folder by resource name.
+type GraphEdge struct {
+	From string // From is the parent's resource name
+	To   string // To is the child folder's resource name
+}
+
+// Graph is the parent-to-child hierarchy of a folder listing.
+type Graph struct {
+	Nodes []GraphNode // Nodes lists root parents in order of first use, then the folders in listing order
+	Edges []GraphEdge // Edges lists one parent-to-child edge per folder with a parent, in listing order
+}
+
+// BuildGraph builds the hierarchy graph of the given folders. Parents that are not in the list become
+// root nodes: organizations as NodeOrganization, and unlisted folders or other parents as NodeOrphan,
+// so every edge ends at known nodes. Folders listed twice appear once.
+func BuildGraph(folderList []*folders.Folder) Graph {
+	listed := make(map[string]bool, len(folderList))
+	for _, folder := range folderList {
+		listed[folderResourceName(folder)] = true
+	}
+
+	var graph Graph
+	roots := make(map[string]bool)
+	seen := make(map[string]bool, len(folderList))
+	nodes := make([]GraphNode, 0, len(folderList))
+
+	for _, folder := range folderList {
+		name := folderResourceName(folder)
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		nodes = append(nodes, GraphNode{Name: name, Label: folderLabel(folder), Kind: NodeFolder})
+
+		if folder.Parent == "" {
+			continue
+		}
+		if !listed[folder.Parent] && !roots[folder.Parent] {
+			roots[folder.Parent] = true
+			graph.Nodes = append(graph.Nodes, rootNode(folder.Parent))
+		}
+		graph.Edges = append(graph.Edges, GraphEdge{From: folder.Parent, To: name})
+	}
+	graph.Nodes = append(graph.Nodes, nodes...)
+
+	return graph
+}
+
+// RenderDOT writes the hierarchy of the given folders to w as a Graphviz DOT digraph, with an edge from
+// each parent to its child folder. Organizations are drawn as ellipses and unlisted parents as dashed
+// boxes; see BuildGraph.
+func RenderDOT(w io.Writer, folderList []*folders.Folder) error {
+	graph := BuildGraph(folderList)
+
+	var b strings.Builder
+	b.WriteString("digraph folders {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+	for _, node := range graph.Nodes {
+		fmt.Fprintf(&b, "  %s [label=%s%s];\n", dotQuote(node.Name), dotQuote(node.Label), dotNodeStyle(node.Kind))
+	}
+	for _, edge := range graph.Edges {
+		fmt.Fprintf(&b, "  %s -> %s;\n", dotQuote(edge.From), dotQuote(edge.To))
+	}
+	b.WriteString("}\n")
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write DOT graph: %w", err)
+	}
+
+	return nil
+}
+
+// RenderMermaid writes the hierarchy of the given folders to w as a top-down Mermaid flowchart for
+// embedding in Markdown, with an edge from each parent to its child folder. Organizations are drawn as
+// stadiums and unlisted parents with dashed borders; see BuildGraph.
+func RenderMermaid(w io.Writer, folderList []*folders.Folder) error {
+	graph := BuildGraph(folderList)
+
+	var b strings.Builder
+	b.WriteString("graph TD\n")
+	hasOrphans := false
+	for _, node := range graph.Nodes {
+		label := mermaidQuote(node.Label)
+		switch node.Kind {
+		case NodeOrganization:
+			fmt.Fprintf(&b, "  %s([%s])\n", mermaidID(node.Name), label)
+		case NodeOrphan:
+			hasOrphans = true
+			fmt.Fprintf(&b, "  %s[%s]:::orphan\n", mermaidID(node.Name), label)
+		default:
+			fmt.Fprintf(&b, "  %s[%s]\n", mermaidID(node.Name), label)
+		}
+	}
+	for _, edge := range graph.Edges {
+		fmt.Fprintf(&b, "  %s --> %s\n", mermaidID(edge.From), mermaidID(edge.To))
+	}
+	if hasOrphans {
+		b.WriteString("  classDef orphan stroke-dasharray: 5 5\n")
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write Mermaid graph: %w", err)
+	}
+
+	return nil
+}
+
+// IsGraphFormat reports whether format is a graph format, FormatDOT or FormatMermaid.
+func IsGraphFormat(format Format) bool {
+	return format == FormatDOT || format == FormatMermaid
+}
+
+// RenderGraph writes the hierarchy of the given folders to w in a graph format, FormatDOT or
+// FormatMermaid.
+func RenderGraph(w io.Writer, folderList []*folders.Folder, format Format) error {
+	switch format {
+	case FormatDOT:
+		return RenderDOT(w, folderList)
+	case FormatMermaid:
+		return RenderMermaid(w, folderList)
+	default:
+		return fmt.Errorf("%w: %s (supported graph formats: %s, %s)",
+			ErrUnsupportedOutputFormat, format, FormatDOT, FormatMermaid)
+	}
+}
+
+// rootNode returns the node for a parent that is not in the listing.
+func rootNode(name string) GraphNode {
+	kind := NodeOrphan
+	if folders.ParentType(name) == folders.ParentTypeOrganization {
+		kind = NodeOrganization
+	}
+
+	return GraphNode{Name: name, Label: name, Kind: kind}
+}
+
+// folderLabel returns "Display Name (ID)", or just the ID when the folder has no display name.
+func folderLabel(folder *folders.Folder) string {
+	if folder.DisplayName == "" {
+		return folder.ID
+	}
+
+	return fmt.Sprintf("%s (%s)", folder.DisplayName, folder.ID)
+}
+
+// dotNodeStyle returns the DOT attributes that set a node's shape apart from the default folder box.
+func dotNodeStyle(kind string) string {
+	switch kind {
+	case NodeOrganization:
+		return ", shape=ellipse"
+	case NodeOrphan:
+		return ", style=dashed"
+	default:
+		return ""
+	}
+}
+
+// mermaidID returns a Mermaid node ID for a resource name, replacing characters other than ASCII
+// letters, digits and underscores, such as the slash in "folders/123", with underscores.
+func mermaidID(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' {
+			return r
+		}
+
+		return '_'
+	}, name)
+}
+
+// mermaidQuote returns s as a Mermaid double-quoted label, with quotes and line breaks escaped.
+func mermaidQuote(s string) string {
+	return `"` + strings.NewReplacer(`"`, "#quot;", "\n", "<br>").Replace(s) + `"`
+}
+
+// dotQuote returns s as a DOT double-quoted string.
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
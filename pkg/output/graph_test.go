@@ -0,0 +1,137 @@
+package output_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func graphFolders() []*folders.Folder {
+	return []*folders.Folder{
+		{ID: "1", Name: "folders/1", DisplayName: "Engineering", Parent: "organizations/9"},
+		{ID: "2", Name: "folders/2", DisplayName: "Backend", Parent: "folders/1"},
+		{ID: "3", Name: "folders/3", DisplayName: `Team "A"`, Parent: "folders/7"},
+		{ID: "2", Name: "folders/2", DisplayName: "Backend", Parent: "folders/1"},
+	}
+}
+
+func TestBuildGraph(t *testing.T) {
+	graph := output.BuildGraph(graphFolders())
+
+	assert.Equal(t, []output.GraphNode{
+		{Name: "organizations/9", Label: "organizations/9", Kind: output.NodeOrganization},
+		{Name: "folders/7", Label: "folders/7", Kind: output.NodeOrphan},
+		{Name: "folders/1", Label: "Engineering (1)", Kind: output.NodeFolder},
+		{Name: "folders/2", Label: "Backend (2)", Kind: output.NodeFolder},
+		{Name: "folders/3", Label: `Team "A" (3)`, Kind: output.NodeFolder},
+	}, graph.Nodes)
+	assert.Equal(t, []output.GraphEdge{
+		{From: "organizations/9", To: "folders/1"},
+		{From: "folders/1", To: "folders/2"},
+		{From: "folders/7", To: "folders/3"},
+	}, graph.Edges)
+}
+
+func TestRenderDOT(t *testing.T) {
+	tests := map[string]struct {
+		folders   []*folders.Folder
+		wantLines []string
+	}{
+		"nodes and edges": {
+			folders: graphFolders(),
+			wantLines: []string{
+				`digraph folders {`,
+				`  "organizations/9" [label="organizations/9", shape=ellipse];`,
+				`  "folders/7" [label="folders/7", style=dashed];`,
+				`  "folders/1" [label="Engineering (1)"];`,
+				`  "folders/3" [label="Team \"A\" (3)"];`,
+				`  "organizations/9" -> "folders/1";`,
+				`  "folders/1" -> "folders/2";`,
+				`  "folders/7" -> "folders/3";`,
+				`}`,
+			},
+		},
+		"name derived from the ID": {
+			folders: []*folders.Folder{{ID: "5", Parent: "organizations/9"}},
+			wantLines: []string{
+				`  "folders/5" [label="5"];`,
+				`  "organizations/9" -> "folders/5";`,
+			},
+		},
+		"empty listing": {
+			folders:   nil,
+			wantLines: []string{`digraph folders {`, `}`},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, output.RenderDOT(&buf, tt.folders))
+
+			lines := strings.Split(buf.String(), "\n")
+			for _, want := range tt.wantLines {
+				assert.Contains(t, lines, want)
+			}
+		})
+	}
+}
+
+func TestRenderDOTListsEachEdgeOnce(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, output.RenderDOT(&buf, graphFolders()))
+
+	assert.Equal(t, 1, strings.Count(buf.String(), `"folders/1" -> "folders/2";`))
+	assert.Equal(t, 1, strings.Count(buf.String(), `"folders/2" [label=`))
+}
+
+func TestRenderMermaid(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, output.RenderMermaid(&buf, graphFolders()))
+
+	lines := strings.Split(buf.String(), "\n")
+	assert.Equal(t, "graph TD", lines[0])
+	for _, want := range []string{
+		`  organizations_9(["organizations/9"])`,
+		`  folders_7["folders/7"]:::orphan`,
+		`  folders_1["Engineering (1)"]`,
+		`  folders_3["Team #quot;A#quot; (3)"]`,
+		`  organizations_9 --> folders_1`,
+		`  folders_1 --> folders_2`,
+		`  folders_7 --> folders_3`,
+		`  classDef orphan stroke-dasharray: 5 5`,
+	} {
+		assert.Contains(t, lines, want)
+	}
+}
+
+func TestRenderGraph(t *testing.T) {
+	tests := map[string]struct {
+		format     output.Format
+		wantPrefix string
+		wantErr    error
+	}{
+		"dot":         {format: output.FormatDOT, wantPrefix: "digraph folders {"},
+		"mermaid":     {format: output.FormatMermaid, wantPrefix: "graph TD"},
+		"not a graph": {format: output.FormatJSON, wantErr: output.ErrUnsupportedOutputFormat},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := output.RenderGraph(&buf, graphFolders(), tt.format)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(buf.String(), tt.wantPrefix))
+		})
+	}
+}
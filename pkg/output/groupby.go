@@ -0,0 +1,79 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidGroupBy is returned when table rows are grouped by an unsupported field.
+var ErrInvalidGroupBy = errors.New("invalid group-by field")
+
+// noGroupKey labels the group of resources with an empty grouping field.
+const noGroupKey = "(none)"
+
+// GroupBy names the field that table rows are grouped by. The empty GroupBy disables grouping.
+type GroupBy string
+
+// Group-by field constants.
+const (
+	GroupByState  GroupBy = "state"  // GroupByState groups resources by lifecycle state
+	GroupByParent GroupBy = "parent" // GroupByParent groups resources by parent resource name
+)
+
+// ParseGroupBy validates a group-by field name, matched case-insensitively. An empty name disables grouping.
+func ParseGroupBy(name string) (GroupBy, error) {
+	switch field := GroupBy(strings.ToLower(name)); field {
+	case "", GroupByState, GroupByParent:
+		return field, nil
+	default:
+		return "", fmt.Errorf("%w: %s (supported: %s, %s)", ErrInvalidGroupBy, name, GroupByState, GroupByParent)
+	}
+}
+
+// SetGroupBy groups table output by the field, rendering one table per group with a title showing the
+// group's value and row count. Other formats ignore grouping.
+func (f *Formatter) SetGroupBy(field GroupBy) {
+	f.groupBy = field
+}
+
+// resourceGroup is a run of resources sharing the same value of the grouping field.
+type resourceGroup struct {
+	key       string
+	resources []Resource
+}
+
+// groupResources sorts resources by the grouping field, keeping their order within a group, and
+// splits them into groups.
+func groupResources(resources []Resource, field GroupBy) []resourceGroup {
+	accessor := filterFields[string(field)]
+	sorted := slices.Clone(resources)
+	slices.SortStableFunc(sorted, func(a, b Resource) int {
+		return strings.Compare(accessor(a), accessor(b))
+	})
+
+	var groups []resourceGroup
+	for _, resource := range sorted {
+		key := accessor(resource)
+		if last := len(groups) - 1; last >= 0 && groups[last].key == key {
+			groups[last].resources = append(groups[last].resources, resource)
+
+			continue
+		}
+		groups = append(groups, resourceGroup{key: key, resources: []Resource{resource}})
+	}
+
+	return groups
+}
+
+// groupTitle returns the title of a group's table, e.g. "state: ACTIVE (3)", grouping the digits of the
+// row count when human is set.
+func groupTitle(field GroupBy, group resourceGroup, human bool) string {
+	key := group.key
+	if key == "" {
+		key = noGroupKey
+	}
+
+	return fmt.Sprintf("%s: %s (%s)", field, key, formatCount(len(group.resources), human))
+}
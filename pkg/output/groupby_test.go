@@ -0,0 +1,102 @@
+package output_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGroupBy(t *testing.T) {
+	tests := map[string]struct {
+		name    string
+		want    output.GroupBy
+		wantErr error
+	}{
+		"empty disables grouping": {name: "", want: ""},
+		"state":                   {name: "state", want: output.GroupByState},
+		"parent case-insensitive": {name: "Parent", want: output.GroupByParent},
+		"unknown field":           {name: "display_name", wantErr: output.ErrInvalidGroupBy},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := output.ParseGroupBy(tt.name)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatterGroupBy(t *testing.T) {
+	resources := output.FoldersToResources([]*folders.Folder{
+		{ID: "1", DisplayName: "prod", Parent: "organizations/9", State: "ACTIVE"},
+		{ID: "2", DisplayName: "old", Parent: "folders/5", State: "DELETE_REQUESTED"},
+		{ID: "3", DisplayName: "dev", Parent: "organizations/9", State: "ACTIVE"},
+		{ID: "4", DisplayName: "orphan", State: "ACTIVE"},
+	})
+
+	tests := map[string]struct {
+		groupBy output.GroupBy
+		format  output.Format
+		want    []string // want lists substrings that must appear in this order
+		absent  []string
+	}{
+		"table grouped by state": {
+			groupBy: output.GroupByState,
+			format:  output.FormatTable,
+			want:    []string{"state: ACTIVE (3)", "prod", "dev", "orphan", "state: DELETE_REQUESTED (1)", "old"},
+		},
+		"table grouped by parent": {
+			groupBy: output.GroupByParent,
+			format:  output.FormatTable,
+			want: []string{
+				"parent: (none) (1)", "orphan",
+				"parent: folders/5 (1)", "old",
+				"parent: organizations/9 (2)", "prod", "dev",
+			},
+		},
+		"csv ignores grouping": {
+			groupBy: output.GroupByState,
+			format:  output.FormatCSV,
+			want:    []string{"prod", "old", "dev", "orphan"},
+			absent:  []string{"state: ACTIVE"},
+		},
+		"json ignores grouping": {
+			groupBy: output.GroupByState,
+			format:  output.FormatJSON,
+			want:    []string{`"prod"`, `"old"`, `"dev"`, `"orphan"`},
+			absent:  []string{"state: ACTIVE"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			formatter := output.NewFormatter(&stdout, &bytes.Buffer{}, false, output.ResourceTypeFolders)
+			formatter.SetGroupBy(tt.groupBy)
+
+			require.NoError(t, formatter.Format(resources, tt.format, output.FolderHeaders()))
+
+			got := stdout.String()
+			pos := 0
+			for _, want := range tt.want {
+				idx := strings.Index(got[pos:], want)
+				require.GreaterOrEqual(t, idx, 0, "missing %q after offset %d in:\n%s", want, pos, got)
+				pos += idx + len(want)
+			}
+			for _, absent := range tt.absent {
+				assert.NotContains(t, got, absent)
+			}
+		})
+	}
+}
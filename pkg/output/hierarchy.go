@@ -0,0 +1,71 @@
+package output
+
+import "github.com/andreygrechin/gcphelper/pkg/folders"
+
+const folderNamePrefix = "folders/"
+
+// ComputeDepths returns the depth of each folder, keyed by folder ID, relative to the highest
+// ancestor present in the given list. Folders whose parent is outside the list have depth 0.
+func ComputeDepths(folderList []*folders.Folder) map[string]int {
+	byName := make(map[string]*folders.Folder, len(folderList))
+	for _, folder := range folderList {
+		byName[folderResourceName(folder)] = folder
+	}
+
+	depths := make(map[string]int, len(folderList))
+	for _, folder := range folderList {
+		folderDepth(folder, byName, depths, make(map[string]bool))
+	}
+
+	return depths
+}
+
+// DepthColumn returns the "Depth" computed column backed by the given depths.
+func DepthColumn(depths map[string]int) Column {
+	return Column{
+		Header: "Depth",
+		Field:  "depth",
+		Value: func(r Resource) interface{} {
+			return depths[r.GetID()]
+		},
+	}
+}
+
+// folderDepth resolves the depth of a folder, memoizing results and treating cycles as roots.
+func folderDepth(
+	folder *folders.Folder,
+	byName map[string]*folders.Folder,
+	depths map[string]int,
+	visiting map[string]bool,
+) int {
+	if depth, ok := depths[folder.ID]; ok {
+		return depth
+	}
+
+	parent, ok := byName[folder.Parent]
+	if !ok || visiting[folder.ID] {
+		depths[folder.ID] = 0
+
+		return 0
+	}
+
+	visiting[folder.ID] = true
+	depth := folderDepth(parent, byName, depths, visiting) + 1
+
+	// a cycle back to this folder has already assigned its depth
+	if existing, ok := depths[folder.ID]; ok {
+		return existing
+	}
+	depths[folder.ID] = depth
+
+	return depth
+}
+
+// folderResourceName returns the folder's resource name, deriving it from the ID when unset.
+func folderResourceName(folder *folders.Folder) string {
+	if folder.Name != "" {
+		return folder.Name
+	}
+
+	return folderNamePrefix + folder.ID
+}
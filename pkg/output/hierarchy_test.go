@@ -0,0 +1,67 @@
+package output_test
+
+import (
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeDepths(t *testing.T) {
+	tests := map[string]struct {
+		folders []*folders.Folder
+		want    map[string]int
+	}{
+		"nested hierarchy under an organization": {
+			folders: []*folders.Folder{
+				{ID: "3", Name: "folders/3", Parent: "folders/2"},
+				{ID: "1", Name: "folders/1", Parent: "organizations/100"},
+				{ID: "2", Name: "folders/2", Parent: "folders/1"},
+				{ID: "4", Name: "folders/4", Parent: "folders/1"},
+			},
+			want: map[string]int{"1": 0, "2": 1, "3": 2, "4": 1},
+		},
+		"parent outside result set is a root": {
+			folders: []*folders.Folder{
+				{ID: "5", Parent: "folders/999"},
+				{ID: "6", Parent: "folders/5"},
+			},
+			want: map[string]int{"5": 0, "6": 1},
+		},
+		"cycle terminates": {
+			folders: []*folders.Folder{
+				{ID: "7", Parent: "folders/8"},
+				{ID: "8", Parent: "folders/7"},
+			},
+			want: map[string]int{"7": 0, "8": 1},
+		},
+		"empty list": {
+			folders: nil,
+			want:    map[string]int{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, output.ComputeDepths(tt.folders))
+		})
+	}
+}
+
+func TestDepthColumn(t *testing.T) {
+	folderList := []*folders.Folder{
+		{ID: "1", Parent: "organizations/100"},
+		{ID: "2", Parent: "folders/1"},
+	}
+
+	resources, headers := output.WithColumns(
+		output.FoldersToResources(folderList),
+		output.FolderHeaders(),
+		output.DepthColumn(output.ComputeDepths(folderList)),
+	)
+
+	assert.Equal(t, "Depth", headers[len(headers)-1])
+	row := resources[1].TableRow()
+	assert.Equal(t, 1, row[len(row)-1])
+}
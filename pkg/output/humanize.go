@@ -0,0 +1,42 @@
+package output
+
+import (
+	"strconv"
+)
+
+// SetHuman groups the digits of counts in table output, group titles and the verbose summary panel,
+// e.g. 12,345. Other formats always write plain numbers, so scripts can parse them.
+func (f *Formatter) SetHuman(human bool) {
+	f.human = human
+}
+
+// HumanizeInt formats n with a comma between each group of three digits, e.g. 12,345 or -1,000,000.
+// The grouping does not depend on the locale.
+func HumanizeInt(n int) string {
+	digits := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, digits = "-", digits[1:]
+	}
+
+	first := len(digits) % 3
+	if first == 0 {
+		first = 3
+	}
+	grouped := []byte(digits[:first])
+	for i := first; i < len(digits); i += 3 {
+		grouped = append(grouped, ',')
+		grouped = append(grouped, digits[i:i+3]...)
+	}
+
+	return sign + string(grouped)
+}
+
+// formatCount formats a count for human-oriented output, grouping its digits when human is set.
+func formatCount(n int, human bool) string {
+	if human {
+		return HumanizeInt(n)
+	}
+
+	return strconv.Itoa(n)
+}
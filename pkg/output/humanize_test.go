@@ -0,0 +1,106 @@
+package output_test
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHumanizeInt(t *testing.T) {
+	tests := map[string]struct {
+		n    int
+		want string
+	}{
+		"zero":                  {n: 0, want: "0"},
+		"one digit":             {n: 7, want: "7"},
+		"three digits":          {n: 999, want: "999"},
+		"four digits":           {n: 1000, want: "1,000"},
+		"five digits":           {n: 12345, want: "12,345"},
+		"six digits":            {n: 123456, want: "123,456"},
+		"seven digits":          {n: 1234567, want: "1,234,567"},
+		"ten digits":            {n: 1234567890, want: "1,234,567,890"},
+		"negative":              {n: -1000000, want: "-1,000,000"},
+		"negative three digits": {n: -999, want: "-999"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, output.HumanizeInt(tt.n))
+		})
+	}
+}
+
+func TestFormatterHumanCounts(t *testing.T) {
+	folderList := make([]*folders.Folder, 1234)
+	for i := range folderList {
+		folderList[i] = &folders.Folder{ID: strconv.Itoa(i + 1), State: "ACTIVE"}
+	}
+	counts, headers := output.CountResources(output.FoldersToResources(folderList), "state")
+
+	tests := map[string]struct {
+		format      output.Format
+		human       bool
+		contains    []string
+		notContains []string
+	}{
+		"table groups digits": {
+			format:      output.FormatTable,
+			human:       true,
+			contains:    []string{"| ACTIVE | 1,234 |"},
+			notContains: []string{"1234"},
+		},
+		"table without human keeps plain numbers": {
+			format:   output.FormatTable,
+			contains: []string{"| ACTIVE |  1234 |"},
+		},
+		"csv keeps plain numbers": {
+			format:   output.FormatCSV,
+			human:    true,
+			contains: []string{"ACTIVE,1234\n"},
+		},
+		"json keeps plain numbers": {
+			format:   output.FormatJSON,
+			human:    true,
+			contains: []string{`"count": 1234`},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			formatter := output.NewFormatter(&stdout, &bytes.Buffer{}, false, output.ResourceTypeFolders)
+			formatter.SetHuman(tt.human)
+			require.NoError(t, formatter.Format(counts, tt.format, headers))
+
+			for _, want := range tt.contains {
+				assert.Contains(t, stdout.String(), want)
+			}
+			for _, unwanted := range tt.notContains {
+				assert.NotContains(t, stdout.String(), unwanted)
+			}
+		})
+	}
+}
+
+func TestFormatterHumanSummary(t *testing.T) {
+	folderList := make([]*folders.Folder, 12345)
+	for i := range folderList {
+		folderList[i] = &folders.Folder{ID: strconv.Itoa(i + 1), State: "ACTIVE"}
+	}
+
+	var stdout, stderr bytes.Buffer
+	formatter := output.NewFormatter(&stdout, &stderr, true, output.ResourceTypeFolders)
+	formatter.SetHuman(true)
+	formatter.SetGroupBy(output.GroupByState)
+	require.NoError(t, formatter.Format(output.FoldersToResources(folderList), output.FormatTable,
+		[]string{"ID", "State"}))
+
+	assert.Contains(t, stdout.String(), "state: ACTIVE (12,345)")
+	assert.Contains(t, stderr.String(), "| Total folders | 12,345 ")
+	assert.Contains(t, stderr.String(), "ACTIVE: 12,345")
+}
@@ -0,0 +1,59 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidIDStyle is returned when an unknown ID style is requested.
+var ErrInvalidIDStyle = errors.New("invalid ID style")
+
+// IDStyle selects how resource IDs are written in ID output and the ID column.
+type IDStyle string
+
+// ID style constants.
+const (
+	IDStyleShort IDStyle = "short" // IDStyleShort writes bare IDs such as "123456789"
+	IDStyleFull  IDStyle = "full"  // IDStyleFull writes resource names such as "folders/123456789"
+)
+
+// ParseIDStyle validates an ID style name. An empty name selects IDStyleShort.
+func ParseIDStyle(name string) (IDStyle, error) {
+	switch style := IDStyle(name); style {
+	case "", IDStyleShort:
+		return IDStyleShort, nil
+	case IDStyleFull:
+		return style, nil
+	default:
+		return "", fmt.Errorf("%w: %s (supported: %s, %s)", ErrInvalidIDStyle, name, IDStyleShort, IDStyleFull)
+	}
+}
+
+// SetIDStyle selects whether ID output and the ID column use bare IDs or full resource names.
+func (f *Formatter) SetIDStyle(style IDStyle) {
+	f.idStyle = style
+}
+
+// resourceID returns the resource's ID in the formatter's ID style. Resources without a resource
+// name keep their bare ID.
+func (f *Formatter) resourceID(resource Resource) string {
+	if name := resource.GetName(); f.idStyle == IDStyleFull && name != "" {
+		return name
+	}
+
+	return resource.GetID()
+}
+
+// idColumn returns the index of the ID column in headers when full IDs are requested, or -1.
+func (f *Formatter) idColumn(headers []string) int {
+	if f.idStyle != IDStyleFull {
+		return -1
+	}
+	for i, header := range headers {
+		if FieldName(header) == "id" {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,105 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/organizations"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatterIDStyle(t *testing.T) {
+	folderResources := output.FoldersToResources([]*folders.Folder{
+		{ID: "123", Name: "folders/123", DisplayName: "prod"},
+	})
+	orgResources := output.OrganizationsToResources([]*organizations.Organization{
+		{ID: "9", Name: "organizations/9", DisplayName: "Acme"},
+	})
+
+	tests := map[string]struct {
+		resources []output.Resource
+		headers   []string
+		style     output.IDStyle
+		format    output.Format
+		fields    []string
+		want      string
+	}{
+		"folders id short": {
+			resources: folderResources, headers: output.FolderHeaders(),
+			style: output.IDStyleShort, format: output.FormatID,
+			want: "123\n",
+		},
+		"folders id full": {
+			resources: folderResources, headers: output.FolderHeaders(),
+			style: output.IDStyleFull, format: output.FormatID,
+			want: "folders/123\n",
+		},
+		"organizations id short": {
+			resources: orgResources, headers: output.OrganizationHeaders(),
+			style: output.IDStyleShort, format: output.FormatID,
+			want: "9\n",
+		},
+		"organizations id full": {
+			resources: orgResources, headers: output.OrganizationHeaders(),
+			style: output.IDStyleFull, format: output.FormatID,
+			want: "organizations/9\n",
+		},
+		"folders csv column full": {
+			resources: folderResources, headers: output.FolderHeaders(),
+			style: output.IDStyleFull, format: output.FormatCSV, fields: []string{"display_name", "id"},
+			want: "Display Name,ID\nprod,folders/123\n",
+		},
+		"organizations csv column short": {
+			resources: orgResources, headers: output.OrganizationHeaders(),
+			style: output.IDStyleShort, format: output.FormatCSV, fields: []string{"id", "display_name"},
+			want: "ID,Display Name\n9,Acme\n",
+		},
+		"organizations csv column full": {
+			resources: orgResources, headers: output.OrganizationHeaders(),
+			style: output.IDStyleFull, format: output.FormatCSV, fields: []string{"id", "display_name"},
+			want: "ID,Display Name\norganizations/9,Acme\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resources, headers, err := output.SelectFields(tt.resources, tt.headers, tt.fields)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			formatter := output.NewFormatter(&buf, nil, false, "")
+			formatter.SetIDStyle(tt.style)
+			require.NoError(t, formatter.Format(resources, tt.format, headers))
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestParseIDStyle(t *testing.T) {
+	tests := map[string]struct {
+		name    string
+		want    output.IDStyle
+		wantErr error
+	}{
+		"empty defaults to short": {name: "", want: output.IDStyleShort},
+		"short":                   {name: "short", want: output.IDStyleShort},
+		"full":                    {name: "full", want: output.IDStyleFull},
+		"unknown":                 {name: "long", wantErr: output.ErrInvalidIDStyle},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := output.ParseIDStyle(tt.name)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package output
+
+import (
+	"sort"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// UniqueParents returns the distinct parent resource names of the resources, sorted by name. Resources
+// without a parent are skipped.
+func UniqueParents(resources []Resource) []string {
+	seen := make(map[string]bool)
+	var parents []string
+	for _, resource := range resources {
+		parent := parentOf(resource)
+		if parent == "" || seen[parent] {
+			continue
+		}
+		seen[parent] = true
+		parents = append(parents, parent)
+	}
+	sort.Strings(parents)
+
+	return parents
+}
+
+// Parent is a parent resource name found in a listing.
+type Parent struct {
+	Name string `json:"parent"` // Name is the parent's resource name, e.g. "folders/123"
+}
+
+// GetID returns the parent's resource name, so that id output lists one parent per line.
+func (p *Parent) GetID() string {
+	return p.Name
+}
+
+// GetName returns the parent's resource name.
+func (p *Parent) GetName() string {
+	return p.Name
+}
+
+// GetDisplayName returns an empty string since parents are listed by resource name only.
+func (p *Parent) GetDisplayName() string {
+	return ""
+}
+
+// GetState returns an empty string since parents are listed by resource name only.
+func (p *Parent) GetState() string {
+	return ""
+}
+
+// GetCreateTime returns the zero time since parents are listed by resource name only.
+func (p *Parent) GetCreateTime() time.Time {
+	return time.Time{}
+}
+
+// GetUpdateTime returns the zero time since parents are listed by resource name only.
+func (p *Parent) GetUpdateTime() time.Time {
+	return time.Time{}
+}
+
+// TableRow returns the parent's resource name.
+func (p *Parent) TableRow() []interface{} {
+	return table.Row{p.Name}
+}
+
+// ParentResources returns one Parent per distinct parent of the resources, as found by UniqueParents,
+// along with the header of the parent table.
+func ParentResources(resources []Resource) ([]Resource, []string) {
+	parents := UniqueParents(resources)
+	rows := make([]Resource, 0, len(parents))
+	for _, parent := range parents {
+		rows = append(rows, &Parent{Name: parent})
+	}
+
+	return rows, []string{"Parent"}
+}
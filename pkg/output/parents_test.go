@@ -0,0 +1,79 @@
+package output_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUniqueParents(t *testing.T) {
+	tests := map[string]struct {
+		folders []*folders.Folder
+		want    []string
+	}{
+		"shared parent listed once": {
+			folders: []*folders.Folder{
+				{ID: "1", Parent: "organizations/9"},
+				{ID: "2", Parent: "organizations/9"},
+			},
+			want: []string{"organizations/9"},
+		},
+		"differing parents sorted by name": {
+			folders: []*folders.Folder{
+				{ID: "1", Parent: "organizations/9"},
+				{ID: "2", Parent: "folders/5"},
+				{ID: "3", Parent: "folders/1"},
+				{ID: "4", Parent: "folders/5"},
+			},
+			want: []string{"folders/1", "folders/5", "organizations/9"},
+		},
+		"folders without a parent skipped": {
+			folders: []*folders.Folder{{ID: "1"}, {ID: "2", Parent: "folders/5"}},
+			want:    []string{"folders/5"},
+		},
+		"no folders": {
+			folders: nil,
+			want:    nil,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resources := output.FoldersToResources(tt.folders)
+			assert.Equal(t, tt.want, output.UniqueParents(resources))
+
+			// parents are found through the wrappers added for computed columns
+			annotated, _ := output.WithColumns(resources, output.FolderHeaders(), output.ParentTypeColumn())
+			assert.Equal(t, tt.want, output.UniqueParents(annotated))
+		})
+	}
+}
+
+func TestParentResources(t *testing.T) {
+	resources := output.FoldersToResources([]*folders.Folder{
+		{ID: "1", Parent: "organizations/9"},
+		{ID: "2", Parent: "folders/5"},
+		{ID: "3", Parent: "organizations/9"},
+	})
+
+	parents, headers := output.ParentResources(resources)
+	assert.Equal(t, []string{"Parent"}, headers)
+
+	var idOut bytes.Buffer
+	formatter := output.NewFormatter(&idOut, io.Discard, false, output.ResourceTypeFolders)
+	require.NoError(t, formatter.Format(parents, output.FormatID, headers))
+	assert.Equal(t, "folders/5\norganizations/9\n", idOut.String())
+
+	var jsonOut bytes.Buffer
+	formatter = output.NewFormatter(&jsonOut, io.Discard, false, output.ResourceTypeFolders)
+	require.NoError(t, formatter.Format(parents, output.FormatJSON, headers))
+	var decoded []output.Parent
+	require.NoError(t, json.Unmarshal(jsonOut.Bytes(), &decoded))
+	assert.Equal(t, []output.Parent{{Name: "folders/5"}, {Name: "organizations/9"}}, decoded)
+}
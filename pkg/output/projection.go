@@ -0,0 +1,54 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidProjection is returned when a gcloud-style format projection is malformed.
+var ErrInvalidProjection = errors.New("invalid format projection")
+
+// Projection is a parsed --format value: an output format and, for gcloud-style projections such as
+// "value(id,displayName)", the fields to output in order.
+type Projection struct {
+	Format Format
+	Fields []string
+}
+
+// projectionFormats maps the supported gcloud projection wrappers to output formats.
+var projectionFormats = map[string]Format{
+	"table": FormatTable,
+	"value": FormatValue,
+}
+
+// ParseProjection parses a --format value. Besides plain format names it accepts the gcloud
+// projections "value(field,...)" and "table(field,...)", so that gcloud scripts can be reused.
+// Field names follow the --columns rules, so gcloud's camelCase names such as displayName match.
+// Unknown wrappers are reported as unsupported formats.
+func ParseProjection(spec string) (Projection, error) {
+	spec = strings.TrimSpace(spec)
+	open := strings.IndexByte(spec, '(')
+	if open < 0 {
+		format, err := ParseFormat(spec)
+		if err != nil {
+			return Projection{}, err
+		}
+
+		return Projection{Format: format}, nil
+	}
+
+	format, ok := projectionFormats[strings.TrimSpace(spec[:open])]
+	if !ok {
+		return Projection{}, fmt.Errorf("%w: %s", ErrUnsupportedOutputFormat, spec)
+	}
+	if !strings.HasSuffix(spec, ")") {
+		return Projection{}, fmt.Errorf("%w: %s: missing closing parenthesis", ErrInvalidProjection, spec)
+	}
+	fields := ParseFieldList(spec[open+1 : len(spec)-1])
+	if len(fields) == 0 {
+		return Projection{}, fmt.Errorf("%w: %s: no fields given", ErrInvalidProjection, spec)
+	}
+
+	return Projection{Format: format, Fields: fields}, nil
+}
@@ -0,0 +1,107 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProjection(t *testing.T) {
+	tests := map[string]struct {
+		spec    string
+		want    output.Projection
+		wantErr error
+	}{
+		"plain format": {
+			spec: "json",
+			want: output.Projection{Format: output.FormatJSON},
+		},
+		"value single field": {
+			spec: "value(id)",
+			want: output.Projection{Format: output.FormatValue, Fields: []string{"id"}},
+		},
+		"value multiple fields": {
+			spec: "value(id, displayName)",
+			want: output.Projection{Format: output.FormatValue, Fields: []string{"id", "displayName"}},
+		},
+		"table fields": {
+			spec: "table(id,state)",
+			want: output.Projection{Format: output.FormatTable, Fields: []string{"id", "state"}},
+		},
+		"unknown wrapper": {
+			spec:    "yaml(id)",
+			wantErr: output.ErrUnsupportedOutputFormat,
+		},
+		"unknown plain format": {
+			spec:    "xml",
+			wantErr: output.ErrUnsupportedOutputFormat,
+		},
+		"missing closing parenthesis": {
+			spec:    "value(id",
+			wantErr: output.ErrInvalidProjection,
+		},
+		"no fields": {
+			spec:    "table( )",
+			wantErr: output.ErrInvalidProjection,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := output.ParseProjection(tt.spec)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatterValue(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	resources := output.FoldersToResources([]*folders.Folder{
+		{ID: "1", Name: "folders/1", DisplayName: "prod", State: "ACTIVE", CreateTime: created},
+		{ID: "2", Name: "folders/2", DisplayName: "dev team", State: "ACTIVE", CreateTime: created},
+	})
+
+	tests := map[string]struct {
+		fields  []string
+		idStyle output.IDStyle
+		want    string
+	}{
+		"single field": {
+			fields: []string{"id"},
+			want:   "1\n2\n",
+		},
+		"multiple fields are tab-separated": {
+			fields: []string{"displayName", "id"},
+			want:   "prod\t1\ndev team\t2\n",
+		},
+		"full ids": {
+			fields:  []string{"id", "state"},
+			idStyle: output.IDStyleFull,
+			want:    "folders/1\tACTIVE\nfolders/2\tACTIVE\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			selected, headers, err := output.SelectFields(resources, output.FolderHeaders(), tt.fields)
+			require.NoError(t, err)
+
+			var stdout bytes.Buffer
+			formatter := output.NewFormatter(&stdout, &bytes.Buffer{}, false, output.ResourceTypeFolders)
+			formatter.SetIDStyle(tt.idStyle)
+			require.NoError(t, formatter.Format(selected, output.FormatValue, headers))
+			assert.Equal(t, tt.want, stdout.String())
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrNoRawResponse is returned by rawjson output for resources that did not keep their API response.
+var ErrNoRawResponse = errors.New("no raw API response kept for resource")
+
+// rawJSON returns the API response a resource was converted from, encoded with protojson using the
+// original proto field names. The encoding is compacted, since protojson output is not stable.
+func rawJSON(resource Resource) (json.RawMessage, error) {
+	var msg proto.Message
+	if p, ok := unwrapAs[interface{ Proto() proto.Message }](resource); ok {
+		msg = p.Proto()
+	}
+	if msg == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoRawResponse, resource.GetID())
+	}
+
+	data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode raw response of %s: %w", resource.GetID(), err)
+	}
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to encode raw response of %s: %w", resource.GetID(), err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// formatRawJSON writes the API responses of the resources as a JSON array, indented like json output
+// unless compact output is enabled.
+func (f *Formatter) formatRawJSON(resources []Resource) error {
+	messages := make([]json.RawMessage, len(resources))
+	for i, resource := range resources {
+		data, err := rawJSON(resource)
+		if err != nil {
+			return err
+		}
+		messages[i] = data
+	}
+
+	encoder := json.NewEncoder(f.writer)
+	if !f.compact {
+		encoder.SetIndent("", "  ")
+	}
+	if err := encoder.Encode(messages); err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+
+	return nil
+}
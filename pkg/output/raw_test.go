@@ -0,0 +1,99 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	resourcemanagerpb "cloud.google.com/go/resourcemanager/apiv3/resourcemanagerpb"
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/organizations"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func rawFolder() *folders.Folder {
+	pb := &resourcemanagerpb.Folder{
+		Name:        "folders/123",
+		Parent:      "organizations/9",
+		DisplayName: "prod",
+		State:       resourcemanagerpb.Folder_DELETE_REQUESTED,
+		DeleteTime:  timestamppb.New(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)),
+		Etag:        "etag-123",
+	}
+	folder := folders.FolderFromProto(pb)
+	folder.Raw = pb
+
+	return folder
+}
+
+func TestFormatRawJSON(t *testing.T) {
+	org := &organizations.Organization{
+		ID: "9",
+		Raw: &resourcemanagerpb.Organization{
+			Name:  "organizations/9",
+			Owner: &resourcemanagerpb.Organization_DirectoryCustomerId{DirectoryCustomerId: "C0abc"},
+		},
+	}
+
+	tests := map[string]struct {
+		resources []output.Resource
+		compact   bool
+		want      string
+	}{
+		"folder fields our struct omits": {
+			resources: output.FoldersToResources([]*folders.Folder{rawFolder()}),
+			compact:   true,
+			want: `[{"name":"folders/123","parent":"organizations/9","display_name":"prod",` +
+				`"state":"DELETE_REQUESTED","delete_time":"2024-05-01T00:00:00Z","etag":"etag-123"}]` + "\n",
+		},
+		"organization owner": {
+			resources: output.OrganizationsToResources([]*organizations.Organization{org}),
+			want:      "[\n  {\n    \"name\": \"organizations/9\",\n    \"directory_customer_id\": \"C0abc\"\n  }\n]\n",
+		},
+		"empty": {
+			resources: nil,
+			want:      "[]\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			formatter := output.NewFormatter(&stdout, &bytes.Buffer{}, false, output.ResourceTypeFolders)
+			formatter.SetCompact(tt.compact)
+
+			require.NoError(t, formatter.Format(tt.resources, output.FormatRawJSON, nil))
+			assert.Equal(t, tt.want, stdout.String())
+		})
+	}
+}
+
+func TestFormatRawJSONWithoutResponse(t *testing.T) {
+	resources := output.FoldersToResources([]*folders.Folder{{ID: "123"}})
+	formatter := output.NewFormatter(&bytes.Buffer{}, &bytes.Buffer{}, false, output.ResourceTypeFolders)
+
+	err := formatter.Format(resources, output.FormatRawJSON, nil)
+	require.ErrorIs(t, err, output.ErrNoRawResponse)
+}
+
+func TestFormatStreamRawJSONMatchesFormat(t *testing.T) {
+	resources := output.FoldersToResources([]*folders.Folder{rawFolder(), rawFolder()})
+	// selected fields do not change the raw response
+	selected, headers, err := output.SelectFields(resources, output.FolderHeaders(), []string{"id"})
+	require.NoError(t, err)
+
+	var batch, streamed bytes.Buffer
+	require.NoError(t, output.NewFormatter(&batch, nil, false, "folders").Format(resources, output.FormatRawJSON, nil))
+
+	ch := make(chan output.Resource, len(selected))
+	for _, resource := range selected {
+		ch <- resource
+	}
+	close(ch)
+	require.NoError(t, output.NewFormatter(&streamed, nil, false, "folders").FormatStream(ch, output.FormatRawJSON, headers))
+
+	assert.Equal(t, batch.String(), streamed.String())
+}
@@ -0,0 +1,44 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+)
+
+// redactedPrefix starts every redacted display name, so that redacted output is recognizable.
+const redactedPrefix = "redacted-"
+
+// redactedHashLength is the number of hex digits of the display name hash kept in a redacted name.
+const redactedHashLength = 12
+
+// RedactDisplayName returns a deterministic stand-in for a display name, such as "redacted-1f2e3d4c5b6a",
+// derived from a hash of the name, so that equal names stay equal without being revealed. An empty name
+// stays empty.
+func RedactDisplayName(name string) string {
+	if name == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(name))
+
+	return redactedPrefix + hex.EncodeToString(sum[:])[:redactedHashLength]
+}
+
+// RedactDisplayNames returns the resources with the display names of folders, organizations and
+// resolved folder IDs replaced by RedactDisplayName, including in the API responses kept for rawjson
+// output. IDs, resource names and all other fields are kept, and the input resources are not modified.
+func RedactDisplayNames(resources []Resource) []Resource {
+	return renameResources(resources, RedactDisplayName)
+}
+
+// RedactResource returns the resource with its display name replaced by RedactDisplayName, as
+// RedactDisplayNames does for each resource of a listing.
+func RedactResource(resource Resource) Resource {
+	return renameResource(resource, RedactDisplayName)
+}
+
+// RedactFolder returns a copy of the folder with its display name replaced by RedactDisplayName.
+func RedactFolder(folder *folders.Folder) *folders.Folder {
+	return renameFolder(folder, RedactDisplayName)
+}
@@ -0,0 +1,76 @@
+package output_test
+
+import (
+	"strings"
+	"testing"
+
+	resourcemanagerpb "cloud.google.com/go/resourcemanager/apiv3/resourcemanagerpb"
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/organizations"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/andreygrechin/gcphelper/pkg/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactDisplayName(t *testing.T) {
+	redacted := output.RedactDisplayName("Finance")
+
+	assert.True(t, strings.HasPrefix(redacted, "redacted-"))
+	assert.NotContains(t, redacted, "Finance")
+	assert.Equal(t, redacted, output.RedactDisplayName("Finance"), "redaction should be deterministic")
+	assert.NotEqual(t, redacted, output.RedactDisplayName("finance"))
+	assert.Empty(t, output.RedactDisplayName(""))
+}
+
+func TestRedactDisplayNames(t *testing.T) {
+	folder := &folders.Folder{
+		ID: "1", Name: "folders/1", DisplayName: "Finance", Parent: "organizations/9", State: "ACTIVE",
+		Raw: &resourcemanagerpb.Folder{Name: "folders/1", DisplayName: "Finance"},
+	}
+	org := &organizations.Organization{ID: "9", Name: "organizations/9", DisplayName: "example.com"}
+	resolution := &folders.Resolution{ID: "2", Name: "folders/2", DisplayName: "Legal"}
+	setting := &settings.Setting{Name: "format", Value: "json"}
+
+	redacted := output.RedactDisplayNames([]output.Resource{folder, org, resolution, setting})
+	require.Len(t, redacted, 4)
+
+	testCases := map[string]struct {
+		resource output.Resource
+		original output.Resource
+	}{
+		"folder":     {resource: redacted[0], original: folder},
+		"org":        {resource: redacted[1], original: org},
+		"resolution": {resource: redacted[2], original: resolution},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, output.RedactDisplayName(tc.original.GetDisplayName()), tc.resource.GetDisplayName())
+			assert.Equal(t, tc.original.GetID(), tc.resource.GetID())
+			assert.Equal(t, tc.original.GetName(), tc.resource.GetName())
+		})
+	}
+
+	redactedFolder, ok := redacted[0].(*folders.Folder)
+	require.True(t, ok)
+	assert.Equal(t, "organizations/9", redactedFolder.Parent)
+	assert.Equal(t, redactedFolder.DisplayName, redactedFolder.Raw.GetDisplayName())
+	assert.Equal(t, "folders/1", redactedFolder.Raw.GetName())
+	assert.Same(t, setting, redacted[3], "resources without display names should be kept")
+
+	// the input resources are not modified
+	assert.Equal(t, "Finance", folder.DisplayName)
+	assert.Equal(t, "Finance", folder.Raw.GetDisplayName())
+	assert.Equal(t, "example.com", org.DisplayName)
+	assert.Equal(t, "Legal", resolution.DisplayName)
+}
+
+func TestRedactResource(t *testing.T) {
+	resolution := &folders.Resolution{ID: "2", Name: "folders/2", DisplayName: "Legal"}
+
+	redacted := output.RedactResource(resolution)
+	assert.Equal(t, output.RedactDisplayName("Legal"), redacted.GetDisplayName())
+	assert.Equal(t, "folders/2", redacted.GetName())
+	assert.Equal(t, "Legal", resolution.DisplayName, "the input resource should not be modified")
+}
@@ -0,0 +1,94 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrUnknownResourceType is returned when no descriptor is registered for a resource type.
+var ErrUnknownResourceType = errors.New("unknown resource type")
+
+// ErrUnexpectedItems is returned when a descriptor adapter receives items of the wrong type.
+var ErrUnexpectedItems = errors.New("unexpected items for resource type")
+
+// ResourceDescriptor describes how a resource type is converted and rendered.
+type ResourceDescriptor struct {
+	Name        string                              // Name is the plural resource type, e.g. "folders"
+	Headers     []string                            // Headers are the table and CSV column titles
+	ToResources func(items any) ([]Resource, error) // ToResources converts a typed slice to resources
+	Fields      []Column                            // Fields are computed fields that can also be selected
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]ResourceDescriptor)
+)
+
+// Register makes a resource type available to commands by name, with fields as its computed fields. The
+// layer that defines or renders a type registers it, so that this package does not depend on it. It panics
+// if the name is empty, toResources is nil, or the name is already registered.
+func Register(name string, headers []string, toResources func(items any) ([]Resource, error), fields ...Column) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if name == "" || toResources == nil {
+		panic("output: Register requires a name and an adapter")
+	}
+	if _, dup := registry[name]; dup {
+		panic("output: Register called twice for resource type " + name)
+	}
+
+	registry[name] = ResourceDescriptor{
+		Name:        name,
+		Headers:     append([]string(nil), headers...),
+		ToResources: toResources,
+		Fields:      append([]Column(nil), fields...),
+	}
+}
+
+// Lookup returns the descriptor registered for the resource type.
+func Lookup(name string) (ResourceDescriptor, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	desc, ok := registry[name]
+	if !ok {
+		return ResourceDescriptor{}, fmt.Errorf("%w: %s", ErrUnknownResourceType, name)
+	}
+	desc.Headers = append([]string(nil), desc.Headers...)
+
+	return desc, nil
+}
+
+// RegisteredTypes returns the names of all registered resource types in sorted order.
+func RegisteredTypes() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// SliceAdapter returns a ToResources adapter for slices of T, for use with Register.
+func SliceAdapter[T Resource]() func(items any) ([]Resource, error) {
+	return func(items any) ([]Resource, error) {
+		typed, ok := items.([]T)
+		if !ok {
+			return nil, fmt.Errorf("%w: got %T", ErrUnexpectedItems, items)
+		}
+
+		resources := make([]Resource, len(typed))
+		for i, item := range typed {
+			resources[i] = item
+		}
+
+		return resources, nil
+	}
+}
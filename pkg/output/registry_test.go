@@ -0,0 +1,39 @@
+package output_test
+
+import (
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/organizations"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupErrors(t *testing.T) {
+	_, err := output.Lookup("liens")
+	require.ErrorIs(t, err, output.ErrUnknownResourceType)
+
+	_, err = output.SliceAdapter[*folders.Folder]()([]*organizations.Organization{})
+	require.ErrorIs(t, err, output.ErrUnexpectedItems)
+}
+
+func TestRegister(t *testing.T) {
+	output.Register("test-widgets", []string{"ID", "Size"}, output.SliceAdapter[*folders.Folder](), output.EtagColumn())
+
+	desc, err := output.Lookup("test-widgets")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ID", "Size"}, desc.Headers)
+	require.Len(t, desc.Fields, 1)
+	assert.Equal(t, "etag", desc.Fields[0].Field)
+	assert.Contains(t, output.RegisteredTypes(), "test-widgets")
+
+	desc.Headers[0] = "mutated"
+	again, err := output.Lookup("test-widgets")
+	require.NoError(t, err)
+	assert.Equal(t, "ID", again.Headers[0])
+
+	assert.Panics(t, func() {
+		output.Register("test-widgets", nil, output.SliceAdapter[*folders.Folder]())
+	})
+}
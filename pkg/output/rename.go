@@ -0,0 +1,117 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	resourcemanagerpb "cloud.google.com/go/resourcemanager/apiv3/resourcemanagerpb"
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/organizations"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrInvalidNameRegex is returned when a display name regular expression does not compile.
+var ErrInvalidNameRegex = errors.New("invalid name regex")
+
+// Renamer rewrites display names before they are rendered, for example to strip a prefix that
+// every folder name carries.
+type Renamer struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// NewRenamer returns a renamer that replaces the matches of the regular expression pattern in
+// display names with replacement, in which $1 or ${name} stand for the text of submatches as in
+// regexp.Regexp.ReplaceAllString.
+func NewRenamer(pattern, replacement string) (*Renamer, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %w", ErrInvalidNameRegex, pattern, err)
+	}
+
+	return &Renamer{pattern: re, replacement: replacement}, nil
+}
+
+// StripPrefix returns a renamer that removes prefix from the start of display names. Names without
+// the prefix are kept.
+func StripPrefix(prefix string) *Renamer {
+	return &Renamer{pattern: regexp.MustCompile("^" + regexp.QuoteMeta(prefix))}
+}
+
+// Rename returns the rewritten display name.
+func (r *Renamer) Rename(name string) string {
+	return r.pattern.ReplaceAllString(name, r.replacement)
+}
+
+// RenameDisplayNames returns the resources with the display names of folders, organizations and
+// resolved folder IDs rewritten by renamer, including in the API responses kept for rawjson output. The input
+// resources are not modified.
+func RenameDisplayNames(resources []Resource, renamer *Renamer) []Resource {
+	return renameResources(resources, renamer.Rename)
+}
+
+// RenameResource returns the resource with its display name rewritten by renamer, as RenameDisplayNames
+// does for each resource of a listing.
+func RenameResource(resource Resource, renamer *Renamer) Resource {
+	return renameResource(resource, renamer.Rename)
+}
+
+// RenameFolder returns a copy of the folder with its display name rewritten by renamer.
+func RenameFolder(folder *folders.Folder, renamer *Renamer) *folders.Folder {
+	return renameFolder(folder, renamer.Rename)
+}
+
+// renameResources returns copies of the resources with their display names mapped by rename.
+func renameResources(resources []Resource, rename func(string) string) []Resource {
+	renamed := make([]Resource, len(resources))
+	for i, resource := range resources {
+		renamed[i] = renameResource(resource, rename)
+	}
+
+	return renamed
+}
+
+// renameFolder returns a copy of the folder, and of its kept API response, with the display name
+// mapped by rename.
+func renameFolder(folder *folders.Folder, rename func(string) string) *folders.Folder {
+	renamed := *folder
+	renamed.DisplayName = rename(folder.DisplayName)
+	if folder.Raw != nil {
+		raw, ok := proto.Clone(folder.Raw).(*resourcemanagerpb.Folder)
+		if ok {
+			raw.DisplayName = renamed.DisplayName
+			renamed.Raw = raw
+		}
+	}
+
+	return &renamed
+}
+
+// renameResource returns the resource with its display name mapped by rename, or the resource itself
+// when it has no display name to map.
+func renameResource(resource Resource, rename func(string) string) Resource {
+	switch r := resource.(type) {
+	case *folders.Folder:
+		return renameFolder(r, rename)
+	case *organizations.Organization:
+		renamed := *r
+		renamed.DisplayName = rename(r.DisplayName)
+		if r.Raw != nil {
+			raw, ok := proto.Clone(r.Raw).(*resourcemanagerpb.Organization)
+			if ok {
+				raw.DisplayName = renamed.DisplayName
+				renamed.Raw = raw
+			}
+		}
+
+		return &renamed
+	case *folders.Resolution:
+		renamed := *r
+		renamed.DisplayName = rename(r.DisplayName)
+
+		return &renamed
+	default:
+		return resource
+	}
+}
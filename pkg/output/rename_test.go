@@ -0,0 +1,101 @@
+package output_test
+
+import (
+	"testing"
+
+	resourcemanagerpb "cloud.google.com/go/resourcemanager/apiv3/resourcemanagerpb"
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/organizations"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/andreygrechin/gcphelper/pkg/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenamer(t *testing.T) {
+	tests := map[string]struct {
+		pattern     string
+		replacement string
+		name        string
+		want        string
+	}{
+		"strips a cost-center prefix": {
+			pattern: `^CC[0-9]+-`,
+			name:    "CC1234-Finance",
+			want:    "Finance",
+		},
+		"keeps names without a match": {
+			pattern: `^CC[0-9]+-`,
+			name:    "Legal",
+			want:    "Legal",
+		},
+		"replacement with submatches": {
+			pattern:     `^(CC[0-9]+)-(.*)$`,
+			replacement: "$2 (${1})",
+			name:        "CC1234-Finance",
+			want:        "Finance (CC1234)",
+		},
+		"replaces every match": {
+			pattern:     `_`,
+			replacement: " ",
+			name:        "shared_services_prod",
+			want:        "shared services prod",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			renamer, err := output.NewRenamer(tt.pattern, tt.replacement)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, renamer.Rename(tt.name))
+		})
+	}
+}
+
+func TestNewRenamer_InvalidRegex(t *testing.T) {
+	_, err := output.NewRenamer(`^(CC[0-9]+`, "")
+
+	require.ErrorIs(t, err, output.ErrInvalidNameRegex)
+	assert.Contains(t, err.Error(), `"^(CC[0-9]+"`)
+}
+
+func TestStripPrefix(t *testing.T) {
+	renamer := output.StripPrefix("CC.1-")
+
+	assert.Equal(t, "Finance", renamer.Rename("CC.1-Finance"))
+	assert.Equal(t, "CCx1-Finance", renamer.Rename("CCx1-Finance"), "the prefix should match literally")
+	assert.Equal(t, "Ops CC.1-", renamer.Rename("Ops CC.1-"), "only a leading prefix should be removed")
+}
+
+func TestRenameDisplayNames(t *testing.T) {
+	folder := &folders.Folder{
+		ID: "1", Name: "folders/1", DisplayName: "CC1-Finance", Parent: "organizations/9",
+		Raw: &resourcemanagerpb.Folder{Name: "folders/1", DisplayName: "CC1-Finance"},
+	}
+	org := &organizations.Organization{ID: "9", Name: "organizations/9", DisplayName: "CC1-example.com"}
+
+	renamed := output.RenameDisplayNames([]output.Resource{folder, org}, output.StripPrefix("CC1-"))
+	require.Len(t, renamed, 2)
+
+	renamedFolder, ok := renamed[0].(*folders.Folder)
+	require.True(t, ok)
+	assert.Equal(t, "Finance", renamedFolder.DisplayName)
+	assert.Equal(t, "Finance", renamedFolder.Raw.GetDisplayName())
+	assert.Equal(t, "folders/1", renamedFolder.Name)
+	assert.Equal(t, "example.com", renamed[1].GetDisplayName())
+
+	// the input resources are not modified
+	assert.Equal(t, "CC1-Finance", folder.DisplayName)
+	assert.Equal(t, "CC1-Finance", folder.Raw.GetDisplayName())
+	assert.Equal(t, "CC1-example.com", org.DisplayName)
+}
+
+func TestRenameResource(t *testing.T) {
+	folder := &folders.Folder{ID: "1", DisplayName: "CC1-Finance"}
+	setting := &settings.Setting{Name: "format", Value: "json"}
+	renamer := output.StripPrefix("CC1-")
+
+	assert.Equal(t, "Finance", output.RenameResource(folder, renamer).GetDisplayName())
+	assert.Equal(t, "CC1-Finance", folder.DisplayName, "the input resource should not be modified")
+	assert.Same(t, setting, output.RenameResource(setting, renamer), "resources without display names should be kept")
+}
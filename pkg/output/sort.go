@@ -0,0 +1,243 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSortKey is returned when a sort key names an unknown field or has an unknown direction.
+var ErrInvalidSortKey = errors.New("invalid sort key")
+
+// descSuffix marks a sort key as descending, as in "createTime:desc".
+const descSuffix = ":desc"
+
+// ascSuffix marks a sort key as ascending, which is also the default.
+const ascSuffix = ":asc"
+
+// sortFields maps lowercase field names to comparisons of two resources by that field.
+var sortFields = map[string]func(a, b Resource) int{
+	"id":           compareByID,
+	"displayname":  compareByDisplayName,
+	"display_name": compareByDisplayName,
+	"state":        compareText(func(r Resource) string { return r.GetState() }),
+	"name":         compareText(func(r Resource) string { return r.GetName() }),
+	"parent":       compareText(parentOf),
+	"createtime":   compareByCreateTime,
+	"create_time":  compareByCreateTime,
+	"updatetime":   compareByUpdateTime,
+	"update_time":  compareByUpdateTime,
+}
+
+// sortInterfaces maps the lowercase field names accepted by SortBy to the sort.Interface ordering by them.
+var sortInterfaces = map[string]func([]Resource) sort.Interface{
+	"id":           func(r []Resource) sort.Interface { return ByID(r) },
+	"displayname":  func(r []Resource) sort.Interface { return ByDisplayName(r) },
+	"display_name": func(r []Resource) sort.Interface { return ByDisplayName(r) },
+	"createtime":   func(r []Resource) sort.Interface { return ByCreateTime(r) },
+	"create_time":  func(r []Resource) sort.Interface { return ByCreateTime(r) },
+	"updatetime":   func(r []Resource) sort.Interface { return ByUpdateTime(r) },
+	"update_time":  func(r []Resource) sort.Interface { return ByUpdateTime(r) },
+}
+
+// ByID orders resources by ID, numeric IDs by value, like the "id" sort key.
+type ByID []Resource
+
+func (s ByID) Len() int           { return len(s) }
+func (s ByID) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s ByID) Less(i, j int) bool { return compareByID(s[i], s[j]) < 0 }
+
+// ByDisplayName orders resources by display name, ignoring case, like the "displayName" sort key.
+type ByDisplayName []Resource
+
+func (s ByDisplayName) Len() int           { return len(s) }
+func (s ByDisplayName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s ByDisplayName) Less(i, j int) bool { return compareByDisplayName(s[i], s[j]) < 0 }
+
+// ByCreateTime orders resources by creation time, like the "createTime" sort key.
+type ByCreateTime []Resource
+
+func (s ByCreateTime) Len() int           { return len(s) }
+func (s ByCreateTime) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s ByCreateTime) Less(i, j int) bool { return compareByCreateTime(s[i], s[j]) < 0 }
+
+// ByUpdateTime orders resources by last update time, like the "updateTime" sort key.
+type ByUpdateTime []Resource
+
+func (s ByUpdateTime) Len() int           { return len(s) }
+func (s ByUpdateTime) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s ByUpdateTime) Less(i, j int) bool { return compareByUpdateTime(s[i], s[j]) < 0 }
+
+// SortBy sorts resources in place by one field, in descending order when desc is set. The field is
+// one of id, displayName, createTime and updateTime, matched case-insensitively and also accepted in
+// snake case. The sort is stable, so resources with equal values keep their original order.
+func SortBy(resources []Resource, field string, desc bool) error {
+	by, ok := sortInterfaces[strings.ToLower(field)]
+	if !ok {
+		return fmt.Errorf("%w: unknown field %q (supported: id, displayName, createTime, updateTime)",
+			ErrInvalidSortKey, field)
+	}
+
+	data := by(resources)
+	if desc {
+		data = sort.Reverse(data)
+	}
+	sort.Stable(data)
+
+	return nil
+}
+
+// SortKey is one key of a sort order.
+type SortKey struct {
+	Field string // Field is the field name as given, such as "displayName"
+	Desc  bool   // Desc sorts the field in descending order
+
+	compare func(a, b Resource) int
+}
+
+// ParseSortKeys parses a comma-separated list of sort keys such as "state,displayName:desc". The first
+// key is the primary order and each later key breaks ties left by the keys before it. A key may end in
+// ":desc" for descending or ":asc" for ascending order, the default. An empty spec returns no keys.
+func ParseSortKeys(spec string) ([]SortKey, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	var keys []SortKey
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		field, desc := part, false
+		if i := strings.LastIndex(part, ":"); i >= 0 {
+			switch direction := strings.ToLower(part[i:]); direction {
+			case descSuffix:
+				desc = true
+			case ascSuffix:
+			default:
+				return nil, fmt.Errorf("%w: %q has unknown direction %q (supported: asc, desc)",
+					ErrInvalidSortKey, part, strings.TrimPrefix(direction, ":"))
+			}
+			field = strings.TrimSpace(part[:i])
+		}
+
+		compare, ok := sortFields[strings.ToLower(field)]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown field %q (supported: %s)",
+				ErrInvalidSortKey, field, strings.Join(SortFieldNames(), ", "))
+		}
+		keys = append(keys, SortKey{Field: field, Desc: desc, compare: compare})
+	}
+
+	return keys, nil
+}
+
+// SortFieldNames returns the field names accepted by sort keys, in alphabetical order.
+func SortFieldNames() []string {
+	names := make([]string, 0, len(sortFields))
+	for name := range sortFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// SortResources returns the resources ordered by the comma-separated sort keys in spec, as parsed by
+// ParseSortKeys. The sort is stable, so resources equal on every key keep their original order. An
+// empty spec returns the resources unchanged.
+func SortResources(resources []Resource, spec string) ([]Resource, error) {
+	keys, err := ParseSortKeys(spec)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return resources, nil
+	}
+
+	sorted := slices.Clone(resources)
+	slices.SortStableFunc(sorted, func(a, b Resource) int {
+		for _, key := range keys {
+			c := key.compare(a, b)
+			if key.Desc {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+
+		return 0
+	})
+
+	return sorted, nil
+}
+
+// compareByID compares resources by ID with compareIDs.
+func compareByID(a, b Resource) int {
+	return compareIDs(a.GetID(), b.GetID())
+}
+
+// compareByDisplayName compares resources by display name, ignoring case.
+func compareByDisplayName(a, b Resource) int {
+	return strings.Compare(strings.ToLower(a.GetDisplayName()), strings.ToLower(b.GetDisplayName()))
+}
+
+// compareByCreateTime compares resources by creation time with compareTimes.
+func compareByCreateTime(a, b Resource) int {
+	return compareTimes(a.GetCreateTime(), b.GetCreateTime())
+}
+
+// compareByUpdateTime compares resources by last update time with compareTimes.
+func compareByUpdateTime(a, b Resource) int {
+	return compareTimes(a.GetUpdateTime(), b.GetUpdateTime())
+}
+
+// compareTimes orders timestamps chronologically with missing (zero) timestamps after all others, so
+// they sort last in ascending order and first in descending order.
+func compareTimes(a, b time.Time) int {
+	switch {
+	case a.IsZero() && b.IsZero():
+		return 0
+	case a.IsZero():
+		return 1
+	case b.IsZero():
+		return -1
+	default:
+		return a.Compare(b)
+	}
+}
+
+// compareText returns a case-insensitive comparison of the values returned by accessor.
+func compareText(accessor func(Resource) string) func(a, b Resource) int {
+	return func(a, b Resource) int {
+		return strings.Compare(strings.ToLower(accessor(a)), strings.ToLower(accessor(b)))
+	}
+}
+
+// compareIDs orders numeric IDs by value and falls back to text order for other IDs.
+func compareIDs(a, b string) int {
+	if isDigits(a) && isDigits(b) {
+		a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+		if len(a) != len(b) {
+			return len(a) - len(b)
+		}
+	}
+
+	return strings.Compare(a, b)
+}
+
+// isDigits reports whether s is a non-empty string of ASCII digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
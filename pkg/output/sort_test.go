@@ -0,0 +1,263 @@
+package output_test
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortResources(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	resources := output.FoldersToResources([]*folders.Folder{
+		{ID: "10", DisplayName: "beta", State: "ACTIVE", CreateTime: base.Add(3 * time.Hour)},
+		{ID: "9", DisplayName: "Alpha", State: "DELETE_REQUESTED", CreateTime: base.Add(1 * time.Hour)},
+		{ID: "30", DisplayName: "alpha", State: "ACTIVE", CreateTime: base.Add(2 * time.Hour)},
+		{ID: "2", DisplayName: "gamma", State: "ACTIVE", CreateTime: base.Add(2 * time.Hour)},
+	})
+
+	tests := map[string]struct {
+		spec    string
+		wantIDs []string
+	}{
+		"empty spec keeps order": {
+			spec:    "",
+			wantIDs: []string{"10", "9", "30", "2"},
+		},
+		"numeric IDs by value": {
+			spec:    "id",
+			wantIDs: []string{"2", "9", "10", "30"},
+		},
+		"ties on the primary key broken by the second": {
+			spec:    "state,displayName",
+			wantIDs: []string{"30", "10", "2", "9"},
+		},
+		"descending tie-breaker": {
+			spec:    "state,displayName:desc",
+			wantIDs: []string{"2", "10", "30", "9"},
+		},
+		"descending primary with time tie-breaker": {
+			spec:    "state:desc,createTime",
+			wantIDs: []string{"9", "30", "2", "10"},
+		},
+		"three keys": {
+			spec:    "createTime,state,id:desc",
+			wantIDs: []string{"9", "30", "2", "10"},
+		},
+		"full ties keep the original order": {
+			spec:    "display_name",
+			wantIDs: []string{"9", "30", "10", "2"},
+		},
+		"explicit ascending and spaces": {
+			spec:    " state:ASC , id ",
+			wantIDs: []string{"2", "10", "30", "9"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			sorted, err := output.SortResources(resources, tt.spec)
+			require.NoError(t, err)
+
+			ids := make([]string, len(sorted))
+			for i, resource := range sorted {
+				ids[i] = resource.GetID()
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+
+	assert.Equal(t, "10", resources[0].GetID(), "sorting should not reorder the input")
+}
+
+func TestParseSortKeys(t *testing.T) {
+	tests := map[string]struct {
+		spec    string
+		want    []output.SortKey
+		wantErr string
+	}{
+		"keys with directions": {
+			spec: "state,createTime:desc",
+			want: []output.SortKey{{Field: "state"}, {Field: "createTime", Desc: true}},
+		},
+		"every key is validated": {
+			spec:    "state,size",
+			wantErr: `unknown field "size"`,
+		},
+		"unknown direction": {
+			spec:    "state:down",
+			wantErr: `unknown direction "down"`,
+		},
+		"empty key": {
+			spec:    "state,,id",
+			wantErr: `unknown field ""`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			keys, err := output.ParseSortKeys(tt.spec)
+			if tt.wantErr != "" {
+				require.ErrorIs(t, err, output.ErrInvalidSortKey)
+				assert.Contains(t, err.Error(), tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, keys, len(tt.want))
+			for i, key := range keys {
+				assert.Equal(t, tt.want[i].Field, key.Field)
+				assert.Equal(t, tt.want[i].Desc, key.Desc)
+			}
+		})
+	}
+}
+
+func TestSortInterfaces(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newResources := func() []output.Resource {
+		return output.FoldersToResources([]*folders.Folder{
+			{ID: "10", DisplayName: "beta", CreateTime: base.Add(3 * time.Hour), UpdateTime: base},
+			{ID: "9", DisplayName: "Alpha", CreateTime: base.Add(1 * time.Hour), UpdateTime: base.Add(time.Hour)},
+			{ID: "30", DisplayName: "alpha", CreateTime: base.Add(2 * time.Hour), UpdateTime: base},
+			{ID: "2", DisplayName: "gamma", CreateTime: base.Add(2 * time.Hour), UpdateTime: base.Add(time.Hour)},
+		})
+	}
+
+	tests := map[string]struct {
+		by      func([]output.Resource) sort.Interface
+		field   string
+		wantIDs []string
+	}{
+		"by ID": {
+			by:      func(r []output.Resource) sort.Interface { return output.ByID(r) },
+			field:   "id",
+			wantIDs: []string{"2", "9", "10", "30"},
+		},
+		"by display name": {
+			by:      func(r []output.Resource) sort.Interface { return output.ByDisplayName(r) },
+			field:   "displayName",
+			wantIDs: []string{"9", "30", "10", "2"},
+		},
+		"by create time": {
+			by:      func(r []output.Resource) sort.Interface { return output.ByCreateTime(r) },
+			field:   "create_time",
+			wantIDs: []string{"9", "30", "2", "10"},
+		},
+		"by update time": {
+			by:      func(r []output.Resource) sort.Interface { return output.ByUpdateTime(r) },
+			field:   "updateTime",
+			wantIDs: []string{"10", "30", "9", "2"},
+		},
+	}
+
+	ids := func(resources []output.Resource) []string {
+		result := make([]string, len(resources))
+		for i, resource := range resources {
+			result[i] = resource.GetID()
+		}
+
+		return result
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resources := newResources()
+			data := tt.by(resources)
+			require.Equal(t, len(resources), data.Len())
+			assert.True(t, data.Less(indexOf(t, resources, tt.wantIDs[0]), indexOf(t, resources, tt.wantIDs[3])))
+			assert.False(t, data.Less(indexOf(t, resources, tt.wantIDs[3]), indexOf(t, resources, tt.wantIDs[0])))
+
+			sort.Stable(data)
+			assert.Equal(t, tt.wantIDs, ids(resources))
+
+			// SortBy matches the comparator and the CLI's sort keys in both directions
+			for _, desc := range []bool{false, true} {
+				sorted := newResources()
+				require.NoError(t, output.SortBy(sorted, tt.field, desc))
+
+				spec := tt.field
+				if desc {
+					spec += ":desc"
+				}
+				want, err := output.SortResources(newResources(), spec)
+				require.NoError(t, err)
+				assert.Equal(t, ids(want), ids(sorted))
+			}
+		})
+	}
+}
+
+func TestSortBy_UnknownField(t *testing.T) {
+	err := output.SortBy(nil, "state", false)
+	require.ErrorIs(t, err, output.ErrInvalidSortKey)
+	assert.Contains(t, err.Error(), `unknown field "state"`)
+}
+
+// indexOf returns the index of the resource with the given ID.
+func indexOf(t *testing.T, resources []output.Resource, id string) int {
+	t.Helper()
+
+	for i, resource := range resources {
+		if resource.GetID() == id {
+			return i
+		}
+	}
+	require.Failf(t, "resource not found", "no resource with ID %s", id)
+
+	return -1
+}
+
+func TestSortResources_ZeroTimes(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	resources := output.FoldersToResources([]*folders.Folder{
+		{ID: "1", CreateTime: base.Add(2 * time.Hour), UpdateTime: base},
+		{ID: "2"},
+		{ID: "3", CreateTime: base.Add(1 * time.Hour)},
+		{ID: "4", UpdateTime: base.Add(time.Hour)},
+		{ID: "5", CreateTime: base.Add(3 * time.Hour)},
+	})
+
+	tests := map[string]struct {
+		spec    string
+		wantIDs []string
+	}{
+		"create time ascending puts zero times last": {
+			spec:    "createTime",
+			wantIDs: []string{"3", "1", "5", "2", "4"},
+		},
+		"create time descending puts zero times first": {
+			spec:    "createTime:desc",
+			wantIDs: []string{"2", "4", "5", "1", "3"},
+		},
+		"update time ascending puts zero times last": {
+			spec:    "update_time",
+			wantIDs: []string{"1", "4", "2", "3", "5"},
+		},
+		"update time descending puts zero times first": {
+			spec:    "update_time:desc",
+			wantIDs: []string{"2", "3", "5", "4", "1"},
+		},
+		"zero times tied on the time broken by the next key": {
+			spec:    "createTime,id:desc",
+			wantIDs: []string{"3", "1", "5", "4", "2"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			sorted, err := output.SortResources(resources, tt.spec)
+			require.NoError(t, err)
+
+			ids := make([]string, len(sorted))
+			for i, resource := range sorted {
+				ids[i] = resource.GetID()
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
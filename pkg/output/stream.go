@@ -0,0 +1,223 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// FormatStream outputs resources as they arrive on the channel, until it is closed. JSON, JSONL, BigQuery,
+// CSV, ID, and value output is written incrementally, producing the same bytes as Format; table output needs every
+// row to size its columns, templates range over the whole result set and env output keeps its variable names
+// unique across it, so these are collected and rendered once the channel is closed, like formats added with
+// RegisterFormat.
+//
+// JSON, raw JSON and CSV output is buffered while resources arrive back to back and flushed whenever
+// the channel has no resource ready, such as while the next page is fetched, so a streaming reader sees
+// each page as soon as it is written. The JSON array is opened before the first resource arrives.
+//
+// FormatStream stops reading on the first error, so producers should select on a cancellable context
+// rather than block on the channel.
+func (f *Formatter) FormatStream(resources <-chan Resource, format Format, headers []string) error {
+	switch format {
+	case FormatJSON:
+		return f.streamJSON(resources, f.jsonElement)
+	case FormatRawJSON:
+		return f.streamJSON(resources, rawJSON)
+	case FormatJSONL:
+		return f.streamJSONL(resources)
+	case FormatBigQuery:
+		return f.streamBigQuery(resources)
+	case FormatCSV:
+		return f.streamCSV(resources, headers)
+	case FormatID:
+		return f.streamID(resources)
+	case FormatValue:
+		return f.streamValue(resources, headers)
+	default:
+		// tables, templates, env and registered formats are rendered once every resource has arrived
+		handler, err := lookupFormat(format)
+		if err != nil {
+			return err
+		}
+
+		var collected []Resource
+		for resource := range resources {
+			collected = append(collected, resource)
+		}
+
+		return handler(f, collected, headers)
+	}
+}
+
+// streamJSON writes a JSON array one element at a time, with each element's value given by element,
+// matching the output of formatJSON and formatRawJSON.
+func (f *Formatter) streamJSON(resources <-chan Resource, element func(Resource) (json.RawMessage, error)) error {
+	w := bufio.NewWriter(f.writer)
+
+	first, sep, closing := "\n  ", ",\n  ", "\n]\n"
+	if f.compact {
+		first, sep, closing = "", ",", "]\n"
+	}
+
+	w.WriteString("[")
+	count := 0
+	for {
+		resource, ok, err := f.next(resources, w)
+		if err != nil {
+			return fmt.Errorf("failed to write JSON: %w", err)
+		}
+		if !ok {
+			break
+		}
+
+		value, err := element(resource)
+		if err != nil {
+			return err
+		}
+		data, err := f.marshalElement(value)
+		if err != nil {
+			return err
+		}
+
+		if count == 0 {
+			w.WriteString(first)
+		} else {
+			w.WriteString(sep)
+		}
+		w.Write(data)
+		count++
+	}
+
+	if count == 0 {
+		w.WriteString("]\n")
+	} else {
+		w.WriteString(closing)
+	}
+
+	if err := f.flush(w); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+
+	return nil
+}
+
+// next receives the next resource from the channel. When none is ready it first flushes w and the
+// formatter's writer, so that output written so far reaches the reader while the producer is busy.
+// It reports false once the channel is closed.
+func (f *Formatter) next(resources <-chan Resource, w *bufio.Writer) (Resource, bool, error) {
+	select {
+	case resource, ok := <-resources:
+		return resource, ok, nil
+	default:
+	}
+
+	if err := f.flush(w); err != nil {
+		return nil, false, err
+	}
+	resource, ok := <-resources
+
+	return resource, ok, nil
+}
+
+// flush writes the buffered output of w and flushes the formatter's writer when it buffers output itself.
+func (f *Formatter) flush(w *bufio.Writer) error {
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if flusher, ok := f.writer.(interface{ Flush() error }); ok {
+		return flusher.Flush()
+	}
+
+	return nil
+}
+
+// jsonElement encodes the JSON value of a resource in json output.
+func (f *Formatter) jsonElement(resource Resource) (json.RawMessage, error) {
+	data, err := json.Marshal(f.jsonResource(resource))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode JSON: %w", err)
+	}
+
+	return data, nil
+}
+
+// marshalElement formats a single array element with the indentation a JSON encoder would give it.
+func (f *Formatter) marshalElement(value json.RawMessage) ([]byte, error) {
+	if f.compact {
+		return value, nil
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, value, "  ", "  "); err != nil {
+		return nil, fmt.Errorf("failed to encode JSON: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (f *Formatter) streamJSONL(resources <-chan Resource) error {
+	encoder := json.NewEncoder(f.writer)
+	for resource := range resources {
+		if err := encoder.Encode(f.jsonResource(resource)); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (f *Formatter) streamCSV(resources <-chan Resource, headers []string) error {
+	w := bufio.NewWriter(f.writer)
+
+	headerRow := make(table.Row, len(headers))
+	for i, h := range headers {
+		headerRow[i] = h
+	}
+	w.WriteString(csvLine(headerRow))
+
+	idColumn := f.idColumn(headers)
+	for {
+		resource, ok, err := f.next(resources, w)
+		if err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+		if !ok {
+			break
+		}
+		w.WriteString(csvLine(f.row(resource, idColumn)))
+	}
+
+	if err := f.flush(w); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	return nil
+}
+
+func (f *Formatter) streamID(resources <-chan Resource) error {
+	count := 0
+	for resource := range resources {
+		if _, err := fmt.Fprintln(f.writer, f.resourceID(resource)); err != nil {
+			return fmt.Errorf("failed to write resource ID: %w", err)
+		}
+		count++
+	}
+
+	if count == 0 && f.verbose {
+		f.printStatus("No %s found.\n", f.resourceLabel())
+	}
+
+	return nil
+}
+
+// csvLine renders a single row with go-pretty so that quoting and escaping match formatCSV.
+func csvLine(row table.Row) string {
+	t := table.NewWriter()
+	t.AppendRow(row)
+
+	return t.RenderCSV() + "\n"
+}
@@ -0,0 +1,187 @@
+package output_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func syntheticFolder(i int) *folders.Folder {
+	baseTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	return &folders.Folder{
+		Name:        "folders/" + strconv.Itoa(i),
+		ID:          strconv.Itoa(i),
+		DisplayName: "Folder " + strconv.Itoa(i),
+		Parent:      "organizations/123456789",
+		State:       "ACTIVE",
+		CreateTime:  baseTime,
+		UpdateTime:  baseTime.Add(time.Hour),
+	}
+}
+
+func sendResources(resources []output.Resource) <-chan output.Resource {
+	ch := make(chan output.Resource)
+	go func() {
+		defer close(ch)
+		for _, resource := range resources {
+			ch <- resource
+		}
+	}()
+
+	return ch
+}
+
+func TestFormatter_FormatStreamMatchesFormat(t *testing.T) {
+	quoted := syntheticFolder(2)
+	quoted.DisplayName = `Team "A", Prod`
+
+	tests := map[string]struct {
+		resources []output.Resource
+	}{
+		"multiple resources": {
+			resources: output.FoldersToResources([]*folders.Folder{syntheticFolder(1), quoted, syntheticFolder(3)}),
+		},
+		"single resource": {
+			resources: output.FoldersToResources([]*folders.Folder{syntheticFolder(1)}),
+		},
+		"no resources": {
+			resources: []output.Resource{},
+		},
+	}
+
+	formats := []output.Format{
+		output.FormatJSON,
+		output.FormatJSONL,
+		output.FormatCSV,
+		output.FormatID,
+		output.FormatTable,
+	}
+
+	for name, tt := range tests {
+		for _, format := range formats {
+			for _, compact := range []bool{false, true} {
+				t.Run(fmt.Sprintf("%s/%s/compact=%t", name, format, compact), func(t *testing.T) {
+					var buffered, streamed bytes.Buffer
+					bufferedFormatter := output.NewFormatter(&buffered, io.Discard, false, "folders")
+					bufferedFormatter.SetCompact(compact)
+					err := bufferedFormatter.Format(tt.resources, format, output.FolderHeaders())
+					require.NoError(t, err)
+
+					streamingFormatter := output.NewFormatter(&streamed, io.Discard, false, "folders")
+					streamingFormatter.SetCompact(compact)
+					err = streamingFormatter.FormatStream(sendResources(tt.resources), format, output.FolderHeaders())
+					require.NoError(t, err)
+
+					assert.Equal(t, buffered.String(), streamed.String())
+				})
+			}
+		}
+	}
+}
+
+func TestFormatter_FormatStreamFlushesJSONPerPage(t *testing.T) {
+	resources := output.FoldersToResources([]*folders.Folder{syntheticFolder(1), syntheticFolder(2)})
+
+	var buffered bytes.Buffer
+	err := output.NewFormatter(&buffered, io.Discard, false, "folders").
+		Format(resources, output.FormatJSON, output.FolderHeaders())
+	require.NoError(t, err)
+
+	reader, writer := io.Pipe()
+	ch := make(chan output.Resource)
+	done := make(chan error, 1)
+	go func() {
+		err := output.NewFormatter(writer, io.Discard, false, "folders").
+			FormatStream(ch, output.FormatJSON, output.FolderHeaders())
+		writer.CloseWithError(err)
+		done <- err
+	}()
+
+	// the first page is readable while the channel is still open
+	ch <- resources[0]
+	var received []byte
+	chunk := make([]byte, 4096)
+	for !bytes.Contains(received, []byte(`"id": "1"`)) {
+		n, err := reader.Read(chunk)
+		require.NoError(t, err)
+		received = append(received, chunk[:n]...)
+	}
+	assert.True(t, bytes.HasPrefix(received, []byte("[\n  {")))
+	assert.NotContains(t, string(received), "]\n")
+
+	ch <- resources[1]
+	close(ch)
+	rest, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, <-done)
+
+	assert.Equal(t, buffered.String(), string(received)+string(rest))
+}
+
+func TestFormatter_FormatJSONL(t *testing.T) {
+	resources := output.FoldersToResources([]*folders.Folder{syntheticFolder(1), syntheticFolder(2)})
+
+	var buf bytes.Buffer
+	err := output.NewFormatter(&buf, io.Discard, false, "folders").
+		Format(resources, output.FormatJSONL, output.FolderHeaders())
+	require.NoError(t, err)
+
+	lines := bytes.Split(bytes.TrimSuffix(buf.Bytes(), []byte("\n")), []byte("\n"))
+	require.Len(t, lines, 2)
+	assert.Contains(t, string(lines[0]), `"id":"1"`)
+	assert.Contains(t, string(lines[1]), `"id":"2"`)
+}
+
+func TestFormatter_FormatStreamUnsupportedFormat(t *testing.T) {
+	err := output.NewFormatter(io.Discard, io.Discard, false, "folders").
+		FormatStream(make(chan output.Resource), output.Format("xml"), output.FolderHeaders())
+	require.ErrorIs(t, err, output.ErrUnsupportedOutputFormat)
+}
+
+const benchmarkFolderCount = 100_000
+
+// BenchmarkFormatJSON_Buffered materializes every folder before encoding, as the slice API does.
+func BenchmarkFormatJSON_Buffered(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		folderList := make([]*folders.Folder, benchmarkFolderCount)
+		for i := range folderList {
+			folderList[i] = syntheticFolder(i)
+		}
+
+		err := output.NewFormatter(io.Discard, io.Discard, false, "folders").
+			Format(output.FoldersToResources(folderList), output.FormatJSON, output.FolderHeaders())
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkFormatJSON_Streaming encodes folders as they are produced, holding at most one at a time.
+func BenchmarkFormatJSON_Streaming(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		ch := make(chan output.Resource)
+		go func() {
+			defer close(ch)
+			for i := range benchmarkFolderCount {
+				ch <- syntheticFolder(i)
+			}
+		}()
+
+		err := output.NewFormatter(io.Discard, io.Discard, false, "folders").
+			FormatStream(ch, output.FormatJSON, output.FolderHeaders())
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// noParentFilter is shown in the summary panel when results were not limited to a parent.
+const noParentFilter = "none"
+
+// SummaryOptions configures the verbose summary panel.
+type SummaryOptions struct {
+	ResourceType string // ResourceType is the plural resource name, e.g. "folders"
+	Parent       string // Parent is the parent filter used for the listing, if any
+	Human        bool   // Human groups the digits of counts, e.g. 12,345
+}
+
+// RenderSummaryPanel writes a framed summary of the resources to w, listing the total count,
+// the count of resources in each state, and the parent filter used.
+func RenderSummaryPanel(w io.Writer, resources []Resource, opts SummaryOptions) {
+	resourceType := opts.ResourceType
+	if resourceType == "" {
+		resourceType = defaultResourceType
+	}
+	parent := opts.Parent
+	if parent == "" {
+		parent = noParentFilter
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	t.SetStyle(table.StyleDefault)
+	t.SetTitle("Summary")
+	t.AppendRow(table.Row{"Total " + resourceType, formatCount(len(resources), opts.Human)})
+	t.AppendRow(table.Row{"By state", stateBreakdown(resources, opts.Human)})
+	t.AppendRow(table.Row{"Parent filter", parent})
+	t.Render()
+}
+
+// SummaryLine is the machine-readable summary of a listing, written as one JSON line.
+type SummaryLine struct {
+	Resource  string `json:"resource"`   // Resource is the plural resource name, e.g. "folders"
+	Count     int    `json:"count"`      // Count is the number of resources written
+	ElapsedMS int64  `json:"elapsed_ms"` // ElapsedMS is the time since the command started, in milliseconds
+}
+
+// WriteSummaryLine writes line to w as a single line of JSON.
+func WriteSummaryLine(w io.Writer, line SummaryLine) error {
+	if err := json.NewEncoder(w).Encode(line); err != nil {
+		return fmt.Errorf("failed to write summary: %w", err)
+	}
+
+	return nil
+}
+
+// stateBreakdown formats per-state counts as "STATE: N" pairs sorted by state name.
+func stateBreakdown(resources []Resource, human bool) string {
+	counts := make(map[string]int)
+	for _, resource := range resources {
+		counts[resource.GetState()]++
+	}
+
+	states := make([]string, 0, len(counts))
+	for state := range counts {
+		states = append(states, state)
+	}
+	sort.Strings(states)
+
+	parts := make([]string, 0, len(states))
+	for _, state := range states {
+		parts = append(parts, state+": "+formatCount(counts[state], human))
+	}
+
+	return strings.Join(parts, ", ")
+}
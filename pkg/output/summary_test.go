@@ -0,0 +1,64 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderSummaryPanel(t *testing.T) {
+	folderList := []*folders.Folder{
+		{ID: "1", State: "ACTIVE"},
+		{ID: "2", State: "DELETE_REQUESTED"},
+		{ID: "3", State: "ACTIVE"},
+		{ID: "4", State: "ACTIVE"},
+	}
+
+	tests := map[string]struct {
+		opts         output.SummaryOptions
+		wantContains []string
+	}{
+		"state breakdown and parent": {
+			opts: output.SummaryOptions{ResourceType: "folders", Parent: "organizations/123"},
+			wantContains: []string{
+				"Summary",
+				"| Total folders | 4 ",
+				"ACTIVE: 3, DELETE_REQUESTED: 1",
+				"organizations/123",
+			},
+		},
+		"human counts": {
+			opts:         output.SummaryOptions{ResourceType: "folders", Human: true},
+			wantContains: []string{"| Total folders | 4 ", "ACTIVE: 3, DELETE_REQUESTED: 1"},
+		},
+		"no parent filter": {
+			opts: output.SummaryOptions{ResourceType: "folders"},
+			wantContains: []string{
+				"ACTIVE: 3, DELETE_REQUESTED: 1",
+				"| Parent filter | none",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			output.RenderSummaryPanel(&buf, output.FoldersToResources(folderList), tt.opts)
+
+			for _, want := range tt.wantContains {
+				assert.Contains(t, buf.String(), want)
+			}
+		})
+	}
+}
+
+func TestWriteSummaryLine(t *testing.T) {
+	var buf bytes.Buffer
+
+	require.NoError(t, output.WriteSummaryLine(&buf, output.SummaryLine{Resource: "folders", Count: 3, ElapsedMS: 42}))
+	assert.Equal(t, `{"resource":"folders","count":3,"elapsed_ms":42}`+"\n", buf.String())
+}
@@ -0,0 +1,123 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// ErrInvalidTemplate is returned when an output template cannot be loaded or parsed.
+var ErrInvalidTemplate = errors.New("invalid template")
+
+// ErrMissingTemplate is returned when template output is requested without a template.
+var ErrMissingTemplate = errors.New("template output requires --template or --template-file")
+
+// templateFuncs are the helper functions available in output templates.
+var templateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+	"join":  joinItems,
+}
+
+// Template is a parsed text/template for rendering a whole result set.
+type Template struct {
+	tmpl *template.Template
+}
+
+// ParseTemplate parses an output template. The template is executed once with the full list of
+// resources, each a map keyed by its JSON field names, so it can {{range}} over the result set:
+//
+//	{{range .}}{{.id}}: {{upper .display_name}}{{"\n"}}{{end}}
+//
+// The helper functions upper, lower, trim and join are available.
+func ParseTemplate(name, text string) (*Template, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTemplate, err)
+	}
+
+	return &Template{tmpl: tmpl}, nil
+}
+
+// ParseTemplateFile reads and parses an output template from path. Errors name the file.
+func ParseTemplateFile(path string) (*Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read template file: %w", ErrInvalidTemplate, err)
+	}
+
+	return ParseTemplate(path, string(data))
+}
+
+// Execute renders the template with data to w.
+func (t *Template) Execute(w io.Writer, data any) error {
+	if err := t.tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return nil
+}
+
+// SetTemplate sets the template used for FormatTemplate output.
+func (f *Formatter) SetTemplate(tmpl *Template) {
+	f.template = tmpl
+}
+
+// formatTemplate executes the formatter's template with the resources as a list of JSON objects.
+func (f *Formatter) formatTemplate(resources []Resource) error {
+	if f.template == nil {
+		return ErrMissingTemplate
+	}
+
+	items := make([]map[string]any, 0, len(resources))
+	for _, resource := range resources {
+		item, err := f.templateItem(resource)
+		if err != nil {
+			return err
+		}
+		items = append(items, item)
+	}
+
+	return f.template.Execute(f.writer, items)
+}
+
+// templateItem converts a resource to the map exposed to templates, keyed by its JSON field names.
+func (f *Formatter) templateItem(resource Resource) (map[string]any, error) {
+	data, err := json.Marshal(f.jsonResource(resource))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode JSON: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var item map[string]any
+	if err := dec.Decode(&item); err != nil {
+		return nil, ErrNonObjectResource
+	}
+
+	return item, nil
+}
+
+// joinItems joins the string forms of a list's elements with sep.
+func joinItems(items any, sep string) string {
+	switch list := items.(type) {
+	case []string:
+		return strings.Join(list, sep)
+	case []any:
+		parts := make([]string, len(list))
+		for i, item := range list {
+			parts[i] = fmt.Sprint(item)
+		}
+
+		return strings.Join(parts, sep)
+	default:
+		return fmt.Sprint(items)
+	}
+}
@@ -0,0 +1,93 @@
+package output_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTemplate(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "report.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func TestFormatTemplateFile(t *testing.T) {
+	created := time.Date(2024, 1, 15, 12, 30, 0, 0, time.UTC)
+	resources := output.FoldersToResources([]*folders.Folder{
+		{ID: "1", DisplayName: "prod", Parent: "organizations/9", State: "ACTIVE", CreateTime: created},
+		{ID: "2", DisplayName: "staging", Parent: "folders/1", State: "ACTIVE", CreateTime: created},
+	})
+
+	tests := map[string]struct {
+		template string
+		want     string
+	}{
+		"range over the result set": {
+			template: "Folders ({{len .}}):\n{{range .}}- {{.id}} {{upper .display_name}} under {{.parent}}\n{{end}}",
+			want:     "Folders (2):\n- 1 PROD under organizations/9\n- 2 STAGING under folders/1\n",
+		},
+		"index into the result set": {
+			template: `first={{(index . 0).display_name}} created={{(index . 0).create_time}}`,
+			want:     "first=prod created=2024-01-15T12:30:00Z",
+		},
+		"helper functions": {
+			template: `{{range $i, $f := .}}{{if $i}},{{end}}{{lower $f.state}}:{{trim " x "}}{{end}}`,
+			want:     "active:x,active:x",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tmpl, err := output.ParseTemplateFile(writeTemplate(t, tt.template))
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			formatter := output.NewFormatter(&buf, nil, false, output.ResourceTypeFolders)
+			formatter.SetTemplate(tmpl)
+			require.NoError(t, formatter.Format(resources, output.FormatTemplate, output.FolderHeaders()))
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestFormatTemplateJoin(t *testing.T) {
+	tmpl, err := output.ParseTemplate("inline", `{{join .ids ", "}}|{{join .names "/"}}|{{join .n "-"}}`)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, tmpl.Execute(&buf, map[string]any{
+		"ids":   []any{"1", 2},
+		"names": []string{"a", "b"},
+		"n":     3,
+	}))
+	assert.Equal(t, "1, 2|a/b|3", buf.String())
+}
+
+func TestParseTemplateFileErrors(t *testing.T) {
+	path := writeTemplate(t, "{{range .}}{{.id}}")
+
+	_, err := output.ParseTemplateFile(path)
+	require.ErrorIs(t, err, output.ErrInvalidTemplate)
+	assert.Contains(t, err.Error(), path)
+
+	_, err = output.ParseTemplateFile(filepath.Join(t.TempDir(), "missing.tmpl"))
+	require.ErrorIs(t, err, output.ErrInvalidTemplate)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestFormatTemplateMissing(t *testing.T) {
+	formatter := output.NewFormatter(&bytes.Buffer{}, nil, false, output.ResourceTypeFolders)
+	err := formatter.Format(nil, output.FormatTemplate, nil)
+	require.ErrorIs(t, err, output.ErrMissingTemplate)
+}
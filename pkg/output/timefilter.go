@@ -0,0 +1,75 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidTime is returned when a time threshold cannot be parsed.
+var ErrInvalidTime = errors.New("invalid time")
+
+// dateLayout is the calendar date form accepted for time thresholds.
+const dateLayout = "2006-01-02"
+
+// TimeFilter keeps resources whose timestamps fall strictly within the configured thresholds.
+// A zero threshold is ignored.
+type TimeFilter struct {
+	CreatedAfter  time.Time // CreatedAfter keeps resources created after this instant
+	CreatedBefore time.Time // CreatedBefore keeps resources created before this instant
+	UpdatedAfter  time.Time // UpdatedAfter keeps resources updated after this instant
+}
+
+// IsZero reports whether the filter has no thresholds and therefore matches every resource.
+func (f TimeFilter) IsZero() bool {
+	return f.CreatedAfter.IsZero() && f.CreatedBefore.IsZero() && f.UpdatedAfter.IsZero()
+}
+
+// Match reports whether the resource satisfies every non-zero threshold.
+func (f TimeFilter) Match(r Resource) bool {
+	if !f.CreatedAfter.IsZero() && !r.GetCreateTime().After(f.CreatedAfter) {
+		return false
+	}
+	if !f.CreatedBefore.IsZero() && !r.GetCreateTime().Before(f.CreatedBefore) {
+		return false
+	}
+	if !f.UpdatedAfter.IsZero() && !r.GetUpdateTime().After(f.UpdatedAfter) {
+		return false
+	}
+
+	return true
+}
+
+// ParseTimeThreshold parses an RFC3339 timestamp (e.g., "2024-01-01T10:00:00Z") or a calendar date
+// (e.g., "2024-01-01", interpreted as midnight UTC). An empty value yields the zero time.
+func ParseTimeThreshold(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(dateLayout, value); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("%w: %q (expected RFC3339 timestamp or YYYY-MM-DD date)", ErrInvalidTime, value)
+}
+
+// FilterByTime returns the resources matching the time filter, preserving order.
+// A zero filter returns the resources unchanged.
+func FilterByTime(resources []Resource, filter TimeFilter) []Resource {
+	if filter.IsZero() {
+		return resources
+	}
+
+	matched := make([]Resource, 0, len(resources))
+	for _, resource := range resources {
+		if filter.Match(resource) {
+			matched = append(matched, resource)
+		}
+	}
+
+	return matched
+}
@@ -0,0 +1,144 @@
+package output_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimeThreshold(t *testing.T) {
+	tests := map[string]struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		"date": {
+			value: "2024-01-01",
+			want:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		"RFC3339 timestamp": {
+			value: "2024-01-01T10:30:00+02:00",
+			want:  time.Date(2024, 1, 1, 8, 30, 0, 0, time.UTC),
+		},
+		"empty value": {
+			value: "",
+			want:  time.Time{},
+		},
+		"unparseable value": {
+			value:   "yesterday",
+			wantErr: true,
+		},
+		"date with invalid day": {
+			value:   "2024-02-30",
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := output.ParseTimeThreshold(tt.value)
+			if tt.wantErr {
+				require.ErrorIs(t, err, output.ErrInvalidTime)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "got %s, want %s", got, tt.want)
+		})
+	}
+}
+
+func TestFilterByTime(t *testing.T) {
+	threshold := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	folderList := []*folders.Folder{
+		{ID: "old", CreateTime: threshold.Add(-time.Hour), UpdateTime: threshold.Add(-time.Minute)},
+		{ID: "exact", CreateTime: threshold, UpdateTime: threshold},
+		{ID: "touched", CreateTime: threshold.Add(-time.Hour), UpdateTime: threshold.Add(time.Minute)},
+		{ID: "new", CreateTime: threshold.Add(time.Hour), UpdateTime: threshold.Add(2 * time.Hour)},
+	}
+
+	tests := map[string]struct {
+		filter output.TimeFilter
+		want   []string
+	}{
+		"updated after keeps later updates only": {
+			filter: output.TimeFilter{UpdatedAfter: threshold},
+			want:   []string{"touched", "new"},
+		},
+		"created after keeps later creations only": {
+			filter: output.TimeFilter{CreatedAfter: threshold},
+			want:   []string{"new"},
+		},
+		"both thresholds must match": {
+			filter: output.TimeFilter{CreatedAfter: threshold.Add(-2 * time.Hour), UpdatedAfter: threshold},
+			want:   []string{"touched", "new"},
+		},
+		"created before keeps earlier creations only": {
+			filter: output.TimeFilter{CreatedBefore: threshold},
+			want:   []string{"old", "touched"},
+		},
+		"zero filter keeps everything": {
+			filter: output.TimeFilter{},
+			want:   []string{"old", "exact", "touched", "new"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := output.FilterByTime(output.FoldersToResources(folderList), tt.filter)
+
+			ids := make([]string, 0, len(got))
+			for _, r := range got {
+				ids = append(ids, r.GetID())
+			}
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+}
+
+func TestFilterByTimeAge(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	folderList := []*folders.Folder{
+		{ID: "last-week", CreateTime: now.Add(-7 * 24 * time.Hour)},
+		{ID: "last-month", CreateTime: now.Add(-25 * 24 * time.Hour)},
+		{ID: "last-year", CreateTime: now.Add(-300 * 24 * time.Hour)},
+	}
+
+	tests := map[string]struct {
+		filter output.TimeFilter
+		want   []string
+	}{
+		"created within 30 days": {
+			filter: output.TimeFilter{CreatedAfter: now.Add(-30 * 24 * time.Hour)},
+			want:   []string{"last-week", "last-month"},
+		},
+		"older than 2 weeks": {
+			filter: output.TimeFilter{CreatedBefore: now.Add(-14 * 24 * time.Hour)},
+			want:   []string{"last-month", "last-year"},
+		},
+		"between 2 weeks and 30 days old": {
+			filter: output.TimeFilter{
+				CreatedAfter:  now.Add(-30 * 24 * time.Hour),
+				CreatedBefore: now.Add(-14 * 24 * time.Hour),
+			},
+			want: []string{"last-month"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := output.FilterByTime(output.FoldersToResources(folderList), tt.filter)
+
+			ids := make([]string, 0, len(got))
+			for _, r := range got {
+				ids = append(ids, r.GetID())
+			}
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+}
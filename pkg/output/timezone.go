@@ -0,0 +1,170 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// ErrUnknownTimezone is returned when a timezone name cannot be loaded.
+var ErrUnknownTimezone = errors.New("unknown timezone")
+
+// tableTimeLayout is the layout of timestamps in table and CSV output.
+const tableTimeLayout = "2006-01-02 15:04:05"
+
+// timeFields are the JSON keys holding resource timestamps.
+var timeFields = map[string]bool{"create_time": true, "update_time": true}
+
+// LoadLocation loads an IANA timezone such as "America/New_York" for SetLocation.
+func LoadLocation(name string) (*time.Location, error) {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTimezone, name)
+	}
+
+	return loc, nil
+}
+
+// SetNullValue sets the text written for missing values and zero timestamps in table, CSV and value
+// output, which is empty by default. JSON output keeps its own encoding of these values.
+func (f *Formatter) SetNullValue(null string) {
+	f.nullValue = null
+}
+
+// SetLocation converts timestamps to loc before rendering. A nil location keeps the stored zone.
+func (f *Formatter) SetLocation(loc *time.Location) {
+	f.location = loc
+}
+
+// row returns the resource's table row with timestamps rendered in the formatter's location, missing
+// values and zero timestamps written as the formatter's null value and, when idColumn is not negative,
+// the ID in that column written in the formatter's ID style.
+func (f *Formatter) row(resource Resource, idColumn int) table.Row {
+	row := resource.TableRow()
+	for i, cell := range row {
+		switch value := cell.(type) {
+		case time.Time:
+			if value.IsZero() {
+				row[i] = f.nullValue
+			} else {
+				row[i] = f.localTime(value).Format(tableTimeLayout)
+			}
+		case nil:
+			row[i] = f.nullValue
+		}
+	}
+	if idColumn >= 0 && idColumn < len(row) {
+		row[idColumn] = f.resourceID(resource)
+	}
+
+	return row
+}
+
+// localTime converts t to the formatter's location. Zero times are left untouched.
+func (f *Formatter) localTime(t time.Time) time.Time {
+	if f.location == nil || t.IsZero() {
+		return t
+	}
+
+	return t.In(f.location)
+}
+
+// jsonResource returns the value encoded for a resource in JSON output.
+func (f *Formatter) jsonResource(resource Resource) any {
+	if f.location == nil {
+		return resource
+	}
+
+	return &localizedResource{Resource: resource, formatter: f}
+}
+
+// jsonResources returns the values encoded for resources in JSON output.
+func (f *Formatter) jsonResources(resources []Resource) any {
+	if f.location == nil || resources == nil {
+		return resources
+	}
+
+	localized := make([]any, len(resources))
+	for i, resource := range resources {
+		localized[i] = f.jsonResource(resource)
+	}
+
+	return localized
+}
+
+// localizedResource wraps a resource so that its JSON timestamps are converted to a location.
+type localizedResource struct {
+	Resource
+
+	formatter *Formatter
+}
+
+// Unwrap returns the wrapped resource.
+func (l *localizedResource) Unwrap() Resource {
+	return l.Resource
+}
+
+// MarshalJSON encodes the wrapped resource, rewriting its timestamp fields in the formatter's
+// location while keeping the key order of the original encoding.
+func (l *localizedResource) MarshalJSON() ([]byte, error) {
+	base, err := json.Marshal(l.Resource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode resource: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(base))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, ErrNonObjectResource
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i := 0; dec.More(); i++ {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode resource: %w", err)
+		}
+		key, _ := tok.(string)
+
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, fmt.Errorf("failed to decode resource field %s: %w", key, err)
+		}
+		if timeFields[key] {
+			value = l.localizeValue(value)
+		}
+
+		encodedKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode field name %s: %w", key, err)
+		}
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(encodedKey)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// localizeValue converts an encoded timestamp, returning values that are not timestamps unchanged.
+func (l *localizedResource) localizeValue(value json.RawMessage) json.RawMessage {
+	var t time.Time
+	if err := json.Unmarshal(value, &t); err != nil {
+		return value
+	}
+
+	localized, err := json.Marshal(l.formatter.localTime(t))
+	if err != nil {
+		return value
+	}
+
+	return localized
+}
@@ -0,0 +1,141 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	resourcemanagerpb "cloud.google.com/go/resourcemanager/apiv3/resourcemanagerpb"
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatterLocation(t *testing.T) {
+	created := time.Date(2024, 1, 15, 12, 30, 0, 0, time.UTC)
+	updated := time.Date(2024, 7, 1, 3, 0, 0, 0, time.UTC)
+	resources := output.FoldersToResources([]*folders.Folder{{
+		ID:         "1",
+		Parent:     "organizations/9",
+		State:      "ACTIVE",
+		CreateTime: created,
+		UpdateTime: updated,
+	}})
+
+	tests := map[string]struct {
+		timezone  string
+		wantCSV   string
+		wantJSONL string
+	}{
+		"stored zone": {
+			wantCSV: "1,,organizations/9,ACTIVE,2024-01-15 12:30:00,2024-07-01 03:00:00\n",
+			wantJSONL: `{"id":"1","name":"","display_name":"","parent":"organizations/9","state":"ACTIVE",` +
+				`"create_time":"2024-01-15T12:30:00Z","update_time":"2024-07-01T03:00:00Z"}` + "\n",
+		},
+		"new york across daylight saving time": {
+			timezone: "America/New_York",
+			wantCSV:  "1,,organizations/9,ACTIVE,2024-01-15 07:30:00,2024-06-30 23:00:00\n",
+			wantJSONL: `{"id":"1","name":"","display_name":"","parent":"organizations/9","state":"ACTIVE",` +
+				`"create_time":"2024-01-15T07:30:00-05:00","update_time":"2024-06-30T23:00:00-04:00"}` + "\n",
+		},
+		"kolkata half-hour offset": {
+			timezone: "Asia/Kolkata",
+			wantCSV:  "1,,organizations/9,ACTIVE,2024-01-15 18:00:00,2024-07-01 08:30:00\n",
+			wantJSONL: `{"id":"1","name":"","display_name":"","parent":"organizations/9","state":"ACTIVE",` +
+				`"create_time":"2024-01-15T18:00:00+05:30","update_time":"2024-07-01T08:30:00+05:30"}` + "\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var loc *time.Location
+			if tt.timezone != "" {
+				var err error
+				loc, err = output.LoadLocation(tt.timezone)
+				require.NoError(t, err)
+			}
+
+			var csvOut, jsonlOut bytes.Buffer
+			formatter := output.NewFormatter(&csvOut, nil, false, output.ResourceTypeFolders)
+			formatter.SetLocation(loc)
+			require.NoError(t, formatter.Format(resources, output.FormatCSV, output.FolderHeaders()))
+			assert.Equal(t, "ID,Display Name,Parent,State,Create Time,Update Time\n"+tt.wantCSV, csvOut.String())
+
+			formatter = output.NewFormatter(&jsonlOut, nil, false, output.ResourceTypeFolders)
+			formatter.SetLocation(loc)
+			require.NoError(t, formatter.Format(resources, output.FormatJSONL, output.FolderHeaders()))
+			assert.Equal(t, tt.wantJSONL, jsonlOut.String())
+		})
+	}
+}
+
+func TestFormatterLocationZeroTime(t *testing.T) {
+	loc, err := output.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	resources := output.FoldersToResources([]*folders.Folder{{ID: "1"}})
+
+	var buf bytes.Buffer
+	formatter := output.NewFormatter(&buf, nil, false, output.ResourceTypeFolders)
+	formatter.SetLocation(loc)
+	require.NoError(t, formatter.Format(resources, output.FormatJSONL, output.FolderHeaders()))
+	assert.Contains(t, buf.String(), `"create_time":"0001-01-01T00:00:00Z"`)
+}
+
+func TestLoadLocationUnknown(t *testing.T) {
+	_, err := output.LoadLocation("Mars/Olympus_Mons")
+	require.ErrorIs(t, err, output.ErrUnknownTimezone)
+}
+
+func TestFormatterNullValue(t *testing.T) {
+	// a folder converted from a response without timestamps, as in TestFolderFromProto's minimal case
+	folder := folders.FolderFromProto(&resourcemanagerpb.Folder{
+		Name:        "folders/123",
+		DisplayName: "Minimal Folder",
+	})
+	resources := output.FoldersToResources([]*folders.Folder{folder})
+
+	tests := map[string]struct {
+		format      output.Format
+		null        string
+		contains    string
+		notContains string
+	}{
+		"csv leaves unset times empty": {
+			format:      output.FormatCSV,
+			contains:    "123,Minimal Folder,,STATE_UNSPECIFIED,,\n",
+			notContains: "0001-01-01",
+		},
+		"csv writes the null value": {
+			format:   output.FormatCSV,
+			null:     "N/A",
+			contains: "123,Minimal Folder,,STATE_UNSPECIFIED,N/A,N/A\n",
+		},
+		"table writes the null value": {
+			format:      output.FormatTable,
+			null:        "-",
+			contains:    "| 123 | Minimal Folder |        | STATE_UNSPECIFIED | -           | -           |",
+			notContains: "0001-01-01",
+		},
+		"json keeps the zero timestamp": {
+			format:   output.FormatJSONL,
+			null:     "N/A",
+			contains: `"create_time":"0001-01-01T00:00:00Z","update_time":"0001-01-01T00:00:00Z"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			formatter := output.NewFormatter(&stdout, &bytes.Buffer{}, false, output.ResourceTypeFolders)
+			formatter.SetNullValue(tt.null)
+			require.NoError(t, formatter.Format(resources, tt.format, output.FolderHeaders()))
+
+			assert.Contains(t, stdout.String(), tt.contains)
+			if tt.notContains != "" {
+				assert.NotContains(t, stdout.String(), tt.notContains)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package output
+
+import (
+	"unicode/utf8"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// ellipsis ends string values shortened by table truncation.
+const ellipsis = "…"
+
+// SetTruncate limits the string cells of table output to width characters, ending shortened values
+// with an ellipsis. Zero keeps full values; other formats always keep them.
+func (f *Formatter) SetTruncate(width int) {
+	f.truncate = width
+}
+
+// Truncate shortens s to at most width runes, with an ellipsis as the last rune when anything was cut.
+// Multibyte characters are never split. A width of zero or less returns s unchanged.
+func Truncate(s string, width int) string {
+	if width <= 0 || utf8.RuneCountInString(s) <= width {
+		return s
+	}
+
+	runes := []rune(s)
+
+	return string(runes[:width-1]) + ellipsis
+}
+
+// tableRow returns the resource's row for table output, with string cells truncated to the
+// formatter's width and, in human mode, the digits of integer cells grouped.
+func (f *Formatter) tableRow(resource Resource, idColumn int) table.Row {
+	row := f.row(resource, idColumn)
+	if f.truncate <= 0 && !f.human {
+		return row
+	}
+
+	for i, cell := range row {
+		switch value := cell.(type) {
+		case string:
+			if f.truncate > 0 {
+				row[i] = Truncate(value, f.truncate)
+			}
+		case int:
+			if f.human {
+				row[i] = HumanizeInt(value)
+			}
+		}
+	}
+
+	return row
+}
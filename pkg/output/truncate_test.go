@@ -0,0 +1,80 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := map[string]struct {
+		s     string
+		width int
+		want  string
+	}{
+		"short value kept":       {s: "prod", width: 10, want: "prod"},
+		"exact width kept":       {s: "production", width: 10, want: "production"},
+		"long value shortened":   {s: "production-workloads", width: 10, want: "productio…"},
+		"multibyte runes intact": {s: "Équipe données clients", width: 8, want: "Équipe …"},
+		"cjk runes":              {s: "開発環境フォルダ", width: 4, want: "開発環…"},
+		"width one":              {s: "prod", width: 1, want: "…"},
+		"zero disables":          {s: "production-workloads", width: 0, want: "production-workloads"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, output.Truncate(tt.s, tt.width))
+		})
+	}
+}
+
+func TestFormatterTruncateTableOnly(t *testing.T) {
+	longName := "Engineering Platform Shared Services"
+	multibyteName := "Développement général"
+	resources := output.FoldersToResources([]*folders.Folder{
+		{ID: "1", DisplayName: longName},
+		{ID: "2", DisplayName: multibyteName},
+	})
+	selected, headers, err := output.SelectFields(resources, output.FolderHeaders(), []string{"id", "display_name"})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		format      output.Format
+		contains    []string
+		notContains []string
+	}{
+		"table is truncated": {
+			format:      output.FormatTable,
+			contains:    []string{"Engineering…", "Développeme…"},
+			notContains: []string{longName, multibyteName},
+		},
+		"csv keeps full values": {
+			format:   output.FormatCSV,
+			contains: []string{longName, multibyteName},
+		},
+		"json keeps full values": {
+			format:   output.FormatJSON,
+			contains: []string{longName, multibyteName},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			formatter := output.NewFormatter(&stdout, &bytes.Buffer{}, false, output.ResourceTypeFolders)
+			formatter.SetTruncate(12)
+			require.NoError(t, formatter.Format(selected, tt.format, headers))
+
+			for _, want := range tt.contains {
+				assert.Contains(t, stdout.String(), want)
+			}
+			for _, unwanted := range tt.notContains {
+				assert.NotContains(t, stdout.String(), unwanted)
+			}
+		})
+	}
+}
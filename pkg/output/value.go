@@ -0,0 +1,45 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// formatValue writes one line per resource with its row values separated by tabs and no header,
+// like gcloud's value format.
+func (f *Formatter) formatValue(resources []Resource, headers []string) error {
+	idColumn := f.idColumn(headers)
+	for _, resource := range resources {
+		if _, err := fmt.Fprintln(f.writer, valueLine(f.row(resource, idColumn))); err != nil {
+			return fmt.Errorf("failed to write values: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// streamValue writes value lines as resources arrive, matching formatValue.
+func (f *Formatter) streamValue(resources <-chan Resource, headers []string) error {
+	idColumn := f.idColumn(headers)
+	for resource := range resources {
+		if _, err := fmt.Fprintln(f.writer, valueLine(f.row(resource, idColumn))); err != nil {
+			return fmt.Errorf("failed to write values: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// valueLine joins the cells of a row with tabs, writing missing values as empty strings.
+func valueLine(row table.Row) string {
+	cells := make([]string, len(row))
+	for i, cell := range row {
+		if cell != nil {
+			cells[i] = fmt.Sprint(cell)
+		}
+	}
+
+	return strings.Join(cells, "\t")
+}
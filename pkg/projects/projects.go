@@ -0,0 +1,91 @@
+// Package projects looks up Google Cloud projects, for example to find the organization a project belongs to.
+package projects
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	resourcemanager "cloud.google.com/go/resourcemanager/apiv3"
+	resourcemanagerpb "cloud.google.com/go/resourcemanager/apiv3/resourcemanagerpb"
+	"google.golang.org/api/option"
+)
+
+const projectPrefix = "projects/"
+
+// Project represents a Google Cloud project.
+type Project struct {
+	ProjectID   string `json:"project_id"`   // ProjectID is the project's unique ID, e.g. "acme-prod"
+	Name        string `json:"name"`         // Name is the project's resource name ("projects/123456789")
+	DisplayName string `json:"display_name"` // DisplayName is the project's human-readable name
+	Parent      string `json:"parent"`       // Parent is the folder or organization containing the project
+	State       string `json:"state"`        // State indicates the project's lifecycle state
+}
+
+// ProjectFromProto converts a protobuf project to our internal type.
+func ProjectFromProto(pb *resourcemanagerpb.Project) *Project {
+	if pb == nil {
+		return nil
+	}
+
+	return &Project{
+		ProjectID:   pb.GetProjectId(),
+		Name:        pb.GetName(),
+		DisplayName: pb.GetDisplayName(),
+		Parent:      pb.GetParent(),
+		State:       pb.GetState().String(),
+	}
+}
+
+// ProjectName returns the resource name of a project given by its ID, number or resource name.
+func ProjectName(project string) string {
+	if strings.HasPrefix(project, projectPrefix) {
+		return project
+	}
+
+	return projectPrefix + project
+}
+
+// Getter retrieves a single project.
+type Getter interface {
+	// GetProject retrieves a project by its ID, number or resource name.
+	GetProject(ctx context.Context, project string) (*Project, error)
+}
+
+// Client implements Getter using the Google Cloud Resource Manager API.
+type Client struct {
+	client *resourcemanager.ProjectsClient
+}
+
+// NewClientFromContext creates a new projects client using application default credentials.
+// Client options such as option.WithEndpoint are passed to the underlying API client.
+func NewClientFromContext(ctx context.Context, clientOpts ...option.ClientOption) (*Client, error) {
+	c, err := resourcemanager.NewProjectsClient(ctx, clientOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create projects client: %w", err)
+	}
+
+	return &Client{
+		client: c,
+	}, nil
+}
+
+// GetProject retrieves a project by its ID, number or resource name.
+func (c *Client) GetProject(ctx context.Context, project string) (*Project, error) {
+	name := ProjectName(project)
+	pb, err := c.client.GetProject(ctx, &resourcemanagerpb.GetProjectRequest{Name: name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project %s: %w", name, err)
+	}
+
+	return ProjectFromProto(pb), nil
+}
+
+// Close releases any resources held by the client.
+func (c *Client) Close() error {
+	if err := c.client.Close(); err != nil {
+		return fmt.Errorf("failed to close projects client: %w", err)
+	}
+
+	return nil
+}
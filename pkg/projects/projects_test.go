@@ -0,0 +1,45 @@
+package projects_test
+
+import (
+	"testing"
+
+	resourcemanagerpb "cloud.google.com/go/resourcemanager/apiv3/resourcemanagerpb"
+	"github.com/andreygrechin/gcphelper/pkg/projects"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectFromProto(t *testing.T) {
+	project := projects.ProjectFromProto(&resourcemanagerpb.Project{
+		Name:        "projects/123",
+		ProjectId:   "acme-prod",
+		DisplayName: "Acme Prod",
+		Parent:      "folders/456",
+		State:       resourcemanagerpb.Project_ACTIVE,
+	})
+
+	assert.Equal(t, &projects.Project{
+		ProjectID:   "acme-prod",
+		Name:        "projects/123",
+		DisplayName: "Acme Prod",
+		Parent:      "folders/456",
+		State:       "ACTIVE",
+	}, project)
+	assert.Nil(t, projects.ProjectFromProto(nil))
+}
+
+func TestProjectName(t *testing.T) {
+	tests := map[string]struct {
+		project string
+		want    string
+	}{
+		"project ID":     {project: "acme-prod", want: "projects/acme-prod"},
+		"project number": {project: "123", want: "projects/123"},
+		"resource name":  {project: "projects/acme-prod", want: "projects/acme-prod"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, projects.ProjectName(tt.project))
+		})
+	}
+}
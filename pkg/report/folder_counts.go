@@ -0,0 +1,159 @@
+// Package report aggregates resource listings into summary reports.
+package report
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"strconv"
+	"time"
+
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	"github.com/andreygrechin/gcphelper/pkg/organizations"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultConcurrency is the number of organizations counted in parallel when none is specified.
+const DefaultConcurrency = 4
+
+// noteDenied explains a missing count for organizations whose folders cannot be listed.
+const noteDenied = "permission denied"
+
+// FolderCount is the number of folders found in one organization.
+type FolderCount struct {
+	OrgID       string `json:"org_id"`         // OrgID is the organization's numeric ID
+	DisplayName string `json:"display_name"`   // DisplayName is the organization's human-readable name
+	Count       *int   `json:"folder_count"`   // Count is the number of folders, nil when they could not be listed
+	Note        string `json:"note,omitempty"` // Note explains a missing count
+}
+
+// GetID returns the organization ID.
+func (c *FolderCount) GetID() string {
+	return c.OrgID
+}
+
+// GetName returns the organization's resource name ("organizations/123").
+func (c *FolderCount) GetName() string {
+	return "organizations/" + c.OrgID
+}
+
+// GetDisplayName returns the organization display name.
+func (c *FolderCount) GetDisplayName() string {
+	return c.DisplayName
+}
+
+// GetState returns an empty string since report rows have no lifecycle state.
+func (c *FolderCount) GetState() string {
+	return ""
+}
+
+// GetCreateTime returns the zero time since report rows have no timestamps.
+func (c *FolderCount) GetCreateTime() time.Time {
+	return time.Time{}
+}
+
+// GetUpdateTime returns the zero time since report rows have no timestamps.
+func (c *FolderCount) GetUpdateTime() time.Time {
+	return time.Time{}
+}
+
+// TableRow returns the report row for table output, leaving the count blank when it is unknown.
+func (c *FolderCount) TableRow() []interface{} {
+	count := ""
+	if c.Count != nil {
+		count = strconv.Itoa(*c.Count)
+	}
+
+	return table.Row{c.OrgID, c.DisplayName, count, c.Note}
+}
+
+// OrganizationSearcher lists the organizations accessible to the caller.
+type OrganizationSearcher interface {
+	SearchOrganizations(ctx context.Context, opts *organizations.OrgFetchOptions) ([]*organizations.Organization, error)
+}
+
+// FolderIterator iterates folders, optionally scoped by parent.
+type FolderIterator interface {
+	ListFoldersIter(ctx context.Context, opts *folders.FetchOptions) iter.Seq2[*folders.Folder, error]
+}
+
+// FolderCountHeaders returns the table headers for the folder count report.
+func FolderCountHeaders() []string {
+	return []string{"Org ID", "Display Name", "Folder Count", "Note"}
+}
+
+// CountFoldersByOrganization counts the folders in every accessible ACTIVE organization, walking each
+// organization's hierarchy level by level. Organizations are counted concurrently, at most
+// concurrency at a time. Organizations whose folders cannot be listed because of missing
+// permissions get a nil count and a note; any other error aborts the report.
+func CountFoldersByOrganization(
+	ctx context.Context,
+	orgSearcher OrganizationSearcher,
+	folderIterator FolderIterator,
+	concurrency int,
+) ([]*FolderCount, error) {
+	orgList, err := orgSearcher.SearchOrganizations(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+	if concurrency < 1 {
+		concurrency = DefaultConcurrency
+	}
+
+	counts := make([]*FolderCount, len(orgList))
+	group, ctx := errgroup.WithContext(ctx)
+	group.SetLimit(concurrency)
+
+	for i, org := range orgList {
+		group.Go(func() error {
+			row := &FolderCount{OrgID: org.ID, DisplayName: org.DisplayName}
+
+			count, err := countSubtree(ctx, folderIterator, org.Name)
+			switch {
+			case err == nil:
+				row.Count = &count
+			case status.Code(err) == codes.PermissionDenied:
+				row.Note = noteDenied
+			default:
+				return fmt.Errorf("failed to count folders in %s: %w", org.Name, err)
+			}
+			counts[i] = row
+
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// countSubtree counts all folders below parent by listing each level of the hierarchy in turn.
+func countSubtree(ctx context.Context, folderIterator FolderIterator, parent string) (int, error) {
+	count := 0
+	pending := []string{parent}
+	for len(pending) > 0 {
+		next := pending[0]
+		pending = pending[1:]
+
+		for folder, err := range folderIterator.ListFoldersIter(ctx, &folders.FetchOptions{Parent: next}) {
+			if err != nil {
+				// folders below the root that cannot be listed are counted but not descended into
+				if next != parent && status.Code(err) == codes.PermissionDenied {
+					break
+				}
+
+				return 0, err
+			}
+			count++
+			pending = append(pending, folder.Name)
+		}
+	}
+
+	return count, nil
+}
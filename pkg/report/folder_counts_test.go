@@ -0,0 +1,150 @@
+package report_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/internal/logger"
+	"github.com/andreygrechin/gcphelper/pkg/folders"
+	foldersmocks "github.com/andreygrechin/gcphelper/pkg/folders/mocks"
+	"github.com/andreygrechin/gcphelper/pkg/organizations"
+	orgmocks "github.com/andreygrechin/gcphelper/pkg/organizations/mocks"
+	"github.com/andreygrechin/gcphelper/pkg/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Test error variables for err113 compliance.
+var errReportTestAPIError = errors.New("API error")
+
+// hierarchy maps a parent resource name to its child folders or to the error listing them returns.
+type hierarchy struct {
+	children map[string][]*folders.Folder
+	errs     map[string]error
+}
+
+// walk implements WalkFolders over the hierarchy, honoring the parent in the fetch options.
+func (h hierarchy) walk(_ context.Context, opts *folders.FetchOptions, fn func(*folders.Folder) error) error {
+	if err, ok := h.errs[opts.Parent]; ok {
+		return err
+	}
+	for _, folder := range h.children[opts.Parent] {
+		if err := fn(folder); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func folder(id string) *folders.Folder {
+	return &folders.Folder{ID: id, Name: "folders/" + id}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestCountFoldersByOrganization(t *testing.T) {
+	orgList := []*organizations.Organization{
+		{ID: "1", Name: "organizations/1", DisplayName: "Acme", State: "ACTIVE"},
+		{ID: "2", Name: "organizations/2", DisplayName: "Denied Corp", State: "ACTIVE"},
+		{ID: "3", Name: "organizations/3", DisplayName: "Empty Inc", State: "ACTIVE"},
+	}
+
+	tests := map[string]struct {
+		hierarchy hierarchy
+		want      []*report.FolderCount
+		wantErr   error
+	}{
+		"counts nested folders and notes denied organizations": {
+			hierarchy: hierarchy{
+				children: map[string][]*folders.Folder{
+					"organizations/1": {folder("10"), folder("11")},
+					"folders/10":      {folder("100"), folder("101")},
+					"folders/100":     {folder("1000")},
+				},
+				errs: map[string]error{
+					"organizations/2": status.Error(codes.PermissionDenied, "denied"),
+				},
+			},
+			want: []*report.FolderCount{
+				{OrgID: "1", DisplayName: "Acme", Count: intPtr(5)},
+				{OrgID: "2", DisplayName: "Denied Corp", Note: "permission denied"},
+				{OrgID: "3", DisplayName: "Empty Inc", Count: intPtr(0)},
+			},
+		},
+		"counts denied subfolders without descending into them": {
+			hierarchy: hierarchy{
+				children: map[string][]*folders.Folder{
+					"organizations/1": {folder("10"), folder("11")},
+					"folders/11":      {folder("110")},
+				},
+				errs: map[string]error{
+					"folders/10": status.Error(codes.PermissionDenied, "denied"),
+				},
+			},
+			want: []*report.FolderCount{
+				{OrgID: "1", DisplayName: "Acme", Count: intPtr(3)},
+				{OrgID: "2", DisplayName: "Denied Corp", Count: intPtr(0)},
+				{OrgID: "3", DisplayName: "Empty Inc", Count: intPtr(0)},
+			},
+		},
+		"other errors abort the report": {
+			hierarchy: hierarchy{
+				errs: map[string]error{"organizations/3": errReportTestAPIError},
+			},
+			wantErr: errReportTestAPIError,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			orgFetcher := orgmocks.NewMockFetcher(t)
+			orgFetcher.On("SearchOrganizations", mock.Anything).Return(orgList, nil)
+			folderFetcher := foldersmocks.NewMockFetcher(t)
+			folderFetcher.On("WalkFolders", mock.Anything, mock.Anything, mock.Anything).
+				Return(tt.hierarchy.walk).Maybe()
+
+			got, err := report.CountFoldersByOrganization(
+				t.Context(),
+				organizations.NewServiceWithLogger(orgFetcher, logger.NewNoOpLogger()),
+				folders.NewServiceWithLogger(folderFetcher, logger.NewNoOpLogger()),
+				2,
+			)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCountFoldersByOrganizationSearchError(t *testing.T) {
+	orgFetcher := orgmocks.NewMockFetcher(t)
+	orgFetcher.On("SearchOrganizations", mock.Anything).Return(nil, errReportTestAPIError)
+
+	_, err := report.CountFoldersByOrganization(
+		t.Context(),
+		organizations.NewServiceWithLogger(orgFetcher, logger.NewNoOpLogger()),
+		folders.NewServiceWithLogger(foldersmocks.NewMockFetcher(t), logger.NewNoOpLogger()),
+		report.DefaultConcurrency,
+	)
+	require.ErrorIs(t, err, errReportTestAPIError)
+}
+
+func TestFolderCountTableRow(t *testing.T) {
+	counted := &report.FolderCount{OrgID: "1", DisplayName: "Acme", Count: intPtr(7)}
+	denied := &report.FolderCount{OrgID: "2", DisplayName: "Denied", Note: "permission denied"}
+
+	assert.Equal(t, []interface{}{"1", "Acme", "7", ""}, counted.TableRow())
+	assert.Equal(t, []interface{}{"2", "Denied", "", "permission denied"}, denied.TableRow())
+}
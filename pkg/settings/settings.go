@@ -0,0 +1,94 @@
+// Package settings describes the effective configuration of a command and where each value came from.
+package settings
+
+import (
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// Sources reported in Setting.Source.
+const (
+	SourceDefault = "default" // SourceDefault marks a built-in default
+	SourceEnv     = "env"     // SourceEnv marks a value read from an environment variable
+	SourceFlag    = "flag"    // SourceFlag marks a value set on the command line
+)
+
+// Environment variables read by the Google Cloud client libraries.
+const (
+	EnvCredentials  = "GOOGLE_APPLICATION_CREDENTIALS"
+	EnvQuotaProject = "GOOGLE_CLOUD_QUOTA_PROJECT"
+)
+
+// Values reported for settings that are not set explicitly.
+const (
+	DefaultCredentials = "application default credentials"
+	DefaultLogLevel    = "debug"
+)
+
+// Setting is one effective configuration value.
+type Setting struct {
+	Name   string `json:"name"`   // Name is the setting, e.g. a global flag name such as "format"
+	Value  string `json:"value"`  // Value is the effective value
+	Source string `json:"source"` // Source tells where the value came from: default, env or flag
+}
+
+// GetID returns the setting name.
+func (s *Setting) GetID() string {
+	return s.Name
+}
+
+// GetName returns the setting name.
+func (s *Setting) GetName() string {
+	return s.Name
+}
+
+// GetDisplayName returns the setting name.
+func (s *Setting) GetDisplayName() string {
+	return s.Name
+}
+
+// GetState returns an empty string since settings have no lifecycle state.
+func (s *Setting) GetState() string {
+	return ""
+}
+
+// GetCreateTime returns the zero time since settings have no timestamps.
+func (s *Setting) GetCreateTime() time.Time {
+	return time.Time{}
+}
+
+// GetUpdateTime returns the zero time since settings have no timestamps.
+func (s *Setting) GetUpdateTime() time.Time {
+	return time.Time{}
+}
+
+// TableRow returns the setting as a table row.
+func (s *Setting) TableRow() []interface{} {
+	return table.Row{s.Name, s.Value, s.Source}
+}
+
+// Headers returns the table headers for settings output.
+func Headers() []string {
+	return []string{"Name", "Value", "Source"}
+}
+
+// FromEnv returns the setting named name with the value of the environment variable key when it is
+// set, and with fallback as a default otherwise.
+func FromEnv(getenv func(string) string, name, key, fallback string) *Setting {
+	if value := getenv(key); value != "" {
+		return &Setting{Name: name, Value: value, Source: SourceEnv}
+	}
+
+	return &Setting{Name: name, Value: fallback, Source: SourceDefault}
+}
+
+// Environment returns the settings the Google Cloud client libraries read from the environment, and the
+// log level, which is fixed.
+func Environment(getenv func(string) string) []*Setting {
+	return []*Setting{
+		FromEnv(getenv, "credentials", EnvCredentials, DefaultCredentials),
+		FromEnv(getenv, "quota_project", EnvQuotaProject, ""),
+		{Name: "log_level", Value: DefaultLogLevel, Source: SourceDefault},
+	}
+}
@@ -0,0 +1,41 @@
+package settings_test
+
+import (
+	"testing"
+
+	"github.com/andreygrechin/gcphelper/pkg/settings"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvironment(t *testing.T) {
+	tests := map[string]struct {
+		env  map[string]string
+		want []*settings.Setting
+	}{
+		"defaults": {
+			want: []*settings.Setting{
+				{Name: "credentials", Value: settings.DefaultCredentials, Source: settings.SourceDefault},
+				{Name: "quota_project", Value: "", Source: settings.SourceDefault},
+				{Name: "log_level", Value: settings.DefaultLogLevel, Source: settings.SourceDefault},
+			},
+		},
+		"environment variables": {
+			env: map[string]string{
+				settings.EnvCredentials:  "/keys/sa.json",
+				settings.EnvQuotaProject: "billing-proj",
+			},
+			want: []*settings.Setting{
+				{Name: "credentials", Value: "/keys/sa.json", Source: settings.SourceEnv},
+				{Name: "quota_project", Value: "billing-proj", Source: settings.SourceEnv},
+				{Name: "log_level", Value: settings.DefaultLogLevel, Source: settings.SourceDefault},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			assert.Equal(t, tt.want, settings.Environment(getenv))
+		})
+	}
+}